@@ -7,6 +7,7 @@ package apidoc
 
 import (
 	"bytes"
+	"context"
 	"log"
 	"net/http"
 	"path/filepath"
@@ -67,6 +68,15 @@ func Build(h *core.MessageHandler, o *build.Output, i ...*build.Input) error {
 	return build.Build(h, o, i...)
 }
 
+// Watch 监视源码目录的变化并重新生成文档
+//
+// 首次调用会完整地生成文档，之后仅重新解析有变化的文件，直到 ctx 被取消。
+// 如果是文档语法错误，则相关的错误信息会反馈给 h，由 h 处理错误信息；
+// 如果是配置项（o 和 i）有问题，则以 *core.Error 类型返回错误信息。
+func Watch(ctx context.Context, h *core.MessageHandler, interval time.Duration, o *build.Output, i ...*build.Input) error {
+	return build.Watch(ctx, h, interval, o, i...)
+}
+
 // Buffer 生成文档内容并返回
 //
 // 如果是文档语法错误，则相关的错误信息会反馈给 h，由 h 处理错误信息；
@@ -82,6 +92,21 @@ func CheckSyntax(h *core.MessageHandler, i ...*build.Input) error {
 	return build.CheckSyntax(h, i...)
 }
 
+// Import 将 openapi 3 文档转换成 apidoc 的 XML 文档
+//
+// path 可以是 JSON 或是 YAML 格式的 openapi 文档，无法转换的内容会以警告的形式输出至 h。
+func Import(h *core.MessageHandler, path core.URI) ([]byte, error) {
+	return build.Import(h, path)
+}
+
+// Diff 比较两个文档之间的差异
+//
+// from 和 to 分别表示旧文档和新文档，文档的语法错误会输出至 h。
+// 返回的每一项都会标明是否为不兼容的改动。
+func Diff(h *core.MessageHandler, from, to core.URI) ([]*build.Change, error) {
+	return build.Diff(h, from, to)
+}
+
 // ServeLSP 提供 language server protocol 服务
 //
 // header 表示传递内容是否带报头；
@@ -4,8 +4,10 @@ package build
 
 import (
 	"bytes"
+	"context"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/issue9/version"
 	"gopkg.in/yaml.v3"
@@ -166,6 +168,15 @@ func (cfg *Config) Build(h *core.MessageHandler) {
 	}
 }
 
+// Watch 监视源码目录的变化并重新生成文档
+//
+// 具体信息可参考 Watch 函数的相关文档。
+func (cfg *Config) Watch(ctx context.Context, h *core.MessageHandler, interval time.Duration) {
+	if err := Watch(ctx, h, interval, cfg.Output, cfg.Inputs...); err != nil {
+		panic(err) // 由 loadConfig 保证配置项的正确，如果还出错则直接 panic
+	}
+}
+
 // Buffer 根据 wd 目录下的配置文件生成文档内容并保存至内存
 //
 // 具体信息可参考 Buffer 函数的相关文档。
@@ -0,0 +1,423 @@
+// SPDX-License-Identifier: MIT
+
+package build
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/lexer"
+	"github.com/caixw/apidoc/v7/internal/locale"
+)
+
+// 文档变化的类型
+const (
+	ChangeAPIAdded        = "api-added"
+	ChangeAPIRemoved      = "api-removed"
+	ChangeParamAdded      = "param-added"
+	ChangeParamRemoved    = "param-removed"
+	ChangeParamRequired   = "param-required"
+	ChangeParamOptional   = "param-optional"
+	ChangeTypeChanged     = "type-changed"
+	ChangeEnumRemoved     = "enum-removed"
+	ChangeStatusAdded     = "status-added"
+	ChangeStatusRemoved   = "status-removed"
+	ChangeMimetypeAdded   = "mimetype-added"
+	ChangeMimetypeRemoved = "mimetype-removed"
+)
+
+var changeLocales = map[string]string{
+	ChangeAPIAdded:        locale.DiffAPIAdded,
+	ChangeAPIRemoved:      locale.DiffAPIRemoved,
+	ChangeParamAdded:      locale.DiffParamAdded,
+	ChangeParamRemoved:    locale.DiffParamRemoved,
+	ChangeParamRequired:   locale.DiffParamRequired,
+	ChangeParamOptional:   locale.DiffParamOptional,
+	ChangeTypeChanged:     locale.DiffTypeChanged,
+	ChangeEnumRemoved:     locale.DiffEnumRemoved,
+	ChangeStatusAdded:     locale.DiffStatusAdded,
+	ChangeStatusRemoved:   locale.DiffStatusRemoved,
+	ChangeMimetypeAdded:   locale.DiffMimetypeAdded,
+	ChangeMimetypeRemoved: locale.DiffMimetypeRemoved,
+}
+
+// Change 表示两个文档之间的一处差异
+type Change struct {
+	Type     string `json:"type"`
+	Breaking bool   `json:"breaking"`        // 是否为不兼容的改动
+	API      string `json:"api,omitempty"`   // 以 METHOD path 的形式表示，为空表示文档级别的改动。
+	Field    string `json:"field,omitempty"` // 改动的字段
+	Old      string `json:"old,omitempty"`   // 改动之前的值
+	New      string `json:"new,omitempty"`   // 改动之后的值
+}
+
+// 比较时参数的表示方式
+//
+// 同时用于表示 ast.Param 以及 ast.Request 的顶层元素。
+type diffParam struct {
+	typ      string
+	array    bool
+	optional bool
+	enums    []string
+}
+
+type differ struct {
+	changes []*Change
+}
+
+// Diff 比较两个文档之间的差异
+//
+// from 和 to 分别表示旧文档和新文档的地址，可以是本地文件，也可以是远程文件。
+// 文档的语法错误会输出至 h 对象。
+func Diff(h *core.MessageHandler, from, to core.URI) ([]*Change, error) {
+	o, err := loadDoc(h, from)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := loadDoc(h, to)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &differ{}
+	d.mimetypes("", "mimetype", elementValues(o.Mimetypes), elementValues(n.Mimetypes))
+
+	oapis := apiMap(o)
+	napis := apiMap(n)
+	for _, key := range sortedKeys(oapis) {
+		if api, found := napis[key]; found {
+			d.api(key, o, oapis[key], n, api)
+			continue
+		}
+		d.add(&Change{Type: ChangeAPIRemoved, Breaking: true, API: key})
+	}
+	for _, key := range sortedKeys(napis) {
+		if _, found := oapis[key]; !found {
+			d.add(&Change{Type: ChangeAPIAdded, API: key})
+		}
+	}
+
+	return d.changes, nil
+}
+
+func (c *Change) String() string {
+	prefix := locale.DiffCompatible
+	if c.Breaking {
+		prefix = locale.DiffBreaking
+	}
+
+	var b strings.Builder
+	b.WriteString(locale.Sprintf(prefix))
+	if c.API != "" {
+		b.WriteByte(' ')
+		b.WriteString(c.API)
+	}
+	if c.Field != "" {
+		b.WriteByte(' ')
+		b.WriteString(c.Field)
+	}
+	b.WriteString(": ")
+
+	var args []any
+	switch c.Type {
+	case ChangeTypeChanged:
+		args = []any{c.Old, c.New}
+	case ChangeEnumRemoved, ChangeStatusRemoved, ChangeMimetypeRemoved:
+		args = []any{c.Old}
+	case ChangeStatusAdded, ChangeMimetypeAdded:
+		args = []any{c.New}
+	}
+	b.WriteString(locale.Sprintf(changeLocales[c.Type], args...))
+
+	return b.String()
+}
+
+// 加载文档内容
+//
+// 文档中的错误信息会转发至 h，同时返回第一个错误，
+// 以免将无法正确解析的文档当作是删除了所有 API。
+func loadDoc(h *core.MessageHandler, uri core.URI) (*ast.APIDoc, error) {
+	data, err := uri.ReadAll(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	b := core.Block{Data: data, Location: core.Location{URI: uri}}
+	p, err := lexer.BlockEndPosition(b)
+	if err != nil {
+		return nil, err
+	}
+	b.Location.Range.End = p.Position
+
+	var parseErr error
+	ph := core.NewMessageHandler(func(msg *core.Message) {
+		if msg.Type == core.Erro && parseErr == nil {
+			if err, ok := msg.Message.(error); ok {
+				parseErr = err
+			} else {
+				parseErr = fmt.Errorf("%v", msg.Message)
+			}
+		}
+		h.Message(msg.Type, msg.Message)
+	})
+	d := &ast.APIDoc{}
+	d.Parse(ph, b)
+	ph.Stop()
+
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	return d, nil
+}
+
+// 以 METHOD path 为键名
+//
+// 缺少 method 或是 path 的 API 无法比较，直接忽略。
+func apiMap(d *ast.APIDoc) map[string]*ast.API {
+	apis := make(map[string]*ast.API, len(d.APIs))
+	for _, api := range d.APIs {
+		if api.Method == nil || api.Path == nil || api.Path.Path == nil {
+			continue
+		}
+		apis[api.Method.V()+" "+api.Path.Path.V()] = api
+	}
+	return apis
+}
+
+func (d *differ) add(c *Change) { d.changes = append(d.changes, c) }
+
+func (d *differ) api(key string, odoc *ast.APIDoc, o *ast.API, ndoc *ast.APIDoc, n *ast.API) {
+	d.params(key, pathParams(o.Path, false), pathParams(n.Path, false), true)
+	d.params(key, pathParams(o.Path, true), pathParams(n.Path, true), true)
+	d.params(key, headerParams(odoc, o), headerParams(ndoc, n), true)
+
+	// request
+	oreqs := requestMap(o.Requests)
+	nreqs := requestMap(n.Requests)
+	d.mimetypes(key, "request", sortedKeys(oreqs), sortedKeys(nreqs))
+	for _, mimetype := range sortedKeys(oreqs) {
+		if nreq, found := nreqs[mimetype]; found {
+			field := fieldName("request", mimetype)
+			d.params(key, requestParams(field, oreqs[mimetype]), requestParams(field, nreq), true)
+		}
+	}
+
+	// response，复制之后再合并，防止写入 ast 中切片的底层数组。
+	oresps := responseMap(append(append([]*ast.Request{}, o.Responses...), odoc.Responses...))
+	nresps := responseMap(append(append([]*ast.Request{}, n.Responses...), ndoc.Responses...))
+	for _, status := range sortedKeys(oresps) {
+		if _, found := nresps[status]; !found {
+			d.add(&Change{Type: ChangeStatusRemoved, Breaking: true, API: key, Field: "response", Old: status})
+		}
+	}
+	for _, status := range sortedKeys(nresps) {
+		oreqs, found := oresps[status]
+		if !found {
+			d.add(&Change{Type: ChangeStatusAdded, API: key, Field: "response", New: status})
+			continue
+		}
+
+		nreqs := nresps[status]
+		field := fieldName("response", status)
+		d.mimetypes(key, field, sortedKeys(oreqs), sortedKeys(nreqs))
+		for _, mimetype := range sortedKeys(oreqs) {
+			if nreq, found := nreqs[mimetype]; found {
+				f := fieldName(field, mimetype)
+				d.params(key, requestParams(f, oreqs[mimetype]), requestParams(f, nreq), false)
+			}
+		}
+	}
+}
+
+func (d *differ) mimetypes(api, field string, o, n []string) {
+	for _, mt := range o {
+		if !containsString(n, mt) {
+			d.add(&Change{Type: ChangeMimetypeRemoved, Breaking: true, API: api, Field: field, Old: mt})
+		}
+	}
+
+	for _, mt := range n {
+		if !containsString(o, mt) {
+			d.add(&Change{Type: ChangeMimetypeAdded, API: api, Field: field, New: mt})
+		}
+	}
+}
+
+// 比较参数列表
+//
+// input 表示参数是否由客户端提交，这决定了改动是否兼容。
+func (d *differ) params(api string, o, n map[string]*diffParam, input bool) {
+	for _, key := range sortedKeys(o) {
+		op := o[key]
+		np, found := n[key]
+		if !found {
+			if parent := parentKey(key); parent == "" || o[parent] == nil || n[parent] != nil { // 父元素已经被删除的，不再输出。
+				d.add(&Change{Type: ChangeParamRemoved, Breaking: true, API: api, Field: key})
+			}
+			continue
+		}
+
+		if op.typ != np.typ || op.array != np.array {
+			d.add(&Change{Type: ChangeTypeChanged, Breaking: true, API: api, Field: key, Old: op.String(), New: np.String()})
+			continue
+		}
+
+		switch {
+		case op.optional && !np.optional:
+			d.add(&Change{Type: ChangeParamRequired, Breaking: input, API: api, Field: key})
+		case !op.optional && np.optional:
+			d.add(&Change{Type: ChangeParamOptional, Breaking: !input, API: api, Field: key})
+		}
+
+		// 请求参数删除了枚举值，或是返回参数取消了枚举的限制，都是不兼容的改动。
+		if len(op.enums) > 0 {
+			breaking := input == (len(np.enums) > 0)
+			for _, e := range op.enums {
+				if !containsString(np.enums, e) {
+					d.add(&Change{Type: ChangeEnumRemoved, Breaking: breaking, API: api, Field: key, Old: e})
+				}
+			}
+		}
+	}
+
+	for _, key := range sortedKeys(n) {
+		if _, found := o[key]; found {
+			continue
+		}
+
+		if parent := parentKey(key); parent != "" && n[parent] != nil && o[parent] == nil { // 父元素也是新添加的
+			continue
+		}
+
+		np := n[key]
+		d.add(&Change{Type: ChangeParamAdded, Breaking: input && !np.optional, API: api, Field: key})
+	}
+}
+
+func (p *diffParam) String() string {
+	if p.array {
+		return p.typ + "[]"
+	}
+	return p.typ
+}
+
+func newDiffParam(t *ast.TypeAttribute, array, optional *ast.BoolAttribute, enums []*ast.Enum) *diffParam {
+	p := &diffParam{
+		typ:      t.V(),
+		array:    array.V(),
+		optional: optional.V(),
+		enums:    make([]string, 0, len(enums)),
+	}
+	for _, e := range enums {
+		p.enums = append(p.enums, e.Value.V())
+	}
+	return p
+}
+
+func flattenParams(prefix string, params []*ast.Param, m map[string]*diffParam) {
+	for _, p := range params {
+		key := prefix + "." + p.Name.V()
+		m[key] = newDiffParam(p.Type, p.Array, p.Optional, p.Enums)
+		flattenParams(key, p.Items, m)
+	}
+}
+
+func pathParams(path *ast.Path, query bool) map[string]*diffParam {
+	m := make(map[string]*diffParam, 10)
+	if path == nil {
+		return m
+	}
+
+	if query {
+		flattenParams("query", path.Queries, m)
+	} else {
+		flattenParams("path", path.Params, m)
+	}
+	return m
+}
+
+func headerParams(doc *ast.APIDoc, api *ast.API) map[string]*diffParam {
+	m := make(map[string]*diffParam, 10)
+	flattenParams("header", doc.Headers, m)
+	flattenParams("header", api.Headers, m)
+	for _, req := range api.Requests {
+		flattenParams("header", req.Headers, m)
+	}
+	return m
+}
+
+func requestParams(field string, req *ast.Request) map[string]*diffParam {
+	m := make(map[string]*diffParam, 10)
+	m[field] = newDiffParam(req.Type, req.Array, nil, req.Enums)
+	flattenParams(field, req.Items, m)
+	return m
+}
+
+// 以 mimetype 为键名
+func requestMap(reqs []*ast.Request) map[string]*ast.Request {
+	m := make(map[string]*ast.Request, len(reqs))
+	for _, req := range reqs {
+		m[req.Mimetype.V()] = req
+	}
+	return m
+}
+
+// 以状态码和 mimetype 为键名
+func responseMap(resps []*ast.Request) map[string]map[string]*ast.Request {
+	m := make(map[string]map[string]*ast.Request, len(resps))
+	for _, resp := range resps {
+		status := strconv.Itoa(resp.Status.V())
+		if _, found := m[status]; !found {
+			m[status] = make(map[string]*ast.Request, 2)
+		}
+		if _, found := m[status][resp.Mimetype.V()]; !found { // api 中的定义优先于 apidoc 中的定义
+			m[status][resp.Mimetype.V()] = resp
+		}
+	}
+	return m
+}
+
+func fieldName(field, key string) string {
+	if key == "" {
+		return field
+	}
+	return field + "[" + key + "]"
+}
+
+func parentKey(key string) string {
+	if index := strings.LastIndexByte(key, '.'); index > 0 {
+		return key[:index]
+	}
+	return ""
+}
+
+func elementValues(elems []*ast.Element) []string {
+	ret := make([]string, 0, len(elems))
+	for _, e := range elems {
+		ret = append(ret, e.Content.Value)
+	}
+	return ret
+}
+
+func containsString(items []string, v string) bool {
+	for _, item := range items {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
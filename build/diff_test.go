@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: MIT
+
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/issue9/assert/v3"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/core/messagetest"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+const diffOld = `<apidoc version="1.1.1">
+	<title>test</title>
+	<mimetype>application/json</mimetype>
+	<mimetype>application/xml</mimetype>
+	<api method="GET">
+		<path path="/users/{id}">
+			<param name="id" type="number" summary="s" />
+			<query name="fields" type="string" summary="s" array="true">
+				<enum value="id" summary="s" />
+				<enum value="name" summary="s" />
+			</query>
+		</path>
+		<response status="200" type="object">
+			<param name="id" type="number" summary="s" />
+			<param name="name" type="string" summary="s" />
+		</response>
+		<response status="404" type="string" />
+	</api>
+	<api method="DELETE">
+		<path path="/users/{id}"><param name="id" type="number" summary="s" /></path>
+		<response status="204" />
+	</api>
+</apidoc>`
+
+const diffNew = `<apidoc version="1.1.1">
+	<title>test</title>
+	<mimetype>application/json</mimetype>
+	<api method="GET">
+		<path path="/users/{id}">
+			<param name="id" type="string" summary="s" />
+			<query name="fields" type="string" summary="s" array="true">
+				<enum value="id" summary="s" />
+			</query>
+			<query name="page" type="number" summary="s" optional="true" />
+			<query name="size" type="number" summary="s" />
+		</path>
+		<response status="200" type="object">
+			<param name="id" type="number" summary="s" />
+			<param name="name" type="string" summary="s" optional="true" />
+			<param name="addr" type="object" summary="s">
+				<param name="city" type="string" summary="s" />
+			</param>
+		</response>
+		<response status="500" type="string" />
+	</api>
+	<api method="POST">
+		<path path="/users" />
+		<response status="201" />
+	</api>
+</apidoc>`
+
+func writeDiffFiles(a *assert.Assertion) (from, to core.URI) {
+	dir := a.TB().TempDir()
+	o := filepath.Join(dir, "old.xml")
+	n := filepath.Join(dir, "new.xml")
+	a.NotError(os.WriteFile(o, []byte(diffOld), os.ModePerm))
+	a.NotError(os.WriteFile(n, []byte(diffNew), os.ModePerm))
+	return core.FileURI(o), core.FileURI(n)
+}
+
+func TestDiff(t *testing.T) {
+	a := assert.New(t, false)
+	from, to := writeDiffFiles(a)
+
+	rslt := messagetest.NewMessageHandler()
+	changes, err := Diff(rslt.Handler, from, to)
+	rslt.Handler.Stop()
+	a.NotError(err).Empty(rslt.Errors)
+
+	a.Equal(changes, []*Change{
+		{Type: ChangeMimetypeRemoved, Breaking: true, Field: "mimetype", Old: "application/xml"},
+		{Type: ChangeAPIRemoved, Breaking: true, API: "DELETE /users/{id}"},
+		{Type: ChangeTypeChanged, Breaking: true, API: "GET /users/{id}", Field: "path.id", Old: "number", New: "string"},
+		{Type: ChangeEnumRemoved, Breaking: true, API: "GET /users/{id}", Field: "query.fields", Old: "name"},
+		{Type: ChangeParamAdded, API: "GET /users/{id}", Field: "query.page"},
+		{Type: ChangeParamAdded, Breaking: true, API: "GET /users/{id}", Field: "query.size"},
+		{Type: ChangeStatusRemoved, Breaking: true, API: "GET /users/{id}", Field: "response", Old: "404"},
+		{Type: ChangeParamOptional, Breaking: true, API: "GET /users/{id}", Field: "response[200].name"},
+		{Type: ChangeParamAdded, API: "GET /users/{id}", Field: "response[200].addr"},
+		{Type: ChangeStatusAdded, API: "GET /users/{id}", Field: "response", New: "500"},
+		{Type: ChangeAPIAdded, API: "POST /users"},
+	})
+
+	// 相同的文档
+	rslt = messagetest.NewMessageHandler()
+	changes, err = Diff(rslt.Handler, from, from)
+	rslt.Handler.Stop()
+	a.NotError(err).Empty(changes)
+
+	// 文件不存在
+	rslt = messagetest.NewMessageHandler()
+	changes, err = Diff(rslt.Handler, from, "file:///not-exists.xml")
+	rslt.Handler.Stop()
+	a.Error(err).Nil(changes)
+}
+
+func TestDiff_invalid(t *testing.T) {
+	a := assert.New(t, false)
+	from, _ := writeDiffFiles(a)
+	dir := a.TB().TempDir()
+
+	// 格式错误的文档
+	invalid := filepath.Join(dir, "invalid.xml")
+	a.NotError(os.WriteFile(invalid, []byte(`<apidoc version="1.1.1"><title>test</title><api method="GET">`), os.ModePerm))
+	rslt := messagetest.NewMessageHandler()
+	changes, err := Diff(rslt.Handler, from, core.FileURI(invalid))
+	rslt.Handler.Stop()
+	a.Error(err).Nil(changes).NotEmpty(rslt.Errors)
+
+	// 缺少 path 的 API
+	noPath := filepath.Join(dir, "no-path.xml")
+	a.NotError(os.WriteFile(noPath, []byte(`<apidoc version="1.1.1">
+	<title>test</title>
+	<api method="GET"><response status="200" type="string" /></api>
+</apidoc>`), os.ModePerm))
+	rslt = messagetest.NewMessageHandler()
+	a.NotPanic(func() {
+		changes, err = Diff(rslt.Handler, from, core.FileURI(noPath))
+	})
+	rslt.Handler.Stop()
+	a.Error(err).Nil(changes)
+	a.NotPanic(func() {
+		apiMap(&ast.APIDoc{APIs: []*ast.API{{Method: &ast.MethodAttribute{Value: xmlenc.String{Value: "GET"}}}}})
+	})
+}
+
+func TestDiff_mimetypes(t *testing.T) {
+	a := assert.New(t, false)
+	dir := a.TB().TempDir()
+
+	const doc = `<apidoc version="1.1.1">
+	<title>test</title>
+	<mimetype>application/json</mimetype>
+	<mimetype>application/xml</mimetype>
+	<mimetype>text/plain</mimetype>
+	<api method="POST">
+		<path path="/users" />
+		<request type="object" mimetype="application/xml"><param name="id" type="%s" summary="s" /></request>
+		<request type="object" mimetype="application/json"><param name="id" type="%s" summary="s" /></request>
+		<request type="object" mimetype="text/plain"><param name="id" type="%s" summary="s" /></request>
+		<response status="201" />
+	</api>
+</apidoc>`
+	o := filepath.Join(dir, "old.xml")
+	n := filepath.Join(dir, "new.xml")
+	a.NotError(os.WriteFile(o, []byte(fmt.Sprintf(doc, "number", "number", "number")), os.ModePerm))
+	a.NotError(os.WriteFile(n, []byte(fmt.Sprintf(doc, "string", "string", "string")), os.ModePerm))
+
+	// 多次比较的结果顺序都相同
+	for i := 0; i < 10; i++ {
+		rslt := messagetest.NewMessageHandler()
+		changes, err := Diff(rslt.Handler, core.FileURI(o), core.FileURI(n))
+		rslt.Handler.Stop()
+		a.NotError(err).Equal(3, len(changes)).
+			Equal(changes[0].Field, "request[application/json].id").
+			Equal(changes[1].Field, "request[application/xml].id").
+			Equal(changes[2].Field, "request[text/plain].id")
+	}
+}
+
+func TestDiffer_api(t *testing.T) {
+	a := assert.New(t, false)
+
+	newResponse := func(status int) *ast.Request {
+		return &ast.Request{Status: &ast.StatusAttribute{Value: ast.Number{Int: status}}}
+	}
+
+	// 合并 doc.Responses 时不能修改 api.Responses 的底层数组
+	o := &ast.API{Path: &ast.Path{}, Responses: make([]*ast.Request, 1, 2)}
+	o.Responses[0] = newResponse(200)
+	n := &ast.API{Path: &ast.Path{}, Responses: make([]*ast.Request, 1, 2)}
+	n.Responses[0] = newResponse(200)
+	doc := &ast.APIDoc{Responses: []*ast.Request{newResponse(500)}}
+
+	d := &differ{}
+	d.api("GET /users", doc, o, doc, n)
+	a.Empty(d.changes).
+		Nil(o.Responses[:2][1]).
+		Nil(n.Responses[:2][1])
+}
+
+func TestChange_String(t *testing.T) {
+	a := assert.New(t, false)
+
+	c := &Change{Type: ChangeTypeChanged, Breaking: true, API: "GET /users", Field: "query.id", Old: "number", New: "string"}
+	a.Contains(c.String(), "GET /users query.id").
+		Contains(c.String(), "number").
+		Contains(c.String(), "string").
+		NotContains(c.String(), "%!")
+
+	c = &Change{Type: ChangeAPIAdded, API: "GET /users"}
+	a.Contains(c.String(), "GET /users").NotContains(c.String(), "%!")
+}
@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: MIT
+
+package build
+
+import (
+	"encoding/xml"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/internal/openapi"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+// Import 将 openapi 文档转换成 apidoc 的 XML 文档
+//
+// path 指向 openapi 3 的文档，可以是 JSON 或是 YAML 格式；
+// 无法转换的内容会以警告的形式输出至 h 对象，
+// 警告信息中的字段名为该内容在源文档中的 JSON pointer。
+func Import(h *core.MessageHandler, path core.URI) ([]byte, error) {
+	data, err := path.ReadAll(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := openapi.Import(h, path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = xmlenc.Encode("\t", d, "", "")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
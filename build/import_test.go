@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: MIT
+
+package build
+
+import (
+	"testing"
+
+	"github.com/issue9/assert/v3"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/core/messagetest"
+	"github.com/caixw/apidoc/v7/internal/ast"
+)
+
+func TestImport(t *testing.T) {
+	a := assert.New(t, false)
+
+	rslt := messagetest.NewMessageHandler()
+	data, err := Import(rslt.Handler, core.FileURI("../internal/openapi/testdata/openapi.json"))
+	rslt.Handler.Stop()
+	a.NotError(err).NotEmpty(data).Empty(rslt.Warns)
+
+	rslt = messagetest.NewMessageHandler()
+	d := &ast.APIDoc{}
+	d.Parse(rslt.Handler, core.Block{Data: data, Location: core.Location{URI: "file:///apidoc.xml"}})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors).
+		Equal(d.Title.Content.Value, "test").
+		Equal(1, len(d.APIs)).
+		Equal(2, len(d.APIs[0].Responses[0].Items))
+
+	rslt = messagetest.NewMessageHandler()
+	data, err = Import(rslt.Handler, core.FileURI("./testdata/not-exists.json"))
+	rslt.Handler.Stop()
+	a.Error(err).Nil(data)
+}
@@ -94,10 +94,22 @@ func (o *Input) sanitize() error {
 }
 
 // 按 Input 中的规则查找所有符合条件的文件列表并保存至 Input.paths
-func (o *Input) recursivePath() error {
+func (o *Input) recursivePath() (err error) {
+	if o.paths, err = o.files(); err != nil {
+		return err
+	}
+
+	if len(o.paths) == 0 {
+		return core.NewError(locale.ErrNoFiles).WithField("dir")
+	}
+	return nil
+}
+
+// 按 Input 中的规则查找所有符合条件的文件列表
+func (o *Input) files() ([]core.URI, error) {
 	local, err := o.Dir.File()
 	if err != nil {
-		return core.WithError(err).WithField("dir")
+		return nil, core.WithError(err).WithField("dir")
 	}
 	local = filepath.Clean(local)
 
@@ -105,6 +117,7 @@ func (o *Input) recursivePath() error {
 		o.Ignores[i] = filepath.FromSlash(pattern)
 	}
 
+	paths := make([]core.URI, 0, 100)
 	walk := func(path string, fi os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -118,19 +131,15 @@ func (o *Input) recursivePath() error {
 			return err
 		}
 		if !ignore {
-			o.paths = append(o.paths, core.FileURI(path))
+			paths = append(paths, core.FileURI(path))
 		}
 		return nil
 	}
 
 	if err := filepath.Walk(local, walk); err != nil {
-		return core.WithError(err).WithField("dir")
-	}
-
-	if len(o.paths) == 0 {
-		return core.NewError(locale.ErrNoFiles).WithField("dir")
+		return nil, core.WithError(err).WithField("dir")
 	}
-	return nil
+	return paths, nil
 }
 
 func (o *Input) isIgnore(root, path string) (bool, error) {
@@ -14,8 +14,11 @@ import (
 	"github.com/caixw/apidoc/v7/core"
 	"github.com/caixw/apidoc/v7/internal/ast"
 	"github.com/caixw/apidoc/v7/internal/docs"
+	"github.com/caixw/apidoc/v7/internal/html"
 	"github.com/caixw/apidoc/v7/internal/locale"
+	"github.com/caixw/apidoc/v7/internal/markdown"
 	"github.com/caixw/apidoc/v7/internal/openapi"
+	"github.com/caixw/apidoc/v7/internal/postman"
 	"github.com/caixw/apidoc/v7/internal/xmlenc"
 )
 
@@ -24,6 +27,9 @@ const (
 	APIDocXML   = "apidoc+xml"
 	OpenapiYAML = "openapi+yaml"
 	OpenapiJSON = "openapi+json"
+	PostmanJSON = "postman+json"
+	Markdown    = "markdown"
+	HTML        = "html"
 )
 
 type marshaler func(*ast.APIDoc) ([]byte, error)
@@ -103,6 +109,12 @@ func (o *Output) sanitize() error {
 		o.marshal = openapi.JSON
 	case OpenapiYAML:
 		o.marshal = openapi.YAML
+	case PostmanJSON:
+		o.marshal = postman.JSON
+	case Markdown:
+		o.marshal = markdown.Render
+	case HTML:
+		o.marshal = html.HTML
 	default:
 		return core.NewError(locale.ErrInvalidValue).WithField("type")
 	}
@@ -61,10 +61,37 @@ func TestOptions_buffer(t *testing.T) {
 	_, err := o.buffer(doc)
 	a.NotError(err)
 
+	doc = asttest.Get()
+	o = &Output{
+		Type: PostmanJSON,
+		Path: "./postman.json",
+	}
+	a.NotError(o.sanitize()).False(o.xml)
+	buf, err := o.buffer(doc)
+	a.NotError(err).Contains(buf.String(), `"schema"`)
+
+	doc = asttest.Get()
+	o = &Output{
+		Type: Markdown,
+		Path: "./apidoc.md",
+	}
+	a.NotError(o.sanitize()).False(o.xml)
+	buf, err = o.buffer(doc)
+	a.NotError(err).Contains(buf.String(), "# ")
+
+	doc = asttest.Get()
+	o = &Output{
+		Type: HTML,
+		Path: "./apidoc.html",
+	}
+	a.NotError(o.sanitize()).False(o.xml)
+	buf, err = o.buffer(doc)
+	a.NotError(err).Contains(buf.String(), "<!DOCTYPE html>")
+
 	doc = asttest.Get()
 	o = &Output{}
 	a.NotError(o.sanitize())
-	buf, err := o.buffer(doc)
+	buf, err = o.buffer(doc)
 	a.NotError(err).NotNil(buf)
 }
 
@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: MIT
+
+package build
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/issue9/sliceutil"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/locale"
+)
+
+type watcher struct {
+	h      *core.MessageHandler
+	output *Output
+	inputs []*Input
+	doc    *ast.APIDoc
+	files  map[core.URI]*watchFile
+}
+
+// 被监视文件的状态
+type watchFile struct {
+	input   *Input
+	modTime time.Time
+	size    int64
+}
+
+// Watch 监视输入目录中文件的变化并重新生成文档
+//
+// 调用时会先完整地生成一次文档，之后每隔 interval 检测一次 i 中的文件，
+// 仅重新解析有变化的文件，并更新 o.Path 的内容。ctx 被取消时返回。
+//
+// 如果是配置文件有问题，则直接返回错误信息，文档错误则输出至 h 对象。
+func Watch(ctx context.Context, h *core.MessageHandler, interval time.Duration, o *Output, i ...*Input) error {
+	for _, item := range i {
+		if err := item.sanitize(); err != nil {
+			return err
+		}
+	}
+	if err := o.sanitize(); err != nil {
+		return err
+	}
+
+	w := &watcher{
+		h:      h,
+		output: o,
+		inputs: i,
+		doc:    &ast.APIDoc{},
+		files:  make(map[core.URI]*watchFile, 100),
+	}
+
+	if err := w.rebuild(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := w.rebuild(); err != nil {
+				h.Error(err)
+			}
+		}
+	}
+}
+
+// 重新解析有变化的文件并输出文档，没有变化则不作任何操作。
+func (w *watcher) rebuild() error {
+	cnt := w.update(w.scan())
+	if cnt == 0 {
+		return nil
+	}
+
+	d := *w.doc // buffer 会修改文档的内容，只能传递副本。
+	buf, err := w.output.buffer(&d)
+	if err != nil {
+		return err
+	}
+	if err := w.output.Path.WriteAll(buf.Bytes()); err != nil {
+		return err
+	}
+
+	w.h.Locale(core.Info, locale.WatchRebuild, cnt, w.output.Path)
+	return nil
+}
+
+// 返回所有符合 Input 规则的文件及其状态
+func (w *watcher) scan() map[core.URI]*watchFile {
+	files := make(map[core.URI]*watchFile, len(w.files))
+	for _, i := range w.inputs {
+		paths, err := i.files()
+		if err != nil {
+			w.h.Error(err)
+			continue
+		}
+
+		for _, uri := range paths {
+			path, err := uri.File()
+			if err != nil {
+				w.h.Error(err)
+				continue
+			}
+
+			stat, err := os.Stat(path)
+			if err != nil { // 在查找之后被删除的文件
+				continue
+			}
+			files[uri] = &watchFile{input: i, modTime: stat.ModTime(), size: stat.Size()}
+		}
+	}
+	return files
+}
+
+// 根据 files 更新文档，返回有变化的文件数量。
+func (w *watcher) update(files map[core.URI]*watchFile) int {
+	modified := make([]core.URI, 0, 10)
+	for uri, f := range files {
+		old, found := w.files[uri]
+		if !found || !old.modTime.Equal(f.modTime) || old.size != f.size {
+			modified = append(modified, uri)
+		}
+	}
+
+	removed := make([]core.URI, 0, 10)
+	for uri := range w.files {
+		if _, found := files[uri]; !found {
+			removed = append(removed, uri)
+		}
+	}
+
+	w.files = files
+	if len(modified) == 0 && len(removed) == 0 {
+		return 0
+	}
+
+	for _, uri := range removed {
+		deleteURI(w.doc, uri)
+	}
+	for _, uri := range modified {
+		deleteURI(w.doc, uri)
+	}
+
+	w.doc.ParseBlocks(w.h, func(blocks chan core.Block) {
+		for _, uri := range modified {
+			files[uri].input.ParseFile(blocks, w.h, uri)
+		}
+	})
+
+	return len(modified) + len(removed)
+}
+
+// 删除文档中由 uri 定义的内容
+func deleteURI(doc *ast.APIDoc, uri core.URI) {
+	doc.APIs = sliceutil.Delete(doc.APIs, func(i *ast.API) bool {
+		return i.URI == uri
+	})
+
+	if doc.URI == uri {
+		*doc = ast.APIDoc{APIs: doc.APIs}
+	}
+}
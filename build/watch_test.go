@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: MIT
+
+package build
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v3"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/core/messagetest"
+)
+
+const (
+	watchDoc = `/*
+<apidoc version="1.1.1">
+	<title>test</title>
+	<mimetype>application/json</mimetype>
+</apidoc>
+*/`
+
+	watchAPI1 = `/*
+<api method="GET">
+	<path path="/users" />
+	<response status="200" type="string" />
+</api>
+*/`
+
+	watchAPI2 = `/*
+<api method="POST">
+	<path path="/users" />
+	<response status="201" type="string" />
+</api>
+*/`
+)
+
+func TestWatch(t *testing.T) {
+	a := assert.New(t, false)
+	dir := t.TempDir()
+	write := func(name, content string) {
+		a.NotError(os.WriteFile(filepath.Join(dir, name), []byte(content), os.ModePerm))
+	}
+	write("doc.go", watchDoc)
+	write("api.go", watchAPI1)
+
+	out := filepath.Join(dir, "apidoc.xml")
+	o := &Output{Path: core.FileURI(out)}
+	i := &Input{Lang: "go", Dir: core.FileURI(dir)}
+
+	rslt := messagetest.NewMessageHandler()
+	ctx, cancel := context.WithCancel(context.Background())
+	exit := make(chan struct{}, 1)
+	go func() {
+		a.NotError(Watch(ctx, rslt.Handler, 10*time.Millisecond, o, i))
+		exit <- struct{}{}
+	}()
+
+	// 轮询输出的文件，直到包含 contains 且不包含 excludes 中的内容，超时则报错。
+	wait := func(contains []string, excludes ...string) {
+		deadline := time.Now().Add(5 * time.Second)
+	LOOP:
+		for {
+			data, err := os.ReadFile(out)
+			if time.Now().After(deadline) {
+				a.TB().Fatalf("等待输出内容超时：%s %v", data, err)
+			}
+			time.Sleep(10 * time.Millisecond)
+			if err != nil {
+				continue
+			}
+
+			for _, s := range contains {
+				if !strings.Contains(string(data), s) {
+					continue LOOP
+				}
+			}
+			for _, s := range excludes {
+				if strings.Contains(string(data), s) {
+					continue LOOP
+				}
+			}
+			return
+		}
+	}
+
+	wait([]string{`method="GET"`}, `method="POST"`)
+
+	// 修改
+	write("api.go", watchAPI2)
+	wait([]string{`method="POST"`}, `method="GET"`)
+
+	// 添加
+	write("api1.go", watchAPI1)
+	wait([]string{`method="POST"`, `method="GET"`})
+
+	// 删除
+	a.NotError(os.Remove(filepath.Join(dir, "api.go")))
+	wait([]string{`method="GET"`}, `method="POST"`)
+
+	cancel()
+	<-exit
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	// 无效的配置项
+	rslt = messagetest.NewMessageHandler()
+	a.Error(Watch(context.Background(), rslt.Handler, time.Second, o, &Input{Lang: "go"}))
+	rslt.Handler.Stop()
+}
@@ -75,6 +75,9 @@ function registerFilter(type) {
 
 function registerLanguageFilter() {
     const menu = document.querySelector('.languages-selector');
+    if (menu === null) { // 服务端生成的 HTML 只有一种语言，不存在该菜单
+        return;
+    }
 
     menu.style.display = 'block';
 
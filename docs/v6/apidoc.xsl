@@ -206,10 +206,16 @@
     <div class="body">
         <div class="requests">
             <h4 class="header"><xsl:copy-of select="$locale-request" /></h4>
+            <xsl:if test="security">
+                <xsl:call-template name="security">
+                    <xsl:with-param name="security" select="security" />
+                </xsl:call-template>
+            </xsl:if>
             <xsl:call-template name="requests">
                 <xsl:with-param name="requests" select="request" />
                 <xsl:with-param name="path" select="path" />
                 <xsl:with-param name="headers" select="header | /apidoc/header" />
+                <xsl:with-param name="cookies" select="cookie" />
             </xsl:call-template>
         </div>
         <div class="responses">
@@ -261,11 +267,69 @@
 </xsl:template>
 
 
+<!-- api/security 的界面元素，类型和描述等信息从 /apidoc/security 中获取 -->
+<xsl:template name="security">
+<xsl:param name="security" />
+<div class="param">
+    <h4 class="title">&#x27a4;&#160;<xsl:copy-of select="$locale-security" /></h4>
+
+    <table class="param-list">
+        <thead>
+            <tr>
+                <th><xsl:copy-of select="$locale-var" /></th>
+                <th><xsl:copy-of select="$locale-type" /></th>
+                <th><xsl:copy-of select="$locale-scope" /></th>
+                <th><xsl:copy-of select="$locale-description" /></th>
+            </tr>
+        </thead>
+        <tbody>
+            <xsl:for-each select="$security">
+                <xsl:variable name="name" select="@name" />
+                <xsl:variable name="scheme" select="/apidoc/security[@name=$name]" />
+                <tr>
+                    <th><xsl:value-of select="$name" /></th>
+
+                    <td>
+                        <xsl:value-of select="$scheme/@type" />
+                        <xsl:if test="$scheme/@key">
+                            <xsl:value-of select="concat(' (', $scheme/@in, ': ', $scheme/@key, ')')" />
+                        </xsl:if>
+                        <xsl:if test="$scheme/@scheme">
+                            <xsl:value-of select="concat(' (', $scheme/@scheme, ')')" />
+                        </xsl:if>
+                    </td>
+
+                    <td>
+                        <xsl:for-each select="scope">
+                            <xsl:value-of select="." /><xsl:if test="position() != last()">, </xsl:if>
+                        </xsl:for-each>
+                    </td>
+
+                    <td>
+                        <xsl:choose>
+                            <xsl:when test="$scheme/description">
+                                <xsl:attribute name="data-type">
+                                    <xsl:value-of select="$scheme/description/@type" />
+                                </xsl:attribute>
+                                <pre><xsl:copy-of select="$scheme/description/node()" /></pre>
+                            </xsl:when>
+                            <xsl:otherwise><xsl:value-of select="$scheme/@summary" /></xsl:otherwise>
+                        </xsl:choose>
+                    </td>
+                </tr>
+            </xsl:for-each>
+        </tbody>
+    </table>
+</div>
+</xsl:template>
+
+
 <!-- api/request 的界面元素 -->
 <xsl:template name="requests">
 <xsl:param name="requests" />
 <xsl:param name="path" />
 <xsl:param name="headers" /> <!-- 公用的报头 -->
+<xsl:param name="cookies" /> <!-- 公用的 cookie -->
 <xsl:if test="$path/param">
     <xsl:call-template name="param">
         <xsl:with-param name="title">
@@ -296,6 +360,16 @@
     </xsl:call-template>
 </xsl:if>
 
+<xsl:if test="$cookies">
+    <xsl:call-template name="param">
+        <xsl:with-param name="title">
+            <xsl:copy-of select="$locale-cookie" />
+        </xsl:with-param>
+        <xsl:with-param name="param" select="$cookies" />
+        <xsl:with-param name="simple" select="'true'" />
+    </xsl:call-template>
+</xsl:if>
+
 <xsl:variable name="request-any" select="$requests[not(@mimetype)]" />
 <xsl:for-each select="/apidoc/mimetype | $requests/@mimetype[not(/apidoc/mimetype=.)]">
     <xsl:variable name="mimetype" select="." />
@@ -394,6 +468,16 @@
     </xsl:call-template>
 </xsl:if>
 
+<xsl:if test="$param/cookie">
+    <xsl:call-template name="param">
+        <xsl:with-param name="title">
+            <xsl:copy-of select="$locale-cookie" />
+        </xsl:with-param>
+        <xsl:with-param name="param" select="$param/cookie" />
+        <xsl:with-param name="simple" select="'true'" />
+    </xsl:call-template>
+</xsl:if>
+
 
 <xsl:call-template name="param">
     <xsl:with-param name="title"><xsl:copy-of select="$locale-body" /></xsl:with-param>
@@ -472,21 +556,75 @@
         <xsl:with-param name="parent" select="$parent" />
     </xsl:call-template>
 
-    <xsl:if test="param">
-        <xsl:variable name="p">
-                <xsl:value-of select="concat($parent, @name)" />
-                <xsl:if test="@name"><xsl:value-of select="'.'" /></xsl:if>
-        </xsl:variable>
+    <xsl:variable name="p">
+            <xsl:value-of select="concat($parent, @name)" />
+            <xsl:if test="@name"><xsl:value-of select="'.'" /></xsl:if>
+            <!-- 字典的子元素描述的是值，以 * 表示任意的键名 -->
+            <xsl:if test="@type='map'"><xsl:value-of select="'*.'" /></xsl:if>
+    </xsl:variable>
 
+    <xsl:if test="param">
         <xsl:call-template name="param-list">
             <xsl:with-param name="param" select="param" />
             <xsl:with-param name="parent" select="$p" />
         </xsl:call-template>
     </xsl:if>
+
+    <xsl:for-each select="variant">
+        <xsl:call-template name="variant-tr">
+            <xsl:with-param name="variant" select="." />
+            <xsl:with-param name="parent" select="$p" />
+        </xsl:call-template>
+
+        <xsl:if test="param">
+            <xsl:call-template name="param-list">
+                <xsl:with-param name="param" select="param" />
+                <xsl:with-param name="parent" select="concat($p, '(', @value, @ref, ').')" />
+            </xsl:call-template>
+        </xsl:if>
+    </xsl:for-each>
 </xsl:for-each>
 </xsl:template>
 
 
+<!-- 显示复合类型中的一个变体，类型列显示变体的组合方式 -->
+<xsl:template name="variant-tr">
+<xsl:param name="variant" />
+<xsl:param name="parent" select="''" />
+<tr>
+    <th>
+        <span class="parent-type"><xsl:value-of select="$parent" /></span>
+        <xsl:value-of select="concat('(', $variant/@value, $variant/@ref, ')')" />
+    </th>
+
+    <td>
+        <xsl:choose>
+            <xsl:when test="$variant/../@compose"><xsl:value-of select="$variant/../@compose" /></xsl:when>
+            <xsl:otherwise><xsl:value-of select="'one-of'" /></xsl:otherwise>
+        </xsl:choose>
+    </td>
+
+    <td>
+        <xsl:if test="$variant/../@discriminator">
+            <xsl:value-of select="concat($variant/../@discriminator, '=', $variant/@value)" />
+        </xsl:if>
+    </td>
+
+    <td>
+        <xsl:choose>
+            <xsl:when test="$variant/description">
+                <xsl:attribute name="data-type">
+                    <xsl:value-of select="$variant/description/@type" />
+                </xsl:attribute>
+                <pre><xsl:copy-of select="$variant/description/node()" /></pre>
+            </xsl:when>
+            <xsl:otherwise><xsl:value-of select="$variant/@summary" /></xsl:otherwise>
+        </xsl:choose>
+    </td>
+</tr>
+</xsl:template>
+
+
 <!-- 显示一行参数数据 -->
 <xsl:template name="param-list-tr">
 <xsl:param name="param" />
@@ -179,6 +179,45 @@ exclude-result-prefixes="l">
     </xsl:call-template>
 </xsl:variable>
 
+<!-- cookie -->
+<xsl:variable name="locale-cookie">
+    <xsl:call-template name="build-locale">
+        <xsl:with-param name="lang" select="'cmn-hans'" />
+        <xsl:with-param name="text" select="'Cookie'" />
+    </xsl:call-template>
+
+    <xsl:call-template name="build-locale">
+        <xsl:with-param name="lang" select="'cmn-hant'" />
+        <xsl:with-param name="text" select="'Cookie'" />
+    </xsl:call-template>
+</xsl:variable>
+
+<!-- security -->
+<xsl:variable name="locale-security">
+    <xsl:call-template name="build-locale">
+        <xsl:with-param name="lang" select="'cmn-hans'" />
+        <xsl:with-param name="text" select="'安全验证'" />
+    </xsl:call-template>
+
+    <xsl:call-template name="build-locale">
+        <xsl:with-param name="lang" select="'cmn-hant'" />
+        <xsl:with-param name="text" select="'安全驗證'" />
+    </xsl:call-template>
+</xsl:variable>
+
+<!-- scope -->
+<xsl:variable name="locale-scope">
+    <xsl:call-template name="build-locale">
+        <xsl:with-param name="lang" select="'cmn-hans'" />
+        <xsl:with-param name="text" select="'授权范围'" />
+    </xsl:call-template>
+
+    <xsl:call-template name="build-locale">
+        <xsl:with-param name="lang" select="'cmn-hant'" />
+        <xsl:with-param name="text" select="'授權範圍'" />
+    </xsl:call-template>
+</xsl:variable>
+
 <!-- body -->
 <xsl:variable name="locale-body">
     <xsl:call-template name="build-locale">
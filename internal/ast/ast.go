@@ -33,6 +33,7 @@ const (
 	TypeNone     = "" // 空值表示不输出任何内容，仅用于 Request
 	TypeBool     = "bool"
 	TypeObject   = "object"
+	TypeMap      = "map" // 键名为字符串的字典，值由子元素描述，也可以用 map.string 等形式指定值的类型
 	TypeNumber   = "number"
 	TypeString   = "string"
 	TypeInt      = "number.int"
@@ -43,6 +44,51 @@ const (
 	TypeDate     = "string.date"      // RFC3339 full-date
 	TypeTime     = "string.time"      // RFC3339 full-time
 	TypeDateTime = "string.date-time" // RFC3339 full-date + full-time
+	TypeFile     = "file"             // 文件，在 JSON 和 XML 中以 base64 编码的字符串表示
+)
+
+// 复合类型中变体的组合方式
+const (
+	ComposeOneOf = "one-of" // 仅符合其中一个变体
+	ComposeAnyOf = "any-of" // 符合任意一个或多个变体
+	ComposeAllOf = "all-of" // 同时符合所有的变体
+)
+
+// 生成模拟数据时可用的 faker，仅作用于 string 类型。
+const (
+	FakerNone     = "none"     // 不采用 faker，即使参数名称可以匹配到其它 faker
+	FakerName     = "name"     // 人名
+	FakerPhone    = "phone"    // 电话号码
+	FakerAddress  = "address"  // 地址
+	FakerUUID     = "uuid"     // UUID v4
+	FakerIP       = "ip"       // IPv4 地址
+	FakerCountry  = "country"  // ISO 3166-1 alpha-2 国家代码
+	FakerCurrency = "currency" // ISO 4217 货币代码
+	FakerWord     = "word"     // 单个单词
+	FakerSentence = "sentence" // 由 lorem ipsum 组成的句子
+)
+
+// 安全验证方案可用的类型
+const (
+	SecurityTypeAPIKey        = "apikey"
+	SecurityTypeHTTP          = "http"
+	SecurityTypeOAuth2        = "oauth2"
+	SecurityTypeOpenIDConnect = "openid-connect"
+)
+
+// apikey 类型的安全验证方案中，凭证可以出现的位置
+const (
+	SecurityInHeader = "header"
+	SecurityInQuery  = "query"
+	SecurityInCookie = "cookie"
+)
+
+// OAuth2 可用的授权方式
+const (
+	OAuthFlowImplicit          = "implicit"
+	OAuthFlowPassword          = "password"
+	OAuthFlowClientCredentials = "client-credentials"
+	OAuthFlowAuthorizationCode = "authorization-code"
 )
 
 // 富文本可用的类型
@@ -113,6 +159,28 @@ func ParseType(t string) (primitive, sub string) {
 	return t[:index], t[index+1:]
 }
 
+// Description 返回 desc 的内容，为空时返回 summary 的内容
+func Description(desc *Richtext, summary *Attribute) string {
+	if desc.V() != "" {
+		return desc.V()
+	}
+	return summary.V()
+}
+
+// Syntax 返回 mimetype 对应的语法名称
+//
+// 仅识别 json 和 xml，其它类型返回空值。
+func Syntax(mimetype string) string {
+	switch {
+	case mimetype == "application/json" || strings.HasSuffix(mimetype, "+json"):
+		return "json"
+	case strings.HasSuffix(mimetype, "/xml") || strings.HasSuffix(mimetype, "+xml"):
+		return "xml"
+	default:
+		return ""
+	}
+}
+
 func trimLeftSpace(v string) string {
 	var min []byte // 找出的最小行首相同空格内容
 
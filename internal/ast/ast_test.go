@@ -8,6 +8,8 @@ import (
 
 	"github.com/issue9/assert/v3"
 	"github.com/issue9/version"
+
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
 )
 
 func TestVersion(t *testing.T) {
@@ -34,6 +36,25 @@ func TestParseType(t *testing.T) {
 	a.Equal(p, TypeNumber).Equal(s, "int")
 }
 
+func TestDescription(t *testing.T) {
+	a := assert.New(t, false)
+
+	summary := &Attribute{Value: xmlenc.String{Value: "summary"}}
+	a.Equal(Description(nil, summary), "summary").
+		Equal(Description(&Richtext{Text: &CData{Value: xmlenc.String{Value: "desc"}}}, summary), "desc").
+		Empty(Description(nil, nil))
+}
+
+func TestSyntax(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(Syntax("application/json"), "json").
+		Equal(Syntax("application/problem+json"), "json").
+		Equal(Syntax("application/xml"), "xml").
+		Equal(Syntax("text/xml"), "xml").
+		Equal(Syntax("text/plain"), "")
+}
+
 func TestTrimLeftSpace(t *testing.T) {
 	a := assert.New(t, false)
 
@@ -65,6 +65,15 @@ type (
 		RootName struct{}      `apidoc:"type,meta,usage-type"`
 	}
 
+	// RefAttribute 表示对 TypeDef 的引用
+	RefAttribute struct {
+		xmlenc.BaseAttribute
+		Value    xmlenc.String `apidoc:"-"`
+		RootName struct{}      `apidoc:"string,meta,usage-string"`
+
+		definition *Definition
+	}
+
 	// APIDocVersionAttribute 版本号属性，同时对版本号进行比较
 	APIDocVersionAttribute Attribute
 )
@@ -136,7 +145,7 @@ func (num *NumberAttribute) FloatValue() float64 {
 
 // IsFloat 当前的数值类型是否为浮点型
 func (num *NumberAttribute) IsFloat() bool {
-	return num.Value.IsFloat
+	return num != nil && num.Value.IsFloat
 }
 
 // DecodeXMLAttr AttrDecoder.DecodeXMLAttr
@@ -233,6 +242,30 @@ func (a *TypeAttribute) V() string {
 	return a.Value.Value
 }
 
+// DecodeXMLAttr AttrDecoder.DecodeXMLAttr
+func (a *RefAttribute) DecodeXMLAttr(p *xmlenc.Parser, attr *xmlenc.Attribute) error {
+	a.Value = attr.Value
+	return nil
+}
+
+// EncodeXMLAttr AttrEncoder.EncodeXMLAttr
+func (a *RefAttribute) EncodeXMLAttr() (string, error) {
+	return a.V(), nil
+}
+
+// V 返回当前属性实际表示的值
+func (a *RefAttribute) V() string {
+	if a == nil {
+		return ""
+	}
+	return a.Value.Value
+}
+
+// Definition Definitioner.Definition
+func (a *RefAttribute) Definition() *Definition {
+	return a.definition
+}
+
 // DecodeXMLAttr AttrDecoder.DecodeXMLAttr
 func (a *VersionAttribute) DecodeXMLAttr(p *xmlenc.Parser, attr *xmlenc.Attribute) error {
 	a.Value = attr.Value
@@ -327,20 +360,87 @@ func isValidStatus(status int) bool {
 		(status <= http.StatusNetworkAuthenticationRequired)
 }
 
+var validTypes = []string{
+	TypeNone,
+	TypeBool,
+	TypeObject,
+	TypeMap,
+	TypeNumber,
+	TypeInt,
+	TypeFloat,
+	TypeString,
+	TypeURL,
+	TypeEmail,
+	TypeImage,
+	TypeDate,
+	TypeTime,
+	TypeDateTime,
+	TypeFile,
+}
+
+var validFakers = []string{
+	FakerNone,
+	FakerName,
+	FakerPhone,
+	FakerAddress,
+	FakerUUID,
+	FakerIP,
+	FakerCountry,
+	FakerCurrency,
+	FakerWord,
+	FakerSentence,
+}
+
+func isValidFaker(f string) bool {
+	for _, v := range validFakers {
+		if v == f {
+			return true
+		}
+	}
+	return false
+}
+
 func isValidType(t string) bool {
-	return t == TypeBool ||
-		t == TypeObject ||
-		t == TypeNumber ||
-		t == TypeInt ||
-		t == TypeFloat ||
-		t == TypeString ||
-		t == TypeURL ||
-		t == TypeEmail ||
-		t == TypeImage ||
-		t == TypeDate ||
-		t == TypeTime ||
-		t == TypeDateTime ||
-		t == TypeNone
+	// map.xx 的形式，值只能是非对象和字典的类型。
+	if primitive, sub := ParseType(t); primitive == TypeMap && sub != "" {
+		if p, _ := ParseType(sub); p == TypeObject || p == TypeMap {
+			return false
+		}
+		return isValidType(sub)
+	}
+
+	for _, v := range validTypes {
+		if v == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Methods 返回所有支持的请求方法
+func Methods() []string {
+	ret := make([]string, len(validMethods))
+	copy(ret, validMethods)
+	return ret
+}
+
+// Types 返回所有支持的类型
+func Types() []string {
+	ret := make([]string, len(validTypes))
+	copy(ret, validTypes)
+	return ret
+}
+
+// Statuses 返回所有有效的状态码
+func Statuses() []int {
+	ret := make([]int, 0, 70)
+	for status := http.StatusContinue; status <= http.StatusNetworkAuthenticationRequired; status++ {
+		if http.StatusText(status) != "" {
+			ret = append(ret, status)
+		}
+	}
+	return ret
 }
 
 func isValidVersion(v string) bool {
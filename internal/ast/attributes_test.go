@@ -168,6 +168,50 @@ func TestIsValidMethod(t *testing.T) {
 	a.False(isValidMethod("not-exists"))
 }
 
+func TestMethods(t *testing.T) {
+	a := assert.New(t, false)
+
+	methods := Methods()
+	a.Equal(methods, validMethods)
+	methods[0] = "not-exists"
+	a.NotEqual(methods, validMethods)
+}
+
+func TestTypes(t *testing.T) {
+	a := assert.New(t, false)
+
+	types := Types()
+	a.Equal(types, validTypes)
+	for _, typ := range types {
+		a.True(isValidType(typ))
+	}
+}
+
+func TestIsValidType(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.True(isValidType(TypeMap)).
+		True(isValidType("map.string")).
+		True(isValidType("map.number.int")).
+		False(isValidType("map.object")).
+		False(isValidType("map.map")).
+		False(isValidType("map.map.string")).
+		False(isValidType("map.")).
+		False(isValidType("map.not-exists"))
+}
+
+func TestStatuses(t *testing.T) {
+	a := assert.New(t, false)
+
+	statuses := Statuses()
+	a.Contains(statuses, 200).
+		Contains(statuses, 404).
+		NotContains(statuses, 209)
+	for _, status := range statuses {
+		a.True(isValidStatus(status))
+	}
+}
+
 func TestIsValidStatus(t *testing.T) {
 	a := assert.New(t, false)
 
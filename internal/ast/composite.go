@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: MIT
+
+package ast
+
+// ComposeType 返回变体的组合方式
+//
+// 未指定 compose 属性时返回 ComposeOneOf。
+func (c *Composite) ComposeType() string {
+	if v := c.Compose.V(); v != "" {
+		return v
+	}
+	return ComposeOneOf
+}
+
+// Expand 将复合类型展开为不包含变体的对象
+//
+// 对于 one-of 和 any-of，按顺序返回每个变体与 p 中共有的子元素合并之后的对象，
+// 如果指定了 discriminator，对应子元素的枚举值会被限定为该变体的 value；
+// 对于 all-of，返回所有变体合并之后的单个对象。
+// 同名的子元素以变体中的定义为准。如果 p 不包含变体，则返回 nil。
+func (p *Param) Expand() []*Param {
+	if len(p.Variants) == 0 {
+		return nil
+	}
+
+	if p.ComposeType() == ComposeAllOf {
+		items := p.Items
+		for _, v := range p.Variants {
+			items = mergeItems(items, v.Items)
+		}
+		return []*Param{p.expand(items)}
+	}
+
+	params := make([]*Param, 0, len(p.Variants))
+	for _, v := range p.Variants {
+		items := mergeItems(p.Items, v.Items)
+
+		if disc := p.Discriminator.V(); disc != "" {
+			for i, item := range items {
+				if item.Name.V() == disc {
+					pp := *item
+					pp.Enums = []*Enum{{Value: v.Value, Summary: v.Summary}}
+					items[i] = &pp
+				}
+			}
+		}
+
+		params = append(params, p.expand(items))
+	}
+	return params
+}
+
+// 返回以 items 作为子元素且不包含变体的 p 副本
+func (p *Param) expand(items []*Param) *Param {
+	pp := *p
+	pp.Items = items
+	pp.Composite = Composite{}
+	return &pp
+}
+
+// 合并两个子元素列表，同名的元素以 items2 中的为准。
+func mergeItems(items1, items2 []*Param) []*Param {
+	items := make([]*Param, 0, len(items1)+len(items2))
+	items = append(items, items1...)
+
+LOOP:
+	for _, item := range items2 {
+		for i, exists := range items {
+			if exists.Name.V() == item.Name.V() {
+				items[i] = item
+				continue LOOP
+			}
+		}
+		items = append(items, item)
+	}
+
+	return items
+}
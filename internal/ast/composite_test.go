@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: MIT
+
+package ast
+
+import (
+	"testing"
+
+	"github.com/issue9/assert/v3"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/core/messagetest"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+const compositeDoc = `<apidoc>
+	<title>title</title>
+	<mimetype>application/json</mimetype>
+	<typedef name="created" type="object" summary="created">
+		<param name="kind" type="string" summary="kind" />
+		<param name="id" type="number" summary="id" />
+	</typedef>
+	<api method="GET">
+		<path path="/events" />
+		<response status="200" type="object" summary="event" discriminator="kind">
+			<param name="kind" type="string" summary="kind" />
+			<param name="time" type="string.date-time" summary="time" />
+			<variant value="created" ref="created" />
+			<variant value="deleted" summary="deleted">
+				<param name="id" type="number" summary="id" />
+				<param name="reason" type="string" summary="reason" optional="true" />
+			</variant>
+		</response>
+	</api>
+</apidoc>`
+
+func TestComposite_ComposeType(t *testing.T) {
+	a := assert.New(t, false)
+
+	c := &Composite{}
+	a.Equal(c.ComposeType(), ComposeOneOf)
+
+	c.Compose = &Attribute{Value: xmlenc.String{Value: ComposeAllOf}}
+	a.Equal(c.ComposeType(), ComposeAllOf)
+}
+
+func TestParam_Expand(t *testing.T) {
+	a := assert.New(t, false)
+
+	rslt := messagetest.NewMessageHandler()
+	doc := &APIDoc{}
+	doc.Parse(rslt.Handler, core.Block{Data: []byte(compositeDoc)})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	resp := doc.APIs[0].Responses[0].Param()
+	a.Nil((&Param{}).Expand())
+
+	variants := resp.Expand()
+	a.Equal(2, len(variants))
+
+	created := variants[0]
+	a.Empty(created.Variants).
+		Equal(3, len(created.Items)).
+		Equal(created.Items[0].Name.V(), "kind").
+		Equal(created.Items[0].Enums[0].Value.V(), "created").
+		Equal(created.Items[1].Name.V(), "time").
+		Equal(created.Items[2].Name.V(), "id")
+	a.Empty(resp.Items[0].Enums) // 不影响原有的对象
+
+	deleted := variants[1]
+	a.Equal(4, len(deleted.Items)).
+		Equal(deleted.Items[0].Enums[0].Value.V(), "deleted").
+		Equal(deleted.Items[3].Name.V(), "reason")
+
+	// all-of
+	resp.Compose = &Attribute{Value: xmlenc.String{Value: ComposeAllOf}}
+	resp.Discriminator = nil
+	variants = resp.Expand()
+	a.Equal(1, len(variants)).
+		Equal(4, len(variants[0].Items)).
+		Empty(variants[0].Items[0].Enums)
+}
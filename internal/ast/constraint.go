@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: MIT
+
+package ast
+
+import (
+	"math"
+	"mime"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/internal/locale"
+)
+
+// CheckValue 检测单个值 v 是否符合 p 中声明的约束条件
+//
+// 仅检测 min、max、min-length、max-length 和 pattern 等约束，
+// 不检测 v 的类型是否与 p.Type 相符。如果 p 是数组，v 表示数组中的某个元素。
+func (p *Param) CheckValue(v string) *core.Error {
+	switch primitive, _ := ParseType(p.Type.V()); primitive {
+	case TypeNumber:
+		if p.Min == nil && p.Max == nil {
+			return nil
+		}
+
+		num, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return core.NewError(locale.ErrInvalidFormat)
+		}
+		if p.Min != nil {
+			if min := p.Min.number(); num < min || (p.ExclusiveMin.V() && num == min) {
+				return core.NewError(locale.ErrOutOfRange)
+			}
+		}
+		if p.Max != nil {
+			if max := p.Max.number(); num > max || (p.ExclusiveMax.V() && num == max) {
+				return core.NewError(locale.ErrOutOfRange)
+			}
+		}
+	case TypeString:
+		size := utf8.RuneCountInString(v)
+		if p.MinLength != nil && size < p.MinLength.IntValue() {
+			return core.NewError(locale.ErrOutOfRange)
+		}
+		if p.MaxLength != nil && size > p.MaxLength.IntValue() {
+			return core.NewError(locale.ErrOutOfRange)
+		}
+
+		if p.Pattern != nil {
+			re, err := p.Regexp()
+			if err != nil {
+				return core.WithError(err)
+			}
+			if !re.MatchString(v) {
+				return core.NewError(locale.ErrInvalidFormat)
+			}
+		}
+	}
+
+	return nil
+}
+
+// CheckItems 检测数组是否符合 p 中声明的 min-items、max-items 和 unique-items 约束
+//
+// size 为数组元素的数量；values 为各元素的字符串表示，仅用于检测元素是否重复，
+// 如果元素无法表示为字符串（比如对象），可以传递 nil，此时不检测是否重复。
+func (p *Param) CheckItems(size int, values []string) *core.Error {
+	if p.MinItems != nil && size < p.MinItems.IntValue() {
+		return core.NewError(locale.ErrOutOfRange)
+	}
+	if p.MaxItems != nil && size > p.MaxItems.IntValue() {
+		return core.NewError(locale.ErrOutOfRange)
+	}
+
+	if p.UniqueItems.V() {
+		exists := make(map[string]struct{}, len(values))
+		for _, v := range values {
+			if _, found := exists[v]; found {
+				return core.NewError(locale.ErrDuplicateValue)
+			}
+			exists[v] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// CheckFile 检测文件是否符合 p 中声明的 min-size、max-size 和 accept 约束
+//
+// size 为文件的字节数；mimetype 为文件的类型，为空表示类型未知，此时不检测 accept。
+func (p *Param) CheckFile(size int, mimetype string) *core.Error {
+	if p.MinSize != nil && size < p.MinSize.IntValue() {
+		return core.NewError(locale.ErrOutOfRange)
+	}
+	if p.MaxSize != nil && size > p.MaxSize.IntValue() {
+		return core.NewError(locale.ErrOutOfRange)
+	}
+
+	if p.Accept == nil || mimetype == "" {
+		return nil
+	}
+	if mt, _, err := mime.ParseMediaType(mimetype); err == nil {
+		mimetype = mt
+	}
+	for _, accept := range strings.Split(p.Accept.V(), ",") {
+		accept = strings.TrimSpace(accept)
+		if accept == "*/*" || accept == mimetype ||
+			(strings.HasSuffix(accept, "/*") && strings.HasPrefix(mimetype, accept[:len(accept)-1])) {
+			return nil
+		}
+	}
+	return core.NewError(locale.ErrInvalidValue)
+}
+
+// Regexp 返回 Pattern 编译后的正则表达式
+//
+// 如果未指定 Pattern，则返回 nil。
+func (p *Param) Regexp() (*regexp.Regexp, error) {
+	if p.pattern != nil {
+		return p.pattern, nil
+	}
+	if p.Pattern == nil {
+		return nil, nil
+	}
+	return regexp.Compile(p.Pattern.V())
+}
+
+// min 和 max 之间是否不存在可用的值
+//
+// 只能取整数时，去掉 exclusive-min 和 exclusive-max 指定的边界值之后，还需要存在可用的整数。
+func (p *Param) emptyRange() bool {
+	if p.Min == nil || p.Max == nil {
+		return false
+	}
+	min, max := p.Min.number(), p.Max.number()
+
+	t := p.Type.V()
+	if t == TypeFloat || (t != TypeInt && (p.Min.IsFloat() || p.Max.IsFloat())) {
+		return min > max || (min == max && (p.ExclusiveMin.V() || p.ExclusiveMax.V()))
+	}
+
+	if c := math.Ceil(min); c == min && p.ExclusiveMin.V() {
+		min = c + 1
+	} else {
+		min = c
+	}
+	if f := math.Floor(max); f == max && p.ExclusiveMax.V() {
+		max = f - 1
+	} else {
+		max = f
+	}
+	return min > max
+}
+
+func (num *NumberAttribute) number() float64 {
+	if num.IsFloat() {
+		return num.FloatValue()
+	}
+	return float64(num.IntValue())
+}
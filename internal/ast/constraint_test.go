@@ -0,0 +1,200 @@
+// SPDX-License-Identifier: MIT
+
+package ast
+
+import (
+	"testing"
+
+	"github.com/issue9/assert/v3"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/core/messagetest"
+	"github.com/caixw/apidoc/v7/internal/locale"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+func TestParam_CheckValue(t *testing.T) {
+	a := assert.New(t, false)
+
+	newType := func(t string) *TypeAttribute { return &TypeAttribute{Value: xmlenc.String{Value: t}} }
+	newTrue := func() *BoolAttribute { return &BoolAttribute{Value: Bool{Value: true}} }
+
+	p := &Param{Type: newType(TypeNumber)}
+	a.Nil(p.CheckValue("abc")) // 没有约束，不检测类型
+
+	p.Min = &NumberAttribute{Value: Number{Int: 5}}
+	p.Max = &NumberAttribute{Value: Number{Float: 10.5, IsFloat: true}}
+	a.Nil(p.CheckValue("5")).
+		Nil(p.CheckValue("10.5")).
+		NotNil(p.CheckValue("4.9")).
+		NotNil(p.CheckValue("11")).
+		NotNil(p.CheckValue("abc"))
+
+	p.ExclusiveMin = newTrue()
+	p.ExclusiveMax = newTrue()
+	a.NotNil(p.CheckValue("5")).
+		NotNil(p.CheckValue("10.5")).
+		Nil(p.CheckValue("5.1"))
+
+	err := p.CheckValue("100")
+	a.NotNil(err).Equal(err.Err.Error(), locale.Sprintf(locale.ErrOutOfRange))
+
+	p = &Param{
+		Type:      newType(TypeString),
+		MinLength: &NumberAttribute{Value: Number{Int: 2}},
+		MaxLength: &NumberAttribute{Value: Number{Int: 3}},
+	}
+	a.Nil(p.CheckValue("ab")).
+		Nil(p.CheckValue("中文字")). // 以字符为单位
+		NotNil(p.CheckValue("a")).
+		NotNil(p.CheckValue("abcd"))
+
+	p = &Param{
+		Type:    newType(TypeEmail),
+		Pattern: &Attribute{Value: xmlenc.String{Value: "^[a-z]+@"}},
+	}
+	a.Nil(p.CheckValue("abc@example.com")).
+		NotNil(p.CheckValue("123@example.com"))
+
+	p.Pattern.Value.Value = "[" // 无效的正则
+	a.NotNil(p.CheckValue("abc"))
+}
+
+func TestParam_CheckItems(t *testing.T) {
+	a := assert.New(t, false)
+
+	p := &Param{}
+	a.Nil(p.CheckItems(0, nil)).
+		Nil(p.CheckItems(2, []string{"1", "1"}))
+
+	p.MinItems = &NumberAttribute{Value: Number{Int: 1}}
+	p.MaxItems = &NumberAttribute{Value: Number{Int: 2}}
+	p.UniqueItems = &BoolAttribute{Value: Bool{Value: true}}
+	a.Nil(p.CheckItems(1, []string{"1"})).
+		Nil(p.CheckItems(2, nil)).
+		NotNil(p.CheckItems(0, nil)).
+		NotNil(p.CheckItems(3, nil))
+
+	err := p.CheckItems(2, []string{"1", "1"})
+	a.NotNil(err).Equal(err.Err.Error(), locale.Sprintf(locale.ErrDuplicateValue))
+}
+
+func TestParam_CheckFile(t *testing.T) {
+	a := assert.New(t, false)
+
+	p := &Param{}
+	a.Nil(p.CheckFile(0, "")).
+		Nil(p.CheckFile(100, "image/png"))
+
+	p.MinSize = &NumberAttribute{Value: Number{Int: 1}}
+	p.MaxSize = &NumberAttribute{Value: Number{Int: 10}}
+	p.Accept = &Attribute{Value: xmlenc.String{Value: "image/*, application/pdf"}}
+	a.Nil(p.CheckFile(1, "image/png")).
+		Nil(p.CheckFile(10, "application/pdf")).
+		Nil(p.CheckFile(5, "image/jpeg; q=1")).
+		Nil(p.CheckFile(5, "")). // 未知的类型
+		NotNil(p.CheckFile(0, "image/png")).
+		NotNil(p.CheckFile(11, "image/png")).
+		NotNil(p.CheckFile(5, "text/plain"))
+}
+
+func TestParam_sanitizeConstraints(t *testing.T) {
+	a := assert.New(t, false)
+
+	data := []*struct {
+		param string
+		errs  int
+	}{
+		{
+			param: `<param name="p" type="number" summary="s" min="1" max="10" exclusive-min="true" default="5" />`,
+		},
+		{
+			param: `<param name="p" type="string" summary="s" min-length="1" max-length="10" pattern="^[a-z]+$" default="abc" />`,
+		},
+		{
+			param: `<param name="p" type="string" summary="s" array="true" min-items="1" max-items="3" unique-items="true" />`,
+		},
+		{
+			param: `<param name="p" ref="user" min="1" max-length="2" />`, // 引用的类型在解析引用时才确定
+		},
+		{
+			param: `<param name="p" type="string" summary="s" min="1" exclusive-max="true" />`,
+			errs:  3, // min 和 exclusive-max 与类型不符，exclusive-max 缺少 max
+		},
+		{
+			param: `<param name="p" type="number" summary="s" max-length="1" pattern="x" />`,
+			errs:  2,
+		},
+		{
+			param: `<param name="p" type="number" summary="s" min-items="1" unique-items="true" />`,
+			errs:  2, // 非数组
+		},
+		{
+			param: `<param name="p" type="number" summary="s" min="10" max="1" />`,
+			errs:  1,
+		},
+		{
+			param: `<param name="p" type="number" summary="s" min="1" max="2" exclusive-min="true" exclusive-max="true" />`,
+			errs:  1, // 去掉边界值之后没有可用的整数
+		},
+		{
+			param: `<param name="p" type="number.int" summary="s" min="1.2" max="1.8" />`,
+			errs:  1,
+		},
+		{
+			param: `<param name="p" type="number" summary="s" min="1" max="2.5" exclusive-min="true" exclusive-max="true" />`,
+		},
+		{
+			param: `<param name="p" type="number.float" summary="s" min="1" max="1" exclusive-max="true" />`,
+			errs:  1,
+		},
+		{
+			param: `<param name="p" type="string" summary="s" min-length="-1" max-length="1.5" />`,
+			errs:  2,
+		},
+		{
+			param: `<param name="p" type="string" summary="s" min-length="3" max-length="1" />`,
+			errs:  1,
+		},
+		{
+			param: `<param name="p" type="string" summary="s" pattern="[" />`,
+			errs:  1,
+		},
+		{
+			param: `<param name="p" type="number" summary="s" max="10" default="11" />`,
+			errs:  1,
+		},
+		{
+			param: `<param name="p" type="string" summary="s" pattern="^[a-z]+$" default="123">
+				<enum value="abc" summary="abc" />
+				<enum value="456" summary="456" />
+			</param>`,
+			errs: 2, // default 和 enum[456]
+		},
+		{
+			param: `<param name="p" type="file" summary="s" min-size="1" max-size="1024" accept="image/*, application/pdf" />`,
+		},
+		{
+			param: `<param name="p" type="string" summary="s" max-size="1024" accept="image/png" />`,
+			errs:  2, // 非 file 类型
+		},
+		{
+			param: `<param name="p" type="file" summary="s" min-size="10" max-size="1" />`,
+			errs:  1,
+		},
+		{
+			param: `<param name="p" type="file" summary="s" accept="image/png,png" />`,
+			errs:  1,
+		},
+	}
+
+	for i, item := range data {
+		rslt := messagetest.NewMessageHandler()
+		p, err := xmlenc.NewParser(rslt.Handler, core.Block{Data: []byte(item.param)})
+		a.NotError(err).NotNil(p)
+		param := &Param{}
+		xmlenc.Decode(p, param, "")
+		rslt.Handler.Stop()
+		a.Equal(len(rslt.Errors), item.errs, "%d 的错误数量不正确，%v", i, rslt.Errors)
+	}
+}
@@ -2,7 +2,11 @@
 
 package ast
 
-import "github.com/caixw/apidoc/v7/internal/xmlenc"
+import (
+	"regexp"
+
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
 
 type (
 	// APIDoc 对应 apidoc 元素
@@ -22,6 +26,8 @@ type (
 		License       *Link                   `apidoc:"license,elem,usage-apidoc-license,omitempty"`         // 版权信息
 		Tags          []*Tag                  `apidoc:"tag,elem,usage-apidoc-tags,omitempty"`                // 标签列表
 		Servers       []*Server               `apidoc:"server,elem,usage-apidoc-servers,omitempty"`          // 服务器列表
+		TypeDefs      []*TypeDef              `apidoc:"typedef,elem,usage-apidoc-typedefs,omitempty"`        // 可复用的类型定义
+		Securities    []*Security             `apidoc:"security,elem,usage-apidoc-securities,omitempty"`     // 安全验证方案
 		APIs          []*API                  `apidoc:"api,elem,usage-apidoc-apis,omitempty"`                // API 列表
 		Headers       []*Param                `apidoc:"header,elem,usage-apidoc-headers,omitempty"`          // 公共报头
 		Responses     []*Request              `apidoc:"response,elem,usage-apidoc-responses,omitempty"`      // 所有 API 都有可能的返回内容
@@ -53,8 +59,10 @@ type (
 		Callback    *Callback         `apidoc:"callback,elem,usage-api-callback,omitempty"`
 		Deprecated  *VersionAttribute `apidoc:"deprecated,attr,usage-api-deprecated,omitempty"`
 		Headers     []*Param          `apidoc:"header,elem,usage-api-headers,omitempty"`
+		Cookies     []*Param          `apidoc:"cookie,elem,usage-api-cookies,omitempty"`
 		Tags        []*TagValue       `apidoc:"tag,elem,usage-api-tags,omitempty"`
 		Servers     []*ServerValue    `apidoc:"server,elem,usage-api-servers,omitempty"`
+		Securities  []*SecurityValue  `apidoc:"security,elem,usage-api-securities,omitempty"` // 满足其中之一即可
 	}
 
 	// Link 表示一个链接
@@ -119,7 +127,8 @@ type (
 
 		XML
 		Name        *Attribute        `apidoc:"name,attr,usage-param-name"`
-		Type        *TypeAttribute    `apidoc:"type,attr,usage-param-type"`
+		Type        *TypeAttribute    `apidoc:"type,attr,usage-param-type,omitempty"`
+		Ref         *RefAttribute     `apidoc:"ref,attr,usage-param-ref,omitempty"`
 		Deprecated  *VersionAttribute `apidoc:"deprecated,attr,usage-param-deprecated,omitempty"`
 		Default     *Attribute        `apidoc:"default,attr,usage-param-default,omitempty"`
 		Optional    *BoolAttribute    `apidoc:"optional,attr,usage-param-optional,omitempty"`
@@ -129,6 +138,34 @@ type (
 		Enums       []*Enum           `apidoc:"enum,elem,usage-param-enums,omitempty"`
 		Description *Richtext         `apidoc:"description,elem,usage-param-description,omitempty"`
 
+		Nullable  *BoolAttribute `apidoc:"nullable,attr,usage-param-nullable,omitempty"`   // 是否可以为 null，仅对 JSON 有效
+		ReadOnly  *BoolAttribute `apidoc:"readonly,attr,usage-param-readonly,omitempty"`   // 仅出现在返回中
+		WriteOnly *BoolAttribute `apidoc:"writeonly,attr,usage-param-writeonly,omitempty"` // 仅出现在请求中
+
+		Composite
+
+		// 对数值的约束，仅作用于 number 类型
+		Min          *NumberAttribute `apidoc:"min,attr,usage-param-min,omitempty"`
+		Max          *NumberAttribute `apidoc:"max,attr,usage-param-max,omitempty"`
+		ExclusiveMin *BoolAttribute   `apidoc:"exclusive-min,attr,usage-param-exclusive-min,omitempty"` // 是否不包含 min 本身
+		ExclusiveMax *BoolAttribute   `apidoc:"exclusive-max,attr,usage-param-exclusive-max,omitempty"` // 是否不包含 max 本身
+
+		// 对字符串的约束，仅作用于 string 类型，长度以字符为单位。
+		MinLength *NumberAttribute `apidoc:"min-length,attr,usage-param-min-length,omitempty"`
+		MaxLength *NumberAttribute `apidoc:"max-length,attr,usage-param-max-length,omitempty"`
+		Pattern   *Attribute       `apidoc:"pattern,attr,usage-param-pattern,omitempty"` // 正则表达式
+		Faker     *Attribute       `apidoc:"faker,attr,usage-param-faker,omitempty"`     // 生成模拟数据时采用的 faker
+
+		// 对数组的约束，仅在 array 为 true 时有效
+		MinItems    *NumberAttribute `apidoc:"min-items,attr,usage-param-min-items,omitempty"`
+		MaxItems    *NumberAttribute `apidoc:"max-items,attr,usage-param-max-items,omitempty"`
+		UniqueItems *BoolAttribute   `apidoc:"unique-items,attr,usage-param-unique-items,omitempty"`
+
+		// 对文件的约束，仅作用于 file 类型，大小以字节为单位。
+		MinSize *NumberAttribute `apidoc:"min-size,attr,usage-param-min-size,omitempty"`
+		MaxSize *NumberAttribute `apidoc:"max-size,attr,usage-param-max-size,omitempty"`
+		Accept  *Attribute       `apidoc:"accept,attr,usage-param-accept,omitempty"` // 允许的 mimetype，多个值以逗号分隔，可以使用 image/* 的形式。
+
 		// 数组参数是否展开
 		//
 		// 数组可以有以下两种展示方式：
@@ -137,6 +174,8 @@ type (
 		// 1 为默认方式，ArrayStyle 为 true，则展示为第二种方式
 		// 该参数目前仅在查询参数中启作用
 		ArrayStyle *BoolAttribute `apidoc:"array-style,attr,usage-param-array-style,omitempty"`
+
+		pattern *regexp.Regexp // 由 Pattern 编译而来
 	}
 
 	// Path 路径信息
@@ -159,6 +198,7 @@ type (
 		Name *Attribute `apidoc:"name,attr,usage-request-name,omitempty"`
 
 		Type        *TypeAttribute    `apidoc:"type,attr,usage-request-type,omitempty"`
+		Ref         *RefAttribute     `apidoc:"ref,attr,usage-request-ref,omitempty"`
 		Deprecated  *VersionAttribute `apidoc:"deprecated,attr,usage-request-deprecated,omitempty"`
 		Enums       []*Enum           `apidoc:"enum,elem,usage-request-enums,omitempty"`
 		Array       *BoolAttribute    `apidoc:"array,attr,usage-request-array,omitempty"`
@@ -168,7 +208,10 @@ type (
 		Mimetype    *Attribute        `apidoc:"mimetype,attr,usage-request-mimetype,omitempty"`
 		Examples    []*Example        `apidoc:"example,elem,usage-request-examples,omitempty"`
 		Headers     []*Param          `apidoc:"header,elem,usage-request-headers,omitempty"` // 当前独有的报头，公用的可以放在 API 中
+		Cookies     []*Param          `apidoc:"cookie,elem,usage-request-cookies,omitempty"` // 当前独有的 cookie，公用的可以放在 API 中
 		Description *Richtext         `apidoc:"description,elem,usage-request-description,omitempty"`
+
+		Composite
 	}
 
 	// Richtext 富文本内容
@@ -206,6 +249,98 @@ type (
 		references []*Reference
 	}
 
+	// TypeDef 可复用的类型定义
+	//
+	// 可以通过 Param 和 Request 的 ref 属性引用。
+	TypeDef struct {
+		xmlenc.BaseTag
+		RootName struct{} `apidoc:"typedef,meta,usage-typedef"`
+
+		Name        *Attribute        `apidoc:"name,attr,usage-typedef-name"` // 类型名称，需要唯一
+		Type        *TypeAttribute    `apidoc:"type,attr,usage-typedef-type"`
+		Deprecated  *VersionAttribute `apidoc:"deprecated,attr,usage-typedef-deprecated,omitempty"`
+		Summary     *Attribute        `apidoc:"summary,attr,usage-typedef-summary,omitempty"`
+		Items       []*Param          `apidoc:"param,elem,usage-typedef-items,omitempty"`
+		Enums       []*Enum           `apidoc:"enum,elem,usage-typedef-enums,omitempty"`
+		Description *Richtext         `apidoc:"description,elem,usage-typedef-description,omitempty"`
+
+		Composite
+
+		references []*Reference
+	}
+
+	// Composite 由多个变体组成的复合类型
+	//
+	// 仅作用于 object 类型，此时对象的子元素表示所有变体共有的内容。
+	Composite struct {
+		Compose       *Attribute `apidoc:"compose,attr,usage-composite-compose,omitempty"`             // 变体的组合方式，默认为 one-of
+		Discriminator *Attribute `apidoc:"discriminator,attr,usage-composite-discriminator,omitempty"` // 用于区分变体的子元素名称
+		Variants      []*Variant `apidoc:"variant,elem,usage-composite-variants,omitempty"`
+	}
+
+	// Variant 复合类型中的变体
+	Variant struct {
+		xmlenc.BaseTag
+		RootName struct{} `apidoc:"variant,meta,usage-variant"`
+
+		Value       *Attribute    `apidoc:"value,attr,usage-variant-value,omitempty"` // 与 discriminator 对应的值
+		Ref         *RefAttribute `apidoc:"ref,attr,usage-variant-ref,omitempty"`
+		Summary     *Attribute    `apidoc:"summary,attr,usage-variant-summary,omitempty"`
+		Items       []*Param      `apidoc:"param,elem,usage-variant-items,omitempty"`
+		Description *Richtext     `apidoc:"description,elem,usage-variant-description,omitempty"`
+	}
+
+	// Security 安全验证方案
+	Security struct {
+		xmlenc.BaseTag
+		RootName struct{} `apidoc:"security,meta,usage-security"`
+
+		Name             *Attribute   `apidoc:"name,attr,usage-security-name"` // 唯一 ID
+		Type             *Attribute   `apidoc:"type,attr,usage-security-type"`
+		Summary          *Attribute   `apidoc:"summary,attr,usage-security-summary,omitempty"`
+		In               *Attribute   `apidoc:"in,attr,usage-security-in,omitempty"`   // 仅作用于 apikey
+		Key              *Attribute   `apidoc:"key,attr,usage-security-key,omitempty"` // 仅作用于 apikey
+		Scheme           *Attribute   `apidoc:"scheme,attr,usage-security-scheme,omitempty"`
+		BearerFormat     *Attribute   `apidoc:"bearer-format,attr,usage-security-bearer-format,omitempty"`
+		OpenIDConnectURL *Attribute   `apidoc:"openid-connect-url,attr,usage-security-openid-connect-url,omitempty"`
+		Flows            []*OAuthFlow `apidoc:"flow,elem,usage-security-flows,omitempty"` // 仅作用于 oauth2
+		Description      *Richtext    `apidoc:"description,elem,usage-security-description,omitempty"`
+
+		references []*Reference
+	}
+
+	// OAuthFlow OAuth2 的授权方式
+	OAuthFlow struct {
+		xmlenc.BaseTag
+		RootName struct{} `apidoc:"flow,meta,usage-flow"`
+
+		Type             *Attribute `apidoc:"type,attr,usage-flow-type"`
+		AuthorizationURL *Attribute `apidoc:"authorization-url,attr,usage-flow-authorization-url,omitempty"`
+		TokenURL         *Attribute `apidoc:"token-url,attr,usage-flow-token-url,omitempty"`
+		RefreshURL       *Attribute `apidoc:"refresh-url,attr,usage-flow-refresh-url,omitempty"`
+		Scopes           []*Scope   `apidoc:"scope,elem,usage-flow-scopes,omitempty"`
+	}
+
+	// Scope OAuth2 的授权范围
+	Scope struct {
+		xmlenc.BaseTag
+		RootName struct{} `apidoc:"scope,meta,usage-scope"`
+
+		Name    *Attribute `apidoc:"name,attr,usage-scope-name"`
+		Summary *Attribute `apidoc:"summary,attr,usage-scope-summary,omitempty"`
+	}
+
+	// SecurityValue api.security 的类型
+	SecurityValue struct {
+		xmlenc.BaseTag
+		RootName struct{} `apidoc:"security,meta,usage-security-value"`
+
+		Name   *Attribute `apidoc:"name,attr,usage-security-value-name"`
+		Scopes []*Element `apidoc:"scope,elem,usage-security-value-scopes,omitempty"`
+
+		definition *Definition
+	}
+
 	// XML 仅作用于 XML 的几个属性
 	XML struct {
 		XMLAttr     *BoolAttribute `apidoc:"xml-attr,attr,usage-xml-attr,omitempty"`        // 作为父元素的 XML 属性存在
@@ -300,6 +435,11 @@ func (s *ServerValue) Definition() *Definition {
 	return s.definition
 }
 
+// Definition Definitioner.Definition
+func (s *SecurityValue) Definition() *Definition {
+	return s.definition
+}
+
 // EncodeXML Encoder.EncodeXML
 func (cdata *CData) EncodeXML() (string, error) {
 	return cdata.Value.Value, nil
@@ -317,6 +457,33 @@ func (v *ExampleValue) EncodeXML() (string, error) {
 	return trimLeftSpace(v.Value.Value), nil
 }
 
+// 解析引用时由 typedef 填充的字段
+var refFields = map[string]bool{
+	"type":          true,
+	"param":         true,
+	"enum":          true,
+	"compose":       true,
+	"discriminator": true,
+	"variant":       true,
+}
+
+// OmitXML xmlenc.Omitter.OmitXML
+//
+// 引用了 typedef 的参数，编码时不输出由 typedef 填充的内容，保证输出的内容可以被再次解析。
+func (p *Param) OmitXML(name string) bool {
+	return p.Ref != nil && refFields[name]
+}
+
+// OmitXML xmlenc.Omitter.OmitXML
+func (r *Request) OmitXML(name string) bool {
+	return r.Ref != nil && refFields[name]
+}
+
+// OmitXML xmlenc.Omitter.OmitXML
+func (v *Variant) OmitXML(name string) bool {
+	return v.Ref != nil && name == "param"
+}
+
 // Param 转换成 Param 对象
 //
 // Request 可以说是 Param 的超级，两者在大部分情况下能用。
@@ -329,6 +496,7 @@ func (r *Request) Param() *Param {
 		XML:         r.XML,
 		Name:        r.Name,
 		Type:        r.Type,
+		Ref:         r.Ref,
 		Deprecated:  r.Deprecated,
 		Optional:    &BoolAttribute{Value: Bool{Value: true}},
 		Array:       r.Array,
@@ -336,7 +504,64 @@ func (r *Request) Param() *Param {
 		Summary:     r.Summary,
 		Enums:       r.Enums,
 		Description: r.Description,
+		Composite:   r.Composite,
+	}
+}
+
+// Param 转换成 Param 对象
+func (t *TypeDef) Param() *Param {
+	if t == nil {
+		return nil
+	}
+
+	return &Param{
+		Name:        t.Name,
+		Type:        t.Type,
+		Deprecated:  t.Deprecated,
+		Items:       t.Items,
+		Summary:     t.Summary,
+		Enums:       t.Enums,
+		Description: t.Description,
+		Composite:   t.Composite,
+	}
+}
+
+// Param 转换成 Param 对象
+//
+// 返回的对象仅包含变体自身的子元素，如果需要包含父元素中共有的子元素，
+// 应该使用 Param.Expand。
+func (v *Variant) Param() *Param {
+	if v == nil {
+		return nil
+	}
+
+	return &Param{
+		Type:        &TypeAttribute{Value: xmlenc.String{Value: TypeObject}},
+		Ref:         v.Ref,
+		Items:       v.Items,
+		Summary:     v.Summary,
+		Description: v.Description,
+	}
+}
+
+// TypeDef 获取指定名称的类型定义
+func (doc *APIDoc) TypeDef(name string) *TypeDef {
+	for _, t := range doc.TypeDefs {
+		if t.Name.V() == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// Security 获取指定名称的安全验证方案
+func (doc *APIDoc) Security(name string) *Security {
+	for _, s := range doc.Securities {
+		if s.Name.V() == name {
+			return s
+		}
 	}
+	return nil
 }
 
 // XMLNamespace 获取指定前缀名称的命名空间
@@ -354,6 +579,16 @@ func (tag *Tag) References() []*Reference {
 	return tag.references
 }
 
+// References impl Referencer
+func (s *Security) References() []*Reference {
+	return s.references
+}
+
+// References impl Referencer
+func (t *TypeDef) References() []*Reference {
+	return t.references
+}
+
 // References impl Referencer
 func (srv *Server) References() []*Reference {
 	return srv.references
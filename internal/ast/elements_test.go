@@ -17,6 +17,13 @@ import (
 var (
 	_ Referencer = &Tag{}
 	_ Referencer = &Server{}
+	_ Referencer = &TypeDef{}
+	_ Referencer = &Security{}
+
+	_ Definitioner = &TagValue{}
+	_ Definitioner = &ServerValue{}
+	_ Definitioner = &RefAttribute{}
+	_ Definitioner = &SecurityValue{}
 
 	_ core.Searcher = &APIDoc{}
 )
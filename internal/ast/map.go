@@ -0,0 +1,31 @@
+// SPDX-License-Identifier: MIT
+
+package ast
+
+import "github.com/caixw/apidoc/v7/internal/xmlenc"
+
+// MapValue 返回描述字典中值的参数
+//
+// 对于 map 类型，值为由子元素描述的对象；
+// 对于 map.xx 类型，值为 xx 类型。
+// 返回的参数与 p 同名，但不包含数组、枚举等与值无关的属性。
+// 如果 p 不是字典类型，则返回 nil。
+func (p *Param) MapValue() *Param {
+	primitive, sub := ParseType(p.Type.V())
+	if primitive != TypeMap {
+		return nil
+	}
+
+	if sub == "" {
+		return &Param{
+			Name:  p.Name,
+			Type:  &TypeAttribute{Value: xmlenc.String{Value: TypeObject}},
+			Items: p.Items,
+		}
+	}
+
+	return &Param{
+		Name: p.Name,
+		Type: &TypeAttribute{Value: xmlenc.String{Value: sub}},
+	}
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: MIT
+
+package ast
+
+import (
+	"testing"
+
+	"github.com/issue9/assert/v3"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/core/messagetest"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+func TestParam_MapValue(t *testing.T) {
+	a := assert.New(t, false)
+
+	p := &Param{Type: &TypeAttribute{Value: xmlenc.String{Value: TypeObject}}}
+	a.Nil(p.MapValue())
+
+	items := []*Param{{Name: &Attribute{Value: xmlenc.String{Value: "id"}}}}
+	p = &Param{
+		Name:  &Attribute{Value: xmlenc.String{Value: "users"}},
+		Type:  &TypeAttribute{Value: xmlenc.String{Value: TypeMap}},
+		Array: &BoolAttribute{Value: Bool{Value: true}},
+		Items: items,
+	}
+	v := p.MapValue()
+	a.NotNil(v).
+		Equal(v.Name.V(), "users").
+		Equal(v.Type.V(), TypeObject).
+		Equal(v.Items, items).
+		False(v.Array.V())
+
+	p = &Param{Type: &TypeAttribute{Value: xmlenc.String{Value: "map.number.int"}}}
+	v = p.MapValue()
+	a.NotNil(v).
+		Equal(v.Type.V(), TypeInt).
+		Empty(v.Items)
+}
+
+func TestParam_Sanitize_map(t *testing.T) {
+	a := assert.New(t, false)
+
+	data := []*struct {
+		param string
+		errs  int
+	}{
+		{
+			param: `<param name="p" type="map" summary="s">
+				<param name="id" type="number" summary="id" />
+			</param>`,
+		},
+		{
+			param: `<param name="p" type="map.string" summary="s" />`,
+		},
+		{
+			param: `<param name="p" type="map" summary="s" />`,
+			errs:  1, // 缺少子元素
+		},
+		{
+			param: `<param name="p" type="map.string" summary="s">
+				<param name="id" type="number" summary="id" />
+			</param>`,
+			errs: 1, // map.xx 不能有子元素
+		},
+		{
+			param: `<param name="p" type="map.string" summary="s">
+				<enum value="1" summary="1" />
+			</param>`,
+			errs: 1, // 不能有枚举值
+		},
+		{
+			param: `<param name="p" type="map.object" summary="s" />`,
+			errs:  1,
+		},
+		{
+			param: `<param name="p" type="map.map.string" summary="s" />`,
+			errs:  1,
+		},
+	}
+
+	for i, item := range data {
+		rslt := messagetest.NewMessageHandler()
+		p, err := xmlenc.NewParser(rslt.Handler, core.Block{Data: []byte(item.param)})
+		a.NotError(err).NotNil(p)
+		param := &Param{}
+		xmlenc.Decode(p, param, "")
+		rslt.Handler.Stop()
+		a.Equal(len(rslt.Errors), item.errs, "%d 的错误数量不正确，%v", i, rslt.Errors)
+	}
+}
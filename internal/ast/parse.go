@@ -56,6 +56,7 @@ func (doc *APIDoc) Parse(h *core.MessageHandler, b core.Block) {
 
 		if doc.Title.V() != "" { // apidoc 已经初始化，检测依赖于 apidoc 的字段
 			api.sanitizeTags(p)
+			api.sanitizeRefs(p)
 		}
 	case "apidoc":
 		if doc.Title != nil { // 多个 apidoc 标签
@@ -3,7 +3,10 @@
 package ast
 
 import (
+	"mime"
+	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/issue9/sliceutil"
 	"github.com/issue9/validation/is"
@@ -15,11 +18,16 @@ import (
 
 // Sanitize token.Sanitizer
 func (api *API) Sanitize(p *xmlenc.Parser) {
-	for _, header := range api.Headers { // 报头不能为 object
-		if header.Type.V() == TypeObject {
+	for _, header := range api.Headers { // 报头不能为 object、map 或 file
+		if isComplexType(header.Type.V()) {
 			p.Error(header.Type.Location.NewError(locale.ErrInvalidValue).WithField("header"))
 		}
 	}
+	for _, cookie := range api.Cookies { // cookie 与报头相同，只能是简单类型
+		if isComplexType(cookie.Type.V()) {
+			p.Error(cookie.Type.Location.NewError(locale.ErrInvalidValue).WithField("cookie"))
+		}
+	}
 
 	// 对 Servers 和 Tags 查重
 	indexes := sliceutil.Dup(api.Servers, func(i, j *ServerValue) bool { return i.V() == j.V() })
@@ -30,6 +38,14 @@ func (api *API) Sanitize(p *xmlenc.Parser) {
 		}
 		p.Error(err)
 	}
+	indexes = sliceutil.Dup(api.Securities, func(i, j *SecurityValue) bool { return i.Name.V() == j.Name.V() })
+	if len(indexes) > 0 {
+		err := api.Securities[indexes[0]].Location.NewError(locale.ErrDuplicateValue).WithField("security")
+		for _, sec := range indexes[1:] {
+			err.Relate(api.Securities[sec].Location, locale.Sprintf(locale.ErrDuplicateValue))
+		}
+		p.Error(err)
+	}
 	indexes = sliceutil.Dup(api.Tags, func(i, j *TagValue) bool { return i.V() == j.V() })
 	if len(indexes) > 0 {
 		err := api.Tags[indexes[0]].Location.NewError(locale.ErrDuplicateValue).WithField("server")
@@ -66,14 +82,14 @@ func (p *Path) Sanitize(pp *xmlenc.Parser) {
 		}
 	}
 
-	// 路径参数和查询参数不能为 object
+	// 路径参数和查询参数不能为 object、map 或 file
 	for _, item := range p.Params {
-		if item.Type.V() == TypeObject {
+		if isComplexType(item.Type.V()) {
 			pp.Error(item.Location.NewError(locale.ErrInvalidValue).WithField("type"))
 		}
 	}
 	for _, q := range p.Queries {
-		if q.Type.V() == TypeObject {
+		if isComplexType(q.Type.V()) {
 			pp.Error(q.Location.NewError(locale.ErrInvalidValue).WithField("type"))
 		}
 	}
@@ -112,10 +128,10 @@ func parsePath(path string) (params map[string]struct{}, err error) {
 
 // Sanitize token.Sanitizer
 func (r *Request) Sanitize(p *xmlenc.Parser) {
-	if r.Type.V() == TypeObject && len(r.Items) == 0 {
+	if hasItems(r.Type.V()) && len(r.Items) == 0 && len(r.Variants) == 0 {
 		p.Error(r.Location.NewError(locale.ErrIsEmpty, "param").WithField("param"))
 	}
-	if r.Type.V() == TypeNone && len(r.Items) > 0 {
+	if r.Ref == nil && r.Type.V() == TypeNone && len(r.Items) > 0 { // 引用时由 checkRef 检测
 		p.Error(r.Location.NewError(locale.ErrInvalidValue).WithField("type"))
 	}
 
@@ -137,26 +153,37 @@ func (r *Request) Sanitize(p *xmlenc.Parser) {
 		}
 	}
 
-	// 报头不能为 object
+	// 报头不能为 object、map 或 file
 	for _, header := range r.Headers {
-		if header.Type.V() == TypeObject {
+		if isComplexType(header.Type.V()) {
 			p.Error(header.Type.Location.NewError(locale.ErrInvalidValue).WithField("type"))
 		}
 	}
+	for _, cookie := range r.Cookies {
+		if isComplexType(cookie.Type.V()) {
+			p.Error(cookie.Type.Location.NewError(locale.ErrInvalidValue).WithField("type"))
+		}
+	}
 
 	checkDuplicateItems(r.Items, p)
+
+	checkComposite(&r.Composite, r.Type, r.Ref != nil, r.Items, p)
+
+	checkRef(r.Ref, r.Type, r.Items, p)
 }
 
 // Sanitize token.Sanitizer
 func (p *Param) Sanitize(pp *xmlenc.Parser) {
-	if p.Type.V() == TypeNone {
+	// 引用了 typedef 的参数，其类型在解析引用时才确定。
+	ref := p.Ref != nil
+	if p.Type.V() == TypeNone && !ref {
 		pp.Error(p.Location.NewError(locale.ErrIsEmpty, "type").WithField("type"))
 	}
-	if p.Type.V() == TypeObject && len(p.Items) == 0 {
+	if hasItems(p.Type.V()) && len(p.Items) == 0 && len(p.Variants) == 0 {
 		pp.Error(p.Location.NewError(locale.ErrIsEmpty, "param").WithField("param"))
 	}
 
-	if p.Type.V() != TypeObject && len(p.Items) > 0 {
+	if !ref && !hasItems(p.Type.V()) && len(p.Items) > 0 { // 引用时由 checkRef 检测
 		pp.Error(p.Type.Value.Location.NewError(locale.ErrInvalidValue).WithField("type"))
 	}
 
@@ -172,9 +199,333 @@ func (p *Param) Sanitize(pp *xmlenc.Parser) {
 		pp.Error(err)
 	}
 
-	if p.Summary.V() == "" && p.Description.V() == "" {
+	if p.Summary.V() == "" && p.Description.V() == "" && !ref {
 		pp.Error(p.Location.NewError(locale.ErrIsEmpty, "summary").WithField("summary"))
 	}
+
+	if p.ReadOnly.V() && p.WriteOnly.V() {
+		pp.Error(p.WriteOnly.Location.NewError(locale.ErrInvalidValue).WithField("writeonly"))
+	}
+
+	checkComposite(&p.Composite, p.Type, ref, p.Items, pp)
+
+	checkRef(p.Ref, p.Type, p.Items, pp)
+
+	p.sanitizeConstraints(pp)
+}
+
+// 引用了 typedef 的对象，其类型和子元素都由 typedef 决定，不能再另行指定。
+func checkRef(ref *RefAttribute, t *TypeAttribute, items []*Param, p *xmlenc.Parser) {
+	if ref == nil {
+		return
+	}
+
+	if t != nil {
+		p.Error(t.Location.NewError(locale.ErrInvalidValue).WithField("type"))
+	}
+	if len(items) > 0 {
+		p.Error(items[0].Location.NewError(locale.ErrInvalidValue).WithField("param"))
+	}
+}
+
+// 检测约束条件是否与类型相符，以及 default 和 enum 是否满足这些约束。
+//
+// 引用了 typedef 的参数，其类型在解析引用时才确定，所以不检测与类型相关的内容。
+func (p *Param) sanitizeConstraints(pp *xmlenc.Parser) {
+	ref := p.Ref != nil
+	primitive, _ := ParseType(p.Type.V())
+
+	if !ref && primitive != TypeNumber {
+		for _, num := range []*NumberAttribute{p.Min, p.Max} {
+			if num != nil {
+				pp.Error(num.Location.NewError(locale.ErrInvalidValue).WithField(num.AttributeName.String()))
+			}
+		}
+		for _, b := range []*BoolAttribute{p.ExclusiveMin, p.ExclusiveMax} {
+			if b != nil {
+				pp.Error(b.Location.NewError(locale.ErrInvalidValue).WithField(b.AttributeName.String()))
+			}
+		}
+	}
+
+	if !ref && primitive != TypeString {
+		for _, num := range []*NumberAttribute{p.MinLength, p.MaxLength} {
+			if num != nil {
+				pp.Error(num.Location.NewError(locale.ErrInvalidValue).WithField(num.AttributeName.String()))
+			}
+		}
+		if p.Pattern != nil {
+			pp.Error(p.Pattern.Location.NewError(locale.ErrInvalidValue).WithField("pattern"))
+		}
+		if p.Faker != nil {
+			pp.Error(p.Faker.Location.NewError(locale.ErrInvalidValue).WithField("faker"))
+		}
+	}
+
+	if p.Faker != nil && !isValidFaker(p.Faker.V()) {
+		pp.Error(p.Faker.Location.NewError(locale.ErrInvalidValue).WithField("faker"))
+	}
+
+	if !ref && primitive != TypeFile {
+		for _, num := range []*NumberAttribute{p.MinSize, p.MaxSize} {
+			if num != nil {
+				pp.Error(num.Location.NewError(locale.ErrInvalidValue).WithField(num.AttributeName.String()))
+			}
+		}
+		if p.Accept != nil {
+			pp.Error(p.Accept.Location.NewError(locale.ErrInvalidValue).WithField("accept"))
+		}
+	}
+
+	if !p.Array.V() {
+		for _, num := range []*NumberAttribute{p.MinItems, p.MaxItems} {
+			if num != nil {
+				pp.Error(num.Location.NewError(locale.ErrInvalidValue).WithField(num.AttributeName.String()))
+			}
+		}
+		if p.UniqueItems != nil {
+			pp.Error(p.UniqueItems.Location.NewError(locale.ErrInvalidValue).WithField("unique-items"))
+		}
+	}
+
+	if p.ExclusiveMin != nil && p.Min == nil {
+		pp.Error(p.ExclusiveMin.Location.NewError(locale.ErrIsEmpty, "min").WithField("min"))
+	}
+	if p.ExclusiveMax != nil && p.Max == nil {
+		pp.Error(p.ExclusiveMax.Location.NewError(locale.ErrIsEmpty, "max").WithField("max"))
+	}
+	if p.emptyRange() {
+		pp.Error(p.Min.Location.NewError(locale.ErrInvalidValue).WithField("min"))
+	}
+
+	checkSizeRange(p.MinLength, p.MaxLength, pp)
+	checkSizeRange(p.MinItems, p.MaxItems, pp)
+	checkSizeRange(p.MinSize, p.MaxSize, pp)
+
+	if p.Accept != nil {
+		for _, mt := range strings.Split(p.Accept.V(), ",") {
+			if !isValidMimetype(strings.TrimSpace(mt)) {
+				pp.Error(p.Accept.Location.NewError(locale.ErrInvalidFormat).WithField("accept"))
+				break
+			}
+		}
+	}
+
+	if p.Pattern != nil {
+		re, err := regexp.Compile(p.Pattern.V())
+		if err != nil {
+			pp.Error(p.Pattern.Location.NewError(locale.ErrInvalidFormat).WithField("pattern"))
+			return
+		}
+		p.pattern = re
+	}
+
+	if ref {
+		return
+	}
+
+	if p.Default != nil && !p.Array.V() {
+		if err := p.CheckValue(p.Default.V()); err != nil {
+			pp.Error(err.WithLocation(p.Default.Location).WithField("default"))
+		}
+	}
+	for _, enum := range p.Enums {
+		if err := p.CheckValue(enum.Value.V()); err != nil {
+			pp.Error(err.WithLocation(enum.Location).WithField(enum.StartTag.String()))
+		}
+	}
+}
+
+// 检测表示长度或数量的 min 和 max 是否为合法的值
+func checkSizeRange(min, max *NumberAttribute, p *xmlenc.Parser) {
+	for _, num := range []*NumberAttribute{min, max} {
+		if num != nil && (num.IsFloat() || num.IntValue() < 0) {
+			p.Error(num.Location.NewError(locale.ErrInvalidValue).WithField(num.AttributeName.String()))
+		}
+	}
+
+	if min != nil && max != nil && min.IntValue() > max.IntValue() {
+		p.Error(min.Location.NewError(locale.ErrInvalidValue).WithField(min.AttributeName.String()))
+	}
+}
+
+// Sanitize token.Sanitizer
+func (t *TypeDef) Sanitize(p *xmlenc.Parser) {
+	if t.Type.V() == TypeNone {
+		p.Error(t.Location.NewError(locale.ErrIsEmpty, "type").WithField("type"))
+	}
+	if hasItems(t.Type.V()) && len(t.Items) == 0 && len(t.Variants) == 0 {
+		p.Error(t.Location.NewError(locale.ErrIsEmpty, "param").WithField("param"))
+	}
+	if !hasItems(t.Type.V()) && len(t.Items) > 0 {
+		p.Error(t.Type.Value.Location.NewError(locale.ErrInvalidValue).WithField("type"))
+	}
+
+	checkDuplicateEnum(t.Enums, p)
+
+	if err := chkEnumsType(t.Type, t.Enums, p); err != nil {
+		p.Error(err)
+	}
+
+	checkDuplicateItems(t.Items, p)
+
+	if t.Summary.V() == "" && t.Description.V() == "" {
+		p.Error(t.Location.NewError(locale.ErrIsEmpty, "summary").WithField("summary"))
+	}
+
+	checkComposite(&t.Composite, t.Type, false, t.Items, p)
+}
+
+// Sanitize token.Sanitizer
+func (v *Variant) Sanitize(p *xmlenc.Parser) {
+	if v.Ref != nil && len(v.Items) > 0 {
+		p.Error(v.Items[0].Location.NewError(locale.ErrInvalidValue).WithField("param"))
+	}
+	if v.Ref == nil && len(v.Items) == 0 {
+		p.Error(v.Location.NewError(locale.ErrIsEmpty, "param").WithField("param"))
+	}
+
+	checkDuplicateItems(v.Items, p)
+}
+
+// 检测复合类型的相关属性
+//
+// t 和 items 为复合类型所在对象的类型和子元素；
+// ref 表示该对象是否引用了 typedef，此时的类型在解析引用时才确定。
+func checkComposite(c *Composite, t *TypeAttribute, ref bool, items []*Param, p *xmlenc.Parser) {
+	if len(c.Variants) == 0 {
+		if c.Compose != nil {
+			p.Error(c.Compose.Location.NewError(locale.ErrIsEmpty, "variant").WithField("compose"))
+		}
+		if c.Discriminator != nil {
+			p.Error(c.Discriminator.Location.NewError(locale.ErrIsEmpty, "variant").WithField("discriminator"))
+		}
+		return
+	}
+
+	if !ref && t.V() != TypeObject {
+		p.Error(c.Variants[0].Location.NewError(locale.ErrInvalidValue).WithField("variant"))
+	}
+
+	switch c.Compose.V() {
+	case "", ComposeOneOf, ComposeAnyOf, ComposeAllOf:
+	default:
+		p.Error(c.Compose.Location.NewError(locale.ErrInvalidValue).WithField("compose"))
+	}
+
+	if c.Discriminator == nil {
+		return
+	}
+
+	if c.ComposeType() == ComposeAllOf {
+		p.Error(c.Discriminator.Location.NewError(locale.ErrInvalidValue).WithField("discriminator"))
+		return
+	}
+
+	// discriminator 指向的子元素必须是所有变体共有的字符串
+	var found bool
+	for _, item := range items {
+		if item.Name.V() != c.Discriminator.V() {
+			continue
+		}
+		found = true
+		if primitive, _ := ParseType(item.Type.V()); item.Ref == nil && (primitive != TypeString || item.Array.V()) {
+			p.Error(c.Discriminator.Location.NewError(locale.ErrInvalidValue).WithField("discriminator"))
+		}
+		break
+	}
+	if !found {
+		p.Error(c.Discriminator.Location.NewError(locale.ErrNotFound).WithField("discriminator"))
+	}
+
+	for _, v := range c.Variants {
+		if v.Value.V() == "" {
+			p.Error(v.Location.NewError(locale.ErrIsEmpty, "@value").WithField("@value"))
+		}
+	}
+
+	indexes := sliceutil.Dup(c.Variants, func(i, j *Variant) bool { return i.Value.V() == j.Value.V() })
+	if len(indexes) > 0 {
+		err := c.Variants[indexes[0]].Location.NewError(locale.ErrDuplicateValue).WithField("variant")
+		for _, i := range indexes[1:] {
+			err.Relate(c.Variants[i].Location, locale.Sprintf(locale.ErrDuplicateValue))
+		}
+		p.Error(err)
+	}
+}
+
+// Sanitize token.Sanitizer
+func (s *Security) Sanitize(p *xmlenc.Parser) {
+	switch s.Type.V() {
+	case SecurityTypeAPIKey:
+		if s.Key.V() == "" {
+			p.Error(s.Location.NewError(locale.ErrIsEmpty, "@key").WithField("@key"))
+		}
+		switch s.In.V() {
+		case SecurityInHeader, SecurityInQuery, SecurityInCookie:
+		default:
+			p.Error(s.Location.NewError(locale.ErrInvalidValue).WithField("@in"))
+		}
+	case SecurityTypeHTTP:
+		if s.Scheme.V() == "" {
+			p.Error(s.Location.NewError(locale.ErrIsEmpty, "@scheme").WithField("@scheme"))
+		}
+	case SecurityTypeOAuth2:
+		if len(s.Flows) == 0 {
+			p.Error(s.Location.NewError(locale.ErrIsEmpty, "flow").WithField("flow"))
+		}
+
+		indexes := sliceutil.Dup(s.Flows, func(i, j *OAuthFlow) bool { return i.Type.V() == j.Type.V() })
+		if len(indexes) > 0 {
+			err := s.Flows[indexes[0]].Location.NewError(locale.ErrDuplicateValue).WithField("flow")
+			for _, i := range indexes[1:] {
+				err.Relate(s.Flows[i].Location, locale.Sprintf(locale.ErrDuplicateValue))
+			}
+			p.Error(err)
+		}
+	case SecurityTypeOpenIDConnect:
+		if s.OpenIDConnectURL.V() == "" {
+			p.Error(s.Location.NewError(locale.ErrIsEmpty, "@openid-connect-url").WithField("@openid-connect-url"))
+		}
+	default:
+		p.Error(s.Location.NewError(locale.ErrInvalidValue).WithField("@type"))
+	}
+
+	if s.Type.V() != SecurityTypeOAuth2 && len(s.Flows) > 0 {
+		p.Error(s.Flows[0].Location.NewError(locale.ErrInvalidValue).WithField("flow"))
+	}
+}
+
+// Sanitize token.Sanitizer
+func (f *OAuthFlow) Sanitize(p *xmlenc.Parser) {
+	var authURL, tokenURL bool
+	switch f.Type.V() {
+	case OAuthFlowImplicit:
+		authURL = true
+	case OAuthFlowPassword, OAuthFlowClientCredentials:
+		tokenURL = true
+	case OAuthFlowAuthorizationCode:
+		authURL = true
+		tokenURL = true
+	default:
+		p.Error(f.Location.NewError(locale.ErrInvalidValue).WithField("@type"))
+	}
+
+	if authURL && f.AuthorizationURL.V() == "" {
+		p.Error(f.Location.NewError(locale.ErrIsEmpty, "@authorization-url").WithField("@authorization-url"))
+	}
+	if tokenURL && f.TokenURL.V() == "" {
+		p.Error(f.Location.NewError(locale.ErrIsEmpty, "@token-url").WithField("@token-url"))
+	}
+
+	indexes := sliceutil.Dup(f.Scopes, func(i, j *Scope) bool { return i.Name.V() == j.Name.V() })
+	if len(indexes) > 0 {
+		err := f.Scopes[indexes[0]].Location.NewError(locale.ErrDuplicateValue).WithField("scope")
+		for _, i := range indexes[1:] {
+			err.Relate(f.Scopes[i].Location, locale.Sprintf(locale.ErrDuplicateValue))
+		}
+		p.Error(err)
+	}
 }
 
 // 检测 enums 中的类型是否符合 t 的标准，比如 Number 要求枚举值也都是数值
@@ -196,13 +547,37 @@ func chkEnumsType(t *TypeAttribute, enums []*Enum, p *xmlenc.Parser) error {
 				return enum.Location.NewError(locale.ErrInvalidFormat).WithField(enum.StartTag.String())
 			}
 		}
-	case TypeObject, TypeNone:
+	case TypeNone:
+		return t.Location.NewError(locale.ErrInvalidValue).WithField(t.AttributeName.String())
+	}
+
+	if isComplexType(t.V()) {
 		return t.Location.NewError(locale.ErrInvalidValue).WithField(t.AttributeName.String())
 	}
 
 	return nil
 }
 
+// 是否为 type/subtype 格式的 mimetype，subtype 和 type 都可以为 *。
+func isValidMimetype(mt string) bool {
+	if strings.Count(mt, "/") != 1 {
+		return false
+	}
+	_, params, err := mime.ParseMediaType(mt)
+	return err == nil && len(params) == 0
+}
+
+// 类型 t 的值是否由子元素描述
+func hasItems(t string) bool {
+	return t == TypeObject || t == TypeMap
+}
+
+// 类型 t 是否为 object、map.xx 或 file 等无法用单个文本值表示的类型
+func isComplexType(t string) bool {
+	primitive, _ := ParseType(t)
+	return primitive == TypeObject || primitive == TypeMap || primitive == TypeFile
+}
+
 func checkDuplicateEnum(enums []*Enum, p *xmlenc.Parser) {
 	indexes := sliceutil.Dup(enums, func(i, j *Enum) bool { return i.Value.V() == j.Value.V() })
 	if len(indexes) > 0 {
@@ -264,12 +639,21 @@ func (doc *APIDoc) Sanitize(p *xmlenc.Parser) {
 	}
 	doc.URI = p.Location.URI
 
+	if err := doc.checkSecurities(); err != nil {
+		p.Error(err)
+	}
+
+	doc.sanitizeTypeDefs(p)
+	doc.resolveParams(p, doc.Headers)
+	doc.resolveRequests(p, doc.Responses)
+
 	for _, api := range doc.APIs {
 		if api.doc == nil {
 			api.doc = doc // 保证单文件的文档能正常解析
 			api.URI = doc.URI
 		}
 		api.sanitizeTags(p)
+		api.sanitizeRefs(p)
 	}
 }
 
@@ -312,6 +696,18 @@ func (doc *APIDoc) checkXMLNamespaces(p *xmlenc.Parser) error {
 	return nil
 }
 
+func (doc *APIDoc) checkSecurities() error {
+	indexes := sliceutil.Dup(doc.Securities, func(i, j *Security) bool { return i.Name.V() == j.Name.V() })
+	if len(indexes) > 0 {
+		err := doc.Securities[indexes[0]].Name.Location.NewError(locale.ErrDuplicateValue).WithField("@name")
+		for _, i := range indexes[1:] {
+			err.Relate(doc.Securities[i].Location, locale.Sprintf(locale.ErrDuplicateValue))
+		}
+		return err
+	}
+	return nil
+}
+
 func (doc *APIDoc) findTag(tag string) *Tag {
 	for _, t := range doc.Tags {
 		if t.Name.V() == tag {
@@ -330,6 +726,186 @@ func (doc *APIDoc) findServer(srv string) *Server {
 	return nil
 }
 
+// typedef 在解析引用时的状态
+const (
+	typeDefResolving = iota + 1
+	typeDefResolved
+)
+
+// 解析 typedef 之间的引用
+func (doc *APIDoc) sanitizeTypeDefs(p *xmlenc.Parser) {
+	indexes := sliceutil.Dup(doc.TypeDefs, func(i, j *TypeDef) bool { return i.Name.V() == j.Name.V() })
+	if len(indexes) > 0 {
+		err := doc.TypeDefs[indexes[0]].Name.Location.NewError(locale.ErrDuplicateValue).WithField("@name")
+		for _, i := range indexes[1:] {
+			err.Relate(doc.TypeDefs[i].Location, locale.Sprintf(locale.ErrDuplicateValue))
+		}
+		p.Error(err)
+	}
+
+	states := make(map[*TypeDef]int, len(doc.TypeDefs))
+	for _, t := range doc.TypeDefs {
+		doc.resolveTypeDef(p, t, states)
+	}
+}
+
+// 解析 t 中的引用，如果 t 正处于解析过程中，说明存在循环引用，返回 false。
+func (doc *APIDoc) resolveTypeDef(p *xmlenc.Parser, t *TypeDef, states map[*TypeDef]int) bool {
+	switch states[t] {
+	case typeDefResolving:
+		return false
+	case typeDefResolved:
+		return true
+	}
+
+	states[t] = typeDefResolving
+	for _, item := range t.Items {
+		doc.resolveParam(p, item, states)
+	}
+	doc.resolveVariants(p, t.Variants, states)
+	states[t] = typeDefResolved
+
+	return true
+}
+
+// 查找 ref 引用的 typedef，并建立两者之间的关联。
+//
+// states 不为空时，会同时解析 typedef 中的引用。
+// typedef 不存在或是存在循环引用时返回 nil。
+func (doc *APIDoc) resolveRef(p *xmlenc.Parser, ref *RefAttribute, states map[*TypeDef]int) *TypeDef {
+	t := doc.TypeDef(ref.V())
+	if t == nil {
+		ref.definition = nil
+		p.Error(ref.Location.NewError(locale.ErrNotFound).WithField(ref.AttributeName.String()))
+		return nil
+	}
+
+	ref.definition = &Definition{
+		Location: t.Location,
+		Target:   t,
+	}
+	t.references = append(t.references, &Reference{
+		Location: ref.Location,
+		Target:   ref,
+	})
+
+	if states != nil && !doc.resolveTypeDef(p, t, states) {
+		err := ref.Location.NewError(locale.ErrCircularReference).WithField(ref.AttributeName.String())
+		err.Relate(t.Location, locale.Sprintf(locale.ErrCircularReference))
+		p.Error(err)
+		return nil
+	}
+
+	return t
+}
+
+// 将 param 及其子元素中引用的 typedef 内容填充到 param
+//
+// 类型、子元素和枚举以 typedef 中的定义为准，
+// 而 summary 和 description 仅在 param 未指定时才采用 typedef 的值。
+func (doc *APIDoc) resolveParam(p *xmlenc.Parser, param *Param, states map[*TypeDef]int) {
+	if param.Ref == nil {
+		for _, item := range param.Items {
+			doc.resolveParam(p, item, states)
+		}
+		doc.resolveVariants(p, param.Variants, states)
+		return
+	}
+
+	if t := doc.resolveRef(p, param.Ref, states); t != nil {
+		param.Type = t.Type
+		param.Items = t.Items
+		param.Enums = t.Enums
+		param.Composite = t.Composite
+		if param.Summary.V() == "" && param.Description.V() == "" {
+			param.Summary = t.Summary
+			param.Description = t.Description
+		}
+	}
+}
+
+// 将变体中引用的 typedef 内容填充到变体
+//
+// 被引用的 typedef 只能是不包含变体的 object 类型。
+func (doc *APIDoc) resolveVariants(p *xmlenc.Parser, variants []*Variant, states map[*TypeDef]int) {
+	for _, v := range variants {
+		if v.Ref == nil {
+			for _, item := range v.Items {
+				doc.resolveParam(p, item, states)
+			}
+			continue
+		}
+
+		t := doc.resolveRef(p, v.Ref, states)
+		if t == nil {
+			continue
+		}
+		if t.Type.V() != TypeObject || len(t.Variants) > 0 {
+			p.Error(v.Ref.Location.NewError(locale.ErrInvalidValue).WithField(v.Ref.AttributeName.String()))
+			continue
+		}
+
+		v.Items = t.Items
+		if v.Summary.V() == "" && v.Description.V() == "" {
+			v.Summary = t.Summary
+			v.Description = t.Description
+		}
+	}
+}
+
+func (doc *APIDoc) resolveParams(p *xmlenc.Parser, params []*Param) {
+	for _, param := range params {
+		doc.resolveParam(p, param, nil)
+	}
+}
+
+func (doc *APIDoc) resolveRequests(p *xmlenc.Parser, requests []*Request) {
+	for _, r := range requests {
+		doc.resolveParams(p, r.Headers)
+		doc.resolveParams(p, r.Cookies)
+
+		if r.Ref == nil {
+			doc.resolveParams(p, r.Items)
+			doc.resolveVariants(p, r.Variants, nil)
+			continue
+		}
+
+		if t := doc.resolveRef(p, r.Ref, nil); t != nil {
+			r.Type = t.Type
+			r.Items = t.Items
+			r.Enums = t.Enums
+			r.Composite = t.Composite
+			if r.Summary.V() == "" && r.Description.V() == "" {
+				r.Summary = t.Summary
+				r.Description = t.Description
+			}
+		}
+	}
+}
+
+// 解析 api 中所有对 typedef 的引用
+func (api *API) sanitizeRefs(p *xmlenc.Parser) {
+	if api.doc == nil {
+		panic("api.doc 未获取正确的值")
+	}
+	doc := api.doc
+
+	if api.Path != nil {
+		doc.resolveParams(p, api.Path.Params)
+		doc.resolveParams(p, api.Path.Queries)
+	}
+	doc.resolveParams(p, api.Headers)
+	doc.resolveParams(p, api.Cookies)
+	doc.resolveRequests(p, api.Requests)
+	doc.resolveRequests(p, api.Responses)
+
+	if api.Callback != nil {
+		doc.resolveParams(p, api.Callback.Headers)
+		doc.resolveRequests(p, api.Callback.Requests)
+		doc.resolveRequests(p, api.Callback.Responses)
+	}
+}
+
 func (api *API) sanitizeTags(p *xmlenc.Parser) {
 	if api.doc == nil {
 		panic("api.doc 未获取正确的值")
@@ -358,6 +934,25 @@ func (api *API) sanitizeTags(p *xmlenc.Parser) {
 		})
 	}
 
+	for _, sec := range api.Securities {
+		s := api.doc.Security(sec.Name.V())
+		if s == nil {
+			p.Error(sec.Name.Location.NewError(locale.ErrNotFound).WithField("@name"))
+			continue
+		}
+
+		sec.definition = &Definition{
+			Location: s.Location,
+			Target:   s,
+		}
+		s.references = append(s.references, &Reference{
+			Location: sec.Location,
+			Target:   sec,
+		})
+
+		sec.checkScopes(s, p)
+	}
+
 	for _, srv := range api.Servers {
 		s := api.doc.findServer(srv.Content.Value)
 		if s == nil {
@@ -421,3 +1016,34 @@ func (api *API) checkDup(p *xmlenc.Parser) {
 		p.Error(err)
 	}
 }
+
+// 检测 scope 是否都在 s 中有定义
+//
+// 仅 oauth2 和 openid-connect 允许指定 scope，
+// 其中 oauth2 的 scope 必须是在其 flow 中声明过的。
+func (sec *SecurityValue) checkScopes(s *Security, p *xmlenc.Parser) {
+	switch s.Type.V() {
+	case SecurityTypeOpenIDConnect:
+	case SecurityTypeOAuth2:
+		for _, scope := range sec.Scopes {
+			if !s.hasScope(scope.V()) {
+				p.Error(scope.Location.NewError(locale.ErrNotFound).WithField("scope"))
+			}
+		}
+	default:
+		if len(sec.Scopes) > 0 {
+			p.Error(sec.Scopes[0].Location.NewError(locale.ErrInvalidValue).WithField("scope"))
+		}
+	}
+}
+
+func (s *Security) hasScope(scope string) bool {
+	for _, f := range s.Flows {
+		for _, item := range f.Scopes {
+			if item.Name.V() == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
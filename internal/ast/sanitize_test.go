@@ -21,6 +21,9 @@ var (
 	_ xmlenc.Sanitizer = &Path{}
 	_ xmlenc.Sanitizer = &Enum{}
 	_ xmlenc.Sanitizer = &XMLNamespace{}
+	_ xmlenc.Sanitizer = &TypeDef{}
+	_ xmlenc.Sanitizer = &Security{}
+	_ xmlenc.Sanitizer = &OAuthFlow{}
 )
 
 func newEmptyParser(a *assert.Assertion) *xmlenc.Parser {
@@ -157,6 +160,26 @@ func TestAPI_Sanitize(t *testing.T) {
 	rslt.Handler.Stop()
 	a.NotEmpty(rslt.Errors)
 
+	// cookies
+
+	api = &API{
+		Cookies: []*Param{
+			{Type: &TypeAttribute{Value: xmlenc.String{Value: TypeNumber}}},
+		},
+	}
+	p, rslt = newParser(a, "", "")
+	api.Sanitize(p)
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors).Empty(rslt.Warns)
+
+	api.Cookies = append(api.Cookies, &Param{
+		Type: &TypeAttribute{Value: xmlenc.String{Value: TypeMap}},
+	})
+	p, rslt = newParser(a, "", "")
+	api.Sanitize(p)
+	rslt.Handler.Stop()
+	a.NotEmpty(rslt.Errors)
+
 	// servers
 
 	api = &API{
@@ -326,6 +349,13 @@ func TestChkEnumsType(t *testing.T) {
 			},
 			err: true,
 		},
+		{ // map 也是不允许的
+			t: &TypeAttribute{Value: xmlenc.String{Value: "map.string"}},
+			enums: []*Enum{
+				{Value: &Attribute{Value: xmlenc.String{Value: "string"}}},
+			},
+			err: true,
+		},
 	}
 
 	for i, item := range data {
@@ -388,6 +418,609 @@ func TestAPI_sanitizeTags(t *testing.T) {
 	a.Empty(rslt.Warns)
 }
 
+func TestAPIDoc_sanitizeTypeDefs(t *testing.T) {
+	a := assert.New(t, false)
+
+	const data = `<apidoc>
+	<title>title</title>
+	<mimetype>application/json</mimetype>
+	<typedef name="user" type="object" summary="user">
+		<param name="id" type="number" summary="id" />
+		<param name="group" ref="group" />
+	</typedef>
+	<typedef name="group" type="object" summary="group">
+		<param name="name" type="string" summary="name" />
+	</typedef>
+	<response status="500" ref="group" />
+	<api method="GET">
+		<path path="/users" />
+		<response status="200" ref="user" array="true" />
+	</api>
+	</apidoc>`
+
+	rslt := messagetest.NewMessageHandler()
+	doc := &APIDoc{}
+	doc.Parse(rslt.Handler, core.Block{Data: []byte(data)})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	user := doc.TypeDef("user")
+	group := doc.TypeDef("group")
+	a.NotNil(user).NotNil(group).Nil(doc.TypeDef("not-exists"))
+
+	g := user.Items[1]
+	a.Equal(g.Type.V(), TypeObject).
+		Equal(g.Items, group.Items).
+		Equal(g.Summary.V(), "group").
+		Equal(g.Ref.Definition().Target, group)
+
+	a.Equal(doc.Responses[0].Type.V(), TypeObject).
+		Equal(doc.Responses[0].Items, group.Items)
+
+	resp := doc.APIs[0].Responses[0]
+	a.Equal(resp.Type.V(), TypeObject).
+		True(resp.Array.V()).
+		Equal(resp.Items, user.Items).
+		Equal(resp.Ref.Definition().Target, user)
+
+	// group 被 user.group 和 apidoc.response 引用
+	a.Equal(2, len(group.References())).
+		Equal(1, len(user.References()))
+
+	// 先解析 api，再解析 apidoc
+	rslt = messagetest.NewMessageHandler()
+	doc = &APIDoc{}
+	doc.Parse(rslt.Handler, core.Block{Data: []byte(`<api method="GET">
+		<path path="/users" />
+		<response status="200" ref="user" />
+	</api>`)})
+	doc.Parse(rslt.Handler, core.Block{Data: []byte(`<apidoc>
+	<title>title</title>
+	<mimetype>application/json</mimetype>
+	<typedef name="user" type="string" summary="user" />
+	</apidoc>`)})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors).
+		Equal(doc.APIs[0].Responses[0].Type.V(), TypeString)
+
+	// 不存在的类型
+	rslt = messagetest.NewMessageHandler()
+	doc = &APIDoc{}
+	doc.Parse(rslt.Handler, core.Block{Data: []byte(`<apidoc>
+	<title>title</title>
+	<mimetype>application/json</mimetype>
+	<api method="GET">
+		<path path="/users" />
+		<response status="200" ref="user" />
+	</api>
+	</apidoc>`)})
+	rslt.Handler.Stop()
+	a.Equal(1, len(rslt.Errors))
+	err, ok := rslt.Errors[0].(*core.Error)
+	a.True(ok).Equal(err.Field, "ref")
+
+	// 重复的类型名称
+	rslt = messagetest.NewMessageHandler()
+	doc = &APIDoc{}
+	doc.Parse(rslt.Handler, core.Block{Data: []byte(`<apidoc>
+	<title>title</title>
+	<mimetype>application/json</mimetype>
+	<typedef name="user" type="string" summary="user" />
+	<typedef name="user" type="number" summary="user" />
+	</apidoc>`)})
+	rslt.Handler.Stop()
+	a.Equal(1, len(rslt.Errors))
+
+	// 循环引用
+	rslt = messagetest.NewMessageHandler()
+	doc = &APIDoc{}
+	doc.Parse(rslt.Handler, core.Block{Data: []byte(`<apidoc>
+	<title>title</title>
+	<mimetype>application/json</mimetype>
+	<typedef name="user" type="object" summary="user">
+		<param name="group" ref="group" />
+	</typedef>
+	<typedef name="group" type="object" summary="group">
+		<param name="owner" ref="user" />
+	</typedef>
+	<typedef name="node" type="object" summary="node">
+		<param name="parent" ref="node" />
+	</typedef>
+	</apidoc>`)})
+	rslt.Handler.Stop()
+	a.Equal(2, len(rslt.Errors))
+	for _, e := range rslt.Errors {
+		err, ok := e.(*core.Error)
+		a.True(ok).Equal(err.Err.Error(), locale.Sprintf(locale.ErrCircularReference))
+	}
+}
+
+func TestTypeDef_Sanitize(t *testing.T) {
+	a := assert.New(t, false)
+
+	p, rslt := newParser(a, "", "")
+	td := &TypeDef{Type: &TypeAttribute{Value: xmlenc.String{Value: TypeObject}}}
+	td.Sanitize(p)
+	rslt.Handler.Stop()
+	a.Equal(2, len(rslt.Errors)) // 缺少子元素和 summary
+
+	p, rslt = newParser(a, "", "")
+	td = &TypeDef{
+		Type:    &TypeAttribute{Value: xmlenc.String{Value: TypeString}},
+		Summary: &Attribute{Value: xmlenc.String{Value: "summary"}},
+		Items: []*Param{
+			{Name: &Attribute{Value: xmlenc.String{Value: "p1"}}},
+		},
+	}
+	td.Sanitize(p)
+	rslt.Handler.Stop()
+	a.Equal(1, len(rslt.Errors))
+
+	p, rslt = newParser(a, "", "")
+	td.Items = nil
+	td.Sanitize(p)
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+}
+
+func TestCheckComposite(t *testing.T) {
+	a := assert.New(t, false)
+
+	data := []*struct {
+		param string
+		errs  int
+	}{
+		{
+			param: `<param name="p" type="object" summary="s" discriminator="kind" compose="any-of">
+				<param name="kind" type="string" summary="kind" />
+				<variant value="v1"><param name="id" type="number" summary="id" /></variant>
+				<variant value="v2" ref="v2" />
+			</param>`,
+		},
+		{
+			param: `<param name="p" type="object" summary="s" compose="all-of">
+				<variant><param name="id" type="number" summary="id" /></variant>
+				<variant><param name="name" type="string" summary="name" /></variant>
+			</param>`,
+		},
+		{
+			param: `<param name="p" ref="event">
+				<variant ref="v1" />
+			</param>`, // 引用的类型在解析引用时才确定
+		},
+		{
+			param: `<param name="p" type="object" summary="s" compose="one-of" discriminator="kind">
+				<param name="kind" type="string" summary="kind" />
+			</param>`,
+			errs: 2, // 没有变体
+		},
+		{
+			param: `<param name="p" type="string" summary="s">
+				<variant><param name="id" type="number" summary="id" /></variant>
+			</param>`,
+			errs: 1, // 非 object
+		},
+		{
+			param: `<param name="p" type="object" summary="s" compose="xx">
+				<variant><param name="id" type="number" summary="id" /></variant>
+			</param>`,
+			errs: 1,
+		},
+		{
+			param: `<param name="p" type="object" summary="s" compose="all-of" discriminator="kind">
+				<param name="kind" type="string" summary="kind" />
+				<variant><param name="id" type="number" summary="id" /></variant>
+			</param>`,
+			errs: 1, // all-of 不能指定 discriminator
+		},
+		{
+			param: `<param name="p" type="object" summary="s" discriminator="kind">
+				<param name="id" type="number" summary="kind" />
+				<variant value="v1"><param name="id" type="number" summary="id" /></variant>
+			</param>`,
+			errs: 1, // kind 不存在
+		},
+		{
+			param: `<param name="p" type="object" summary="s" discriminator="kind">
+				<param name="kind" type="number" summary="kind" />
+				<variant value="1"><param name="id" type="number" summary="id" /></variant>
+			</param>`,
+			errs: 1, // kind 不是字符串
+		},
+		{
+			param: `<param name="p" type="object" summary="s" discriminator="kind">
+				<param name="kind" type="string" summary="kind" />
+				<variant value="v1"><param name="id" type="number" summary="id" /></variant>
+				<variant value="v1"><param name="id" type="number" summary="id" /></variant>
+				<variant><param name="id" type="number" summary="id" /></variant>
+			</param>`,
+			errs: 2, // 重复的 value 以及缺少 value
+		},
+		{
+			param: `<param name="p" type="object" summary="s">
+				<variant ref="v1"><param name="id" type="number" summary="id" /></variant>
+				<variant />
+			</param>`,
+			errs: 2, // ref 与子元素同时存在，以及两者都不存在
+		},
+	}
+
+	for i, item := range data {
+		rslt := messagetest.NewMessageHandler()
+		p, err := xmlenc.NewParser(rslt.Handler, core.Block{Data: []byte(item.param)})
+		a.NotError(err).NotNil(p)
+		param := &Param{}
+		xmlenc.Decode(p, param, "")
+		rslt.Handler.Stop()
+		a.Equal(len(rslt.Errors), item.errs, "%d 的错误数量不正确，%v", i, rslt.Errors)
+	}
+}
+
+func TestCheckRef(t *testing.T) {
+	a := assert.New(t, false)
+
+	params := map[string]int{
+		`<param name="p" ref="user" />`:                                                                   0,
+		`<param name="p" ref="user" summary="s" array="true" />`:                                          0,
+		`<param name="p" ref="user" type="string" />`:                                                     1,
+		`<param name="p" ref="user"><param name="id" type="number" summary="id" /></param>`:               1,
+		`<param name="p" ref="user" type="object"><param name="id" type="number" summary="id" /></param>`: 2,
+	}
+	for param, errs := range params {
+		rslt := messagetest.NewMessageHandler()
+		p, err := xmlenc.NewParser(rslt.Handler, core.Block{Data: []byte(param)})
+		a.NotError(err).NotNil(p)
+		xmlenc.Decode(p, &Param{}, "")
+		rslt.Handler.Stop()
+		a.Equal(len(rslt.Errors), errs, "%s 的错误数量不正确，%v", param, rslt.Errors)
+	}
+
+	requests := map[string]int{
+		`<request ref="user" />`:               0,
+		`<request ref="user" type="string" />`: 1,
+		`<request ref="user"><param name="id" type="number" summary="id" /></request>`: 1,
+	}
+	for req, errs := range requests {
+		rslt := messagetest.NewMessageHandler()
+		p, err := xmlenc.NewParser(rslt.Handler, core.Block{Data: []byte(req)})
+		a.NotError(err).NotNil(p)
+		xmlenc.Decode(p, &Request{}, "")
+		rslt.Handler.Stop()
+		a.Equal(len(rslt.Errors), errs, "%s 的错误数量不正确，%v", req, rslt.Errors)
+	}
+}
+
+// 解析引用之后编码的内容，可以被再次解析。
+func TestAPIDoc_encodeRefs(t *testing.T) {
+	a := assert.New(t, false)
+
+	const data = `<apidoc>
+	<title>title</title>
+	<mimetype>application/json</mimetype>
+	<typedef name="user" type="object" summary="user">
+		<param name="id" type="number" summary="id" />
+		<param name="group" ref="group" />
+	</typedef>
+	<typedef name="group" type="object" summary="group">
+		<param name="name" type="string" summary="name" />
+	</typedef>
+	<typedef name="created" type="object" summary="created">
+		<param name="id" type="number" summary="id" />
+	</typedef>
+	<api method="POST">
+		<path path="/users" />
+		<request ref="user" />
+		<response status="200" type="object" summary="event">
+			<variant ref="created" />
+		</response>
+	</api>
+	</apidoc>`
+
+	rslt := messagetest.NewMessageHandler()
+	doc := &APIDoc{}
+	doc.Parse(rslt.Handler, core.Block{Data: []byte(data)})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	encoded, err := xmlenc.Encode("\t", doc, "", "")
+	a.NotError(err).NotEmpty(encoded)
+
+	rslt = messagetest.NewMessageHandler()
+	doc2 := &APIDoc{}
+	doc2.Parse(rslt.Handler, core.Block{Data: encoded})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors, "%v\n%s", rslt.Errors, encoded)
+
+	req := doc2.APIs[0].Requests[0]
+	a.Equal(req.Ref.V(), "user").
+		Equal(req.Type.V(), TypeObject).
+		Equal(req.Items, doc2.TypeDef("user").Items).
+		Equal(req.Items[1].Type.V(), TypeObject).
+		Equal(req.Items[1].Items, doc2.TypeDef("group").Items)
+	a.Equal(doc2.APIs[0].Responses[0].Variants[0].Items, doc2.TypeDef("created").Items)
+}
+
+func TestParam_Sanitize_access(t *testing.T) {
+	a := assert.New(t, false)
+
+	data := map[string]int{
+		`<param name="p" type="string" summary="s" nullable="true" readonly="true" />`:   0,
+		`<param name="p" type="string" summary="s" writeonly="true" />`:                  0,
+		`<param name="p" type="string" summary="s" readonly="true" writeonly="true" />`:  1,
+		`<param name="p" type="string" summary="s" readonly="true" writeonly="false" />`: 0,
+	}
+
+	for param, errs := range data {
+		rslt := messagetest.NewMessageHandler()
+		p, err := xmlenc.NewParser(rslt.Handler, core.Block{Data: []byte(param)})
+		a.NotError(err).NotNil(p)
+		pp := &Param{}
+		xmlenc.Decode(p, pp, "")
+		rslt.Handler.Stop()
+		a.Equal(len(rslt.Errors), errs, "%s 的错误数量不正确，%v", param, rslt.Errors)
+	}
+}
+
+func TestParam_Sanitize_faker(t *testing.T) {
+	a := assert.New(t, false)
+
+	data := map[string]int{
+		`<param name="p" type="string" summary="s" faker="name" />`:       0,
+		`<param name="p" type="string.email" summary="s" faker="none" />`: 0,
+		`<param name="p" type="string" summary="s" faker="not-exists" />`: 1,
+		`<param name="p" type="number" summary="s" faker="name" />`:       1,
+		`<param name="p" type="number" summary="s" faker="not-exists" />`: 2,
+	}
+
+	for param, errs := range data {
+		rslt := messagetest.NewMessageHandler()
+		p, err := xmlenc.NewParser(rslt.Handler, core.Block{Data: []byte(param)})
+		a.NotError(err).NotNil(p)
+		pp := &Param{}
+		xmlenc.Decode(p, pp, "")
+		rslt.Handler.Stop()
+		a.Equal(len(rslt.Errors), errs, "%s 的错误数量不正确，%v", param, rslt.Errors)
+	}
+}
+
+func TestAPIDoc_resolveVariants(t *testing.T) {
+	a := assert.New(t, false)
+
+	const data = `<apidoc>
+	<title>title</title>
+	<mimetype>application/json</mimetype>
+	<typedef name="created" type="object" summary="created">
+		<param name="id" type="number" summary="id" />
+	</typedef>
+	<typedef name="event" type="object" summary="event">
+		<param name="time" type="string" summary="time" />
+		<variant ref="created" />
+		<variant summary="deleted">
+			<param name="by" ref="created" />
+		</variant>
+	</typedef>
+	<typedef name="name" type="string" summary="name" />
+	<api method="GET">
+		<path path="/events" />
+		<response status="200" ref="event" />
+		<response status="201" type="object" summary="s">
+			<variant ref="name" />
+		</response>
+	</api>
+	</apidoc>`
+
+	rslt := messagetest.NewMessageHandler()
+	doc := &APIDoc{}
+	doc.Parse(rslt.Handler, core.Block{Data: []byte(data)})
+	rslt.Handler.Stop()
+	a.Equal(1, len(rslt.Errors)) // 变体引用了非 object 的 name
+
+	created := doc.TypeDef("created")
+	event := doc.TypeDef("event")
+	a.Equal(event.Variants[0].Items, created.Items).
+		Equal(event.Variants[0].Summary.V(), "created").
+		Equal(event.Variants[1].Items[0].Items, created.Items).
+		Equal(2, len(created.References()))
+
+	resp := doc.APIs[0].Responses[0]
+	a.Equal(resp.Variants, event.Variants).
+		Equal(resp.Items, event.Items)
+}
+
+func TestSecurity_Sanitize(t *testing.T) {
+	a := assert.New(t, false)
+
+	newAttr := func(v string) *Attribute { return &Attribute{Value: xmlenc.String{Value: v}} }
+
+	data := []*struct {
+		security *Security
+		errs     int
+	}{
+		{
+			security: &Security{Type: newAttr(SecurityTypeAPIKey), In: newAttr(SecurityInHeader), Key: newAttr("token")},
+		},
+		{
+			security: &Security{Type: newAttr(SecurityTypeAPIKey), In: newAttr("body")},
+			errs:     2, // 缺少 key，in 无效
+		},
+		{
+			security: &Security{Type: newAttr(SecurityTypeHTTP), Scheme: newAttr("bearer")},
+		},
+		{
+			security: &Security{Type: newAttr(SecurityTypeHTTP)},
+			errs:     1,
+		},
+		{
+			security: &Security{Type: newAttr(SecurityTypeOAuth2), Flows: []*OAuthFlow{{Type: newAttr(OAuthFlowImplicit)}}},
+		},
+		{
+			security: &Security{Type: newAttr(SecurityTypeOAuth2)},
+			errs:     1,
+		},
+		{
+			security: &Security{Type: newAttr(SecurityTypeOAuth2), Flows: []*OAuthFlow{
+				{Type: newAttr(OAuthFlowImplicit)},
+				{Type: newAttr(OAuthFlowImplicit)},
+			}},
+			errs: 1,
+		},
+		{
+			security: &Security{Type: newAttr(SecurityTypeOpenIDConnect), OpenIDConnectURL: newAttr("https://example.com")},
+		},
+		{
+			security: &Security{Type: newAttr(SecurityTypeOpenIDConnect), Flows: []*OAuthFlow{{Type: newAttr(OAuthFlowImplicit)}}},
+			errs:     2, // 缺少 openid-connect-url，不能有 flow
+		},
+		{
+			security: &Security{Type: newAttr("not-exists")},
+			errs:     1,
+		},
+		{
+			security: &Security{},
+			errs:     1,
+		},
+	}
+
+	for i, item := range data {
+		p, rslt := newParser(a, "", "")
+		item.security.Sanitize(p)
+		rslt.Handler.Stop()
+		a.Equal(len(rslt.Errors), item.errs, "%d 的错误数量不正确，%v", i, rslt.Errors)
+	}
+}
+
+func TestOAuthFlow_Sanitize(t *testing.T) {
+	a := assert.New(t, false)
+
+	newAttr := func(v string) *Attribute { return &Attribute{Value: xmlenc.String{Value: v}} }
+
+	data := []*struct {
+		flow *OAuthFlow
+		errs int
+	}{
+		{
+			flow: &OAuthFlow{Type: newAttr(OAuthFlowImplicit), AuthorizationURL: newAttr("https://example.com/auth")},
+		},
+		{
+			flow: &OAuthFlow{Type: newAttr(OAuthFlowImplicit)},
+			errs: 1,
+		},
+		{
+			flow: &OAuthFlow{Type: newAttr(OAuthFlowPassword), TokenURL: newAttr("https://example.com/token")},
+		},
+		{
+			flow: &OAuthFlow{Type: newAttr(OAuthFlowClientCredentials)},
+			errs: 1,
+		},
+		{
+			flow: &OAuthFlow{Type: newAttr(OAuthFlowAuthorizationCode)},
+			errs: 2,
+		},
+		{
+			flow: &OAuthFlow{Type: newAttr("not-exists")},
+			errs: 1,
+		},
+		{
+			flow: &OAuthFlow{
+				Type:     newAttr(OAuthFlowPassword),
+				TokenURL: newAttr("https://example.com/token"),
+				Scopes: []*Scope{
+					{Name: newAttr("read")},
+					{Name: newAttr("read")},
+				},
+			},
+			errs: 1,
+		},
+	}
+
+	for i, item := range data {
+		p, rslt := newParser(a, "", "")
+		item.flow.Sanitize(p)
+		rslt.Handler.Stop()
+		a.Equal(len(rslt.Errors), item.errs, "%d 的错误数量不正确，%v", i, rslt.Errors)
+	}
+}
+
+func TestAPI_sanitizeSecurities(t *testing.T) {
+	a := assert.New(t, false)
+
+	const data = `<apidoc>
+	<title>title</title>
+	<mimetype>application/json</mimetype>
+	<security name="token" type="apikey" in="header" key="X-Token" summary="token" />
+	<security name="oauth" type="oauth2" summary="oauth">
+		<flow type="password" token-url="https://example.com/token">
+			<scope name="read" summary="read" />
+			<scope name="write" summary="write" />
+		</flow>
+	</security>
+	<api method="GET">
+		<path path="/users" />
+		<security name="token" />
+		<security name="oauth"><scope>read</scope></security>
+		<response status="200" type="string" />
+	</api>
+	</apidoc>`
+
+	rslt := messagetest.NewMessageHandler()
+	doc := &APIDoc{}
+	doc.Parse(rslt.Handler, core.Block{Data: []byte(data)})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	token := doc.Security("token")
+	oauth := doc.Security("oauth")
+	a.NotNil(token).NotNil(oauth).Nil(doc.Security("not-exists"))
+
+	secs := doc.APIs[0].Securities
+	a.Equal(2, len(secs)).
+		Equal(secs[0].Definition().Target, token).
+		Equal(secs[1].Definition().Target, oauth).
+		Equal(1, len(token.References())).
+		Equal(1, len(oauth.References()))
+
+	// 不存在的方案和 scope，以及 apikey 不能指定 scope
+	rslt = messagetest.NewMessageHandler()
+	doc = &APIDoc{}
+	doc.Parse(rslt.Handler, core.Block{Data: []byte(`<apidoc>
+	<title>title</title>
+	<mimetype>application/json</mimetype>
+	<security name="token" type="apikey" in="header" key="X-Token" />
+	<security name="oauth" type="oauth2">
+		<flow type="password" token-url="https://example.com/token">
+			<scope name="read" />
+		</flow>
+	</security>
+	<api method="GET">
+		<path path="/users" />
+		<security name="not-exists" />
+		<security name="token"><scope>read</scope></security>
+		<security name="oauth"><scope>write</scope></security>
+		<response status="200" type="string" />
+	</api>
+	</apidoc>`)})
+	rslt.Handler.Stop()
+	a.Equal(3, len(rslt.Errors))
+
+	// 重复的方案
+	rslt = messagetest.NewMessageHandler()
+	doc = &APIDoc{}
+	doc.Parse(rslt.Handler, core.Block{Data: []byte(`<apidoc>
+	<title>title</title>
+	<mimetype>application/json</mimetype>
+	<security name="token" type="apikey" in="header" key="X-Token" />
+	<security name="token" type="http" scheme="basic" />
+	<api method="GET">
+		<path path="/users" />
+		<security name="token" />
+		<security name="token" />
+		<response status="200" type="string" />
+	</api>
+	</apidoc>`)})
+	rslt.Handler.Stop()
+	a.Equal(2, len(rslt.Errors))
+}
+
 func TestAPI_checkDup(t *testing.T) {
 	a := assert.New(t, false)
 
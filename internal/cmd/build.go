@@ -3,7 +3,10 @@
 package cmd
 
 import (
+	"context"
 	"io"
+	"os"
+	"os/signal"
 	"time"
 
 	"github.com/issue9/cmdopt"
@@ -13,11 +16,18 @@ import (
 	"github.com/caixw/apidoc/v7/internal/locale"
 )
 
-var buildDir = uri("./")
+// 监视模式下检测文件变化的时间间隔
+const watchInterval = time.Second
+
+var (
+	buildDir   = uri("./")
+	buildWatch bool
+)
 
 func initBuild(command *cmdopt.CmdOpt) {
 	fs := command.New("build", locale.Sprintf(locale.CmdBuildUsage), doBuild)
 	fs.Var(&buildDir, "d", locale.Sprintf(locale.FlagBuildDirUsage))
+	fs.BoolVar(&buildWatch, "watch", false, locale.Sprintf(locale.FlagBuildWatchUsage))
 }
 
 func doBuild(io.Writer) error {
@@ -31,6 +41,13 @@ func doBuild(io.Writer) error {
 	h := core.NewMessageHandler(messageHandle)
 	defer h.Stop()
 
+	if buildWatch {
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		cfg.Watch(ctx, h, watchInterval)
+		return nil
+	}
+
 	cfg.Build(h)
 	h.Locale(core.Info, locale.Complete, cfg.Output.Path, time.Since(start))
 	return nil
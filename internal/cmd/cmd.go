@@ -86,6 +86,9 @@ func Init(out io.Writer) *cmdopt.CmdOpt {
 	initMock(command)
 	initStatic(command)
 	initLSP(command)
+	initImport(command)
+	initDiff(command)
+	initProxy(command)
 
 	return command
 }
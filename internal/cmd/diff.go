@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/issue9/cmdopt"
+
+	"github.com/caixw/apidoc/v7/build"
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/internal/locale"
+)
+
+var (
+	diffOld  uri
+	diffNew  uri
+	diffJSON bool
+)
+
+func initDiff(command *cmdopt.CmdOpt) {
+	fs := command.New("diff", locale.Sprintf(locale.CmdDiffUsage), doDiff)
+	fs.Var(&diffOld, "old", locale.Sprintf(locale.FlagDiffOldUsage))
+	fs.Var(&diffNew, "new", locale.Sprintf(locale.FlagDiffNewUsage))
+	fs.BoolVar(&diffJSON, "json", false, locale.Sprintf(locale.FlagDiffJSONUsage))
+}
+
+func doDiff(w io.Writer) error {
+	h := core.NewMessageHandler(messageHandle)
+	defer h.Stop()
+
+	changes, err := build.Diff(h, diffOld.URI(), diffNew.URI())
+	if err != nil {
+		return err
+	}
+
+	if diffJSON {
+		if changes == nil {
+			changes = []*build.Change{}
+		}
+		data, err := json.MarshalIndent(changes, "", "\t")
+		if err != nil {
+			return err
+		}
+		if _, err = fmt.Fprintln(w, string(data)); err != nil {
+			return err
+		}
+	} else {
+		if len(changes) == 0 {
+			if _, err := fmt.Fprintln(w, locale.Sprintf(locale.DiffNoChanges)); err != nil {
+				return err
+			}
+		}
+		for _, c := range changes {
+			if _, err := fmt.Fprintln(w, c.String()); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 返回错误，使命令行以非零值退出。
+	var cnt int
+	for _, c := range changes {
+		if c.Breaking {
+			cnt++
+		}
+	}
+	if cnt > 0 {
+		return locale.NewError(locale.ErrBreakingChanges, cnt)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/issue9/assert/v3"
+
+	"github.com/caixw/apidoc/v7/build"
+)
+
+const (
+	diffOldDoc = `<apidoc version="1.1.1">
+	<title>test</title>
+	<mimetype>application/json</mimetype>
+	<api method="GET">
+		<path path="/users" />
+		<response status="200" type="string" />
+	</api>
+</apidoc>`
+
+	diffNewDoc = `<apidoc version="1.1.1">
+	<title>test</title>
+	<mimetype>application/json</mimetype>
+	<api method="POST">
+		<path path="/users" />
+		<response status="201" type="string" />
+	</api>
+</apidoc>`
+)
+
+func TestCmdDiff(t *testing.T) {
+	a := assert.New(t, false)
+
+	dir := t.TempDir()
+	o := filepath.Join(dir, "old.xml")
+	n := filepath.Join(dir, "new.xml")
+	a.NotError(os.WriteFile(o, []byte(diffOldDoc), os.ModePerm))
+	a.NotError(os.WriteFile(n, []byte(diffNewDoc), os.ModePerm))
+
+	// 存在不兼容的改动
+	buf := new(bytes.Buffer)
+	erro, _, _, _ := resetPrinters()
+	err := Init(buf).Exec([]string{"diff", "-old", o, "-new", n})
+	a.Error(err).
+		Empty(erro.String()).
+		Contains(buf.String(), "GET /users").
+		Contains(buf.String(), "POST /users")
+
+	// json
+	buf.Reset()
+	err = Init(buf).Exec([]string{"diff", "-old", o, "-new", n, "-json"})
+	a.Error(err)
+	changes := make([]*build.Change, 0, 2)
+	a.NotError(json.Unmarshal(buf.Bytes(), &changes))
+	a.Equal(changes, []*build.Change{
+		{Type: build.ChangeAPIRemoved, Breaking: true, API: "GET /users"},
+		{Type: build.ChangeAPIAdded, API: "POST /users"},
+	})
+
+	// 没有改动
+	buf.Reset()
+	err = Init(buf).Exec([]string{"diff", "-old", n, "-new", n})
+	a.NotError(err).NotEmpty(buf.String())
+}
@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"io"
+	"time"
+
+	"github.com/issue9/cmdopt"
+
+	"github.com/caixw/apidoc/v7/build"
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/internal/locale"
+)
+
+var (
+	importInput  uri
+	importOutput uri
+)
+
+func initImport(command *cmdopt.CmdOpt) {
+	fs := command.New("import", locale.Sprintf(locale.CmdImportUsage), doImport)
+	fs.Var(&importInput, "i", locale.Sprintf(locale.FlagImportInputUsage))
+	fs.Var(&importOutput, "o", locale.Sprintf(locale.FlagImportOutputUsage))
+}
+
+func doImport(w io.Writer) error {
+	start := time.Now()
+
+	h := core.NewMessageHandler(messageHandle)
+	defer h.Stop()
+
+	data, err := build.Import(h, importInput.URI())
+	if err != nil {
+		return err
+	}
+
+	if importOutput == "" {
+		_, err = w.Write(data)
+		return err
+	}
+
+	if err := importOutput.URI().WriteAll(data); err != nil {
+		return err
+	}
+	h.Locale(core.Info, locale.Complete, importOutput.URI(), time.Since(start))
+	return nil
+}
@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/issue9/assert/v3"
+)
+
+func TestCmdImport(t *testing.T) {
+	a := assert.New(t, false)
+
+	buf := new(bytes.Buffer)
+	cmd := Init(buf)
+	erro, warn, _, _ := resetPrinters()
+	err := cmd.Exec([]string{"import", "-i", "../openapi/testdata/openapi.json"})
+	a.NotError(err)
+	a.Contains(buf.String(), `<path path="/users">`).
+		Empty(erro.String()).
+		Empty(warn.String())
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/caixw/apidoc/v7"
 	"github.com/caixw/apidoc/v7/core"
 	"github.com/caixw/apidoc/v7/internal/locale"
+	"github.com/caixw/apidoc/v7/internal/mock"
 )
 
 // servers 参数
@@ -26,6 +27,8 @@ type (
 	dateRange struct {
 		start, end time.Time
 	}
+	latency     apidoc.Latency
+	pathLatency map[string]apidoc.Latency
 )
 
 func (s servers) Get() any {
@@ -145,6 +148,64 @@ func (d *dateRange) String() string {
 	return d.start.Format(time.RFC3339) + "," + d.end.Format(time.RFC3339)
 }
 
+func (l latency) Get() any {
+	return apidoc.Latency(l)
+}
+
+func (l *latency) Set(v string) error {
+	val, err := mock.ParseLatency(v)
+	if err != nil {
+		return err
+	}
+	*l = latency(val)
+	return nil
+}
+
+func (l *latency) String() string {
+	if l.Max <= l.Min {
+		return l.Min.String()
+	}
+	return l.Min.String() + "-" + l.Max.String()
+}
+
+func (p pathLatency) Get() any {
+	return map[string]apidoc.Latency(p)
+}
+
+func (p pathLatency) Set(v string) error {
+	for _, pair := range strings.Split(v, ",") {
+		index := strings.IndexByte(pair, '=')
+		if index <= 0 {
+			return locale.NewError(locale.ErrInvalidFormat)
+		}
+
+		l := &latency{}
+		if err := l.Set(pair[index+1:]); err != nil {
+			return err
+		}
+		p[strings.TrimSpace(pair[:index])] = apidoc.Latency(*l)
+	}
+
+	return nil
+}
+
+func (p pathLatency) String() string {
+	if len(p) == 0 {
+		return ""
+	}
+
+	var buf errwrap.Buffer
+	for k, v := range p {
+		l := latency(v)
+		buf.WString(k).WByte('=').WString(l.String()).WByte(',')
+	}
+	buf.Truncate(buf.Len() - 1)
+	if buf.Err != nil {
+		panic(buf.Err)
+	}
+	return buf.String()
+}
+
 var (
 	mockOptions = &apidoc.MockOptions{}
 
@@ -159,6 +220,8 @@ var (
 	mockEmailDomains = &slice{"example.com"}
 	mockURLDomains   = &slice{"https://example.com"}
 	mockDateRange    = &dateRange{}
+	mockLatency      = &latency{}
+	mockPathLatency  = pathLatency{}
 )
 
 func initMock(command *cmdopt.CmdOpt) {
@@ -184,6 +247,24 @@ func initMock(command *cmdopt.CmdOpt) {
 	fs.StringVar(&mockOptions.ImageBasePrefix, "image.prefix", "/__image__", locale.Sprintf(locale.FlagMockImagePrefixUsage))
 
 	fs.Var(mockDateRange, "date.range", locale.Sprintf(locale.FlagMockDateRangeUsage))
+
+	fs.BoolVar(&mockOptions.Stateful, "stateful", false, locale.Sprintf(locale.FlagMockStatefulUsage))
+	fs.BoolVar(&mockOptions.Examples, "examples", false, locale.Sprintf(locale.FlagMockExamplesUsage))
+	fs.BoolVar(&mockOptions.Security, "security", false, locale.Sprintf(locale.FlagMockSecurityUsage))
+	fs.BoolVar(&mockOptions.Callback, "callback", false, locale.Sprintf(locale.FlagMockCallbackUsage))
+
+	fs.Var(mockLatency, "latency", locale.Sprintf(locale.FlagMockLatencyUsage))
+	fs.Var(mockPathLatency, "latency.paths", locale.Sprintf(locale.FlagMockPathLatencyUsage))
+	fs.Float64Var(&mockOptions.ErrorRate, "fault.error", 0, locale.Sprintf(locale.FlagMockErrorRateUsage))
+	fs.Float64Var(&mockOptions.ServerErrorRate, "fault.server-error", 0, locale.Sprintf(locale.FlagMockServerErrorRateUsage))
+	fs.Float64Var(&mockOptions.ResetRate, "fault.reset", 0, locale.Sprintf(locale.FlagMockResetRateUsage))
+	fs.IntVar(&mockOptions.Bandwidth, "bandwidth", 0, locale.Sprintf(locale.FlagMockBandwidthUsage))
+
+	fs.StringVar(&mockOptions.Fixtures, "fixtures", "", locale.Sprintf(locale.FlagMockFixturesUsage))
+	fs.StringVar(&mockOptions.Record, "record", "", locale.Sprintf(locale.FlagMockRecordUsage))
+
+	fs.Int64Var(&mockOptions.Seed, "seed", 0, locale.Sprintf(locale.FlagMockSeedUsage))
+	fs.StringVar(&mockOptions.Admin, "admin", "", locale.Sprintf(locale.FlagMockAdminUsage))
 }
 
 func doMock(io.Writer) error {
@@ -200,6 +281,8 @@ func doMock(io.Writer) error {
 	mockOptions.EmailUsernameSize = apidoc.Range(*mockUsernameSize)
 	mockOptions.DateStart = mockDateRange.start
 	mockOptions.DateEnd = mockDateRange.end
+	mockOptions.Latency = apidoc.Latency(*mockLatency)
+	mockOptions.PathLatency = mockPathLatency
 	handler, err := apidoc.MockFile(h, mockPath.URI(), mockOptions)
 	if err != nil {
 		return err
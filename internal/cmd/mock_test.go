@@ -15,6 +15,8 @@ var (
 	_ flag.Getter = &slice{}
 	_ flag.Getter = &size{}
 	_ flag.Getter = &dateRange{}
+	_ flag.Getter = &latency{}
+	_ flag.Getter = pathLatency{}
 )
 
 func TestServers_Set(t *testing.T) {
@@ -77,6 +79,35 @@ func TestDateRange_Set(t *testing.T) {
 	a.Equal(d.start, start).Equal(d.end, end)
 }
 
+func TestLatency_Set(t *testing.T) {
+	a := assert.New(t, false)
+
+	l := &latency{}
+	a.Error(l.Set(""))
+	a.Error(l.Set("100"))
+	a.Error(l.Set("500ms-100ms"))
+
+	a.NotError(l.Set("100ms"))
+	a.Equal(l.Min, 100*time.Millisecond).Equal(l.Max, 0).Equal(l.String(), "100ms")
+
+	a.NotError(l.Set("100ms-1s"))
+	a.Equal(l.Min, 100*time.Millisecond).Equal(l.Max, time.Second).Equal(l.String(), "100ms-1s")
+}
+
+func TestPathLatency_Set(t *testing.T) {
+	a := assert.New(t, false)
+
+	p := pathLatency{}
+	a.Equal(p.String(), "")
+	a.Error(p.Set(""))
+	a.Error(p.Set("/users=abc"))
+
+	a.NotError(p.Set("/users/{id}=100ms-500ms, /users=1s"))
+	a.Equal(2, len(p)).
+		Equal(p["/users/{id}"].Max, 500*time.Millisecond).
+		Equal(p["/users"].Min, time.Second)
+}
+
 func TestSlice_Set(t *testing.T) {
 	a := assert.New(t, false)
 
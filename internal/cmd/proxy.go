@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/issue9/cmdopt"
+
+	"github.com/caixw/apidoc/v7"
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/internal/locale"
+)
+
+var (
+	proxyPort     string
+	proxyPath     = uri("./")
+	proxyUpstream string
+	proxyServers  = servers{}
+)
+
+func initProxy(command *cmdopt.CmdOpt) {
+	fs := command.New("proxy", locale.Sprintf(locale.CmdProxyUsage), doProxy)
+	fs.StringVar(&proxyPort, "p", ":8080", locale.Sprintf(locale.FlagProxyPortUsage))
+	fs.Var(&proxyPath, "path", locale.Sprintf(locale.FlagProxyPathUsage))
+	fs.StringVar(&proxyUpstream, "upstream", "", locale.Sprintf(locale.FlagProxyUpstreamUsage))
+	fs.Var(proxyServers, "servers", locale.Sprintf(locale.FlagProxyServersUsage))
+}
+
+func doProxy(io.Writer) error {
+	h := core.NewMessageHandler(messageHandle)
+	defer h.Stop()
+
+	if proxyUpstream == "" {
+		return core.NewError(locale.ErrIsEmpty, "upstream").WithField("upstream")
+	}
+
+	handler, err := apidoc.Proxy(h, proxyPath.URI(), proxyUpstream, proxyServers)
+	if err != nil {
+		return err
+	}
+
+	h.Locale(core.Succ, locale.ServerStart, proxyPort)
+
+	return http.ListenAndServe(proxyPort, handler)
+}
@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/issue9/assert/v3"
+)
+
+func TestCmdProxy(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 未指定 upstream
+	buf := new(bytes.Buffer)
+	resetPrinters()
+	err := Init(buf).Exec([]string{"proxy", "-path", "../../docs/example/index.xml"})
+	a.Error(err)
+
+	// 文档不存在
+	err = Init(buf).Exec([]string{"proxy", "-path", "./not-exists.xml", "-upstream", "http://localhost:8080"})
+	a.Error(err)
+}
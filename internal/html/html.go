@@ -0,0 +1,460 @@
+// SPDX-License-Identifier: MIT
+
+// Package html 将文档输出为 HTML 格式
+//
+// 与 apidoc+xml 依赖浏览器的 XSLT 不同，该包在服务端直接生成 HTML，
+// 样式与脚本都内嵌在页面中，生成的文件可以独立访问。
+package html
+
+import (
+	"bytes"
+	_ "embed" // 用于嵌入 html.tmpl
+	"encoding/base64"
+	"html/template"
+	"io/fs"
+	"strings"
+
+	"github.com/caixw/apidoc/v7/docs"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/locale"
+)
+
+// 页面中内嵌的样式、脚本以及默认图标
+const (
+	cssFile  = "v6/apidoc.css"
+	jsFile   = "v6/apidoc.js"
+	iconFile = "icon.svg"
+)
+
+// 模板中用到的本地化内容
+var texts = map[string]string{
+	"server":        locale.MarkdownServers,
+	"tag":           locale.HTMLTag,
+	"uncategorized": locale.HTMLUncategorized,
+	"expand":        locale.HTMLExpand,
+	"method":        locale.HTMLMethod,
+	"request":       locale.MarkdownRequest,
+	"response":      locale.MarkdownResponse,
+	"callback":      locale.MarkdownCallback,
+	"path-param":    locale.MarkdownPathParams,
+	"query":         locale.MarkdownQueries,
+	"header":        locale.MarkdownHeaders,
+	"cookie":        locale.MarkdownCookies,
+	"security":      locale.HTMLSecurity,
+	"scope":         locale.HTMLScope,
+	"body":          locale.MarkdownBody,
+	"example":       locale.MarkdownExample,
+	"var":           locale.HTMLVar,
+	"type":          locale.MarkdownType,
+	"value":         locale.HTMLValue,
+	"description":   locale.MarkdownDescription,
+	"enum":          locale.HTMLEnums,
+	"goto-top":      locale.HTMLGotoTop,
+	"generator":     locale.GeneratorBy,
+}
+
+//go:embed html.tmpl
+var tpl string
+
+type page struct {
+	Doc     *ast.APIDoc
+	Lang    string
+	Icon    any // 文档指定的 logo 为 string，由模板进行过滤；默认图标为 template.URL。
+	CSS     template.CSS
+	JS      template.JS
+	Version string
+	Created string
+	Methods []string
+	APIs    []*api
+}
+
+type api struct {
+	*ast.API
+	ID         string
+	Tags       string // 以逗号分隔的标签，供 JS 过滤使用
+	Servers    []string
+	Deprecated string
+	Headers    []*ast.Param // 包含了文档中的公共报头
+	Securities []*security
+	Requests   []*body
+	Responses  []*body
+	Callback   *callback
+}
+
+type callback struct {
+	*ast.Callback
+	Requests  []*body
+	Responses []*body
+}
+
+type security struct {
+	Name        string
+	Type        string
+	Scopes      string
+	Description template.HTML
+}
+
+// 某一 mimetype 下的请求或是返回内容
+type body struct {
+	Mimetype string
+	Items    []*ast.Request
+}
+
+// 某一 mimetype 下的单个请求或是返回内容，作为模板 top-param 的参数。
+type request struct {
+	Mimetype string
+	Request  *ast.Request
+}
+
+// 带标题的参数列表，作为模板 param 的参数。
+type params struct {
+	Title string
+	Rows  []*row
+}
+
+// 参数列表中的一行
+type row struct {
+	Parent      string
+	Name        string
+	Type        string
+	Value       string
+	Deprecated  string
+	Description template.HTML
+	Enums       []*enum
+}
+
+type enum struct {
+	Value       string
+	Deprecated  string
+	Description template.HTML
+}
+
+// HTML 将 doc 输出为 HTML 格式的内容
+func HTML(doc *ast.APIDoc) ([]byte, error) {
+	p, err := newPage(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := template.New("html").Funcs(funcs(p.Lang)).Parse(tpl)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := t.Execute(buf, p); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func newPage(doc *ast.APIDoc) (*page, error) {
+	css, err := fs.ReadFile(docs.FS, cssFile)
+	if err != nil {
+		return nil, err
+	}
+	js, err := fs.ReadFile(docs.FS, jsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &page{
+		Doc:     doc,
+		Lang:    doc.Lang.V(),
+		CSS:     template.CSS(css),
+		JS:      template.JS(js),
+		Version: version(doc.Version),
+		Methods: make([]string, 0, 10),
+		APIs:    make([]*api, 0, len(doc.APIs)),
+	}
+	if p.Lang == "" {
+		p.Lang = "und"
+	}
+	if doc.Created != nil {
+		p.Created = doc.Created.V().Format("2006-01-02T15:04:05Z07:00")
+	}
+
+	if logo := doc.Logo.V(); logo != "" {
+		p.Icon = logo
+	} else {
+		icon, err := fs.ReadFile(docs.FS, iconFile)
+		if err != nil {
+			return nil, err
+		}
+		p.Icon = template.URL("data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString(icon))
+	}
+
+	for _, a := range doc.APIs {
+		if m := a.Method.V(); !contains(p.Methods, m) {
+			p.Methods = append(p.Methods, m)
+		}
+		p.APIs = append(p.APIs, newAPI(doc, a))
+	}
+
+	return p, nil
+}
+
+func newAPI(doc *ast.APIDoc, a *ast.API) *api {
+	tags := make([]string, 0, len(a.Tags))
+	for _, tag := range a.Tags {
+		tags = append(tags, tag.V())
+	}
+	servers := make([]string, 0, len(a.Servers))
+	for _, srv := range a.Servers {
+		servers = append(servers, srv.V())
+	}
+
+	v := &api{
+		API:        a,
+		ID:         id(strings.Join(servers, "") + a.Method.V() + a.Path.Path.V()),
+		Tags:       strings.Join(tags, ","),
+		Servers:    servers,
+		Deprecated: version(a.Deprecated),
+		Headers:    append(append([]*ast.Param{}, a.Headers...), doc.Headers...),
+		Securities: make([]*security, 0, len(a.Securities)),
+		Requests:   bodies(doc, a.Requests, false),
+		Responses:  bodies(doc, append(append([]*ast.Request{}, a.Responses...), doc.Responses...), true),
+	}
+
+	for _, s := range a.Securities {
+		v.Securities = append(v.Securities, newSecurity(doc, s))
+	}
+
+	if cb := a.Callback; cb != nil {
+		v.Callback = &callback{
+			Callback:  cb,
+			Requests:  bodies(doc, cb.Requests, false),
+			Responses: bodies(doc, cb.Responses, true),
+		}
+	}
+
+	return v
+}
+
+func newSecurity(doc *ast.APIDoc, s *ast.SecurityValue) *security {
+	scopes := make([]string, 0, len(s.Scopes))
+	for _, scope := range s.Scopes {
+		scopes = append(scopes, scope.V())
+	}
+
+	v := &security{Name: s.Name.V(), Scopes: strings.Join(scopes, ", ")}
+	if scheme := doc.Security(s.Name.V()); scheme != nil {
+		v.Type = scheme.Type.V()
+		if key := scheme.Key.V(); key != "" {
+			v.Type += " (" + scheme.In.V() + ": " + key + ")"
+		}
+		if sch := scheme.Scheme.V(); sch != "" {
+			v.Type += " (" + sch + ")"
+		}
+		v.Description = richtext(scheme.Description, scheme.Summary)
+	}
+	return v
+}
+
+// 将 requests 按 mimetype 进行分组
+//
+// 未指定 mimetype 的内容适用于所有的 mimetype，与 apidoc.xsl 的处理方式相同：
+// 返回内容会同时包含指定了该 mimetype 和未指定 mimetype 的内容；
+// 请求内容则优先采用指定了该 mimetype 的内容，不存在时才采用未指定 mimetype 的内容。
+func bodies(doc *ast.APIDoc, requests []*ast.Request, multiple bool) []*body {
+	mimetypes := make([]string, 0, len(doc.Mimetypes)+len(requests))
+	for _, mt := range doc.Mimetypes {
+		mimetypes = append(mimetypes, mt.V())
+	}
+	for _, r := range requests {
+		if mt := r.Mimetype.V(); mt != "" && !contains(mimetypes, mt) {
+			mimetypes = append(mimetypes, mt)
+		}
+	}
+
+	ret := make([]*body, 0, len(mimetypes))
+	for _, mt := range mimetypes {
+		b := &body{Mimetype: mt, Items: make([]*ast.Request, 0, len(requests))}
+
+		for _, r := range requests {
+			if mtt := r.Mimetype.V(); mtt == mt || (multiple && mtt == "") {
+				b.Items = append(b.Items, r)
+			}
+		}
+		if !multiple && len(b.Items) == 0 {
+			for _, r := range requests {
+				if r.Mimetype.V() == "" {
+					b.Items = append(b.Items, r)
+				}
+			}
+		}
+
+		if len(b.Items) > 0 {
+			ret = append(ret, b)
+		}
+	}
+	return ret
+}
+
+func funcs(lang string) template.FuncMap {
+	return template.FuncMap{
+		"t": func(name string, v ...any) string {
+			return locale.Translate(lang, texts[name], v...)
+		},
+		"richtext": richtext,
+		"version":  version,
+		"rows":     rows,
+		"bodyRows": bodyRows,
+		"examples": examples,
+		"join":     strings.Join,
+		"params": func(title string, rows []*row) *params {
+			return &params{Title: title, Rows: rows}
+		},
+		"body": func(mimetype string, r *ast.Request) *request {
+			return &request{Mimetype: mimetype, Request: r}
+		},
+	}
+}
+
+// 将 params 展开为参数列表
+//
+// nested 表示是否需要展开子元素，报头等简单类型的参数不需要。
+func rows(params []*ast.Param, nested bool) []*row {
+	ret := make([]*row, 0, len(params))
+	for _, p := range params {
+		ret = appendRows(ret, "", p, nested)
+	}
+	return ret
+}
+
+// 请求和返回的内容
+//
+// 与 apidoc.xsl 相同，顶层元素没有名称，仅在有类型时输出。
+func bodyRows(r *ast.Request) []*row {
+	if r.Type.V() == ast.TypeNone {
+		return nil
+	}
+	p := r.Param()
+	p.Optional = nil // Param() 会将顶层元素设置为可选
+	return appendRows(nil, "", p, true)
+}
+
+func appendRows(rows []*row, parent string, p *ast.Param, nested bool) []*row {
+	typ := p.Type.V()
+	if p.Array.V() {
+		typ += "[]"
+	}
+
+	value := "R"
+	if p.Optional.V() {
+		value = "O"
+	}
+	if def := p.Default.V(); def != "" {
+		value += " " + def
+	}
+
+	r := &row{
+		Parent:      parent,
+		Name:        p.Name.V(),
+		Type:        typ,
+		Value:       value,
+		Deprecated:  version(p.Deprecated),
+		Description: richtext(p.Description, p.Summary),
+		Enums:       make([]*enum, 0, len(p.Enums)),
+	}
+	for _, e := range p.Enums {
+		r.Enums = append(r.Enums, &enum{
+			Value:       e.Value.V(),
+			Deprecated:  version(e.Deprecated),
+			Description: richtext(e.Description, e.Summary),
+		})
+	}
+	rows = append(rows, r)
+
+	if !nested {
+		return rows
+	}
+
+	prefix := parent + p.Name.V()
+	if p.Name.V() != "" {
+		prefix += "."
+	}
+	if p.Type.V() == ast.TypeMap { // 字典的子元素描述的是值，以 * 表示任意的键名
+		prefix += "*."
+	}
+
+	for _, item := range p.Items {
+		rows = appendRows(rows, prefix, item, nested)
+	}
+
+	for _, v := range p.Variants {
+		name := "(" + v.Value.V() + v.Ref.V() + ")"
+		compose := p.Compose.V()
+		if compose == "" {
+			compose = "one-of"
+		}
+		var value string
+		if d := p.Discriminator.V(); d != "" {
+			value = d + "=" + v.Value.V()
+		}
+
+		rows = append(rows, &row{
+			Parent:      prefix,
+			Name:        name,
+			Type:        compose,
+			Value:       value,
+			Description: richtext(v.Description, v.Summary),
+		})
+		for _, item := range v.Items {
+			rows = appendRows(rows, prefix+name+".", item, nested)
+		}
+	}
+
+	return rows
+}
+
+// 获取与 mimetype 相匹配的示例代码
+func examples(r *ast.Request, mimetype string) []string {
+	ret := make([]string, 0, len(r.Examples))
+	for _, exp := range r.Examples {
+		if exp.Content == nil {
+			continue
+		}
+		if mt := exp.Mimetype.V(); mt == "" || mt == mimetype {
+			ret = append(ret, strings.TrimSpace(exp.Content.Value.Value))
+		}
+	}
+	return ret
+}
+
+// 输出描述内容
+//
+// html 类型的内容原样输出，markdown 则与 apidoc.xsl 相同，以 pre 的形式输出。
+// desc 为空时，采用 summary 的内容。
+func richtext(desc *ast.Richtext, summary *ast.Attribute) template.HTML {
+	if desc.V() == "" {
+		return template.HTML(template.HTMLEscapeString(summary.V()))
+	}
+
+	if desc.Type.V() == ast.RichtextTypeHTML {
+		return template.HTML(desc.V())
+	}
+	return template.HTML(`<pre data-type="` + template.HTMLEscapeString(desc.Type.V()) + `">` +
+		template.HTMLEscapeString(desc.V()) + "</pre>")
+}
+
+func version(v *ast.VersionAttribute) string {
+	if v == nil {
+		return ""
+	}
+	return v.V()
+}
+
+// 将 API 地址转换成合法的 ID，与 apidoc.xsl 中的 id-from 和 id-to 相对应。
+func id(v string) string {
+	return strings.NewReplacer("{", "_", "}", "_", "/", "-").Replace(v)
+}
+
+func contains(slice []string, v string) bool {
+	for _, s := range slice {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
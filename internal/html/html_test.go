@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: MIT
+
+package html
+
+import (
+	"html/template"
+	"net/http"
+	"testing"
+
+	"github.com/issue9/assert/v3"
+
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/ast/asttest"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+func newParam(name, typ string) *ast.Param {
+	return &ast.Param{
+		Name: &ast.Attribute{Value: xmlenc.String{Value: name}},
+		Type: &ast.TypeAttribute{Value: xmlenc.String{Value: typ}},
+	}
+}
+
+func newRequest(mimetype string, status int) *ast.Request {
+	r := &ast.Request{Status: &ast.StatusAttribute{Value: ast.Number{Int: status}}}
+	if mimetype != "" {
+		r.Mimetype = &ast.Attribute{Value: xmlenc.String{Value: mimetype}}
+	}
+	return r
+}
+
+func TestHTML(t *testing.T) {
+	a := assert.New(t, false)
+
+	data, err := HTML(asttest.Get())
+	a.NotError(err).NotNil(data)
+	text := string(data)
+	a.Contains(text, "<!DOCTYPE html>").
+		Contains(text, `<html lang="und">`).
+		Contains(text, "<style>").
+		Contains(text, "registerFilter").
+		Contains(text, `src="data:image/svg`).
+		Contains(text, `data-tag="t1,t2"`).
+		Contains(text, `<li data-method="POST"`).
+		Contains(text, "<p>desc</p>"). // html 类型的描述原样输出
+		NotContains(text, "xml-stylesheet")
+
+	doc := &ast.APIDoc{
+		Title: &ast.Element{Content: ast.Content{Value: "<test>"}},
+		Logo:  &ast.Attribute{Value: xmlenc.String{Value: "https://example.com/logo.png"}},
+		APIs: []*ast.API{
+			{
+				Method:     &ast.MethodAttribute{Value: xmlenc.String{Value: http.MethodGet}},
+				Path:       &ast.Path{Path: &ast.Attribute{Value: xmlenc.String{Value: "/users/{id}"}}, Params: []*ast.Param{newParam("id", ast.TypeInt)}},
+				Deprecated: &ast.VersionAttribute{Value: xmlenc.String{Value: "1.0.0"}},
+			},
+		},
+	}
+	data, err = HTML(doc)
+	a.NotError(err)
+	text = string(data)
+	a.Contains(text, `<html lang="und">`).
+		Contains(text, "<title>&lt;test&gt;</title>").
+		Contains(text, `src="https://example.com/logo.png"`).
+		NotContains(text, "tag-selector").
+		NotContains(text, "server-selector").
+		Contains(text, `id="GET-users-_id_"`).
+		Contains(text, `<span class="del" title="1.0.0">/users/{id}</span>`).
+		Contains(text, "<td>number.int</td>")
+
+	// 不安全的 logo 地址会被过滤
+	doc.Logo = &ast.Attribute{Value: xmlenc.String{Value: "javascript:alert(1)"}}
+	data, err = HTML(doc)
+	a.NotError(err)
+	text = string(data)
+	a.NotContains(text, "javascript:alert").
+		Contains(text, `src="#ZgotmplZ"`)
+}
+
+func TestBodies(t *testing.T) {
+	a := assert.New(t, false)
+
+	doc := &ast.APIDoc{
+		Mimetypes: []*ast.Element{
+			{Content: ast.Content{Value: "application/json"}},
+			{Content: ast.Content{Value: "application/xml"}},
+		},
+	}
+
+	// 请求优先采用指定了 mimetype 的内容
+	json := newRequest("application/json", 0)
+	anyMimetype := newRequest("", 0)
+	bs := bodies(doc, []*ast.Request{json, anyMimetype}, false)
+	a.Equal(2, len(bs)).
+		Equal(bs[0].Mimetype, "application/json").
+		Equal(bs[0].Items, []*ast.Request{json}).
+		Equal(bs[1].Mimetype, "application/xml").
+		Equal(bs[1].Items, []*ast.Request{anyMimetype})
+
+	// 返回同时包含指定了 mimetype 和未指定 mimetype 的内容
+	ok := newRequest("application/json", http.StatusOK)
+	yaml := newRequest("application/yaml", http.StatusOK)
+	notFound := newRequest("", http.StatusNotFound)
+	bs = bodies(doc, []*ast.Request{ok, yaml, notFound}, true)
+	a.Equal(3, len(bs)).
+		Equal(bs[0].Items, []*ast.Request{ok, notFound}).
+		Equal(bs[1].Items, []*ast.Request{notFound}).
+		Equal(bs[2].Mimetype, "application/yaml").
+		Equal(bs[2].Items, []*ast.Request{yaml, notFound})
+
+	a.Empty(bodies(doc, nil, false))
+}
+
+func TestAppendRows(t *testing.T) {
+	a := assert.New(t, false)
+
+	user := newParam("user", ast.TypeObject)
+	user.Optional = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+	user.Items = []*ast.Param{newParam("name", ast.TypeString)}
+	tags := newParam("tags", ast.TypeMap)
+	tags.Items = []*ast.Param{newParam("id", ast.TypeInt)}
+	age := newParam("age", ast.TypeInt)
+	age.Default = &ast.Attribute{Value: xmlenc.String{Value: "18"}}
+	age.Array = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+
+	rs := rows([]*ast.Param{user, tags, age}, true)
+	a.Equal(5, len(rs))
+	a.Equal(rs[0].Name, "user").Equal(rs[0].Value, "O")
+	a.Equal(rs[1].Parent, "user.").Equal(rs[1].Name, "name").Equal(rs[1].Value, "R")
+	a.Equal(rs[3].Parent, "tags.*.").Equal(rs[3].Name, "id")
+	a.Equal(rs[4].Type, "number.int[]").Equal(rs[4].Value, "R 18")
+
+	// 不展开子元素
+	a.Equal(3, len(rows([]*ast.Param{user, tags, age}, false)))
+
+	// 变体
+	obj := newParam("obj", ast.TypeObject)
+	obj.Discriminator = &ast.Attribute{Value: xmlenc.String{Value: "kind"}}
+	obj.Variants = []*ast.Variant{
+		{
+			Value: &ast.Attribute{Value: xmlenc.String{Value: "v1"}},
+			Items: []*ast.Param{newParam("f1", ast.TypeString)},
+		},
+	}
+	rs = rows([]*ast.Param{obj}, true)
+	a.Equal(3, len(rs))
+	a.Equal(rs[1].Parent, "obj.").
+		Equal(rs[1].Name, "(v1)").
+		Equal(rs[1].Type, "one-of").
+		Equal(rs[1].Value, "kind=v1")
+	a.Equal(rs[2].Parent, "obj.(v1).").Equal(rs[2].Name, "f1")
+}
+
+func TestBodyRows(t *testing.T) {
+	a := assert.New(t, false)
+
+	r := newRequest("", 0)
+	a.Empty(bodyRows(r))
+
+	r.Type = &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeObject}}
+	r.Items = []*ast.Param{newParam("id", ast.TypeInt)}
+	rs := bodyRows(r)
+	a.Equal(2, len(rs)).
+		Equal(rs[0].Name, "").
+		Equal(rs[0].Value, "R").
+		Equal(rs[1].Parent, "").
+		Equal(rs[1].Name, "id")
+}
+
+func TestExamples(t *testing.T) {
+	a := assert.New(t, false)
+
+	r := &ast.Request{
+		Examples: []*ast.Example{
+			{
+				Mimetype: &ast.Attribute{Value: xmlenc.String{Value: "application/json"}},
+				Content:  &ast.ExampleValue{Value: xmlenc.String{Value: "\n{}\n"}},
+			},
+			{
+				Mimetype: &ast.Attribute{Value: xmlenc.String{Value: "application/xml"}},
+				Content:  &ast.ExampleValue{Value: xmlenc.String{Value: "<xml />"}},
+			},
+		},
+	}
+	a.Equal(examples(r, "application/json"), []string{"{}"}).
+		Empty(examples(r, "text/plain"))
+}
+
+func TestRichtext(t *testing.T) {
+	a := assert.New(t, false)
+
+	summary := &ast.Attribute{Value: xmlenc.String{Value: "<summary>"}}
+	a.Equal(richtext(nil, summary), template.HTML("&lt;summary&gt;")).
+		Equal(richtext(nil, nil), template.HTML(""))
+
+	desc := &ast.Richtext{
+		Type: &ast.Attribute{Value: xmlenc.String{Value: ast.RichtextTypeHTML}},
+		Text: &ast.CData{Value: xmlenc.String{Value: "<p>desc</p>"}},
+	}
+	a.Equal(richtext(desc, summary), template.HTML("<p>desc</p>"))
+
+	desc.Type = &ast.Attribute{Value: xmlenc.String{Value: ast.RichtextTypeMarkdown}}
+	desc.Text = &ast.CData{Value: xmlenc.String{Value: "# <desc>"}}
+	a.Equal(richtext(desc, summary), template.HTML(`<pre data-type="markdown"># &lt;desc&gt;</pre>`))
+}
+
+func TestID(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(id("GET/users/{id}"), "GET-users-_id_")
+}
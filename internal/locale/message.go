@@ -31,43 +31,73 @@ const (
 mock 服务会根据接口定义检测用户提交的数据是否合法，并生成随机的数据返回给用户。
 对于数据只作检测是否合规，但是无法理解其内容，比如提交地址中添加了 size=20，
 只会检测 20 的类型是否符合 size 的要求，但是不会只返回给用户 20 条数据。
+
+可以通过 Prefer: code=404, example=name 报头指定返回的状态码以及示例代码。
+可以通过 X-Apidoc-Fault 报头覆盖延迟与故障注入的设置。
 `
 	CmdBuildUsage  = "生成文档内容\n"
 	CmdStaticUsage = "启用静态文件服务\n"
 	CmdLSPUsage    = "启动 language server protocol 服务\n"
+	CmdImportUsage = "将 openapi 文档转换为 apidoc 文档\n"
+	CmdDiffUsage   = "比较两个文档之间的差异\n"
+	CmdProxyUsage  = "启用契约测试的代理服务\n\n将请求转发至真实的服务，同时根据文档验证请求以及返回的内容，\n不符合文档定义的内容会以错误的形式输出，但不影响返回给客户端的内容。\n"
 	Version        = "版本：%s\n文档：%s\nLSP：%s\nopenapi：%s\nGo：%s"
 	CmdNotFound    = "子命令 %s 未找到\n"
 
-	FlagSyntaxDirUsage         = "以 `URI` 形式表示测试项目地址"
-	FlagBuildDirUsage          = "以 `URI` 形式表示的项目地址"
-	FlagMockPortUsage          = "指定 mock 服务的端口号"
-	FlagMockServersUsage       = "指定 mock 服务时，文档中 server 变量对应的路由前缀"
-	FlagMockIndentUsage        = "指定缩进内容"
-	FlagMockSliceSizeUsage     = "生成数组大小的范围，格式为 [min,max]。"
-	FlagMockNumSliceUsage      = "生成数值类型的数据时的数值范围，格式为 [min,max]。"
-	FlagMockNumFloatUsage      = "生成的数值是否允许有浮点数存在"
-	FlagMockPathUsage          = "指定文档的 `URI` 格式路径，根据此文档的内容生成 mock 数据。"
-	FlagMockStringSizeUsage    = "生成字符串类型数据时字符串的长度范围，格式为 [min,max]。"
-	FlagMockStringAlphaUsage   = "生成的字符串中允许出现的字符"
-	FlagMockUsernameSizeUsage  = "生成邮箱地址时，用户名的长度范围，格式为 [min,max]。"
-	FlagMockEmailDomainsUsage  = "生成邮箱地址时所可用的域名列表，多个用半角逗号分隔。"
-	FlagMockURLDomainsUsage    = "生成 URL 地址时所可用的域名列表，多个用半角逗号分隔。"
-	FlagMockImagePrefixUsage   = "生成图片类型数据的基地址"
-	FlagMockDateRangeUsage     = "生成可用的日期范围，格式为 [start,end]，start 和 end 均为 RFC3339 格式。"
-	FlagDetectRecursiveUsage   = "detect 子命令是否检测子目录的值"
-	FlagDetectDirUsage         = "以 `URI` 形式表示检测项目地址"
-	FlagDetectWrite            = "是否将配置内容写入文件，如果为 true，会将配置内容写入检测目录下的 .apidoc.yaml 文件。"
-	FlagStaticPortUsage        = "指定 static 服务的端口号"
-	FlagStaticDocsUsage        = "指定 static 服务静态文件所在的 `URI`"
-	FlagStaticStylesheetUsage  = "指定 static 是否只启用样式文件内容"
-	FlagStaticContentTypeUsage = "指定 static 的 content-type 值，不指定，则根据扩展名自动获取"
-	FlagStaticURLUsage         = "指定 static 服务中文档的输出地址"
-	FlagStaticPathUsage        = "指定 static 服务 `URI` 格式的文档路径，如果未指定，则不生成相关的文档内容。"
-	FlagLSPPortUsage           = "指定 LSP 服务的端口号。"
-	FlagLSPModeUsage           = "指定 LSP 的运行方式，可以是 stdio、tcp、unix、ipc 和 udp。"
-	FlagLSPHeaderUsage         = "指定 LSP 传递内容是否带报头信息。"
-	FlagLSPTimeoutUsage        = "指定 LSP 每次读取客户端数据的超时时间，超进不会触发错误，只会再次读取。"
-	FlagVersionKindUsage       = "只显示该类型的版本号，可以是 apidoc、doc、lsp、openapi 和 all"
+	FlagSyntaxDirUsage           = "以 `URI` 形式表示测试项目地址"
+	FlagBuildDirUsage            = "以 `URI` 形式表示的项目地址"
+	FlagBuildWatchUsage          = "监视源码目录，在文件有变化时自动重新生成文档。"
+	FlagImportInputUsage         = "以 `URI` 形式表示的 openapi 文档路径，可以是 JSON 或是 YAML 格式。"
+	FlagImportOutputUsage        = "以 `URI` 形式表示的输出路径，为空表示输出到标准输出。"
+	FlagDiffOldUsage             = "以 `URI` 形式表示的旧文档路径"
+	FlagDiffNewUsage             = "以 `URI` 形式表示的新文档路径"
+	FlagDiffJSONUsage            = "以 JSON 格式输出比较结果"
+	FlagMockPortUsage            = "指定 mock 服务的端口号"
+	FlagMockServersUsage         = "指定 mock 服务时，文档中 server 变量对应的路由前缀"
+	FlagMockIndentUsage          = "指定缩进内容"
+	FlagMockSliceSizeUsage       = "生成数组大小的范围，格式为 [min,max]。"
+	FlagMockNumSliceUsage        = "生成数值类型的数据时的数值范围，格式为 [min,max]。"
+	FlagMockNumFloatUsage        = "生成的数值是否允许有浮点数存在"
+	FlagMockPathUsage            = "指定文档的 `URI` 格式路径，根据此文档的内容生成 mock 数据。"
+	FlagMockStringSizeUsage      = "生成字符串类型数据时字符串的长度范围，格式为 [min,max]。"
+	FlagMockStringAlphaUsage     = "生成的字符串中允许出现的字符"
+	FlagMockUsernameSizeUsage    = "生成邮箱地址时，用户名的长度范围，格式为 [min,max]。"
+	FlagMockEmailDomainsUsage    = "生成邮箱地址时所可用的域名列表，多个用半角逗号分隔。"
+	FlagMockURLDomainsUsage      = "生成 URL 地址时所可用的域名列表，多个用半角逗号分隔。"
+	FlagMockImagePrefixUsage     = "生成图片类型数据的基地址"
+	FlagMockDateRangeUsage       = "生成可用的日期范围，格式为 [start,end]，start 和 end 均为 RFC3339 格式。"
+	FlagMockStatefulUsage        = "启用有状态模式，POST、PUT、PATCH 和 DELETE 请求会修改内存中的数据，GET 请求优先返回这些数据。"
+	FlagMockExamplesUsage        = "优先使用文档中的示例代码作为返回内容，可通过报头 X-Apidoc-Example 指定示例代码的 summary。"
+	FlagMockSecurityUsage        = "验证接口要求的安全验证方案，未提供相应凭证时返回 401。"
+	FlagMockCallbackUsage        = "发送接口中定义的回调，回调地址由请求的 X-Apidoc-Callback 报头指定。"
+	FlagMockLatencyUsage         = "所有接口的延迟时间，格式为 100ms 或是 100ms-500ms，后者表示在该范围内随机。"
+	FlagMockPathLatencyUsage     = "指定接口的延迟时间，格式为 /users/{id}=100ms-500ms，多个用半角逗号分隔。"
+	FlagMockErrorRateUsage       = "返回文档中定义的错误内容的概率，取值范围为 [0,1]。"
+	FlagMockServerErrorRateUsage = "返回 500 的概率，取值范围为 [0,1]。"
+	FlagMockResetRateUsage       = "直接断开连接的概率，取值范围为 [0,1]。"
+	FlagMockBandwidthUsage       = "每秒输出的最大字节数，0 表示不限制。"
+	FlagMockFixturesUsage        = "录制内容的保存目录，存在与请求相匹配的录制内容时，优先返回该内容。"
+	FlagMockRecordUsage          = "被代理的服务地址，指定后所有请求都将转发至该地址，并将符合文档定义的请求与返回内容录制至 fixtures 指定的目录。"
+	FlagMockSeedUsage            = "生成随机数据的种子，不为 0 时，相同的请求始终返回相同的内容。"
+	FlagMockAdminUsage           = "管理接口的路由前缀，比如 /__admin__，为空表示不启用管理接口。"
+	FlagProxyPortUsage           = "指定代理服务的端口号"
+	FlagProxyPathUsage           = "指定文档的 `URI` 格式路径，根据此文档的内容验证请求和返回的内容。"
+	FlagProxyUpstreamUsage       = "指定被代理的服务地址"
+	FlagProxyServersUsage        = "指定代理服务时，文档中 server 变量对应的路由前缀"
+	FlagDetectRecursiveUsage     = "detect 子命令是否检测子目录的值"
+	FlagDetectDirUsage           = "以 `URI` 形式表示检测项目地址"
+	FlagDetectWrite              = "是否将配置内容写入文件，如果为 true，会将配置内容写入检测目录下的 .apidoc.yaml 文件。"
+	FlagStaticPortUsage          = "指定 static 服务的端口号"
+	FlagStaticDocsUsage          = "指定 static 服务静态文件所在的 `URI`"
+	FlagStaticStylesheetUsage    = "指定 static 是否只启用样式文件内容"
+	FlagStaticContentTypeUsage   = "指定 static 的 content-type 值，不指定，则根据扩展名自动获取"
+	FlagStaticURLUsage           = "指定 static 服务中文档的输出地址"
+	FlagStaticPathUsage          = "指定 static 服务 `URI` 格式的文档路径，如果未指定，则不生成相关的文档内容。"
+	FlagLSPPortUsage             = "指定 LSP 服务的端口号。"
+	FlagLSPModeUsage             = "指定 LSP 的运行方式，可以是 stdio、tcp、unix、ipc 和 udp。"
+	FlagLSPHeaderUsage           = "指定 LSP 传递内容是否带报头信息。"
+	FlagLSPTimeoutUsage          = "指定 LSP 每次读取客户端数据的超时时间，超进不会触发错误，只会再次读取。"
+	FlagVersionKindUsage         = "只显示该类型的版本号，可以是 apidoc、doc、lsp、openapi 和 all"
 
 	VersionInCompatible = "当前程序与配置文件中指定的版本号不兼容"
 	Complete            = "完成！文档保存在：%s，总用时：%v"
@@ -78,11 +108,65 @@ mock 服务会根据接口定义检测用户提交的数据是否合法，并生
 	LangExts            = "扩展名"
 	LoadAPI             = "加载 API：%s %s"
 	RequestAPI          = "访问 API：%s %s"
+	RequestCallback     = "发送回调：%s %s"
+	MockFaultInjected   = "%s %s 注入故障：%s"
+	MockFixtureRecorded = "%s %s 已录制至 %s"
+	MockReloaded        = "已重新加载文档 %s"
 	DeprecatedWarn      = "%s %s 将于 %s 被废弃"
 	GeneratorBy         = "当前文档由 %s 生成"
 	ServerStart         = "服务启动，可通过 %s 访问"
 	UnimplementedRPC    = "未实现该 RPC 服务 %s"
 	PackFileHeader      = "文档由 %s 自动生成，请勿手动修改！"
+	WatchRebuild        = "%d 个文件有变化，文档已重新生成至 %s"
+	UndocumentedAPI     = "%s %s 未在文档中定义"
+	DiffBreaking        = "[不兼容]"
+	DiffCompatible      = "[兼容]"
+	DiffNoChanges       = "文档没有变化"
+	DiffAPIAdded        = "添加了 API"
+	DiffAPIRemoved      = "删除了 API"
+	DiffParamAdded      = "添加了参数"
+	DiffParamRemoved    = "删除了参数"
+	DiffParamRequired   = "参数变为必填"
+	DiffParamOptional   = "参数变为可选"
+	DiffTypeChanged     = "类型由 %s 变为 %s"
+	DiffEnumRemoved     = "删除了枚举值 %s"
+	DiffStatusAdded     = "添加了状态码 %s"
+	DiffStatusRemoved   = "删除了状态码 %s"
+	DiffMimetypeAdded   = "添加了 mimetype %s"
+	DiffMimetypeRemoved = "删除了 mimetype %s"
+	MarkdownVersion     = "版本：%s"
+	MarkdownContact     = "联系方式"
+	MarkdownLicense     = "版权"
+	MarkdownServers     = "服务器"
+	MarkdownOthers      = "其它"
+	MarkdownPathParams  = "路径参数"
+	MarkdownQueries     = "查询参数"
+	MarkdownHeaders     = "报头"
+	MarkdownCookies     = "Cookie"
+	MarkdownBody        = "报文"
+	MarkdownRequest     = "请求"
+	MarkdownResponse    = "返回"
+	MarkdownCallback    = "回调"
+	MarkdownExample     = "示例"
+	MarkdownParam       = "参数"
+	MarkdownType        = "类型"
+	MarkdownRequired    = "必填"
+	MarkdownDescription = "描述"
+	MarkdownYes         = "是"
+	MarkdownNo          = "否"
+	MarkdownDefault     = "默认值：%s"
+	MarkdownEnums       = "可选值：%s"
+	MarkdownDeprecated  = "将于 %s 被废弃"
+	HTMLExpand          = "展开"
+	HTMLTag             = "标签"
+	HTMLMethod          = "请求方法"
+	HTMLUncategorized   = "未分类"
+	HTMLSecurity        = "安全验证"
+	HTMLScope           = "授权范围"
+	HTMLVar             = "变量"
+	HTMLValue           = "值"
+	HTMLEnums           = "枚举"
+	HTMLGotoTop         = "返回顶部"
 
 	// 文档树中各个字段的介绍
 	UsageAPIDoc              = "usage-apidoc"
@@ -97,6 +181,8 @@ mock 服务会根据接口定义检测用户提交的数据是否合法，并生
 	UsageAPIDocLicense       = "usage-apidoc-license"
 	UsageAPIDocTags          = "usage-apidoc-tags"
 	UsageAPIDocServers       = "usage-apidoc-servers"
+	UsageAPIDocTypeDefs      = "usage-apidoc-typedefs"
+	UsageAPIDocSecurities    = "usage-apidoc-securities"
 	UsageAPIDocAPIs          = "usage-apidoc-apis"
 	UsageAPIDocHeaders       = "usage-apidoc-headers"
 	UsageAPIDocResponses     = "usage-apidoc-responses"
@@ -119,8 +205,10 @@ mock 服务会根据接口定义检测用户提交的数据是否合法，并生
 	UsageAPICallback    = "usage-api-callback"
 	UsageAPIDeprecated  = "usage-api-deprecated"
 	UsageAPIHeaders     = "usage-api-headers"
+	UsageAPICookies     = "usage-api-cookies"
 	UsageAPITags        = "usage-api-tags"
 	UsageAPIServers     = "usage-api-servers"
+	UsageAPISecurities  = "usage-api-securities"
 
 	UsageLink     = "usage-link"
 	UsageLinkText = "usage-link-text"
@@ -152,18 +240,36 @@ mock 服务会根据接口定义检测用户提交的数据是否合法，并生
 	UsageExampleSummary  = "usage-example-summary"
 	UsageExampleContent  = "usage-example-content"
 
-	UsageParam            = "usage-param"
-	UsageParamName        = "usage-param-name"
-	UsageParamType        = "usage-param-type"
-	UsageParamDeprecated  = "usage-param-deprecated"
-	UsageParamDefault     = "usage-param-default"
-	UsageParamOptional    = "usage-param-optional"
-	UsageParamArray       = "usage-param-array"
-	UsageParamItems       = "usage-param-items"
-	UsageParamSummary     = "usage-param-summary"
-	UsageParamEnums       = "usage-param-enums"
-	UsageParamDescription = "usage-param-description"
-	UsageParamArrayStyle  = "usage-param-array-style"
+	UsageParam             = "usage-param"
+	UsageParamName         = "usage-param-name"
+	UsageParamType         = "usage-param-type"
+	UsageParamRef          = "usage-param-ref"
+	UsageParamDeprecated   = "usage-param-deprecated"
+	UsageParamDefault      = "usage-param-default"
+	UsageParamOptional     = "usage-param-optional"
+	UsageParamNullable     = "usage-param-nullable"
+	UsageParamReadOnly     = "usage-param-readonly"
+	UsageParamWriteOnly    = "usage-param-writeonly"
+	UsageParamArray        = "usage-param-array"
+	UsageParamItems        = "usage-param-items"
+	UsageParamSummary      = "usage-param-summary"
+	UsageParamEnums        = "usage-param-enums"
+	UsageParamDescription  = "usage-param-description"
+	UsageParamArrayStyle   = "usage-param-array-style"
+	UsageParamMin          = "usage-param-min"
+	UsageParamMax          = "usage-param-max"
+	UsageParamExclusiveMin = "usage-param-exclusive-min"
+	UsageParamExclusiveMax = "usage-param-exclusive-max"
+	UsageParamMinLength    = "usage-param-min-length"
+	UsageParamMaxLength    = "usage-param-max-length"
+	UsageParamPattern      = "usage-param-pattern"
+	UsageParamFaker        = "usage-param-faker"
+	UsageParamMinItems     = "usage-param-min-items"
+	UsageParamMaxItems     = "usage-param-max-items"
+	UsageParamUniqueItems  = "usage-param-unique-items"
+	UsageParamMinSize      = "usage-param-min-size"
+	UsageParamMaxSize      = "usage-param-max-size"
+	UsageParamAccept       = "usage-param-accept"
 
 	UsagePath        = "usage-path"
 	UsagePathPath    = "usage-path-path"
@@ -173,6 +279,7 @@ mock 服务会根据接口定义检测用户提交的数据是否合法，并生
 	UsageRequest            = "usage-request"
 	UsageRequestName        = "usage-request-name"
 	UsageRequestType        = "usage-request-type"
+	UsageRequestRef         = "usage-request-ref"
 	UsageRequestDeprecated  = "usage-request-deprecated"
 	UsageRequestArray       = "usage-request-array"
 	UsageRequestItems       = "usage-request-items"
@@ -183,6 +290,7 @@ mock 服务会根据接口定义检测用户提交的数据是否合法，并生
 	UsageRequestMimetype    = "usage-request-mimetype"
 	UsageRequestExamples    = "usage-request-examples"
 	UsageRequestHeaders     = "usage-request-headers"
+	UsageRequestCookies     = "usage-request-cookies"
 
 	UsageRichtext     = "usage-richtext"
 	UsageRichtextType = "usage-richtext-type"
@@ -201,12 +309,59 @@ mock 服务会根据接口定义检测用户提交的数据是否合法，并生
 	UsageServerSummary     = "usage-server-summary"
 	UsageServerDescription = "usage-server-description"
 
+	UsageTypeDef            = "usage-typedef"
+	UsageTypeDefName        = "usage-typedef-name"
+	UsageTypeDefType        = "usage-typedef-type"
+	UsageTypeDefDeprecated  = "usage-typedef-deprecated"
+	UsageTypeDefSummary     = "usage-typedef-summary"
+	UsageTypeDefItems       = "usage-typedef-items"
+	UsageTypeDefEnums       = "usage-typedef-enums"
+	UsageTypeDefDescription = "usage-typedef-description"
+
+	UsageSecurity                 = "usage-security"
+	UsageSecurityName             = "usage-security-name"
+	UsageSecurityType             = "usage-security-type"
+	UsageSecuritySummary          = "usage-security-summary"
+	UsageSecurityIn               = "usage-security-in"
+	UsageSecurityKey              = "usage-security-key"
+	UsageSecurityScheme           = "usage-security-scheme"
+	UsageSecurityBearerFormat     = "usage-security-bearer-format"
+	UsageSecurityOpenIDConnectURL = "usage-security-openid-connect-url"
+	UsageSecurityFlows            = "usage-security-flows"
+	UsageSecurityDescription      = "usage-security-description"
+
+	UsageFlow                 = "usage-flow"
+	UsageFlowType             = "usage-flow-type"
+	UsageFlowAuthorizationURL = "usage-flow-authorization-url"
+	UsageFlowTokenURL         = "usage-flow-token-url"
+	UsageFlowRefreshURL       = "usage-flow-refresh-url"
+	UsageFlowScopes           = "usage-flow-scopes"
+
+	UsageScope        = "usage-scope"
+	UsageScopeName    = "usage-scope-name"
+	UsageScopeSummary = "usage-scope-summary"
+
+	UsageSecurityValue       = "usage-security-value"
+	UsageSecurityValueName   = "usage-security-value-name"
+	UsageSecurityValueScopes = "usage-security-value-scopes"
+
+	UsageVariant            = "usage-variant"
+	UsageVariantValue       = "usage-variant-value"
+	UsageVariantRef         = "usage-variant-ref"
+	UsageVariantSummary     = "usage-variant-summary"
+	UsageVariantItems       = "usage-variant-items"
+	UsageVariantDescription = "usage-variant-description"
+
 	UsageXMLAttr    = "usage-xml-attr"
 	UsageXMLExtract = "usage-xml-extract"
 	UsageXMLCData   = "usage-xml-cdata"
 	UsageXMLPrefix  = "usage-xml-prefix"
 	UsageXMLWrapped = "usage-xml-wrapped"
 
+	UsageCompositeCompose       = "usage-composite-compose"
+	UsageCompositeDiscriminator = "usage-composite-discriminator"
+	UsageCompositeVariants      = "usage-composite-variants"
+
 	// 基本类型
 	UsageString  = "usage-string"
 	UsageNumber  = "usage-number"
@@ -260,6 +415,13 @@ mock 服务会根据接口定义检测用户提交的数据是否合法，并生
 	ErrInvalidURIScheme          = "无效的 URI 协议：%s"
 	ErrInvalidURI                = "无效的 URI：%s"
 	ErrFileNotFound              = "未找到文件 %s"
+	ErrUnsupportedOpenAPI        = "无法转换为 apidoc 的内容"
+	ErrCircularReference         = "存在循环引用"
+	ErrBreakingChanges           = "存在 %d 处不兼容的改动"
+	ErrMissingCredential         = "缺少访问凭证"
+	ErrOutOfRange                = "超出范围"
+	ErrReadOnlyField             = "只读字段不能出现在请求中"
+	ErrWriteOnlyField            = "只写字段不能出现在返回中"
 
 	// logs
 	InfoPrefix    = "[INFO] "
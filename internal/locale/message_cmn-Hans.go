@@ -21,43 +21,73 @@ var cmnHans = map[string]string{
 mock 服务会根据接口定义检测用户提交的数据是否合法，并生成随机的数据返回给用户。
 对于数据只作检测是否合规，但是无法理解其内容，比如提交地址中添加了 size=20，
 只会检测 20 的类型是否符合 size 的要求，但是不会只返回给用户 20 条数据。
+
+可以通过 Prefer: code=404, example=name 报头指定返回的状态码以及示例代码。
+可以通过 X-Apidoc-Fault 报头覆盖延迟与故障注入的设置。
 `,
 	CmdBuildUsage:  "生成文档内容\n",
 	CmdStaticUsage: "启用静态文件服务\n",
 	CmdLSPUsage:    "启动 language server protocol 服务\n",
+	CmdImportUsage: "将 openapi 文档转换为 apidoc 文档\n",
+	CmdDiffUsage:   "比较两个文档之间的差异\n",
+	CmdProxyUsage:  "启用契约测试的代理服务\n\n将请求转发至真实的服务，同时根据文档验证请求以及返回的内容，\n不符合文档定义的内容会以错误的形式输出，但不影响返回给客户端的内容。\n",
 	Version:        "版本：%s\n文档：%s\nLSP：%s\nopenapi：%s\nGo：%s",
 	CmdNotFound:    "子命令 %s 未找到\n",
 
-	FlagSyntaxDirUsage:         "以 `URI` 形式表示测试项目地址",
-	FlagBuildDirUsage:          "以 `URI` 形式表示的项目地址",
-	FlagMockPortUsage:          "指定 mock 服务的端口号",
-	FlagMockServersUsage:       "指定 mock 服务时，文档中 server 名对应的路由前缀。",
-	FlagMockIndentUsage:        "指定缩进内容",
-	FlagMockSliceSizeUsage:     "生成数组大小的范围，格式为 [min,max]。",
-	FlagMockNumSliceUsage:      "生成数值类型的数据时的数值范围，格式为 [min,max]。",
-	FlagMockNumFloatUsage:      "生成的数值是否允许有浮点数存在",
-	FlagMockPathUsage:          "指定文档的 `URI` 格式路径，根据此文档的内容生成 mock 数据。",
-	FlagMockStringSizeUsage:    "生成字符串类型数据时字符串的长度范围，格式为 [min,max]。",
-	FlagMockStringAlphaUsage:   "生成的字符串中允许出现的字符",
-	FlagMockUsernameSizeUsage:  "生成邮箱地址时，用户名的长度范围，格式为 [min,max]。",
-	FlagMockEmailDomainsUsage:  "生成邮箱地址时所可用的域名列表，多个用半角逗号分隔。",
-	FlagMockURLDomainsUsage:    "生成 URL 地址时所可用的域名列表，多个用半角逗号分隔。",
-	FlagMockImagePrefixUsage:   "生成图片类型数据的基地址",
-	FlagMockDateRangeUsage:     "生成可用的日期范围，格式为 [start,end]，start 和 end 均为 RFC3339 格式。",
-	FlagDetectRecursiveUsage:   "detect 子命令是否检测子目录的值",
-	FlagDetectDirUsage:         "以 `URI` 形式表示检测项目地址",
-	FlagDetectWrite:            "是否将配置内容写入文件，如果为 true，会将配置内容写入检测目录下的 .apidoc.yaml 文件。",
-	FlagStaticPortUsage:        "指定 static 服务的端口号",
-	FlagStaticDocsUsage:        "指定 static 服务静态文件所在的 `URI`",
-	FlagStaticStylesheetUsage:  "指定 static 是否只启用样式文件内容",
-	FlagStaticContentTypeUsage: "指定 static 的 content-type 值，不指定，则根据扩展名自动获取",
-	FlagStaticURLUsage:         "指定 static 服务中文档的输出地址",
-	FlagStaticPathUsage:        "指定 static 服务 `URI` 格式的文档路径，如果未指定，则不生成相关的文档内容。",
-	FlagLSPPortUsage:           "指定 LSP 服务的端口号。",
-	FlagLSPModeUsage:           "指定 LSP 的运行方式，可以是 stdio、tcp、unix 和 udp。",
-	FlagLSPHeaderUsage:         "指定 LSP 传递内容是否带报头信息",
-	FlagLSPTimeoutUsage:        "指定 LSP 每次读取客户端数据的超时时间，超时不会触发错误，只会再次读取。",
-	FlagVersionKindUsage:       "只显示该类型的版本号，可以是 apidoc、doc、lsp、openapi 和 all",
+	FlagSyntaxDirUsage:           "以 `URI` 形式表示测试项目地址",
+	FlagBuildDirUsage:            "以 `URI` 形式表示的项目地址",
+	FlagBuildWatchUsage:          "监视源码目录，在文件有变化时自动重新生成文档。",
+	FlagImportInputUsage:         "以 `URI` 形式表示的 openapi 文档路径，可以是 JSON 或是 YAML 格式。",
+	FlagImportOutputUsage:        "以 `URI` 形式表示的输出路径，为空表示输出到标准输出。",
+	FlagDiffOldUsage:             "以 `URI` 形式表示的旧文档路径",
+	FlagDiffNewUsage:             "以 `URI` 形式表示的新文档路径",
+	FlagDiffJSONUsage:            "以 JSON 格式输出比较结果",
+	FlagMockPortUsage:            "指定 mock 服务的端口号",
+	FlagMockServersUsage:         "指定 mock 服务时，文档中 server 名对应的路由前缀。",
+	FlagMockIndentUsage:          "指定缩进内容",
+	FlagMockSliceSizeUsage:       "生成数组大小的范围，格式为 [min,max]。",
+	FlagMockNumSliceUsage:        "生成数值类型的数据时的数值范围，格式为 [min,max]。",
+	FlagMockNumFloatUsage:        "生成的数值是否允许有浮点数存在",
+	FlagMockPathUsage:            "指定文档的 `URI` 格式路径，根据此文档的内容生成 mock 数据。",
+	FlagMockStringSizeUsage:      "生成字符串类型数据时字符串的长度范围，格式为 [min,max]。",
+	FlagMockStringAlphaUsage:     "生成的字符串中允许出现的字符",
+	FlagMockUsernameSizeUsage:    "生成邮箱地址时，用户名的长度范围，格式为 [min,max]。",
+	FlagMockEmailDomainsUsage:    "生成邮箱地址时所可用的域名列表，多个用半角逗号分隔。",
+	FlagMockURLDomainsUsage:      "生成 URL 地址时所可用的域名列表，多个用半角逗号分隔。",
+	FlagMockImagePrefixUsage:     "生成图片类型数据的基地址",
+	FlagMockDateRangeUsage:       "生成可用的日期范围，格式为 [start,end]，start 和 end 均为 RFC3339 格式。",
+	FlagMockStatefulUsage:        "启用有状态模式，POST、PUT、PATCH 和 DELETE 请求会修改内存中的数据，GET 请求优先返回这些数据。",
+	FlagMockExamplesUsage:        "优先使用文档中的示例代码作为返回内容，可通过报头 X-Apidoc-Example 指定示例代码的 summary。",
+	FlagMockSecurityUsage:        "验证接口要求的安全验证方案，未提供相应凭证时返回 401。",
+	FlagMockCallbackUsage:        "发送接口中定义的回调，回调地址由请求的 X-Apidoc-Callback 报头指定。",
+	FlagMockLatencyUsage:         "所有接口的延迟时间，格式为 100ms 或是 100ms-500ms，后者表示在该范围内随机。",
+	FlagMockPathLatencyUsage:     "指定接口的延迟时间，格式为 /users/{id}=100ms-500ms，多个用半角逗号分隔。",
+	FlagMockErrorRateUsage:       "返回文档中定义的错误内容的概率，取值范围为 [0,1]。",
+	FlagMockServerErrorRateUsage: "返回 500 的概率，取值范围为 [0,1]。",
+	FlagMockResetRateUsage:       "直接断开连接的概率，取值范围为 [0,1]。",
+	FlagMockBandwidthUsage:       "每秒输出的最大字节数，0 表示不限制。",
+	FlagMockFixturesUsage:        "录制内容的保存目录，存在与请求相匹配的录制内容时，优先返回该内容。",
+	FlagMockRecordUsage:          "被代理的服务地址，指定后所有请求都将转发至该地址，并将符合文档定义的请求与返回内容录制至 fixtures 指定的目录。",
+	FlagMockSeedUsage:            "生成随机数据的种子，不为 0 时，相同的请求始终返回相同的内容。",
+	FlagMockAdminUsage:           "管理接口的路由前缀，比如 /__admin__，为空表示不启用管理接口。",
+	FlagProxyPortUsage:           "指定代理服务的端口号",
+	FlagProxyPathUsage:           "指定文档的 `URI` 格式路径，根据此文档的内容验证请求和返回的内容。",
+	FlagProxyUpstreamUsage:       "指定被代理的服务地址",
+	FlagProxyServersUsage:        "指定代理服务时，文档中 server 变量对应的路由前缀",
+	FlagDetectRecursiveUsage:     "detect 子命令是否检测子目录的值",
+	FlagDetectDirUsage:           "以 `URI` 形式表示检测项目地址",
+	FlagDetectWrite:              "是否将配置内容写入文件，如果为 true，会将配置内容写入检测目录下的 .apidoc.yaml 文件。",
+	FlagStaticPortUsage:          "指定 static 服务的端口号",
+	FlagStaticDocsUsage:          "指定 static 服务静态文件所在的 `URI`",
+	FlagStaticStylesheetUsage:    "指定 static 是否只启用样式文件内容",
+	FlagStaticContentTypeUsage:   "指定 static 的 content-type 值，不指定，则根据扩展名自动获取",
+	FlagStaticURLUsage:           "指定 static 服务中文档的输出地址",
+	FlagStaticPathUsage:          "指定 static 服务 `URI` 格式的文档路径，如果未指定，则不生成相关的文档内容。",
+	FlagLSPPortUsage:             "指定 LSP 服务的端口号。",
+	FlagLSPModeUsage:             "指定 LSP 的运行方式，可以是 stdio、tcp、unix 和 udp。",
+	FlagLSPHeaderUsage:           "指定 LSP 传递内容是否带报头信息",
+	FlagLSPTimeoutUsage:          "指定 LSP 每次读取客户端数据的超时时间，超时不会触发错误，只会再次读取。",
+	FlagVersionKindUsage:         "只显示该类型的版本号，可以是 apidoc、doc、lsp、openapi 和 all",
 
 	VersionInCompatible: "当前程序与配置文件中指定的版本号不兼容",
 	Complete:            "完成！文档保存在：%s，总用时：%v",
@@ -68,11 +98,65 @@ mock 服务会根据接口定义检测用户提交的数据是否合法，并生
 	LangExts:            "扩展名",
 	LoadAPI:             "加载 API：%s %s",
 	RequestAPI:          "访问 API：%s %s",
+	RequestCallback:     "发送回调：%s %s",
+	MockFaultInjected:   "%s %s 注入故障：%s",
+	MockFixtureRecorded: "%s %s 已录制至 %s",
+	MockReloaded:        "已重新加载文档 %s",
 	DeprecatedWarn:      "%s %s 将于 %s 被废弃",
 	GeneratorBy:         "当前文档由 %s 生成",
 	ServerStart:         "服务启动，可通过 %s 访问",
 	UnimplementedRPC:    "未实现该 RPC 服务 %s",
 	PackFileHeader:      "文档由 %s 自动生成，请勿手动修改！",
+	WatchRebuild:        "%d 个文件有变化，文档已重新生成至 %s",
+	UndocumentedAPI:     "%s %s 未在文档中定义",
+	DiffBreaking:        "[不兼容]",
+	DiffCompatible:      "[兼容]",
+	DiffNoChanges:       "文档没有变化",
+	DiffAPIAdded:        "添加了 API",
+	DiffAPIRemoved:      "删除了 API",
+	DiffParamAdded:      "添加了参数",
+	DiffParamRemoved:    "删除了参数",
+	DiffParamRequired:   "参数变为必填",
+	DiffParamOptional:   "参数变为可选",
+	DiffTypeChanged:     "类型由 %s 变为 %s",
+	DiffEnumRemoved:     "删除了枚举值 %s",
+	DiffStatusAdded:     "添加了状态码 %s",
+	DiffStatusRemoved:   "删除了状态码 %s",
+	DiffMimetypeAdded:   "添加了 mimetype %s",
+	DiffMimetypeRemoved: "删除了 mimetype %s",
+	MarkdownVersion:     "版本：%s",
+	MarkdownContact:     "联系方式",
+	MarkdownLicense:     "版权",
+	MarkdownServers:     "服务器",
+	MarkdownOthers:      "其它",
+	MarkdownPathParams:  "路径参数",
+	MarkdownQueries:     "查询参数",
+	MarkdownHeaders:     "报头",
+	MarkdownCookies:     "Cookie",
+	MarkdownBody:        "报文",
+	MarkdownRequest:     "请求",
+	MarkdownResponse:    "返回",
+	MarkdownCallback:    "回调",
+	MarkdownExample:     "示例",
+	MarkdownParam:       "参数",
+	MarkdownType:        "类型",
+	MarkdownRequired:    "必填",
+	MarkdownDescription: "描述",
+	MarkdownYes:         "是",
+	MarkdownNo:          "否",
+	MarkdownDefault:     "默认值：%s",
+	MarkdownEnums:       "可选值：%s",
+	MarkdownDeprecated:  "将于 %s 被废弃",
+	HTMLExpand:          "展开",
+	HTMLTag:             "标签",
+	HTMLMethod:          "请求方法",
+	HTMLUncategorized:   "未分类",
+	HTMLSecurity:        "安全验证",
+	HTMLScope:           "授权范围",
+	HTMLVar:             "变量",
+	HTMLValue:           "值",
+	HTMLEnums:           "枚举",
+	HTMLGotoTop:         "返回顶部",
 
 	// 文档树中各个字段的介绍
 	UsageAPIDoc:              "用于描述整个文档的相关内容，只能出现一次。",
@@ -87,6 +171,8 @@ mock 服务会根据接口定义检测用户提交的数据是否合法，并生
 	UsageAPIDocLicense:       "文档的版权信息",
 	UsageAPIDocTags:          "文档中定义的所有标签",
 	UsageAPIDocServers:       "API 基地址列表，每个 API 最少应该有一个 server。",
+	UsageAPIDocTypeDefs:      "可复用的类型定义，可以在 param、request 和 response 中通过 ref 属性引用。",
+	UsageAPIDocSecurities:    "文档中可用的安全验证方案",
 	UsageAPIDocAPIs:          "文档中的 API 文档",
 	UsageAPIDocHeaders:       "文档中所有 API 都包含的公共报头",
 	UsageAPIDocResponses:     "文档中所有 API 文档都需要支持的返回内容",
@@ -109,8 +195,10 @@ mock 服务会根据接口定义检测用户提交的数据是否合法，并生
 	UsageAPICallback:    "定义回调接口内容",
 	UsageAPIDeprecated:  "在此版本之后将会被弃用",
 	UsageAPIHeaders:     "传递的报头内容，如果是某个 mimetype 专用的，可以放在 request 元素中。",
+	UsageAPICookies:     "传递的 cookie 内容，如果是某个 mimetype 专用的，可以放在 request 元素中。",
 	UsageAPITags:        "关联的标签",
 	UsageAPIServers:     "关联的服务",
+	UsageAPISecurities:  "访问该接口需要满足的安全验证方案，满足其中之一即可。",
 
 	UsageLink:     "用于描述链接信息，一般转换为 HTML 的 <code>a</code> 标签。",
 	UsageLinkText: "链接的字面文字",
@@ -142,18 +230,36 @@ mock 服务会根据接口定义检测用户提交的数据是否合法，并生
 	UsageExampleSummary:  "示例代码的概要信息",
 	UsageExampleContent:  "示例代码的内容，需要使用 CDATA 包含代码。",
 
-	UsageParam:            "参数类型，基本上可以作为 request 的子集使用。",
-	UsageParamName:        "值的名称",
-	UsageParamType:        "值的类型",
-	UsageParamDeprecated:  "表示在大于等于该版本号时不再启作用",
-	UsageParamDefault:     "默认值",
-	UsageParamOptional:    "是否为可选的参数",
-	UsageParamArray:       "是否为数组",
-	UsageParamItems:       "子类型，比如对象的子元素。",
-	UsageParamSummary:     "简要介绍",
-	UsageParamEnums:       "当前参数可用的枚举值",
-	UsageParamDescription: "详细介绍，为 HTML 内容。",
-	UsageParamArrayStyle:  "以数组的方式展示数据",
+	UsageParam:             "参数类型，基本上可以作为 request 的子集使用。",
+	UsageParamName:         "值的名称",
+	UsageParamType:         "值的类型",
+	UsageParamRef:          "引用的类型定义名称，指定该值之后，类型、子元素以及枚举值都将采用类型定义中的值。",
+	UsageParamDeprecated:   "表示在大于等于该版本号时不再启作用",
+	UsageParamDefault:      "默认值",
+	UsageParamOptional:     "是否为可选的参数",
+	UsageParamNullable:     "值是否可以为 null",
+	UsageParamReadOnly:     "是否为只读的字段，只读字段仅出现在返回内容中，不能出现在请求中。",
+	UsageParamWriteOnly:    "是否为只写的字段，只写字段仅出现在请求内容中，不能出现在返回中。",
+	UsageParamArray:        "是否为数组",
+	UsageParamItems:        "子类型，比如对象的子元素。",
+	UsageParamSummary:      "简要介绍",
+	UsageParamEnums:        "当前参数可用的枚举值",
+	UsageParamDescription:  "详细介绍，为 HTML 内容。",
+	UsageParamArrayStyle:   "以数组的方式展示数据",
+	UsageParamMin:          "数值的最小值，仅对 number 类型有效。",
+	UsageParamMax:          "数值的最大值，仅对 number 类型有效。",
+	UsageParamExclusiveMin: "是否不包含 min 本身，需要同时指定 min。",
+	UsageParamExclusiveMax: "是否不包含 max 本身，需要同时指定 max。",
+	UsageParamMinLength:    "字符串的最小长度，以字符为单位，仅对 string 类型有效。",
+	UsageParamMaxLength:    "字符串的最大长度，以字符为单位，仅对 string 类型有效。",
+	UsageParamPattern:      "字符串需要匹配的正则表达式，仅对 string 类型有效。",
+	UsageParamFaker:        "生成模拟数据时采用的 faker，仅对 string 类型有效，可以是 name、phone、address、uuid、ip、country、currency、word 和 sentence，none 表示不采用 faker。未指定时会根据参数名称自动选择。",
+	UsageParamMinItems:     "数组的最小元素数量，仅在 array 为 true 时有效。",
+	UsageParamMaxItems:     "数组的最大元素数量，仅在 array 为 true 时有效。",
+	UsageParamUniqueItems:  "数组中的元素是否不能重复，仅在 array 为 true 时有效。",
+	UsageParamMinSize:      "文件的最小字节数，仅对 file 类型有效。",
+	UsageParamMaxSize:      "文件的最大字节数，仅对 file 类型有效。",
+	UsageParamAccept:       "文件允许的 mimetype，多个值以逗号分隔，可以使用 <var>image/*</var> 的形式匹配同一类的所有类型，仅对 file 类型有效。",
 
 	UsagePath:        "用于定义请求时与路径相关的内容",
 	UsagePathPath:    "接口地址",
@@ -163,6 +269,7 @@ mock 服务会根据接口定义检测用户提交的数据是否合法，并生
 	UsageRequest:            "定义了请求和返回的相关内容",
 	UsageRequestName:        "当 mimetype 为 <var>application/xml</var> 时，此值表示 XML 的顶层元素名称，否则无用。",
 	UsageRequestType:        "值的类型",
+	UsageRequestRef:         "引用的类型定义名称，指定该值之后，类型、子元素以及枚举值都将采用类型定义中的值。",
 	UsageRequestDeprecated:  "表示在大于等于该版本号时不再启作用",
 	UsageRequestArray:       "是否为数组",
 	UsageRequestItems:       "子类型，比如对象的子元素。",
@@ -173,6 +280,7 @@ mock 服务会根据接口定义检测用户提交的数据是否合法，并生
 	UsageRequestMimetype:    "媒体类型，比如 <var>application/json</var> 等。",
 	UsageRequestExamples:    "示例代码",
 	UsageRequestHeaders:     "传递的报头内容",
+	UsageRequestCookies:     "传递的 cookie 内容，在 response 中表示通过 Set-Cookie 返回的内容",
 
 	UsageRichtext:     "富文本内容",
 	UsageRichtextType: "指定富文本内容的格式，目前支持 <var>html</var> 和 <var>markdown</var>。",
@@ -191,6 +299,49 @@ mock 服务会根据接口定义检测用户提交的数据是否合法，并生
 	UsageServerSummary:     "服务的摘要信息",
 	UsageServerDescription: "服务的详细描述",
 
+	UsageTypeDef:            "用于定义可复用的类型，可以被 param、request 和 response 通过 ref 属性引用。类型之间不能存在循环引用。",
+	UsageTypeDefName:        "类型的唯一名称，ref 属性通过此值引用该类型。",
+	UsageTypeDefType:        "值的类型",
+	UsageTypeDefDeprecated:  "表示在大于等于该版本号时不再启作用",
+	UsageTypeDefSummary:     "类型的简要描述",
+	UsageTypeDefItems:       "子类型，比如对象的子元素。",
+	UsageTypeDefEnums:       "当前类型可用的枚举值",
+	UsageTypeDefDescription: "类型的详细描述",
+
+	UsageSecurity:                 "定义安全验证方案",
+	UsageSecurityName:             "方案的唯一名称，api 中的 security 通过此值引用该方案。",
+	UsageSecurityType:             "方案的类型，可以是 <var>apikey</var>、<var>http</var>、<var>oauth2</var> 和 <var>openid-connect</var>。",
+	UsageSecuritySummary:          "方案的简要描述",
+	UsageSecurityIn:               "凭证所在的位置，可以是 <var>header</var>、<var>query</var> 和 <var>cookie</var>，仅对 <var>apikey</var> 有效。",
+	UsageSecurityKey:              "凭证的参数名称，仅对 <var>apikey</var> 有效。",
+	UsageSecurityScheme:           "报头 Authorization 中的验证方式，比如 <var>basic</var> 和 <var>bearer</var>，仅对 <var>http</var> 有效。",
+	UsageSecurityBearerFormat:     "bearer 凭证的格式，比如 <var>JWT</var>，仅对 <var>http</var> 有效。",
+	UsageSecurityOpenIDConnectURL: "OpenID Connect 的配置地址，仅对 <var>openid-connect</var> 有效。",
+	UsageSecurityFlows:            "OAuth2 的授权方式，仅对 <var>oauth2</var> 有效。",
+	UsageSecurityDescription:      "方案的详细描述",
+
+	UsageFlow:                 "OAuth2 的授权方式",
+	UsageFlowType:             "授权方式的类型，可以是 <var>implicit</var>、<var>password</var>、<var>client-credentials</var> 和 <var>authorization-code</var>。",
+	UsageFlowAuthorizationURL: "授权地址，<var>implicit</var> 和 <var>authorization-code</var> 必须指定。",
+	UsageFlowTokenURL:         "获取令牌的地址，<var>password</var>、<var>client-credentials</var> 和 <var>authorization-code</var> 必须指定。",
+	UsageFlowRefreshURL:       "刷新令牌的地址",
+	UsageFlowScopes:           "可用的授权范围",
+
+	UsageScope:        "OAuth2 的授权范围",
+	UsageScopeName:    "授权范围的名称",
+	UsageScopeSummary: "授权范围的简要描述",
+
+	UsageSecurityValue:       "引用文档中定义的安全验证方案",
+	UsageSecurityValueName:   "安全验证方案的名称",
+	UsageSecurityValueScopes: "需要的授权范围，仅对 <var>oauth2</var> 和 <var>openid-connect</var> 有效。",
+
+	UsageVariant:            "复合类型中的变体，表示对象可能的一种结构。变体的子元素会与父元素中的子元素合并。",
+	UsageVariantValue:       "在指定了 discriminator 时，用于区分变体的值，需要唯一。",
+	UsageVariantRef:         "引用 typedef 定义的对象作为变体的内容，不能与子元素同时使用。",
+	UsageVariantSummary:     "变体的简要描述",
+	UsageVariantItems:       "变体的子元素",
+	UsageVariantDescription: "变体的详细描述",
+
 	UsageXMLAttr:    "是否作为父元素的属性，仅作用于 XML 元素。是否作为父元素的属性，仅用于 XML 的请求。",
 	UsageXMLExtract: "将当前元素的内容作为父元素的内容，要求父元素必须为 <var>object</var>。",
 	UsageXMLCData:   "当前内容为 CDATA，与 <code>@xml-attr</code> 互斥。",
@@ -202,6 +353,10 @@ mock 服务会根据接口定义检测用户提交的数据是否合法，并生
 	<li><samp>&gt;name</samp>：表示将当前数组元素的名称改为 <var>name</var>；</li>
 	</ul>`,
 
+	UsageCompositeCompose:       "变体的组合方式，可以是 one-of、any-of 或是 all-of，默认为 one-of。",
+	UsageCompositeDiscriminator: "用于区分变体的子元素名称，该子元素需要在当前对象中声明，且其值与变体的 value 相对应。不能用于 all-of。",
+	UsageCompositeVariants:      "对象的变体列表，指定之后，当前对象的子元素为所有变体共有的内容。仅对 object 类型有效。",
+
 	// 基本类型
 	UsageString:  "普通的字符串类型，特殊字符需要使用 XML 实体，比如 <samp>&lt;</samp> 需要使用 <samp>&amp;lt;</samp> 代替。",
 	UsageNumber:  "普通的数值类型，比如：<samp>1</samp>、<samp>-11.1</samp> 等。",
@@ -213,6 +368,7 @@ mock 服务会根据接口定义检测用户提交的数据是否合法，并生
 	<li>空值；</li>
 	<li><var>bool</var> 布尔值；</li>
 	<li><var>object</var> 对象；</li>
+	<li><var>map</var> 键名为字符串的字典，值的类型由子元素描述；也可以通过 <var>map.string</var> 等形式指定值的类型，子类型不能为 <var>object</var> 和 <var>map</var>；</li>
 	<li><var>number</var> 数值类型；</li>
 	<li><var>number.int</var> 整数类型的数值；</li>
 	<li><var>number.float</var> 浮点类型的数值；</li>
@@ -223,6 +379,7 @@ mock 服务会根据接口定义检测用户提交的数据是否合法，并生
 	<li><var>string.date</var> 表示 <a href="https://tools.ietf.org/html/rfc3339#section-5.6">RFC3339</a> 中的 <code>full-date</code> 日期格式，比如 <samp>2020-01-02</samp>；</li>
 	<li><var>string.time</var> 表示 <a href="https://tools.ietf.org/html/rfc3339#section-5.6">RFC3339</a> 中的 <code>full-time</code> 时间格式，比如 <samp>15:16:17Z</samp>、<samp>15:16:17+08:00</samp>；</li>
 	<li><var>string.date-time</var> 表示 <a href="https://tools.ietf.org/html/rfc3339#section-5.6">RFC3339</a> 中的 <code>date-time</code> 格式，比如 <samp>2020-01-02T15:16:17-08:00</samp>；</li>
+	<li><var>file</var> 文件，一般用于 <code>multipart/form-data</code> 格式的请求，在 JSON 和 XML 中以 base64 编码的字符串表示；</li>
 	</ul>`,
 
 	// 以下是有关 build.Config 的字段说明
@@ -235,7 +392,7 @@ mock 服务会根据接口定义检测用户提交的数据是否合法，并生
 	UsageConfigInputsEncoding:        `编码，默认为 <var>utf-8</var>，值可以是 <a href="https://www.iana.org/assignments/character-sets/character-sets.xhtml">character-sets</a> 中的内容。`,
 	UsageConfigInputsIgnores:         "忽略的文件或目录，比如 node_modules 等。",
 	UsageConfigOutput:                "控制输出行为",
-	UsageConfigOutputType:            "输出的类型，目前可以 <var>apidoc+xml</var>、<var>openapi+json</var> 和 <var>openapi+yaml</var>。",
+	UsageConfigOutputType:            "输出的类型，目前可以 <var>apidoc+xml</var>、<var>openapi+json</var>、<var>openapi+yaml</var>、<var>postman+json</var>、<var>markdown</var> 和 <var>html</var>。",
 	UsageConfigOutputPath:            "指定输出的文件名，包含路径信息。",
 	UsageConfigOutputTags:            "只输出与这些标签相关联的文档，默认为全部。",
 	UsageConfigOutputStyle:           "为 XML 文件指定的 XSL 文件",
@@ -270,6 +427,13 @@ mock 服务会根据接口定义检测用户提交的数据是否合法，并生
 	ErrInvalidURIScheme:          "无效的 URI 协议：%s",
 	ErrInvalidURI:                "无效的 URI：%s",
 	ErrFileNotFound:              "未找到文件 %s",
+	ErrUnsupportedOpenAPI:        "无法转换为 apidoc 的内容",
+	ErrCircularReference:         "存在循环引用",
+	ErrBreakingChanges:           "存在 %d 处不兼容的改动",
+	ErrMissingCredential:         "缺少访问凭证",
+	ErrOutOfRange:                "超出范围",
+	ErrReadOnlyField:             "只读字段不能出现在请求中",
+	ErrWriteOnlyField:            "只写字段不能出现在返回中",
 
 	// logs
 	InfoPrefix:    "[信息] ",
@@ -21,43 +21,73 @@ var cmnHant = map[string]string{
 mock 服務會根據接口定義檢測用戶提交的數據是否合法，並生成隨機的數據返回給用戶。
 對於數據只作檢測是否合規，但是無法理解其內容，比如提交地址中添加了 size=20，
 只會檢測 20 的類型是否符合 size 的要求，但是不會只返回給用戶 20 條數據。
+
+可以通過 Prefer: code=404, example=name 報頭指定返回的狀態碼以及示例代碼。
+可以通過 X-Apidoc-Fault 報頭覆蓋延遲與故障註入的設置。
 `,
 	CmdBuildUsage:  "生成文檔內容\n",
 	CmdStaticUsage: "啟用靜態文件服務\n",
 	CmdLSPUsage:    "啟動 language server protocol 服務\n",
+	CmdImportUsage: "將 openapi 文檔轉換為 apidoc 文檔\n",
+	CmdDiffUsage:   "比較兩個文檔之間的差異\n",
+	CmdProxyUsage:  "啟用契約測試的代理服務\n\n將請求轉發至真實的服務，同時根據文檔驗證請求以及返回的內容，\n不符合文檔定義的內容會以錯誤的形式輸出，但不影響返回給客戶端的內容。\n",
 	Version:        "版本：%s\n文檔：%s\nLSP：%s\nopenapi：%s\nGo：%s",
 	CmdNotFound:    "子命令 %s 未找到\n",
 
-	FlagSyntaxDirUsage:         "以 `URI` 形式表示的測試項目地址",
-	FlagBuildDirUsage:          "以 `URI` 形式表示的項目地址",
-	FlagMockPortUsage:          "指定 mock 服務的端口號",
-	FlagMockServersUsage:       "指定 mock 服務時，文檔中 server 名對應的路由前綴。",
-	FlagMockIndentUsage:        "指定縮進內容",
-	FlagMockSliceSizeUsage:     "生成數組大小的範圍，格式為 [min,max]。",
-	FlagMockNumSliceUsage:      "生成數值類型的數據時的數值範圍，格式為 [min,max]。",
-	FlagMockNumFloatUsage:      "生成的數值是否允許有浮點數存在",
-	FlagMockPathUsage:          "指定文檔的 `URI` 格式路徑，根據此文檔的內容生成 mock 數據。",
-	FlagMockStringSizeUsage:    "生成字符串類型數據時字符串的長度範圍，格式為 [min,max]。",
-	FlagMockStringAlphaUsage:   "生成的字符串中允許出現的字符",
-	FlagMockUsernameSizeUsage:  "生成郵箱地址時，用戶名的長度範圍，格式為 [min,max]。",
-	FlagMockEmailDomainsUsage:  "生成郵箱地址時所可用的域名列表，多個用半角逗號分隔。",
-	FlagMockURLDomainsUsage:    "生成 URL 地址時所可用的域名列表，多個用半角逗號分隔。",
-	FlagMockImagePrefixUsage:   "生成圖片類型數據的基地址",
-	FlagMockDateRangeUsage:     "生成可用的日期範圍，格式為 [start,end]，start 和 end 均為 RFC3339 格式。",
-	FlagDetectRecursiveUsage:   "detect 子命令是否檢測子目錄的值",
-	FlagDetectDirUsage:         "以 `URI` 形式表示的檢測項目地址",
-	FlagDetectWrite:            "是否將配置內容寫入文件，如果為 true，會將配置內容寫入檢測目錄下的 .apidoc.yaml 文件。",
-	FlagStaticPortUsage:        "指定 static 服務的端口號",
-	FlagStaticDocsUsage:        "指定 static 服務靜態文件所在的 `URI`",
-	FlagStaticStylesheetUsage:  "指定 static 是否只啟用樣式文件內容",
-	FlagStaticContentTypeUsage: "指定 static 的 content-type 值，不指定，則根據擴展名自動獲取",
-	FlagStaticURLUsage:         "指定 static 服務中文檔的輸出地址",
-	FlagStaticPathUsage:        "指定 static 服務 `URI` 格式的文檔路徑，如果未指定，則不生成相關的文檔內容。",
-	FlagLSPPortUsage:           "指定 LSP 服務的端口號。",
-	FlagLSPModeUsage:           "指定 LSP 的運行方式，可以是 stdio、tcp、unix 和 udp。",
-	FlagLSPHeaderUsage:         "指定 LSP 傳遞內容是否帶報頭信息。",
-	FlagLSPTimeoutUsage:        "指定 LSP 每次讀取客戶端數據的超時時間，超時不會觸發錯誤，只會再次讀取。",
-	FlagVersionKindUsage:       "只顯示該類型的版本號，可以是 apidoc、doc、lsp、openapi 和 all",
+	FlagSyntaxDirUsage:           "以 `URI` 形式表示的測試項目地址",
+	FlagBuildDirUsage:            "以 `URI` 形式表示的項目地址",
+	FlagBuildWatchUsage:          "監視源碼目錄，在文件有變化時自動重新生成文檔。",
+	FlagImportInputUsage:         "以 `URI` 形式表示的 openapi 文檔路徑，可以是 JSON 或是 YAML 格式。",
+	FlagImportOutputUsage:        "以 `URI` 形式表示的輸出路徑，為空表示輸出到標準輸出。",
+	FlagDiffOldUsage:             "以 `URI` 形式表示的舊文檔路徑",
+	FlagDiffNewUsage:             "以 `URI` 形式表示的新文檔路徑",
+	FlagDiffJSONUsage:            "以 JSON 格式輸出比較結果",
+	FlagMockPortUsage:            "指定 mock 服務的端口號",
+	FlagMockServersUsage:         "指定 mock 服務時，文檔中 server 名對應的路由前綴。",
+	FlagMockIndentUsage:          "指定縮進內容",
+	FlagMockSliceSizeUsage:       "生成數組大小的範圍，格式為 [min,max]。",
+	FlagMockNumSliceUsage:        "生成數值類型的數據時的數值範圍，格式為 [min,max]。",
+	FlagMockNumFloatUsage:        "生成的數值是否允許有浮點數存在",
+	FlagMockPathUsage:            "指定文檔的 `URI` 格式路徑，根據此文檔的內容生成 mock 數據。",
+	FlagMockStringSizeUsage:      "生成字符串類型數據時字符串的長度範圍，格式為 [min,max]。",
+	FlagMockStringAlphaUsage:     "生成的字符串中允許出現的字符",
+	FlagMockUsernameSizeUsage:    "生成郵箱地址時，用戶名的長度範圍，格式為 [min,max]。",
+	FlagMockEmailDomainsUsage:    "生成郵箱地址時所可用的域名列表，多個用半角逗號分隔。",
+	FlagMockURLDomainsUsage:      "生成 URL 地址時所可用的域名列表，多個用半角逗號分隔。",
+	FlagMockImagePrefixUsage:     "生成圖片類型數據的基地址",
+	FlagMockDateRangeUsage:       "生成可用的日期範圍，格式為 [start,end]，start 和 end 均為 RFC3339 格式。",
+	FlagMockStatefulUsage:        "啟用有狀態模式，POST、PUT、PATCH 和 DELETE 請求會修改內存中的數據，GET 請求優先返回這些數據。",
+	FlagMockExamplesUsage:        "優先使用文檔中的示例代碼作為返回內容，可通過報頭 X-Apidoc-Example 指定示例代碼的 summary。",
+	FlagMockSecurityUsage:        "驗證接口要求的安全驗證方案，未提供相應憑證時返回 401。",
+	FlagMockCallbackUsage:        "發送接口中定義的回調，回調地址由請求的 X-Apidoc-Callback 報頭指定。",
+	FlagMockLatencyUsage:         "所有接口的延遲時間，格式為 100ms 或是 100ms-500ms，後者表示在該範圍內隨機。",
+	FlagMockPathLatencyUsage:     "指定接口的延遲時間，格式為 /users/{id}=100ms-500ms，多個用半角逗號分隔。",
+	FlagMockErrorRateUsage:       "返回文檔中定義的錯誤內容的概率，取值範圍為 [0,1]。",
+	FlagMockServerErrorRateUsage: "返回 500 的概率，取值範圍為 [0,1]。",
+	FlagMockResetRateUsage:       "直接斷開連接的概率，取值範圍為 [0,1]。",
+	FlagMockBandwidthUsage:       "每秒輸出的最大字節數，0 表示不限制。",
+	FlagMockFixturesUsage:        "錄製內容的保存目錄，存在與請求相匹配的錄製內容時，優先返回該內容。",
+	FlagMockRecordUsage:          "被代理的服務地址，指定後所有請求都將轉發至該地址，並將符合文檔定義的請求與返回內容錄製至 fixtures 指定的目錄。",
+	FlagMockSeedUsage:            "生成隨機數據的種子，不為 0 時，相同的請求始終返回相同的內容。",
+	FlagMockAdminUsage:           "管理接口的路由前綴，比如 /__admin__，為空表示不啟用管理接口。",
+	FlagProxyPortUsage:           "指定代理服務的端口號",
+	FlagProxyPathUsage:           "指定文檔的 `URI` 格式路徑，根據此文檔的內容驗證請求和返回的內容。",
+	FlagProxyUpstreamUsage:       "指定被代理的服務地址",
+	FlagProxyServersUsage:        "指定代理服務時，文檔中 server 變量對應的路由前綴",
+	FlagDetectRecursiveUsage:     "detect 子命令是否檢測子目錄的值",
+	FlagDetectDirUsage:           "以 `URI` 形式表示的檢測項目地址",
+	FlagDetectWrite:              "是否將配置內容寫入文件，如果為 true，會將配置內容寫入檢測目錄下的 .apidoc.yaml 文件。",
+	FlagStaticPortUsage:          "指定 static 服務的端口號",
+	FlagStaticDocsUsage:          "指定 static 服務靜態文件所在的 `URI`",
+	FlagStaticStylesheetUsage:    "指定 static 是否只啟用樣式文件內容",
+	FlagStaticContentTypeUsage:   "指定 static 的 content-type 值，不指定，則根據擴展名自動獲取",
+	FlagStaticURLUsage:           "指定 static 服務中文檔的輸出地址",
+	FlagStaticPathUsage:          "指定 static 服務 `URI` 格式的文檔路徑，如果未指定，則不生成相關的文檔內容。",
+	FlagLSPPortUsage:             "指定 LSP 服務的端口號。",
+	FlagLSPModeUsage:             "指定 LSP 的運行方式，可以是 stdio、tcp、unix 和 udp。",
+	FlagLSPHeaderUsage:           "指定 LSP 傳遞內容是否帶報頭信息。",
+	FlagLSPTimeoutUsage:          "指定 LSP 每次讀取客戶端數據的超時時間，超時不會觸發錯誤，只會再次讀取。",
+	FlagVersionKindUsage:         "只顯示該類型的版本號，可以是 apidoc、doc、lsp、openapi 和 all",
 
 	VersionInCompatible: "當前程序與配置文件中指定的版本號不兼容",
 	Complete:            "完成！文檔保存在：%s，總用時：%v",
@@ -68,11 +98,65 @@ mock 服務會根據接口定義檢測用戶提交的數據是否合法，並生
 	LangExts:            "擴展名",
 	LoadAPI:             "加載 API：%s %s",
 	RequestAPI:          "訪問 API：%s %s",
+	RequestCallback:     "發送回調：%s %s",
+	MockFaultInjected:   "%s %s 註入故障：%s",
+	MockFixtureRecorded: "%s %s 已錄製至 %s",
+	MockReloaded:        "已重新加載文檔 %s",
 	DeprecatedWarn:      "%s %s 將於 %s 被廢棄",
 	GeneratorBy:         "當前文檔由 %s 生成",
 	ServerStart:         "服務啟動，可通過 %s 訪問",
 	UnimplementedRPC:    "未實現該 RPC 服務 %s",
 	PackFileHeader:      "文檔由 %s 自動生成，請勿手動修改！",
+	WatchRebuild:        "%d 個文件有變化，文檔已重新生成至 %s",
+	UndocumentedAPI:     "%s %s 未在文檔中定義",
+	DiffBreaking:        "[不兼容]",
+	DiffCompatible:      "[兼容]",
+	DiffNoChanges:       "文檔沒有變化",
+	DiffAPIAdded:        "添加了 API",
+	DiffAPIRemoved:      "刪除了 API",
+	DiffParamAdded:      "添加了參數",
+	DiffParamRemoved:    "刪除了參數",
+	DiffParamRequired:   "參數變為必填",
+	DiffParamOptional:   "參數變為可選",
+	DiffTypeChanged:     "類型由 %s 變為 %s",
+	DiffEnumRemoved:     "刪除了枚舉值 %s",
+	DiffStatusAdded:     "添加了狀態碼 %s",
+	DiffStatusRemoved:   "刪除了狀態碼 %s",
+	DiffMimetypeAdded:   "添加了 mimetype %s",
+	DiffMimetypeRemoved: "刪除了 mimetype %s",
+	MarkdownVersion:     "版本：%s",
+	MarkdownContact:     "聯繫方式",
+	MarkdownLicense:     "版權",
+	MarkdownServers:     "服務器",
+	MarkdownOthers:      "其它",
+	MarkdownPathParams:  "路徑參數",
+	MarkdownQueries:     "查詢參數",
+	MarkdownHeaders:     "報頭",
+	MarkdownCookies:     "Cookie",
+	MarkdownBody:        "報文",
+	MarkdownRequest:     "請求",
+	MarkdownResponse:    "返回",
+	MarkdownCallback:    "回調",
+	MarkdownExample:     "示例",
+	MarkdownParam:       "參數",
+	MarkdownType:        "類型",
+	MarkdownRequired:    "必填",
+	MarkdownDescription: "描述",
+	MarkdownYes:         "是",
+	MarkdownNo:          "否",
+	MarkdownDefault:     "默認值：%s",
+	MarkdownEnums:       "可選值：%s",
+	MarkdownDeprecated:  "將於 %s 被廢棄",
+	HTMLExpand:          "展開",
+	HTMLTag:             "標簽",
+	HTMLMethod:          "請求方法",
+	HTMLUncategorized:   "未分類",
+	HTMLSecurity:        "安全驗證",
+	HTMLScope:           "授權範圍",
+	HTMLVar:             "變量",
+	HTMLValue:           "值",
+	HTMLEnums:           "枚舉",
+	HTMLGotoTop:         "返回頂部",
 
 	// 文檔樹中各個字段的介紹
 	UsageAPIDoc:              "用於描述整個文檔的相關內容，只能出現壹次。",
@@ -87,6 +171,8 @@ mock 服務會根據接口定義檢測用戶提交的數據是否合法，並生
 	UsageAPIDocLicense:       "文檔的版權信息",
 	UsageAPIDocTags:          "文檔中定義的所有標簽",
 	UsageAPIDocServers:       "API 基地址列表，每個 API 最少應該有壹個 server。",
+	UsageAPIDocTypeDefs:      "可復用的類型定義，可以在 param、request 和 response 中通過 ref 屬性引用。",
+	UsageAPIDocSecurities:    "文檔中可用的安全驗證方案",
 	UsageAPIDocAPIs:          "文檔中的 API 文檔",
 	UsageAPIDocHeaders:       "文檔中所有 API 都包含的公共報頭",
 	UsageAPIDocResponses:     "文檔中所有 API 文檔都需要支持的返回內容",
@@ -109,8 +195,10 @@ mock 服務會根據接口定義檢測用戶提交的數據是否合法，並生
 	UsageAPICallback:    "定義回調接口內容",
 	UsageAPIDeprecated:  "在此版本之後將會被棄用",
 	UsageAPIHeaders:     "傳遞的報頭內容，如果是某個 mimetype 專用的，可以放在 request 元素中。",
+	UsageAPICookies:     "傳遞的 cookie 內容，如果是某個 mimetype 專用的，可以放在 request 元素中。",
 	UsageAPITags:        "關聯的標簽",
 	UsageAPIServers:     "關聯的服務",
+	UsageAPISecurities:  "訪問該接口需要滿足的安全驗證方案，滿足其中之壹即可。",
 
 	UsageLink:     "用於描述鏈接信息，壹般轉換為 HTML 的 <code>a</code> 標簽。",
 	UsageLinkText: "鏈接的字面文字",
@@ -142,18 +230,36 @@ mock 服務會根據接口定義檢測用戶提交的數據是否合法，並生
 	UsageExampleSummary:  "示例代碼的概要信息",
 	UsageExampleContent:  "示例代碼的內容，需要使用 CDATA 包含代碼。",
 
-	UsageParam:            "參數類型，基本上可以作為 request 的子集使用。",
-	UsageParamName:        "值的名稱",
-	UsageParamType:        "值的類型",
-	UsageParamDeprecated:  "表示在大於等於該版本號時不再啟作用",
-	UsageParamDefault:     "默認值",
-	UsageParamOptional:    "是否為可選的參數",
-	UsageParamArray:       "是否為數組",
-	UsageParamItems:       "子類型，比如對象的子元素。",
-	UsageParamSummary:     "簡要介紹",
-	UsageParamEnums:       "當前參數可用的枚舉值",
-	UsageParamDescription: "詳細介紹，為 HTML 內容。",
-	UsageParamArrayStyle:  "以數組的方式展示數據",
+	UsageParam:             "參數類型，基本上可以作為 request 的子集使用。",
+	UsageParamName:         "值的名稱",
+	UsageParamType:         "值的類型",
+	UsageParamRef:          "引用的類型定義名稱，指定該值之後，類型、子元素以及枚舉值都將采用類型定義中的值。",
+	UsageParamDeprecated:   "表示在大於等於該版本號時不再啟作用",
+	UsageParamDefault:      "默認值",
+	UsageParamOptional:     "是否為可選的參數",
+	UsageParamNullable:     "值是否可以為 null",
+	UsageParamReadOnly:     "是否為只讀的字段，只讀字段僅出現在返回內容中，不能出現在請求中。",
+	UsageParamWriteOnly:    "是否為只寫的字段，只寫字段僅出現在請求內容中，不能出現在返回中。",
+	UsageParamArray:        "是否為數組",
+	UsageParamItems:        "子類型，比如對象的子元素。",
+	UsageParamSummary:      "簡要介紹",
+	UsageParamEnums:        "當前參數可用的枚舉值",
+	UsageParamDescription:  "詳細介紹，為 HTML 內容。",
+	UsageParamArrayStyle:   "以數組的方式展示數據",
+	UsageParamMin:          "數值的最小值，僅對 number 類型有效。",
+	UsageParamMax:          "數值的最大值，僅對 number 類型有效。",
+	UsageParamExclusiveMin: "是否不包含 min 本身，需要同時指定 min。",
+	UsageParamExclusiveMax: "是否不包含 max 本身，需要同時指定 max。",
+	UsageParamMinLength:    "字符串的最小長度，以字符為單位，僅對 string 類型有效。",
+	UsageParamMaxLength:    "字符串的最大長度，以字符為單位，僅對 string 類型有效。",
+	UsageParamPattern:      "字符串需要匹配的正則表達式，僅對 string 類型有效。",
+	UsageParamFaker:        "生成模擬數據時采用的 faker，僅對 string 類型有效，可以是 name、phone、address、uuid、ip、country、currency、word 和 sentence，none 表示不采用 faker。未指定時會根據參數名稱自動選擇。",
+	UsageParamMinItems:     "數組的最小元素數量，僅在 array 為 true 時有效。",
+	UsageParamMaxItems:     "數組的最大元素數量，僅在 array 為 true 時有效。",
+	UsageParamUniqueItems:  "數組中的元素是否不能重復，僅在 array 為 true 時有效。",
+	UsageParamMinSize:      "文件的最小字節數，僅對 file 類型有效。",
+	UsageParamMaxSize:      "文件的最大字節數，僅對 file 類型有效。",
+	UsageParamAccept:       "文件允許的 mimetype，多個值以逗號分隔，可以使用 <var>image/*</var> 的形式匹配同一類的所有類型，僅對 file 類型有效。",
 
 	UsagePath:        "用於定義請求時與路徑相關的內容",
 	UsagePathPath:    "接口地址",
@@ -163,6 +269,7 @@ mock 服務會根據接口定義檢測用戶提交的數據是否合法，並生
 	UsageRequest:            "定義了請求和返回的相關內容",
 	UsageRequestName:        "當 mimetype 為 <var>application/xml</var> 時，此值表示 XML 的頂層元素名稱，否則無用。",
 	UsageRequestType:        "值的類型",
+	UsageRequestRef:         "引用的類型定義名稱，指定該值之後，類型、子元素以及枚舉值都將采用類型定義中的值。",
 	UsageRequestDeprecated:  "表示在大於等於該版本號時不再啟作用",
 	UsageRequestArray:       "是否為數組",
 	UsageRequestItems:       "子類型，比如對象的子元素。",
@@ -173,6 +280,7 @@ mock 服務會根據接口定義檢測用戶提交的數據是否合法，並生
 	UsageRequestMimetype:    "媒體類型，比如 <var>application/json</var> 等。",
 	UsageRequestExamples:    "示例代碼",
 	UsageRequestHeaders:     "傳遞的報頭內容",
+	UsageRequestCookies:     "傳遞的 cookie 內容，在 response 中表示通過 Set-Cookie 返回的內容",
 
 	UsageRichtext:     "富文本內容",
 	UsageRichtextType: "指定富文本內容的格式，目前支持 <var>html</var> 和 <var>markdown</var>。",
@@ -191,6 +299,49 @@ mock 服務會根據接口定義檢測用戶提交的數據是否合法，並生
 	UsageServerSummary:     "服務的摘要信息",
 	UsageServerDescription: "服務的詳細描述",
 
+	UsageTypeDef:            "用於定義可復用的類型，可以被 param、request 和 response 通過 ref 屬性引用。類型之間不能存在循環引用。",
+	UsageTypeDefName:        "類型的唯壹名稱，ref 屬性通過此值引用該類型。",
+	UsageTypeDefType:        "值的類型",
+	UsageTypeDefDeprecated:  "表示在大於等於該版本號時不再啟作用",
+	UsageTypeDefSummary:     "類型的簡要描述",
+	UsageTypeDefItems:       "子類型，比如對象的子元素。",
+	UsageTypeDefEnums:       "當前類型可用的枚舉值",
+	UsageTypeDefDescription: "類型的詳細描述",
+
+	UsageSecurity:                 "定義安全驗證方案",
+	UsageSecurityName:             "方案的唯壹名稱，api 中的 security 通過此值引用該方案。",
+	UsageSecurityType:             "方案的類型，可以是 <var>apikey</var>、<var>http</var>、<var>oauth2</var> 和 <var>openid-connect</var>。",
+	UsageSecuritySummary:          "方案的簡要描述",
+	UsageSecurityIn:               "憑證所在的位置，可以是 <var>header</var>、<var>query</var> 和 <var>cookie</var>，僅對 <var>apikey</var> 有效。",
+	UsageSecurityKey:              "憑證的參數名稱，僅對 <var>apikey</var> 有效。",
+	UsageSecurityScheme:           "報頭 Authorization 中的驗證方式，比如 <var>basic</var> 和 <var>bearer</var>，僅對 <var>http</var> 有效。",
+	UsageSecurityBearerFormat:     "bearer 憑證的格式，比如 <var>JWT</var>，僅對 <var>http</var> 有效。",
+	UsageSecurityOpenIDConnectURL: "OpenID Connect 的配置地址，僅對 <var>openid-connect</var> 有效。",
+	UsageSecurityFlows:            "OAuth2 的授權方式，僅對 <var>oauth2</var> 有效。",
+	UsageSecurityDescription:      "方案的詳細描述",
+
+	UsageFlow:                 "OAuth2 的授權方式",
+	UsageFlowType:             "授權方式的類型，可以是 <var>implicit</var>、<var>password</var>、<var>client-credentials</var> 和 <var>authorization-code</var>。",
+	UsageFlowAuthorizationURL: "授權地址，<var>implicit</var> 和 <var>authorization-code</var> 必須指定。",
+	UsageFlowTokenURL:         "獲取令牌的地址，<var>password</var>、<var>client-credentials</var> 和 <var>authorization-code</var> 必須指定。",
+	UsageFlowRefreshURL:       "刷新令牌的地址",
+	UsageFlowScopes:           "可用的授權範圍",
+
+	UsageScope:        "OAuth2 的授權範圍",
+	UsageScopeName:    "授權範圍的名稱",
+	UsageScopeSummary: "授權範圍的簡要描述",
+
+	UsageSecurityValue:       "引用文檔中定義的安全驗證方案",
+	UsageSecurityValueName:   "安全驗證方案的名稱",
+	UsageSecurityValueScopes: "需要的授權範圍，僅對 <var>oauth2</var> 和 <var>openid-connect</var> 有效。",
+
+	UsageVariant:            "復合類型中的變體，表示對象可能的一種結構。變體的子元素會與父元素中的子元素合併。",
+	UsageVariantValue:       "在指定了 discriminator 時，用於區分變體的值，需要唯一。",
+	UsageVariantRef:         "引用 typedef 定義的對象作為變體的內容，不能與子元素同時使用。",
+	UsageVariantSummary:     "變體的簡要描述",
+	UsageVariantItems:       "變體的子元素",
+	UsageVariantDescription: "變體的詳細描述",
+
 	UsageXMLAttr:    "是否作為父元素的屬性，僅作用於 XML 元素。是否作為父元素的屬性，僅用於 XML 的請求。",
 	UsageXMLExtract: "將當前元素的內容作為父元素的內容，要求父元素必須為 <var>object</var>。",
 	UsageXMLCData:   "當前內容為 CDATA，與 <code>@xml-attr</code> 互斥。",
@@ -202,6 +353,10 @@ mock 服務會根據接口定義檢測用戶提交的數據是否合法，並生
 	<li><samp>&gt;name</samp>：表示將當前數組元素的名稱改為 <var>name</var>；</li>
 	</ul>`,
 
+	UsageCompositeCompose:       "變體的組合方式，可以是 one-of、any-of 或是 all-of，默認為 one-of。",
+	UsageCompositeDiscriminator: "用於區分變體的子元素名稱，該子元素需要在當前對象中聲明，且其值與變體的 value 相對應。不能用於 all-of。",
+	UsageCompositeVariants:      "對象的變體列表，指定之後，當前對象的子元素為所有變體共有的內容。僅對 object 類型有效。",
+
 	// 基本类型
 	UsageString:  "普通的字符串類型，特殊字符需要使用 XML 實體，比如 <samp>&lt;</samp> 需要使用 <samp>&amp;lt;</samp> 代替。",
 	UsageNumber:  "普通的數值類型，比如：<samp>1</samp>、<samp>-11.1</samp> 等。",
@@ -213,6 +368,7 @@ mock 服務會根據接口定義檢測用戶提交的數據是否合法，並生
 	<li>空值；</li>
 	<li><var>bool</var> 布爾值；</li>
 	<li><var>object</var> 對象；</li>
+	<li><var>map</var> 鍵名為字符串的字典，值的類型由子元素描述；也可以通過 <var>map.string</var> 等形式指定值的類型，子類型不能為 <var>object</var> 和 <var>map</var>；</li>
 	<li><var>number</var> 數值類型；</li>
 	<li><var>number.int</var> 整數類型的數值；</li>
 	<li><var>number.float</var> 浮點類型的數值；</li>
@@ -223,6 +379,7 @@ mock 服務會根據接口定義檢測用戶提交的數據是否合法，並生
 	<li><var>string.date</var> 表示 <a href="https://tools.ietf.org/html/rfc3339#section-5.6">RFC3339</a> 中的 <code>full-date</code> 日期格式，比如 <samp>2020-01-02</samp>；</li>
 	<li><var>string.time</var> 表示 <a href="https://tools.ietf.org/html/rfc3339#section-5.6">RFC3339</a> 中的 <code>full-time</code> 時間格式，比如 <samp>15:16:17Z</samp>、<samp>15:16:17+08:00</samp>；</li>
 	<li><var>string.date-time</var> 表示 <a href="https://tools.ietf.org/html/rfc3339#section-5.6">RFC3339</a> 中的 <code>date-time</code> 格式，比如 <samp>2020-01-02T15:16:17-08:00</samp>；</li>
+	<li><var>file</var> 文件，一般用於 <code>multipart/form-data</code> 格式的請求，在 JSON 和 XML 中以 base64 編碼的字符串表示；</li>
 	</ul>`,
 
 	// 以下是有关 build.Config 的字段说明
@@ -235,7 +392,7 @@ mock 服務會根據接口定義檢測用戶提交的數據是否合法，並生
 	UsageConfigInputsEncoding:        `編碼，默認為 <var>utf-8</var>，值可以是 <a href="https://www.iana.org/assignments/character-sets/character-sets.xhtml">character-sets</a> 中的內容。`,
 	UsageConfigInputsIgnores:         "忽略的文件或目錄，比如 node_modules 等。",
 	UsageConfigOutput:                "控制輸出行為",
-	UsageConfigOutputType:            "輸出的類型，目前可以 <var>apidoc+xml</var>、<var>openapi+json</var> 和 <var>openapi+yaml</var>。",
+	UsageConfigOutputType:            "輸出的類型，目前可以 <var>apidoc+xml</var>、<var>openapi+json</var>、<var>openapi+yaml</var>、<var>postman+json</var>、<var>markdown</var> 和 <var>html</var>。",
 	UsageConfigOutputPath:            "指定輸出的文件名，包含路徑信息。",
 	UsageConfigOutputTags:            "只輸出與這些標簽相關聯的文檔，默認為全部。",
 	UsageConfigOutputStyle:           "為 XML 文件指定的 XSL 文件",
@@ -270,6 +427,13 @@ mock 服務會根據接口定義檢測用戶提交的數據是否合法，並生
 	ErrInvalidURIScheme:          "無效的 URI 協議：%s",
 	ErrInvalidURI:                "無效的 URI：%s",
 	ErrFileNotFound:              "未找到文件 %s",
+	ErrUnsupportedOpenAPI:        "無法轉換為 apidoc 的內容",
+	ErrCircularReference:         "存在循環引用",
+	ErrBreakingChanges:           "存在 %d 處不兼容的改動",
+	ErrMissingCredential:         "缺少訪問憑證",
+	ErrOutOfRange:                "超出範圍",
+	ErrReadOnlyField:             "只讀字段不能出現在請求中",
+	ErrWriteOnlyField:            "只寫字段不能出現在返回中",
 
 	// logs
 	InfoPrefix:    "[信息] ",
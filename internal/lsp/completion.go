@@ -0,0 +1,209 @@
+// SPDX-License-Identifier: MIT
+
+package lsp
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"unicode"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/locale"
+	"github.com/caixw/apidoc/v7/internal/lsp/protocol"
+	"github.com/caixw/apidoc/v7/internal/node"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+type tagger interface {
+	core.Searcher
+	SelfClose() bool
+}
+
+var (
+	attrEncoderType = reflect.TypeOf((*xmlenc.AttrEncoder)(nil)).Elem()
+	taggerType      = reflect.TypeOf((*tagger)(nil)).Elem()
+)
+
+// textDocument/completion
+//
+// https://microsoft.github.io/language-server-protocol/specifications/specification-current/#textDocument_completion
+func (s *server) textDocumentCompletion(notify bool, in *protocol.CompletionParams, out *protocol.CompletionList) error {
+	f := s.findFolder(in.TextDocument.URI)
+	if f == nil {
+		return nil // 非项目文件，不应该出错
+	}
+
+	f.parsedMux.RLock()
+	defer f.parsedMux.RUnlock()
+
+	out.Items = f.completion(in.TextDocument.URI, in.Position)
+	return nil
+}
+
+// 返回 uri 中 pos 位置的可用的补全项
+func (f *folder) completion(uri core.URI, pos core.Position) []protocol.CompletionItem {
+	v := searchCompletion(reflect.ValueOf(f.doc), uri, pos)
+	if !v.IsValid() { // apidoc 的 uri 可以与 api 的 uri 不同
+		for _, api := range f.doc.APIs {
+			if v = searchCompletion(reflect.ValueOf(api), uri, pos); v.IsValid() {
+				break
+			}
+		}
+	}
+	if !v.IsValid() {
+		return nil
+	}
+
+	switch v.Addr().Interface().(type) {
+	case *ast.TypeAttribute:
+		return completionValues(ast.Types())
+	case *ast.MethodAttribute:
+		return completionValues(ast.Methods())
+	case *ast.StatusAttribute:
+		statuses := ast.Statuses()
+		items := make([]protocol.CompletionItem, 0, len(statuses))
+		for _, status := range statuses {
+			items = append(items, protocol.CompletionItem{
+				Label:  strconv.Itoa(status),
+				Kind:   protocol.CompletionItemKindEnumMember,
+				Detail: http.StatusText(status),
+			})
+		}
+		return items
+	case *ast.TagValue:
+		items := make([]protocol.CompletionItem, 0, len(f.doc.Tags))
+		for _, tag := range f.doc.Tags {
+			items = append(items, protocol.CompletionItem{
+				Label:  tag.Name.V(),
+				Kind:   protocol.CompletionItemKindReference,
+				Detail: tag.Title.V(),
+			})
+		}
+		return items
+	case *ast.ServerValue:
+		items := make([]protocol.CompletionItem, 0, len(f.doc.Servers))
+		for _, srv := range f.doc.Servers {
+			items = append(items, protocol.CompletionItem{
+				Label:  srv.Name.V(),
+				Kind:   protocol.CompletionItemKindReference,
+				Detail: srv.URL.V(),
+			})
+		}
+		return items
+	}
+
+	if v.Addr().Type().Implements(attrEncoderType) { // 其它无法枚举值的属性
+		return nil
+	}
+	return completionNode(v)
+}
+
+// 返回元素 v 可用的子元素和属性
+//
+// 已经存在的属性以及非数组类型的元素不再出现在补全列表中。
+func completionNode(v reflect.Value) []protocol.CompletionItem {
+	n := node.New("", v)
+	items := make([]protocol.CompletionItem, 0, len(n.Attributes)+len(n.Elements))
+
+	for _, attr := range n.Attributes {
+		if !attr.IsZero() {
+			continue
+		}
+		items = append(items, completionItem(attr, protocol.CompletionItemKindProperty))
+	}
+
+	for _, elem := range n.Elements {
+		if elem.Kind() != reflect.Slice && !elem.IsZero() {
+			continue
+		}
+		items = append(items, completionItem(elem, protocol.CompletionItemKindField))
+	}
+
+	return items
+}
+
+func completionItem(v *node.Value, kind protocol.CompletionItemKind) protocol.CompletionItem {
+	item := protocol.CompletionItem{
+		Label: v.Name,
+		Kind:  kind,
+	}
+
+	if v.Usage != "" {
+		item.Documentation = &protocol.MarkupContent{
+			Kind:  protocol.MarkupKindMarkdown,
+			Value: locale.Sprintf(v.Usage),
+		}
+	}
+
+	return item
+}
+
+func completionValues(values []string) []protocol.CompletionItem {
+	items := make([]protocol.CompletionItem, 0, len(values))
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		items = append(items, protocol.CompletionItem{
+			Label: v,
+			Kind:  protocol.CompletionItemKindEnumMember,
+		})
+	}
+	return items
+}
+
+// 查找包含 pos 的最小元素或是属性
+//
+// 与 ast.APIDoc.Search 不同，此函数不会初始化值为 nil 的字段，
+// 也不会进入属性的内部查找，返回值为结构体本身。
+// 元素的内容（content、cdata 等）会被当作元素本身处理。
+func searchCompletion(v reflect.Value, uri core.URI, pos core.Position) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct || !v.CanAddr() {
+		return reflect.Value{}
+	}
+
+	s, ok := v.Addr().Interface().(core.Searcher)
+	if !ok || !s.Contains(uri, pos) {
+		return reflect.Value{}
+	}
+
+	pt := v.Addr().Type()
+	if pt.Implements(attrEncoderType) {
+		return v
+	}
+
+	for vt, i := v.Type(), 0; i < vt.NumField(); i++ {
+		ft := vt.Field(i)
+		if ft.Anonymous || unicode.IsLower(rune(ft.Name[0])) {
+			continue
+		}
+
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Array || fv.Kind() == reflect.Slice {
+			for j := 0; j < fv.Len(); j++ {
+				if r := searchCompletion(fv.Index(j), uri, pos); r.IsValid() {
+					return r
+				}
+			}
+			continue
+		}
+
+		if r := searchCompletion(fv, uri, pos); r.IsValid() {
+			return r
+		}
+	}
+
+	if pt.Implements(taggerType) {
+		return v
+	}
+	return reflect.Value{}
+}
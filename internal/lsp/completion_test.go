@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: MIT
+
+package lsp
+
+import (
+	"io/ioutil"
+	"log"
+	"testing"
+
+	"github.com/issue9/assert/v3"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/core/messagetest"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/lsp/protocol"
+)
+
+func TestServer_textDocumentCompletion(t *testing.T) {
+	a := assert.New(t, false)
+	s := newTestServer(true, log.New(ioutil.Discard, "", 0), log.New(ioutil.Discard, "", 0))
+	list := &protocol.CompletionList{}
+	err := s.textDocumentCompletion(false, &protocol.CompletionParams{}, list)
+	a.NotError(err).Empty(list.Items)
+
+	const b = `<apidoc version="1.1.1">
+	<title>标题</title>
+	<tag name="t1" title="tag1" />
+	<server name="s1" url="https://example.com" />
+	<mimetype>json</mimetype>
+	<api method="GET">
+		<path path="/users" />
+		<tag>t1</tag>
+		<server>s1</server>
+		<response status="200" type="string" />
+	</api>
+</apidoc>`
+	blk := core.Block{Data: []byte(b), Location: core.Location{URI: "file:///test/doc.go"}}
+	rslt := messagetest.NewMessageHandler()
+	doc := &ast.APIDoc{}
+	doc.Parse(rslt.Handler, blk)
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	s.folders = []*folder{
+		{
+			WorkspaceFolder: protocol.WorkspaceFolder{Name: "test", URI: "file:///test"},
+			doc:             doc,
+		},
+	}
+
+	complete := func(line, char int) []protocol.CompletionItem {
+		list := &protocol.CompletionList{}
+		err := s.textDocumentCompletion(false, &protocol.CompletionParams{TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+			TextDocument: protocol.TextDocumentIdentifier{URI: "file:///test/doc.go"},
+			Position:     core.Position{Line: line, Character: char},
+		}}, list)
+		a.NotError(err)
+		return list.Items
+	}
+
+	labels := func(items []protocol.CompletionItem) []string {
+		ret := make([]string, 0, len(items))
+		for _, item := range items {
+			ret = append(ret, item.Label)
+		}
+		return ret
+	}
+
+	// apidoc 元素
+	items := labels(complete(0, 2))
+	a.NotContains(items, "version"). // 已经存在的属性
+						NotContains(items, "title"). // 已经存在的非数组元素
+						Contains(items, "lang").
+						Contains(items, "api")
+
+	// api 元素
+	items = labels(complete(5, 3))
+	a.NotContains(items, "method").
+		Contains(items, "summary").
+		Contains(items, "request").
+		Contains(items, "response")
+
+	// api.method
+	items = labels(complete(5, 15))
+	a.Contains(items, "GET").Contains(items, "POST")
+
+	// api.tag
+	a.Equal(labels(complete(7, 8)), []string{"t1"})
+
+	// api.server
+	a.Equal(labels(complete(8, 11)), []string{"s1"})
+
+	// response.status
+	items = labels(complete(9, 22))
+	a.Contains(items, "200").Contains(items, "404")
+
+	// response.type
+	items = labels(complete(9, 35))
+	a.Contains(items, "string").Contains(items, "number.int").NotContains(items, "")
+
+	// path.path 无法枚举
+	a.Empty(complete(6, 16))
+
+	// 超出范围
+	a.Empty(complete(100, 1))
+}
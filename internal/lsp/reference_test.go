@@ -98,6 +98,50 @@ func TestServer_textDocumentDefinition(t *testing.T) {
 			End:   core.Position{Line: 3, Character: 31},
 		},
 	})
+
+	// 引用 typedef
+	const typeDefDoc = `<apidoc version="1.1.1">
+	<title>标题</title>
+	<mimetype>xml</mimetype>
+	<typedef name="user" type="string" summary="user" />
+	<api method="GET">
+		<path path="/users" />
+		<response status="200" ref="user" />
+		<response status="404" ref="not-exists" />
+	</api>
+</apidoc>`
+	blk := core.Block{Data: []byte(typeDefDoc), Location: core.Location{URI: "file:///root/doc.go"}}
+	rslt := messagetest.NewMessageHandler()
+	doc := &ast.APIDoc{}
+	doc.Parse(rslt.Handler, blk)
+	rslt.Handler.Stop()
+	s.folders[0].doc = doc
+
+	locs = nil
+	err = s.textDocumentDefinition(false, &protocol.DefinitionParams{TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: "file:///root/doc.go"},
+		Position:     core.Position{Line: 6, Character: 26},
+	}}, &locs)
+	a.NotError(err).Equal(len(locs), 1)
+	a.Equal(locs[0], core.Location{
+		URI: "file:///root/doc.go",
+		Range: core.Range{
+			Start: core.Position{Line: 3, Character: 1},
+			End:   core.Position{Line: 3, Character: 53},
+		},
+	})
+
+	// 引用的类型不存在
+	locs = nil
+	err = s.textDocumentDefinition(false, &protocol.DefinitionParams{TextDocumentPositionParams: protocol.TextDocumentPositionParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: "file:///root/doc.go"},
+		Position:     core.Position{Line: 7, Character: 26},
+	}}, &locs)
+	a.NotError(err).Empty(locs)
+
+	locs = references(doc, "file:///root/doc.go", core.Position{Line: 3, Character: 16}, false)
+	a.Equal(len(locs), 1).
+		Equal(locs[0].Range.Start, core.Position{Line: 6, Character: 25})
 }
 
 func TestReferences(t *testing.T) {
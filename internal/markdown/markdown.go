@@ -0,0 +1,334 @@
+// SPDX-License-Identifier: MIT
+
+// Package markdown 将文档输出为 markdown 格式
+package markdown
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/issue9/errwrap"
+
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/locale"
+)
+
+type renderer struct {
+	doc    *ast.APIDoc
+	langID string
+	buf    *errwrap.Buffer
+}
+
+// Render 将 doc 输出为 markdown 格式的内容
+//
+// 每个标签对应一个二级标题，未指定标签的 API 归类到最后的其它中，
+// 参数以表格的形式输出，嵌套的参数会被展开为 parent.child 的形式。
+func Render(doc *ast.APIDoc) ([]byte, error) {
+	langID := doc.Lang.V()
+	if langID == "" {
+		langID = "und"
+	}
+
+	r := &renderer{
+		doc:    doc,
+		langID: langID,
+		buf:    &errwrap.Buffer{},
+	}
+	r.render()
+
+	if r.buf.Err != nil {
+		return nil, r.buf.Err
+	}
+	return r.buf.Bytes(), nil
+}
+
+func (r *renderer) t(key string, v ...any) string {
+	return locale.Translate(r.langID, key, v...)
+}
+
+func (r *renderer) render() {
+	doc := r.doc
+
+	r.buf.Printf("# %s\n\n", doc.Title.V())
+	if doc.Version != nil && doc.Version.V() != "" {
+		r.buf.WString(r.t(locale.MarkdownVersion, doc.Version.V())).WString("\n\n")
+	}
+	r.block(doc.Description.V())
+
+	if c := doc.Contact; c != nil {
+		r.buf.Printf("## %s\n\n", r.t(locale.MarkdownContact))
+		r.buf.WString(link(c.Name.V(), c.URL.V()))
+		if c.Email.V() != "" {
+			r.buf.Printf(" <%s>", c.Email.V())
+		}
+		r.buf.WString("\n\n")
+	}
+
+	if l := doc.License; l != nil {
+		r.buf.Printf("## %s\n\n%s\n\n", r.t(locale.MarkdownLicense), link(l.Text.V(), l.URL.V()))
+	}
+
+	if len(doc.Servers) > 0 {
+		r.buf.Printf("## %s\n\n", r.t(locale.MarkdownServers))
+		for _, srv := range doc.Servers {
+			r.buf.Printf("- **%s**: %s", srv.Name.V(), srv.URL.V())
+			if desc := inline(ast.Description(srv.Description, srv.Summary)); desc != "" {
+				r.buf.WString(" ").WString(desc)
+			}
+			r.buf.WByte('\n')
+		}
+		r.buf.WByte('\n')
+	}
+
+	tagged := make(map[*ast.API]bool, len(doc.APIs))
+	for _, tag := range doc.Tags {
+		apis := make([]*ast.API, 0, len(doc.APIs))
+		for _, api := range doc.APIs {
+			if hasTag(api, tag.Name.V()) {
+				apis = append(apis, api)
+				tagged[api] = true
+			}
+		}
+		if len(apis) == 0 {
+			continue
+		}
+
+		title := tag.Title.V()
+		if title == "" {
+			title = tag.Name.V()
+		}
+		r.buf.Printf("## %s\n\n", title)
+		for _, api := range apis {
+			r.api(api)
+		}
+	}
+
+	others := make([]*ast.API, 0, len(doc.APIs))
+	for _, api := range doc.APIs {
+		if !tagged[api] {
+			others = append(others, api)
+		}
+	}
+	if len(others) > 0 {
+		if len(tagged) > 0 { // 所有 API 都未指定标签时，不需要额外的标题。
+			r.buf.Printf("## %s\n\n", r.t(locale.MarkdownOthers))
+		}
+		for _, api := range others {
+			r.api(api)
+		}
+	}
+}
+
+func (r *renderer) api(api *ast.API) {
+	title := api.Summary.V()
+	if title == "" {
+		title = api.Method.V() + " " + api.Path.Path.V()
+	}
+	r.buf.Printf("### %s\n\n`%s %s`\n\n", title, api.Method.V(), api.Path.Path.V())
+	if api.Deprecated != nil {
+		r.buf.Printf("> %s\n\n", r.t(locale.MarkdownDeprecated, api.Deprecated.V()))
+	}
+	r.block(api.Description.V())
+
+	r.params(r.t(locale.MarkdownPathParams), api.Path.Params)
+	r.params(r.t(locale.MarkdownQueries), api.Path.Queries)
+	r.params(r.t(locale.MarkdownHeaders), append(append([]*ast.Param{}, r.doc.Headers...), api.Headers...))
+	r.params(r.t(locale.MarkdownCookies), api.Cookies)
+
+	for _, req := range api.Requests {
+		r.request(r.t(locale.MarkdownRequest), req)
+	}
+	for _, resp := range api.Responses {
+		r.request(r.t(locale.MarkdownResponse)+" "+strconv.Itoa(resp.Status.V()), resp)
+	}
+
+	if cb := api.Callback; cb != nil {
+		r.buf.Printf("#### %s\n\n", r.t(locale.MarkdownCallback))
+		r.buf.Printf("`%s", cb.Method.V())
+		if cb.Path != nil && cb.Path.Path.V() != "" {
+			r.buf.WString(" ").WString(cb.Path.Path.V())
+		}
+		r.buf.WString("`\n\n")
+		r.block(ast.Description(cb.Description, cb.Summary))
+
+		if cb.Path != nil {
+			r.params(r.t(locale.MarkdownQueries), cb.Path.Queries)
+		}
+		r.params(r.t(locale.MarkdownHeaders), cb.Headers)
+		for _, req := range cb.Requests {
+			r.request(r.t(locale.MarkdownRequest), req)
+		}
+		for _, resp := range cb.Responses {
+			r.request(r.t(locale.MarkdownResponse)+" "+strconv.Itoa(resp.Status.V()), resp)
+		}
+	}
+}
+
+// 输出请求或是返回的内容
+func (r *renderer) request(title string, req *ast.Request) {
+	if mt := req.Mimetype.V(); mt != "" {
+		title += " " + mt
+	}
+	r.buf.Printf("#### %s\n\n", title)
+	r.block(ast.Description(req.Description, req.Summary))
+
+	r.params(r.t(locale.MarkdownHeaders), req.Headers)
+	r.params(r.t(locale.MarkdownCookies), req.Cookies)
+
+	if req.Type.V() != ast.TypeNone {
+		p := req.Param()
+		p.Optional = nil // Param() 会将顶层元素设置为可选
+		r.buf.Printf("##### %s\n\n", r.t(locale.MarkdownBody))
+		rows := make([][]string, 0, len(req.Items))
+		if len(p.Items) == 0 && len(p.Variants) == 0 {
+			rows = r.rows(rows, "", p)
+		} else {
+			rows = r.children(rows, "", p)
+		}
+		r.table(rows)
+	}
+
+	for _, exp := range req.Examples {
+		if exp.Content == nil {
+			continue
+		}
+
+		r.buf.Printf("%s", r.t(locale.MarkdownExample))
+		if s := exp.Summary.V(); s != "" {
+			r.buf.WString(": ").WString(s)
+		}
+		r.buf.Printf("\n\n```%s\n%s\n```\n\n", ast.Syntax(exp.Mimetype.V()), strings.TrimSpace(exp.Content.Value.Value))
+	}
+}
+
+// 以表格的形式输出 params，params 为空时不输出任何内容。
+func (r *renderer) params(title string, params []*ast.Param) {
+	if len(params) == 0 {
+		return
+	}
+
+	r.buf.Printf("##### %s\n\n", title)
+	rows := make([][]string, 0, len(params))
+	for _, p := range params {
+		rows = r.rows(rows, "", p)
+	}
+	r.table(rows)
+}
+
+// 将 p 及其子元素展开为表格的行
+func (r *renderer) rows(rows [][]string, parent string, p *ast.Param) [][]string {
+	name := p.Name.V()
+	if parent != "" {
+		name = parent + "." + name
+	}
+
+	typ := p.Type.V()
+	if p.Array.V() {
+		typ += "[]"
+	}
+
+	required := r.t(locale.MarkdownYes)
+	if p.Optional.V() {
+		required = r.t(locale.MarkdownNo)
+	}
+
+	rows = append(rows, []string{name, typ, required, r.description(p)})
+	return r.children(rows, name, p)
+}
+
+func (r *renderer) children(rows [][]string, parent string, p *ast.Param) [][]string {
+	items := p.Items
+	for _, v := range p.Variants {
+		items = append(items[:len(items):len(items)], v.Items...)
+	}
+
+	if primitive, sub := ast.ParseType(p.Type.V()); primitive == ast.TypeMap && sub == "" {
+		parent += ".*"
+	}
+
+	for _, item := range items {
+		rows = r.rows(rows, parent, item)
+	}
+	return rows
+}
+
+func (r *renderer) description(p *ast.Param) string {
+	desc := inline(ast.Description(p.Description, p.Summary))
+
+	notes := make([]string, 0, 3)
+	if p.Deprecated != nil {
+		notes = append(notes, r.t(locale.MarkdownDeprecated, p.Deprecated.V()))
+	}
+	if p.Default.V() != "" {
+		notes = append(notes, r.t(locale.MarkdownDefault, "`"+p.Default.V()+"`"))
+	}
+	if len(p.Enums) > 0 {
+		enums := make([]string, 0, len(p.Enums))
+		for _, e := range p.Enums {
+			enums = append(enums, "`"+e.Value.V()+"`")
+		}
+		notes = append(notes, r.t(locale.MarkdownEnums, strings.Join(enums, ", ")))
+	}
+
+	if len(notes) > 0 {
+		if desc != "" {
+			desc += " "
+		}
+		desc += "(" + strings.Join(notes, "; ") + ")"
+	}
+	return escape(desc)
+}
+
+func (r *renderer) table(rows [][]string) {
+	r.buf.Printf("| %s | %s | %s | %s |\n",
+		r.t(locale.MarkdownParam),
+		r.t(locale.MarkdownType),
+		r.t(locale.MarkdownRequired),
+		r.t(locale.MarkdownDescription))
+	r.buf.WString("| --- | --- | --- | --- |\n")
+
+	for _, row := range rows {
+		if row[0] == "" {
+			row[0] = "-"
+		}
+		r.buf.Printf("| %s | `%s` | %s | %s |\n", escape(row[0]), row[1], row[2], row[3])
+	}
+	r.buf.WByte('\n')
+}
+
+// 输出一段独立的文本内容
+func (r *renderer) block(text string) {
+	if text = strings.TrimSpace(text); text != "" {
+		r.buf.WString(text).WString("\n\n")
+	}
+}
+
+func hasTag(api *ast.API, tag string) bool {
+	for _, t := range api.Tags {
+		if t.V() == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func link(text, url string) string {
+	switch {
+	case url == "":
+		return text
+	case text == "":
+		return "<" + url + ">"
+	default:
+		return "[" + text + "](" + url + ")"
+	}
+}
+
+// 将多行内容合并为一行，以便在表格和列表中使用。
+func inline(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// 转义表格中的竖线
+func escape(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: MIT
+
+package markdown
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/issue9/assert/v3"
+	"github.com/issue9/errwrap"
+
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/ast/asttest"
+	"github.com/caixw/apidoc/v7/internal/locale"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+func newParam(name, typ string) *ast.Param {
+	return &ast.Param{
+		Name: &ast.Attribute{Value: xmlenc.String{Value: name}},
+		Type: &ast.TypeAttribute{Value: xmlenc.String{Value: typ}},
+	}
+}
+
+func newRenderer() *renderer {
+	return &renderer{langID: "cmn-Hans"}
+}
+
+func TestRender(t *testing.T) {
+	a := assert.New(t, false)
+
+	data, err := Render(asttest.Get())
+	a.NotError(err).NotNil(data)
+
+	doc := &ast.APIDoc{
+		Lang:  &ast.Attribute{Value: xmlenc.String{Value: "cmn-Hans"}},
+		Title: &ast.Element{Content: ast.Content{Value: "test"}},
+		Tags: []*ast.Tag{
+			{
+				Name:  &ast.Attribute{Value: xmlenc.String{Value: "t1"}},
+				Title: &ast.Attribute{Value: xmlenc.String{Value: "tag1"}},
+			},
+			{Name: &ast.Attribute{Value: xmlenc.String{Value: "empty"}}},
+		},
+		APIs: []*ast.API{
+			{
+				Method:  &ast.MethodAttribute{Value: xmlenc.String{Value: http.MethodGet}},
+				Path:    &ast.Path{Path: &ast.Attribute{Value: xmlenc.String{Value: "/users/{id}"}}, Params: []*ast.Param{newParam("id", ast.TypeInt)}},
+				Summary: &ast.Attribute{Value: xmlenc.String{Value: "get user"}},
+				Tags:    []*ast.TagValue{{Content: ast.Content{Value: "t1"}}},
+			},
+			{
+				Method: &ast.MethodAttribute{Value: xmlenc.String{Value: http.MethodDelete}},
+				Path:   &ast.Path{Path: &ast.Attribute{Value: xmlenc.String{Value: "/users"}}},
+			},
+		},
+	}
+	data, err = Render(doc)
+	a.NotError(err)
+	text := string(data)
+	a.Contains(text, "# test\n").
+		Contains(text, "## tag1\n").
+		NotContains(text, "## empty\n").
+		Contains(text, "### get user\n\n`GET /users/{id}`").
+		Contains(text, "##### "+locale.Translate("cmn-Hans", locale.MarkdownPathParams)).
+		Contains(text, "| id | `number.int` |").
+		Contains(text, "## "+locale.Translate("cmn-Hans", locale.MarkdownOthers)+"\n").
+		Contains(text, "### DELETE /users\n")
+
+	// 所有 API 都未指定标签，不输出其它的标题。
+	doc.APIs = doc.APIs[1:]
+	data, err = Render(doc)
+	a.NotError(err).
+		NotContains(string(data), "## "+locale.Translate("cmn-Hans", locale.MarkdownOthers))
+}
+
+func TestRenderer_request(t *testing.T) {
+	a := assert.New(t, false)
+
+	user := newParam("user", ast.TypeObject)
+	user.Items = []*ast.Param{newParam("name", ast.TypeString)}
+	req := &ast.Request{
+		Type:     &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeObject}},
+		Mimetype: &ast.Attribute{Value: xmlenc.String{Value: "application/json"}},
+		Status:   &ast.StatusAttribute{Value: ast.Number{Int: http.StatusOK}},
+		Items:    []*ast.Param{newParam("id", ast.TypeInt), user},
+		Examples: []*ast.Example{
+			{
+				Mimetype: &ast.Attribute{Value: xmlenc.String{Value: "application/json"}},
+				Content:  &ast.ExampleValue{Value: xmlenc.String{Value: "\n{\"id\":1}\n"}},
+			},
+		},
+	}
+
+	r := newRenderer()
+	r.buf = &errwrap.Buffer{}
+	r.request("返回 200", req)
+	a.NotError(r.buf.Err)
+	text := r.buf.String()
+	a.Contains(text, "#### 返回 200 application/json\n").
+		Contains(text, "##### 报文\n").
+		Contains(text, "| id | `number.int` | 是 |").
+		Contains(text, "| user.name | `string` | 是 |").
+		Contains(text, "```json\n{\"id\":1}\n```")
+
+	// 非对象的内容
+	r.buf = &errwrap.Buffer{}
+	req = &ast.Request{Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}}}
+	r.request("请求", req)
+	a.Contains(r.buf.String(), "| - | `string` | 是 |")
+}
+
+func TestRenderer_rows(t *testing.T) {
+	a := assert.New(t, false)
+	r := newRenderer()
+
+	tags := newParam("tags", ast.TypeObject)
+	tags.Array = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+	tags.Optional = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+	tags.Items = []*ast.Param{newParam("name", ast.TypeString)}
+	rows := r.rows(nil, "", tags)
+	a.Equal(rows, [][]string{
+		{"tags", "object[]", "否", ""},
+		{"tags.name", "string", "是", ""},
+	})
+
+	m := newParam("attrs", ast.TypeMap)
+	m.Items = []*ast.Param{newParam("name", ast.TypeString)}
+	rows = r.rows(nil, "", m)
+	a.Equal(2, len(rows)).Equal(rows[1][0], "attrs.*.name")
+}
+
+func TestRenderer_description(t *testing.T) {
+	a := assert.New(t, false)
+	r := newRenderer()
+
+	p := newParam("status", ast.TypeString)
+	p.Summary = &ast.Attribute{Value: xmlenc.String{Value: "a|b"}}
+	a.Equal(r.description(p), `a\|b`)
+
+	p.Default = &ast.Attribute{Value: xmlenc.String{Value: "active"}}
+	p.Enums = []*ast.Enum{
+		{Value: &ast.Attribute{Value: xmlenc.String{Value: "active"}}},
+		{Value: &ast.Attribute{Value: xmlenc.String{Value: "locked"}}},
+	}
+	a.Equal(r.description(p), `a\|b (默认值：`+"`active`; 可选值：`active`, `locked`)")
+}
+
+func TestLink(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(link("text", ""), "text").
+		Equal(link("", "https://example.com"), "<https://example.com>").
+		Equal(link("text", "https://example.com"), "[text](https://example.com)")
+}
+
+func TestInline(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(inline("  line1\n  line2\n"), "line1 line2").
+		Equal(inline(""), "")
+}
@@ -0,0 +1,343 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/issue9/mux/v7/examples/std"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/locale"
+)
+
+// 请求日志最多保存的条数
+const journalSize = 100
+
+// 带管理接口的 mock 服务
+//
+// 管理接口位于 prefix 之下：
+//
+//	GET    /routes    列出所有已加载的路由；
+//	POST   /reload    重新加载文档；
+//	GET    /overrides 列出所有的覆盖设置；
+//	PUT    /overrides 添加或是修改指定 API 的覆盖设置；
+//	DELETE /overrides 删除覆盖设置，可以通过查询参数 method 和 path 指定 API，否则删除所有；
+//	DELETE /state     清空有状态模式下的数据；
+//	GET    /journal   最近的请求记录；
+//	DELETE /journal   清空请求记录。
+type admin struct {
+	msgHandler *core.MessageHandler
+	prefix     string
+	path       core.URI // 文档的路径，为空表示不能重新加载文档。
+	options    *Options
+	router     *std.Router
+
+	mux       sync.RWMutex
+	mock      *mock
+	overrides map[string]*override
+
+	journalMux sync.Mutex
+	journal    []*journalEntry
+}
+
+// 通过管理接口指定的 API 行为
+type override struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`              // 文档中 API 的路径，比如 /users/{id}。
+	Status  int               `json:"status,omitempty"`  // 返回的状态码
+	Body    string            `json:"body,omitempty"`    // 返回的内容，不为空时直接输出该内容，而不是生成随机数据。
+	Headers map[string]string `json:"headers,omitempty"` // 返回的报头，仅在直接输出内容时有效。
+	Delay   string            `json:"delay,omitempty"`   // 延迟时间，格式为 100ms 或是 100ms-500ms。
+
+	latency Latency
+}
+
+// 请求日志
+type journalEntry struct {
+	Time   time.Time `json:"time"`
+	Method string    `json:"method"`
+	URL    string    `json:"url"`
+	API    string    `json:"api,omitempty"` // 匹配的 API，未在文档中定义的请求为空。
+	Status int       `json:"status"`
+	Errors []string  `json:"errors,omitempty"` // 验证请求时产生的错误
+}
+
+type journalContextKey int
+
+const journalKey journalContextKey = 1
+
+type route struct {
+	Path    string   `json:"path"`
+	Methods []string `json:"methods"`
+}
+
+func newAdmin(msg *core.MessageHandler, m *mock, o *Options, path core.URI) *admin {
+	if o.Admin[0] != '/' || o.Admin[len(o.Admin)-1] == '/' {
+		panic("参数 o.Admin 必须以 / 开头且不能以 / 结尾")
+	}
+
+	a := &admin{
+		msgHandler: msg,
+		prefix:     o.Admin,
+		path:       path,
+		options:    o,
+		router:     std.NewRouter("apidoc mock admin"),
+		mock:       m,
+		overrides:  make(map[string]*override, 10),
+		journal:    make([]*journalEntry, 0, journalSize),
+	}
+	m.admin = a
+
+	a.router.Get(a.prefix+"/routes", http.HandlerFunc(a.getRoutes)).
+		Post(a.prefix+"/reload", http.HandlerFunc(a.reload)).
+		Get(a.prefix+"/overrides", http.HandlerFunc(a.getOverrides)).
+		Put(a.prefix+"/overrides", http.HandlerFunc(a.putOverride)).
+		Delete(a.prefix+"/overrides", http.HandlerFunc(a.deleteOverrides)).
+		Delete(a.prefix+"/state", http.HandlerFunc(a.resetState)).
+		Get(a.prefix+"/journal", http.HandlerFunc(a.getJournal)).
+		Delete(a.prefix+"/journal", http.HandlerFunc(a.resetJournal))
+
+	return a
+}
+
+func (a *admin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, a.prefix+"/") {
+		a.router.ServeHTTP(w, r)
+		return
+	}
+
+	e := &journalEntry{Time: time.Now(), Method: r.Method, URL: r.URL.RequestURI()}
+	sw := &statusWriter{ResponseWriter: w}
+	defer func() { // 断开连接时会 panic，所以需要在 defer 中记录。
+		e.Status = sw.status
+		a.addJournal(e)
+	}()
+
+	a.current().ServeHTTP(sw, r.WithContext(context.WithValue(r.Context(), journalKey, e)))
+}
+
+func (a *admin) current() *mock {
+	a.mux.RLock()
+	defer a.mux.RUnlock()
+	return a.mock
+}
+
+func apiName(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+func (a *admin) getRoutes(w http.ResponseWriter, _ *http.Request) {
+	routes := a.current().router.Routes()
+	ret := make([]*route, 0, len(routes))
+	for path, methods := range routes {
+		ret = append(ret, &route{Path: path, Methods: methods})
+	}
+	sort.SliceStable(ret, func(i, j int) bool { return ret[i].Path < ret[j].Path })
+
+	writeJSON(w, http.StatusOK, ret)
+}
+
+// 重新加载文档
+//
+// 有状态模式下保存的数据以及覆盖设置都会被保留。
+func (a *admin) reload(w http.ResponseWriter, r *http.Request) {
+	if a.path == "" {
+		http.Error(w, locale.Sprintf(locale.ErrIsEmpty, "path"), http.StatusBadRequest)
+		return
+	}
+
+	d, err := loadDoc(a.msgHandler, a.path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	m, err := newMock(a.msgHandler, d, a.options)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	m.admin = a
+
+	a.mux.Lock()
+	if a.mock.store != nil { // 保留已有的数据，但采用新文档中的 ID 参数名称。
+		a.mock.store.load(d)
+	}
+	m.store = a.mock.store
+	a.mock = m
+	a.mux.Unlock()
+
+	a.msgHandler.Locale(core.Info, locale.MockReloaded, a.path)
+	a.getRoutes(w, r)
+}
+
+func (a *admin) getOverrides(w http.ResponseWriter, _ *http.Request) {
+	a.mux.RLock()
+	ret := make([]*override, 0, len(a.overrides))
+	for _, ov := range a.overrides {
+		ret = append(ret, ov)
+	}
+	a.mux.RUnlock()
+	sort.SliceStable(ret, func(i, j int) bool {
+		return apiName(ret[i].Method, ret[i].Path) < apiName(ret[j].Method, ret[j].Path)
+	})
+
+	writeJSON(w, http.StatusOK, ret)
+}
+
+func (a *admin) putOverride(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ov := &override{}
+	if err = json.Unmarshal(body, ov); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if ov.Status != 0 && (ov.Status < 100 || ov.Status > 599) {
+		http.Error(w, locale.Sprintf(locale.ErrInvalidValue), http.StatusBadRequest)
+		return
+	}
+	if ov.Delay != "" {
+		if ov.latency, err = ParseLatency(ov.Delay); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	name := apiName(ov.Method, ov.Path)
+	if !a.current().hasAPI(name) {
+		http.Error(w, locale.Sprintf(locale.ErrNotFound), http.StatusNotFound)
+		return
+	}
+
+	a.mux.Lock()
+	a.overrides[name] = ov
+	a.mux.Unlock()
+
+	writeJSON(w, http.StatusOK, ov)
+}
+
+func (a *admin) deleteOverrides(w http.ResponseWriter, r *http.Request) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	q := r.URL.Query()
+	if q.Get("method") == "" && q.Get("path") == "" {
+		a.overrides = make(map[string]*override, 10)
+	} else {
+		delete(a.overrides, apiName(q.Get("method"), q.Get("path")))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *admin) resetState(w http.ResponseWriter, _ *http.Request) {
+	if s := a.current().store; s != nil {
+		s.reset()
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *admin) addJournal(e *journalEntry) {
+	a.journalMux.Lock()
+	defer a.journalMux.Unlock()
+
+	if len(a.journal) >= journalSize {
+		a.journal = append(a.journal[:0], a.journal[1:]...)
+	}
+	a.journal = append(a.journal, e)
+}
+
+func (a *admin) getJournal(w http.ResponseWriter, _ *http.Request) {
+	a.journalMux.Lock()
+	ret := make([]*journalEntry, len(a.journal))
+	copy(ret, a.journal)
+	a.journalMux.Unlock()
+
+	writeJSON(w, http.StatusOK, ret)
+}
+
+func (a *admin) resetJournal(w http.ResponseWriter, _ *http.Request) {
+	a.journalMux.Lock()
+	a.journal = a.journal[:0]
+	a.journalMux.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// 获取与 r 相关联的请求日志，未启用管理接口时返回 nil。
+func journalEntryOf(r *http.Request) *journalEntry {
+	e, _ := r.Context().Value(journalKey).(*journalEntry)
+	return e
+}
+
+// 文档中是否存在名为 name 的 API
+func (m *mock) hasAPI(name string) bool {
+	for _, api := range m.doc.APIs {
+		if apiName(api.Method.V(), api.Path.Path.V()) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// 获取通过管理接口为 api 指定的覆盖设置，不存在则返回 nil。
+func (m *mock) override(api *ast.API) *override {
+	if m.admin == nil {
+		return nil
+	}
+
+	m.admin.mux.RLock()
+	defer m.admin.mux.RUnlock()
+	return m.admin.overrides[apiName(api.Method.V(), api.Path.Path.V())]
+}
+
+// 应用覆盖设置
+//
+// 返回 false 表示已经输出了内容，调用方无须再处理。
+func (m *mock) applyOverride(ov *override, api *ast.API, w http.ResponseWriter, r *http.Request) bool {
+	if !sleep(r, ov.latency.duration(m.gen)) {
+		return false
+	}
+
+	// 指定了文档中未定义的状态码，同样直接输出。
+	if ov.Body == "" && (ov.Status == 0 || len(filterResponses(api.Responses, ov.Status))+len(filterResponses(m.doc.Responses, ov.Status)) > 0) {
+		return true
+	}
+
+	for k, v := range ov.Headers {
+		w.Header().Set(k, v)
+	}
+	status := ov.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	if _, err := io.WriteString(w, ov.Body); err != nil {
+		m.msgHandler.Error(requestError(r, "", err))
+	}
+	return false
+}
@@ -0,0 +1,282 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert/v3"
+	"github.com/issue9/assert/v3/rest"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/core/messagetest"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+func newTestAdmin(a *assert.Assertion) (*rest.Server, *messagetest.Result) {
+	rslt := messagetest.NewMessageHandler()
+	d := &ast.APIDoc{APIDoc: &ast.APIDocVersionAttribute{Value: xmlenc.String{Value: ast.Version}}}
+	d.Parse(rslt.Handler, core.Block{Data: []byte(statefulDoc)})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	rslt = messagetest.NewMessageHandler()
+	h, err := New(rslt.Handler, d, &Options{ImageURL: "/images", Gen: testOptions, Stateful: true, Admin: "/__admin__"})
+	a.NotError(err).NotNil(h)
+	_, ok := h.(*admin)
+	a.True(ok)
+
+	return rest.NewServer(a, h, nil), rslt
+}
+
+func routePaths(a *assert.Assertion, body []byte) []string {
+	routes := make([]*route, 0, 10)
+	a.NotError(json.Unmarshal(body, &routes))
+
+	paths := make([]string, 0, len(routes))
+	for _, r := range routes {
+		paths = append(paths, r.Path)
+	}
+	return paths
+}
+
+func TestNewAdmin(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Panic(func() {
+		newAdmin(nil, &mock{}, &Options{Admin: "__admin__"}, "")
+	})
+
+	a.Panic(func() {
+		newAdmin(nil, &mock{}, &Options{Admin: "/__admin__/"}, "")
+	})
+}
+
+func TestAdmin_routes(t *testing.T) {
+	a := assert.New(t, false)
+	srv, rslt := newTestAdmin(a)
+	defer rslt.Handler.Stop()
+
+	srv.Get("/__admin__/routes").Do(nil).
+		Status(http.StatusOK).
+		Header("Content-Type", "application/json").
+		BodyFunc(func(a *assert.Assertion, body []byte) {
+			paths := routePaths(a, body)
+			a.Contains(paths, "/users").
+				Contains(paths, "/users/{id}").
+				Contains(paths, "/images/{path}")
+		})
+
+	// 未定义的管理接口
+	srv.Get("/__admin__/not-exists").Do(nil).Status(http.StatusNotFound)
+}
+
+func TestAdmin_overrides(t *testing.T) {
+	a := assert.New(t, false)
+	srv, rslt := newTestAdmin(a)
+	defer rslt.Handler.Stop()
+
+	// 文档中定义的状态码
+	srv.Put("/__admin__/overrides", []byte(`{"method":"get","path":"/users/{id}","status":200}`)).Do(nil).
+		Status(http.StatusOK)
+	srv.Get("/users/1").Header("accept", "application/json").Do(nil).
+		Status(http.StatusOK).
+		BodyNotEmpty()
+
+	// 指定了返回内容
+	srv.Put("/__admin__/overrides", []byte(`{"method":"GET","path":"/users/{id}","status":503,"body":"maintenance","headers":{"Retry-After":"10"}}`)).Do(nil).
+		Status(http.StatusOK)
+	srv.Get("/users/1").Header("accept", "application/json").Do(nil).
+		Status(http.StatusServiceUnavailable).
+		Header("Retry-After", "10").
+		StringBody("maintenance")
+
+	// 文档中未定义的状态码，即使没有指定返回内容也直接输出。
+	srv.Put("/__admin__/overrides", []byte(`{"method":"DELETE","path":"/users/{id}","status":500}`)).Do(nil).
+		Status(http.StatusOK)
+	srv.Delete("/users/1").Header("accept", "application/json").Do(nil).
+		Status(http.StatusInternalServerError).
+		BodyEmpty()
+
+	srv.Get("/__admin__/overrides").Do(nil).
+		Status(http.StatusOK).
+		BodyFunc(func(a *assert.Assertion, body []byte) {
+			ovs := make([]*override, 0, 2)
+			a.NotError(json.Unmarshal(body, &ovs)).
+				Equal(2, len(ovs)).
+				Equal(ovs[0].Method, "DELETE").
+				Equal(ovs[1].Status, http.StatusServiceUnavailable)
+		})
+
+	// 不存在的 API
+	srv.Put("/__admin__/overrides", []byte(`{"method":"GET","path":"/not-exists","status":200}`)).Do(nil).
+		Status(http.StatusNotFound)
+
+	// 无效的值
+	srv.Put("/__admin__/overrides", []byte(`{"method":"GET","path":"/users","status":1000}`)).Do(nil).
+		Status(http.StatusBadRequest)
+	srv.Put("/__admin__/overrides", []byte(`{"method":"GET","path":"/users","delay":"xx"}`)).Do(nil).
+		Status(http.StatusBadRequest)
+	srv.Put("/__admin__/overrides", []byte(`xx`)).Do(nil).
+		Status(http.StatusBadRequest)
+
+	// 删除指定的 API
+	srv.Delete("/__admin__/overrides?method=get&path=/users/{id}").Do(nil).
+		Status(http.StatusNoContent)
+	srv.Get("/users/1").Header("accept", "application/json").Do(nil).
+		Status(http.StatusOK)
+	srv.Delete("/users/1").Header("accept", "application/json").Do(nil).
+		Status(http.StatusInternalServerError)
+
+	// 删除所有
+	srv.Delete("/__admin__/overrides").Do(nil).
+		Status(http.StatusNoContent)
+	srv.Delete("/users/1").Header("accept", "application/json").Do(nil).
+		Status(http.StatusNotFound) // 有状态模式下不存在该数据
+	srv.Get("/__admin__/overrides").Do(nil).
+		Status(http.StatusOK).
+		StringBody("[]")
+}
+
+func TestAdmin_journal(t *testing.T) {
+	a := assert.New(t, false)
+	srv, rslt := newTestAdmin(a)
+	defer rslt.Handler.Stop()
+
+	srv.Get("/users/1").Header("accept", "application/json").Do(nil).
+		Status(http.StatusOK)
+	srv.Post("/users", []byte(`{"name":1}`)).
+		Header("accept", "application/json").
+		Header("content-type", "application/json").
+		Do(nil).
+		Status(http.StatusBadRequest)
+	srv.Get("/not-exists").Do(nil).
+		Status(http.StatusNotFound)
+
+	srv.Get("/__admin__/journal").Do(nil).
+		Status(http.StatusOK).
+		BodyFunc(func(a *assert.Assertion, body []byte) {
+			entries := make([]*journalEntry, 0, 3)
+			a.NotError(json.Unmarshal(body, &entries)).Equal(3, len(entries))
+
+			a.Equal(entries[0].Method, http.MethodGet).
+				Equal(entries[0].URL, "/users/1").
+				Equal(entries[0].API, "GET /users/{id}").
+				Equal(entries[0].Status, http.StatusOK).
+				Empty(entries[0].Errors)
+
+			a.Equal(entries[1].API, "POST /users").
+				Equal(entries[1].Status, http.StatusBadRequest).
+				Equal(1, len(entries[1].Errors))
+
+			a.Empty(entries[2].API).
+				Equal(entries[2].Status, http.StatusNotFound)
+		})
+
+	srv.Delete("/__admin__/journal").Do(nil).
+		Status(http.StatusNoContent)
+	srv.Get("/__admin__/journal").Do(nil).
+		Status(http.StatusOK).
+		StringBody("[]")
+}
+
+func TestAdmin_addJournal(t *testing.T) {
+	a := assert.New(t, false)
+
+	adm := &admin{journal: make([]*journalEntry, 0, journalSize)}
+	for i := 0; i < journalSize+10; i++ {
+		adm.addJournal(&journalEntry{Status: i})
+	}
+	a.Equal(journalSize, len(adm.journal)).
+		Equal(adm.journal[0].Status, 10).
+		Equal(adm.journal[journalSize-1].Status, journalSize+9)
+}
+
+func TestAdmin_resetState(t *testing.T) {
+	a := assert.New(t, false)
+	srv, rslt := newTestAdmin(a)
+	defer rslt.Handler.Stop()
+
+	srv.Post("/users", []byte(`{"name":"n1"}`)).
+		Header("accept", "application/json").
+		Header("content-type", "application/json").
+		Do(nil).
+		Status(http.StatusCreated).
+		JSONBody(&stateUser{ID: 1, Name: "n1"})
+	srv.Get("/users/1").Header("accept", "application/json").Do(nil).
+		Status(http.StatusOK).
+		JSONBody(&stateUser{ID: 1, Name: "n1"})
+
+	srv.Delete("/__admin__/state").Do(nil).
+		Status(http.StatusNoContent)
+
+	srv.Delete("/users/1").Header("accept", "application/json").Do(nil).
+		Status(http.StatusNotFound)
+	srv.Post("/users", []byte(`{"name":"n2"}`)).
+		Header("accept", "application/json").
+		Header("content-type", "application/json").
+		Do(nil).
+		Status(http.StatusCreated).
+		JSONBody(&stateUser{ID: 1, Name: "n2"})
+}
+
+func TestAdmin_reload(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 从文件加载时需要指定 apidoc 属性
+	doc := strings.Replace(statefulDoc, "<apidoc ", `<apidoc apidoc="`+ast.Version+`" `, 1)
+	path := filepath.Join(t.TempDir(), "apidoc.xml")
+	a.NotError(os.WriteFile(path, []byte(doc), os.ModePerm))
+
+	rslt := messagetest.NewMessageHandler()
+	h, err := Load(rslt.Handler, core.FileURI(path), &Options{ImageURL: "/images", Gen: testOptions, Stateful: true, Admin: "/__admin__"})
+	a.NotError(err).NotNil(h)
+	srv := rest.NewServer(a, h, nil)
+
+	srv.Post("/users", []byte(`{"name":"n1"}`)).
+		Header("accept", "application/json").
+		Header("content-type", "application/json").
+		Do(nil).
+		Status(http.StatusCreated)
+	srv.Put("/__admin__/overrides", []byte(`{"method":"GET","path":"/users","status":503,"body":"maintenance"}`)).Do(nil).
+		Status(http.StatusOK)
+	srv.Get("/posts").Do(nil).Status(http.StatusNotFound)
+
+	doc = strings.Replace(doc, "</apidoc>", `<api method="GET" summary="posts">
+		<path path="/posts" />
+		<response status="200" type="string" />
+	</api>
+</apidoc>`, 1)
+	a.NotError(os.WriteFile(path, []byte(doc), os.ModePerm))
+
+	srv.Post("/__admin__/reload", nil).Do(nil).
+		Status(http.StatusOK).
+		BodyFunc(func(a *assert.Assertion, body []byte) {
+			a.Contains(routePaths(a, body), "/posts")
+		})
+
+	srv.Get("/posts").Header("accept", "application/json").Do(nil).Status(http.StatusOK)
+
+	// 数据和覆盖设置都被保留
+	srv.Get("/users/1").Header("accept", "application/json").Do(nil).
+		Status(http.StatusOK).
+		JSONBody(&stateUser{ID: 1, Name: "n1"})
+	srv.Get("/users").Header("accept", "application/json").Do(nil).
+		Status(http.StatusServiceUnavailable).
+		StringBody("maintenance")
+
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors).NotEmpty(rslt.Infos)
+
+	// 没有文档路径
+	srv, rslt = newTestAdmin(a)
+	srv.Post("/__admin__/reload", nil).Do(nil).
+		Status(http.StatusBadRequest)
+	rslt.Handler.Stop()
+}
@@ -3,8 +3,9 @@
 package mock
 
 import (
-	"fmt"
+	"bytes"
 	"io"
+	"mime"
 	"net/http"
 	"strconv"
 	"strings"
@@ -17,43 +18,133 @@ import (
 	"github.com/caixw/apidoc/v7/internal/locale"
 )
 
+// ExampleHeader 用于指定示例代码的报头
+//
+// 在启用了示例代码的情况下，可以通过该报头指定与 summary 相同的示例代码。
+const ExampleHeader = "X-Apidoc-Example"
+
 func (m *mock) buildAPI(api *ast.API) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		m.msgHandler.Locale(core.Succ, locale.RequestAPI, r.Method, r.URL.Path)
 		if api.Deprecated != nil {
 			m.msgHandler.Locale(core.Warn, locale.DeprecatedWarn, r.Method, r.URL.Path, api.Deprecated.V())
 		}
+		if e := journalEntryOf(r); e != nil {
+			e.API = apiName(api.Method.V(), api.Path.Path.V())
+		}
 
-		if err := validQueries(api.Path.Queries, r); err != nil {
-			m.handleError(w, r, "", err)
+		ov := m.override(api)
+		if ov != nil && !m.applyOverride(ov, api, w, r) {
 			return
 		}
 
-		for _, header := range api.Headers {
-			field := "headers[" + header.Name.V() + "]"
-			if err := validSimpleParam(header, field, r.Header.Get(header.Name.V())); err != nil {
-				m.handleError(w, r, field, err)
-				return
-			}
+		w, status, ok := m.injectFault(api, w, r)
+		if !ok {
+			return
+		}
+		if ov != nil && ov.Status > 0 { // 管理接口指定的状态码优先于故障注入
+			status = ov.Status
+		}
+
+		if m.security && !hasCredential(m.doc, api, r) {
+			m.reportError(r, "security", locale.NewError(locale.ErrMissingCredential))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
 		}
 
-		if len(api.Requests) > 0 { // GET、OPTIONS 之类的可能没有 body
-			if err := validRequest(m.doc.XMLNamespaces, api.Requests, r); err != nil {
+		var body []byte
+		if m.store != nil || m.fixtures != nil { // 有状态模式下需要保存提交的内容，匹配录制内容时也需要。
+			var err error
+			if body, err = io.ReadAll(r.Body); err != nil {
 				m.handleError(w, r, "request.body.", err)
 				return
 			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		if field, err := validAPIRequest(m.doc.XMLNamespaces, api, r); err != nil {
+			m.handleError(w, r, field, err)
+			return
+		}
+
+		if !m.callback {
+			m.render(api, w, r, status, body)
+			return
 		}
 
-		m.renderResponse(api, w, r)
+		// 仅在成功返回之后才发送回调
+		sw := &statusWriter{ResponseWriter: w}
+		m.render(api, sw, r, status, body)
+		if sw.success() {
+			m.triggerCallback(api, r)
+		}
 	})
 }
 
+// 输出 api 的返回内容
+//
+// status 为通过 Prefer 或是故障注入指定的状态码，为 0 表示未指定。
+func (m *mock) render(api *ast.API, w http.ResponseWriter, r *http.Request, status int, body []byte) {
+	// 通过 Prefer 或是故障注入指定了返回内容的，不再采用有状态模式和录制的内容。
+	specified := status > 0 || preferred(r)
+
+	if m.store != nil && !specified && m.renderState(api, w, r, body) {
+		return
+	}
+
+	if m.fixtures != nil && !specified {
+		if fx := m.fixtures.find(r, body); fx != nil {
+			if err := fx.render(w); err != nil {
+				m.msgHandler.Error(requestError(r, "", err))
+			}
+			return
+		}
+	}
+
+	m.renderResponse(api, w, r, status)
+}
+
+// 验证请求是否符合 api 的定义
+//
+// 返回出错的字段前缀以及错误信息。
+func validAPIRequest(ns []*ast.XMLNamespace, api *ast.API, r *http.Request) (string, error) {
+	if err := validQueries(api.Path.Queries, r); err != nil {
+		return "", err
+	}
+
+	for _, header := range api.Headers {
+		field := "headers[" + header.Name.V() + "]"
+		if err := validSimpleParam(header, field, r.Header.Get(header.Name.V())); err != nil {
+			return field, err
+		}
+	}
+
+	for _, cookie := range api.Cookies {
+		field := "cookies[" + cookie.Name.V() + "]"
+		if err := validSimpleParam(cookie, field, findCookie(r.Cookies(), cookie.Name.V())); err != nil {
+			return field, err
+		}
+	}
+
+	if len(api.Requests) > 0 { // GET、OPTIONS 之类的可能没有 body
+		if err := validRequest(ns, api.Requests, r); err != nil {
+			return "request.body.", err
+		}
+	}
+
+	return "", nil
+}
+
 func validRequest(ns []*ast.XMLNamespace, requests []*ast.Request, r *http.Request) error {
 	ct := r.Header.Get("Content-Type")
 	if ct == "" || ct == "*/*" || strings.HasSuffix(ct, "/*") { // 用户提交的 content-type 必须是明确的值
 		return core.NewError(locale.ErrInvalidValue).WithField("headers[content-type]")
 	}
-	req := findRequestByContentType(requests, ct)
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return core.NewError(locale.ErrInvalidValue).WithField("headers[content-type]")
+	}
+	req := findRequestByContentType(requests, mt)
 	if req == nil {
 		return core.NewError(locale.ErrInvalidValue).WithField("headers[content-type]")
 	}
@@ -64,6 +155,12 @@ func validRequest(ns []*ast.XMLNamespace, requests []*ast.Request, r *http.Reque
 		}
 	}
 
+	for _, cookie := range req.Cookies {
+		if err := validSimpleParam(cookie, "cookies["+cookie.Name.V()+"]", findCookie(r.Cookies(), cookie.Name.V())); err != nil {
+			return err
+		}
+	}
+
 	content, err := io.ReadAll(r.Body)
 	if err != nil {
 		return err
@@ -72,49 +169,114 @@ func validRequest(ns []*ast.XMLNamespace, requests []*ast.Request, r *http.Reque
 		return err
 	}
 
-	switch ct {
+	return validContent(ns, ct, req, content, true)
+}
+
+// 根据 ct 验证 content 是否符合 req 的定义
+//
+// ct 为完整的 Content-Type 报头，可以包含 boundary 等参数；
+// request 表示 content 是否为请求的内容，否则为返回的内容。
+func validContent(ns []*ast.XMLNamespace, ct string, req *ast.Request, content []byte, request bool) error {
+	mt, params, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return core.NewError(locale.ErrInvalidValue).WithField("headers[content-type]")
+	}
+
+	switch mt {
 	case "application/json":
-		return validJSON(req, content)
+		return validJSON(req, content, request)
 	case "application/xml", "text/xml":
-		return validXML(ns, req, content)
+		return validXML(ns, req, content, request)
+	case "application/x-www-form-urlencoded":
+		return validForm(req, content)
+	case "multipart/form-data":
+		return validMultipart(req, content, params["boundary"])
 	default:
 		return core.NewError(locale.ErrInvalidValue).WithField("headers[content-type]")
 	}
 }
 
-func (m *mock) renderResponse(api *ast.API, w http.ResponseWriter, r *http.Request) {
+func (m *mock) renderResponse(api *ast.API, w http.ResponseWriter, r *http.Request, status int) {
+	p, err := parsePrefer(r)
+	if err != nil {
+		m.handleError(w, r, "headers["+preferHeader+"].", err)
+		return
+	}
+
+	code := p.code
+	if code == 0 { // Prefer 的优先级高于故障注入
+		code = status
+	}
+
+	responses, docResponses := api.Responses, m.doc.Responses
+	if code > 0 { // 只能返回指定状态码的内容
+		responses = filterResponses(responses, code)
+		docResponses = filterResponses(docResponses, code)
+		if len(responses) == 0 && len(docResponses) == 0 {
+			m.handleError(w, r, "headers["+preferHeader+"].code", locale.NewError(locale.ErrNotFound))
+			return
+		}
+	}
+
 	accepts := qheader.Accept(r)
 
-	resp, accept := findResponseByAccept(m.doc.Mimetypes, api.Responses, accepts.Items)
+	resp, accept := findResponseByAccept(m.doc.Mimetypes, responses, accepts.Items)
 	if resp == nil {
 		// 仅在 api.Responses 无法匹配任何内容的时候，才从 doc.Responses 中查找内容
-		resp, accept = findResponseByAccept(m.doc.Mimetypes, m.doc.Responses, accepts.Items)
+		resp, accept = findResponseByAccept(m.doc.Mimetypes, docResponses, accepts.Items)
 		if resp == nil {
 			m.handleError(w, r, "headers[Accept]", locale.NewError(locale.ErrInvalidValue))
 			return
 		}
 	}
 
-	data, err := m.buildResponse(resp, r)
-	if err != nil {
-		m.handleError(w, r, "response.body.", err)
-		return
+	var data []byte
+	if m.examples || p.example != "" { // 通过 Prefer 指定了示例代码，则忽略 m.examples 的设置。
+		field := "headers[" + preferHeader + "].example"
+		name := p.example
+		if name == "" {
+			field = "headers[" + ExampleHeader + "]"
+			name = r.Header.Get(ExampleHeader)
+		}
+
+		example, err := findExample(resp.Examples, accept, name)
+		if err != nil {
+			m.handleError(w, r, field, err)
+			return
+		}
+		if example != nil {
+			data = []byte(strings.TrimSpace(example.Content.Value.Value))
+		}
+	}
+
+	g := m.requestGen(r)
+	if data == nil {
+		if data, err = m.buildResponse(resp, r, g); err != nil {
+			m.handleError(w, r, "response.body.", err)
+			return
+		}
 	}
 
 	w.Header().Set("Content-Type", accept)
 	w.Header().Set("Server", core.Name)
+	if applied := p.applied(); applied != "" {
+		w.Header().Set(preferAppliedHeader, applied)
+	}
 	for _, item := range resp.Headers {
-		switch primitive, _ := ast.ParseType(item.Type.V()); primitive {
-		case ast.TypeBool:
-			w.Header().Set(item.Name.V(), strconv.FormatBool(m.gen.generateBool()))
-		case ast.TypeNumber:
-			w.Header().Set(item.Name.V(), fmt.Sprint(m.gen.generateNumber(item)))
-		case ast.TypeString:
-			w.Header().Set(item.Name.V(), m.gen.generateString(item))
-		default:
+		val, ok := g.generateSimple(item)
+		if !ok {
 			m.handleError(w, r, "response.headers", locale.NewError(locale.ErrInvalidFormat))
 			return
 		}
+		w.Header().Set(item.Name.V(), val)
+	}
+	for _, item := range resp.Cookies {
+		val, ok := g.generateSimple(item)
+		if !ok {
+			m.handleError(w, r, "response.cookies", locale.NewError(locale.ErrInvalidFormat))
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: item.Name.V(), Value: val})
 	}
 
 	w.WriteHeader(resp.Status.V())
@@ -123,6 +285,37 @@ func (m *mock) renderResponse(api *ast.API, w http.ResponseWriter, r *http.Reque
 	}
 }
 
+// 查找名称为 name 的 cookie 值，找不到则返回空值。
+func findCookie(cookies []*http.Cookie, name string) string {
+	for _, cookie := range cookies {
+		if cookie.Name == name {
+			return cookie.Value
+		}
+	}
+	return ""
+}
+
+// 查找与 mimetype 相匹配的示例代码
+//
+// name 不为空时，仅匹配 summary 与 name 相同的示例代码，找不到则返回错误；
+// name 为空时，返回第一个与 mimetype 相匹配的示例代码，找不到则返回 nil。
+func findExample(examples []*ast.Example, mimetype, name string) (*ast.Example, error) {
+	for _, example := range examples {
+		if example.Mimetype.V() != mimetype || example.Content == nil {
+			continue
+		}
+
+		if name == "" || example.Summary.V() == name {
+			return example, nil
+		}
+	}
+
+	if name != "" {
+		return nil, core.NewError(locale.ErrNotFound)
+	}
+	return nil, nil
+}
+
 // 需要保证 ct 的值不能为空
 func findRequestByContentType(requests []*ast.Request, ct string) *ast.Request {
 	var none *ast.Request
@@ -185,6 +378,21 @@ func matchContentType(ct string, accepts []*qheader.Item) bool {
 
 // 处理 serveHTTP 中的错误
 func (m *mock) handleError(w http.ResponseWriter, r *http.Request, field string, err error) {
+	m.reportError(r, field, err)
+	w.WriteHeader(http.StatusBadRequest)
+}
+
+// 输出错误信息，同时记录到请求日志中。
+func (m *mock) reportError(r *http.Request, field string, err error) {
+	err = requestError(r, field, err)
+	m.msgHandler.Error(err)
+	if e := journalEntryOf(r); e != nil {
+		e.Errors = append(e.Errors, err.Error())
+	}
+}
+
+// 将 err 转换为与当前请求相关联的错误信息
+func requestError(r *http.Request, field string, err error) error {
 	// 这并不是一个真实存在的 URI
 	file := core.URI(r.Method + ": " + r.URL.Path)
 
@@ -200,8 +408,7 @@ func (m *mock) handleError(w http.ResponseWriter, r *http.Request, field string,
 		err = (core.Location{URI: file}).WithError(err).WithField(field)
 	}
 
-	m.msgHandler.Error(err)
-	w.WriteHeader(http.StatusBadRequest)
+	return err
 }
 
 func validQueries(queries []*ast.Param, r *http.Request) error {
@@ -220,22 +427,34 @@ func validQueries(queries []*ast.Param, r *http.Request) error {
 			if err := valid(query, r.FormValue(query.Name.V())); err != nil {
 				return err
 			}
-		} else if !query.ArrayStyle.V() { // 默认的 form 格式
+			continue
+		}
+
+		var values []string
+		if !query.ArrayStyle.V() { // 默认的 form 格式
 			if err := r.ParseForm(); err != nil {
 				return err
 			}
-			for _, v := range r.Form[query.Name.V()] {
+			values = r.Form[query.Name.V()]
+			for _, v := range values {
 				if err := valid(query, v); err != nil {
 					return err
 				}
 			}
 		} else {
-			values := strings.Split(r.FormValue(query.Name.V()), ",")
+			values = strings.Split(r.FormValue(query.Name.V()), ",")
 			for _, v := range values {
 				if err := valid(query, v); err != nil {
 					return err
 				}
 			}
+			if len(values) == 1 && values[0] == "" { // 未提交任何值
+				values = nil
+			}
+		}
+
+		if err := query.CheckItems(len(values), values); err != nil {
+			return err.WithField(field)
 		}
 	}
 
@@ -248,12 +467,15 @@ func validSimpleParam(p *ast.Param, name, val string) error {
 		return nil
 	}
 
-	if val == "" && p.Type.V() != ast.TypeString { // 字符串的默认值可以为 “”
+	if val == "" {
 		if (p.Optional != nil && p.Optional.V()) ||
 			(p.Default != nil && p.Default.V() != "") {
 			return nil
 		}
-		return core.NewError(locale.ErrIsEmpty, name)
+
+		if p.Type.V() != ast.TypeString { // 字符串的默认值可以为 “”
+			return core.NewError(locale.ErrIsEmpty, name)
+		}
 	}
 
 	switch p.Type.V() {
@@ -287,10 +509,16 @@ func validSimpleParam(p *ast.Param, name, val string) error {
 		}
 	}
 
+	if val != "" {
+		if err := p.CheckValue(val); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func (m *mock) buildResponse(p *ast.Request, r *http.Request) ([]byte, error) {
+func (m *mock) buildResponse(p *ast.Request, r *http.Request, g *GenOptions) ([]byte, error) {
 	if p == nil {
 		return nil, nil
 	}
@@ -306,9 +534,9 @@ func (m *mock) buildResponse(p *ast.Request, r *http.Request) ([]byte, error) {
 		for _, h := range headers.Items {
 			switch strings.ToLower(h.Value) {
 			case "application/json", "*/*":
-				return buildJSON(p, m.indent, m.gen)
+				return buildJSON(p, false, m.indent, g)
 			case "application/xml", "text/xml":
-				return buildXML(m.doc.XMLNamespaces, p, m.indent, m.gen)
+				return buildXML(m.doc.XMLNamespaces, p, false, m.indent, g)
 			}
 		}
 	}
@@ -213,6 +213,43 @@ func TestFindResponseByAccept(t *testing.T) {
 	}
 }
 
+func TestFindExample(t *testing.T) {
+	a := assert.New(t, false)
+
+	newExample := func(mimetype, summary, content string) *ast.Example {
+		return &ast.Example{
+			Mimetype: &ast.Attribute{Value: xmlenc.String{Value: mimetype}},
+			Summary:  &ast.Attribute{Value: xmlenc.String{Value: summary}},
+			Content:  &ast.ExampleValue{Value: xmlenc.String{Value: content}},
+		}
+	}
+	examples := []*ast.Example{
+		newExample("application/json", "s1", "json1"),
+		newExample("application/xml", "s2", "xml"),
+		newExample("application/json", "s3", "json3"),
+	}
+
+	example, err := findExample(examples, "application/json", "")
+	a.NotError(err).Equal(example, examples[0])
+
+	example, err = findExample(examples, "application/json", "s3")
+	a.NotError(err).Equal(example, examples[2])
+
+	example, err = findExample(examples, "application/xml", "")
+	a.NotError(err).Equal(example, examples[1])
+
+	// 不存在的 mimetype
+	example, err = findExample(examples, "text/plain", "")
+	a.NotError(err).Nil(example)
+
+	// 不存在的名称
+	example, err = findExample(examples, "application/json", "s2")
+	a.Error(err).Nil(example)
+
+	example, err = findExample(nil, "application/json", "")
+	a.NotError(err).Nil(example)
+}
+
 func TestValidRequest(t *testing.T) {
 	a := assert.New(t, false)
 
@@ -241,6 +278,95 @@ func TestValidRequest(t *testing.T) {
 	a.Error(validRequest(nil, []*ast.Request{dataWithHeader.Type}, r))
 }
 
+func TestValidAPIRequest_cookies(t *testing.T) {
+	a := assert.New(t, false)
+
+	api := &ast.API{
+		Path: &ast.Path{},
+		Cookies: []*ast.Param{
+			{
+				Name: &ast.Attribute{Value: xmlenc.String{Value: "sid"}},
+				Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeNumber}},
+			},
+			{
+				Name:     &ast.Attribute{Value: xmlenc.String{Value: "lang"}},
+				Type:     &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+				Optional: &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+			},
+		},
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/path", nil)
+	r.AddCookie(&http.Cookie{Name: "sid", Value: "1024"})
+	field, err := validAPIRequest(nil, api, r)
+	a.NotError(err).Empty(field)
+
+	// 格式不正确
+	r = httptest.NewRequest(http.MethodGet, "/path", nil)
+	r.AddCookie(&http.Cookie{Name: "sid", Value: "xx"})
+	field, err = validAPIRequest(nil, api, r)
+	a.Error(err).Equal(field, "cookies[sid]")
+
+	// 缺少必须的 cookie
+	r = httptest.NewRequest(http.MethodGet, "/path", nil)
+	r.AddCookie(&http.Cookie{Name: "lang", Value: "cmn-Hans"})
+	field, err = validAPIRequest(nil, api, r)
+	a.Error(err).Equal(field, "cookies[sid]")
+}
+
+func TestMock_renderResponse_cookies(t *testing.T) {
+	a := assert.New(t, false)
+
+	m := &mock{
+		indent: indent,
+		gen:    testOptions,
+		doc:    &ast.APIDoc{},
+	}
+	api := &ast.API{
+		Responses: []*ast.Request{
+			{
+				Status:   &ast.StatusAttribute{Value: ast.Number{Int: http.StatusOK}},
+				Mimetype: &ast.Attribute{Value: xmlenc.String{Value: "application/json"}},
+				Type:     &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeNone}},
+				Cookies: []*ast.Param{
+					{
+						Name: &ast.Attribute{Value: xmlenc.String{Value: "sid"}},
+						Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeNumber}},
+					},
+					{
+						Name: &ast.Attribute{Value: xmlenc.String{Value: "lang"}},
+						Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+					},
+				},
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/path", nil)
+	r.Header.Set("accept", "application/json")
+	m.renderResponse(api, w, r, 0)
+	a.Equal(w.Code, http.StatusOK)
+
+	cookies := w.Result().Cookies()
+	a.Equal(len(cookies), 2).
+		Equal(findCookie(cookies, "sid"), "1024").
+		Equal(findCookie(cookies, "lang"), "1024")
+}
+
+func TestFindCookie(t *testing.T) {
+	a := assert.New(t, false)
+
+	cookies := []*http.Cookie{
+		{Name: "c1", Value: "v1"},
+		{Name: "c2", Value: "v2"},
+	}
+	a.Equal(findCookie(cookies, "c1"), "v1").
+		Equal(findCookie(cookies, "c2"), "v2").
+		Empty(findCookie(cookies, "c3")).
+		Empty(findCookie(nil, "c1"))
+}
+
 func TestBuildResponse(t *testing.T) {
 	a := assert.New(t, false)
 
@@ -250,28 +376,28 @@ func TestBuildResponse(t *testing.T) {
 		doc:    &ast.APIDoc{},
 	}
 
-	resp, err := m.buildResponse(nil, nil)
+	resp, err := m.buildResponse(nil, nil, testOptions)
 	a.NotError(err).Nil(resp)
 
 	// 匹配 json
 	r := httptest.NewRequest(http.MethodGet, "/path", nil)
 	r.Header.Set("accept", "application/json")
 	r.Header.Set("encoding", "xxx")
-	resp, err = m.buildResponse(dataWithHeader.Type, r)
+	resp, err = m.buildResponse(dataWithHeader.Type, r, testOptions)
 	a.NotError(err).Equal(string(resp), dataWithHeader.JSON)
 
 	// 匹配 xml
 	r = httptest.NewRequest(http.MethodGet, "/path", nil)
 	r.Header.Set("accept", "application/json;q=0.1,application/xml")
 	r.Header.Set("encoding", "yyy")
-	resp, err = m.buildResponse(dataWithHeader.Type, r)
+	resp, err = m.buildResponse(dataWithHeader.Type, r, testOptions)
 	a.NotError(err).Equal(string(resp), dataWithHeader.XML)
 
 	// 无法匹配 content-type
 	r = httptest.NewRequest(http.MethodGet, "/path", nil)
 	r.Header.Set("content-type", "not-exists")
 	r.Header.Set("encoding", "xxx")
-	resp, err = m.buildResponse(dataWithHeader.Type, r)
+	resp, err = m.buildResponse(dataWithHeader.Type, r, testOptions)
 	a.Error(err).Nil(resp)
 }
 
@@ -355,6 +481,56 @@ func TestValidSimpleParam(t *testing.T) {
 			p:     &ast.Param{Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}}},
 			v:     "-xxx10.2",
 		},
+		{
+			title: "string with optional enum",
+			p: &ast.Param{
+				Type:     &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+				Optional: &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+				Enums: []*ast.Enum{
+					{Value: &ast.Attribute{Value: xmlenc.String{Value: "1"}}},
+				},
+			},
+			v: "",
+		},
+		{
+			title: "string with enum failed",
+			p: &ast.Param{
+				Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+				Enums: []*ast.Enum{
+					{Value: &ast.Attribute{Value: xmlenc.String{Value: "1"}}},
+				},
+			},
+			v:   "",
+			err: true,
+		},
+		{
+			title: "number with range",
+			p: &ast.Param{
+				Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeNumber}},
+				Min:  &ast.NumberAttribute{Value: ast.Number{Int: 1}},
+				Max:  &ast.NumberAttribute{Value: ast.Number{Int: 10}},
+			},
+			v: "10",
+		},
+		{
+			title: "number with range failed",
+			p: &ast.Param{
+				Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeNumber}},
+				Min:  &ast.NumberAttribute{Value: ast.Number{Int: 1}},
+				Max:  &ast.NumberAttribute{Value: ast.Number{Int: 10}},
+			},
+			v:   "11",
+			err: true,
+		},
+		{
+			title: "string with pattern failed",
+			p: &ast.Param{
+				Type:    &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+				Pattern: &ast.Attribute{Value: xmlenc.String{Value: "^[a-z]+$"}},
+			},
+			v:   "123",
+			err: true,
+		},
 		{
 			title: "doc.None",
 			p:     &ast.Param{Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeNone}}},
@@ -477,6 +653,32 @@ func TestValidQueries(t *testing.T) {
 			r:   httptest.NewRequest(http.MethodGet, "/users?k1=1&k2=2,3,not-number", nil),
 			err: true,
 		},
+		{
+			title: "数组-unique-items",
+			p: []*ast.Param{
+				{
+					Name:        &ast.Attribute{Value: xmlenc.String{Value: "k1"}},
+					Type:        &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeNumber}},
+					Array:       &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+					UniqueItems: &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+				},
+			},
+			r:   httptest.NewRequest(http.MethodGet, "/users?k1=1&k1=1", nil),
+			err: true,
+		},
+		{
+			title: "数组-max-items",
+			p: []*ast.Param{
+				{
+					Name:     &ast.Attribute{Value: xmlenc.String{Value: "k1"}},
+					Type:     &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeNumber}},
+					Array:    &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+					MaxItems: &ast.NumberAttribute{Value: ast.Number{Int: 2}},
+				},
+			},
+			r:   httptest.NewRequest(http.MethodGet, "/users?k1=1&k1=2&k1=3", nil),
+			err: true,
+		},
 	}
 
 	for _, item := range data {
@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/issue9/qheader"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/locale"
+)
+
+// CallbackHeader 用于指定回调地址的报头
+//
+// 启用回调之后，包含此报头的请求在处理完成之后，
+// 会根据 api 中 callback 的定义向该报头指定的地址发送请求。
+const CallbackHeader = "X-Apidoc-Callback"
+
+// 发送回调请求的超时时间
+const callbackTimeout = 10 * time.Second
+
+// 回调请求可以生成的内容类型
+var callbackAccepts = []*qheader.Item{
+	{Value: "application/json"},
+	{Value: "application/xml"},
+	{Value: "text/xml"},
+}
+
+// 用于获取输出状态码的 http.ResponseWriter
+//
+// 回调和管理接口的请求日志都依赖该对象获取状态码。
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// 故障注入需要通过 Hijack 断开连接
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hj, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return hj.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// 是否返回了 2XX 的状态码，未输出任何内容时相当于 200。
+func (w *statusWriter) success() bool {
+	return w.status == 0 || (w.status >= 200 && w.status < 300)
+}
+
+// 根据 api.Callback 的定义向 r 中指定的地址发送回调请求
+//
+// 回调请求在单独的 goroutine 中发送，不会影响对 r 的返回，
+// 所有的错误信息都只输出到 m.msgHandler。
+func (m *mock) triggerCallback(api *ast.API, r *http.Request) {
+	target := r.Header.Get(CallbackHeader)
+	if api.Callback == nil || target == "" {
+		return
+	}
+
+	req, err := m.newCallbackRequest(api.Callback, target)
+	if err != nil {
+		m.msgHandler.Error(requestError(r, "callback.", err))
+		return
+	}
+
+	go m.sendCallback(api.Callback, req)
+}
+
+// 根据 callback 的定义生成发送至 target 的请求
+func (m *mock) newCallbackRequest(callback *ast.Callback, target string) (*http.Request, error) {
+	u, err := url.Parse(target)
+	if err != nil || !u.IsAbs() {
+		return nil, core.NewError(locale.ErrInvalidFormat).WithField("headers[" + CallbackHeader + "]")
+	}
+
+	req, mimetype := findResponseByAccept(m.doc.Mimetypes, callback.Requests, callbackAccepts)
+	if req == nil {
+		return nil, core.NewError(locale.ErrInvalidValue).WithField("request.mimetype")
+	}
+
+	var body []byte
+	if mimetype == "application/json" {
+		body, err = buildJSON(req, true, m.indent, m.gen)
+	} else {
+		body, err = buildXML(m.doc.XMLNamespaces, req, true, m.indent, m.gen)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if callback.Path != nil && len(callback.Path.Queries) > 0 {
+		queries := u.Query()
+		for _, query := range callback.Path.Queries {
+			val, ok := m.gen.generateSimple(query)
+			if !ok {
+				return nil, core.NewError(locale.ErrInvalidFormat).WithField("path.queries")
+			}
+			queries.Add(query.Name.V(), val)
+		}
+		u.RawQuery = queries.Encode()
+	}
+
+	r, err := http.NewRequest(callback.Method.V(), u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > 0 {
+		r.Header.Set("Content-Type", mimetype)
+	}
+	r.Header.Set("User-Agent", core.Name)
+
+	for _, headers := range [][]*ast.Param{callback.Headers, req.Headers} {
+		for _, header := range headers {
+			val, ok := m.gen.generateSimple(header)
+			if !ok {
+				return nil, core.NewError(locale.ErrInvalidFormat).WithField("headers[" + header.Name.V() + "]")
+			}
+			r.Header.Set(header.Name.V(), val)
+		}
+	}
+	for _, cookie := range req.Cookies {
+		val, ok := m.gen.generateSimple(cookie)
+		if !ok {
+			return nil, core.NewError(locale.ErrInvalidFormat).WithField("cookies[" + cookie.Name.V() + "]")
+		}
+		r.AddCookie(&http.Cookie{Name: cookie.Name.V(), Value: val})
+	}
+
+	return r, nil
+}
+
+// 发送回调请求，并根据 callback.Responses 验证返回的内容
+func (m *mock) sendCallback(callback *ast.Callback, r *http.Request) {
+	m.msgHandler.Locale(core.Succ, locale.RequestCallback, r.Method, r.URL.String())
+
+	client := &http.Client{Timeout: callbackTimeout}
+	resp, err := client.Do(r)
+	if err != nil {
+		m.msgHandler.Error(requestError(r, "callback.", err))
+		return
+	}
+	defer resp.Body.Close()
+
+	if len(callback.Responses) == 0 { // 未定义返回内容，则不作验证。
+		return
+	}
+
+	responses := filterResponses(callback.Responses, resp.StatusCode)
+	if field, err := validHTTPResponse(m.doc.XMLNamespaces, responses, resp); err != nil {
+		m.msgHandler.Error(requestError(r, "callback."+field, err))
+	}
+}
@@ -0,0 +1,189 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v3"
+	"github.com/issue9/assert/v3/rest"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/core/messagetest"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+const callbackDoc = `<apidoc version="1.1.1">
+	<title>test</title>
+	<mimetype>application/json</mimetype>
+	<api method="POST" summary="create">
+		<path path="/users" />
+		<request type="object" mimetype="application/json">
+			<param name="name" type="string" summary="name" />
+		</request>
+		<response status="201" />
+		<response status="400" type="string" />
+		<callback method="POST" summary="callback">
+			<path path="/events">
+				<query name="event" type="string" summary="event" />
+			</path>
+			<header name="x-sign" type="string" summary="sign" />
+			<request type="object" mimetype="application/json">
+				<param name="id" type="number" summary="id" />
+				<param name="secret" type="string" summary="secret" writeonly="true" />
+				<param name="created" type="string" summary="created" readonly="true" />
+			</request>
+			<response status="200" />
+		</callback>
+	</api>
+</apidoc>`
+
+func newCallbackMock(a *assert.Assertion, callback bool) (*mock, *messagetest.Result) {
+	rslt := messagetest.NewMessageHandler()
+	d := &ast.APIDoc{APIDoc: &ast.APIDocVersionAttribute{Value: xmlenc.String{Value: ast.Version}}}
+	d.Parse(rslt.Handler, core.Block{Data: []byte(callbackDoc)})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	rslt = messagetest.NewMessageHandler()
+	m, err := New(rslt.Handler, d, &Options{Indent: indent, Gen: testOptions, Callback: callback})
+	a.NotError(err).NotNil(m)
+	return m.(*mock), rslt
+}
+
+func TestMock_callback(t *testing.T) {
+	a := assert.New(t, false)
+
+	requests := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		a.NotError(err)
+		requests <- r
+		bodies <- body
+	}))
+	defer srv.Close()
+
+	m, rslt := newCallbackMock(a, true)
+	rest.NewServer(a, m, nil).Post("/users", []byte(`{"name":"n1"}`)).
+		Header("content-type", "application/json").
+		Header("accept", "application/json").
+		Header(CallbackHeader, srv.URL+"/hook").
+		Do(nil).
+		Status(http.StatusCreated)
+
+	select {
+	case r := <-requests:
+		a.Equal(r.Method, http.MethodPost).
+			Equal(r.URL.Path, "/hook").
+			Equal(r.URL.Query().Get("event"), "1024").
+			Equal(r.Header.Get("x-sign"), "1024").
+			Equal(r.Header.Get("Content-Type"), "application/json")
+
+		obj := map[string]any{}
+		a.NotError(json.Unmarshal(<-bodies, &obj)).
+			Equal(obj["id"], 1024).
+			Equal(obj["secret"], "1024"). // 回调的请求内容包含只写字段，不包含只读字段。
+			NotContains(obj, "created")
+	case <-time.After(time.Second):
+		a.TB().Fatal("未收到回调请求")
+	}
+	rslt.Handler.Stop()
+
+	// 未成功返回的请求，不发送回调。
+	m, rslt = newCallbackMock(a, true)
+	srv2 := rest.NewServer(a, m, nil)
+	srv2.Post("/users", []byte(`{"name":"n1"}`)).
+		Header("content-type", "application/json").
+		Header("accept", "application/json").
+		Header("Prefer", "code=400").
+		Header(CallbackHeader, srv.URL+"/hook").
+		Do(nil).
+		Status(http.StatusBadRequest)
+	srv2.Post("/users", []byte(`{"name":"n1"}`)).
+		Header("content-type", "application/json").
+		Header("accept", "application/xml"). // 无法生成该类型的返回内容
+		Header(CallbackHeader, srv.URL+"/hook").
+		Do(nil).
+		Status(http.StatusBadRequest)
+	select {
+	case <-requests:
+		a.TB().Error("请求失败，不应该收到回调请求")
+	case <-time.After(100 * time.Millisecond):
+	}
+	rslt.Handler.Stop()
+
+	// 未启用回调
+	m, rslt = newCallbackMock(a, false)
+	rest.NewServer(a, m, nil).Post("/users", []byte(`{"name":"n1"}`)).
+		Header("content-type", "application/json").
+		Header("accept", "application/json").
+		Header(CallbackHeader, srv.URL+"/hook").
+		Do(nil).
+		Status(http.StatusCreated)
+	select {
+	case <-requests:
+		a.TB().Error("未启用回调，不应该收到回调请求")
+	case <-time.After(100 * time.Millisecond):
+	}
+	rslt.Handler.Stop()
+}
+
+func TestMock_newCallbackRequest(t *testing.T) {
+	a := assert.New(t, false)
+	m, rslt := newCallbackMock(a, true)
+	defer rslt.Handler.Stop()
+	callback := m.doc.APIs[0].Callback
+
+	r, err := m.newCallbackRequest(callback, "/not-abs")
+	a.Error(err).Nil(r)
+
+	r, err = m.newCallbackRequest(callback, "https://example.com/hook?k=v")
+	a.NotError(err).NotNil(r)
+	a.Equal(r.URL.Query().Get("k"), "v").
+		Equal(r.URL.Query().Get("event"), "1024").
+		Equal(r.Header.Get("User-Agent"), core.Name)
+
+	// 无法匹配 mimetype
+	callback = &ast.Callback{
+		Method: &ast.MethodAttribute{Value: xmlenc.String{Value: http.MethodPost}},
+		Requests: []*ast.Request{
+			{Mimetype: &ast.Attribute{Value: xmlenc.String{Value: "text/plain"}}},
+		},
+	}
+	r, err = m.newCallbackRequest(callback, "https://example.com/hook")
+	a.Error(err).Nil(r)
+}
+
+func TestMock_sendCallback(t *testing.T) {
+	a := assert.New(t, false)
+
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	m, rslt := newCallbackMock(a, true)
+	callback := m.doc.APIs[0].Callback
+	r, err := m.newCallbackRequest(callback, srv.URL)
+	a.NotError(err).NotNil(r)
+	m.sendCallback(callback, r)
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	// 返回了未定义的状态码
+	status = http.StatusNotFound
+	m, rslt = newCallbackMock(a, true)
+	r, err = m.newCallbackRequest(callback, srv.URL)
+	a.NotError(err).NotNil(r)
+	m.sendCallback(callback, r)
+	rslt.Handler.Stop()
+	a.NotEmpty(rslt.Errors)
+}
@@ -0,0 +1,198 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/caixw/apidoc/v7/internal/ast"
+)
+
+var (
+	firstNames = []string{"James", "Mary", "John", "Patricia", "Robert", "Linda", "Michael", "Barbara", "William", "Elizabeth", "David", "Jennifer"}
+	lastNames  = []string{"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Wilson", "Taylor", "Clark", "Lewis"}
+	streets    = []string{"Main", "Oak", "Pine", "Maple", "Cedar", "Elm", "Lake", "Hill", "Park", "Sunset"}
+	streetKind = []string{"St", "Ave", "Rd", "Blvd", "Ln"}
+	cities     = []string{"Springfield", "Riverside", "Franklin", "Greenville", "Bristol", "Clinton", "Fairview", "Salem"}
+	countries  = []string{"CN", "US", "GB", "DE", "FR", "JP", "KR", "CA", "AU", "BR", "IN", "IT", "ES", "NL", "SE", "SG"}
+	currencies = []string{"CNY", "USD", "EUR", "GBP", "JPY", "KRW", "CAD", "AUD", "BRL", "INR", "CHF", "SGD"}
+	loremWords = []string{
+		"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit",
+		"sed", "do", "eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore",
+		"magna", "aliqua", "enim", "ad", "minim", "veniam", "quis", "nostrud",
+	}
+)
+
+// 默认的 faker 实现
+var defaultFakers = map[string]func(*GenOptions) string{
+	ast.FakerName:     fakeName,
+	ast.FakerPhone:    fakePhone,
+	ast.FakerAddress:  fakeAddress,
+	ast.FakerUUID:     fakeUUID,
+	ast.FakerIP:       fakeIP,
+	ast.FakerCountry:  func(g *GenOptions) string { return pick(g, countries) },
+	ast.FakerCurrency: func(g *GenOptions) string { return pick(g, currencies) },
+	ast.FakerWord:     func(g *GenOptions) string { return pick(g, loremWords) },
+	ast.FakerSentence: fakeSentence,
+}
+
+// 根据参数名称推断 faker 时，各个 faker 对应的单词
+var (
+	uuidWords     = []string{"uuid", "guid"}
+	phoneWords    = []string{"phone", "mobile", "tel", "telephone", "fax"}
+	addressWords  = []string{"address", "addr", "street"}
+	nameWords     = []string{"username", "nickname", "firstname", "lastname", "fullname", "realname", "author"}
+	namePrefixes  = []string{"user", "nick", "first", "last", "full", "real", "display", "contact", "person"}
+	wordWords     = []string{"tag", "label", "keyword"}
+	sentenceWords = []string{"description", "summary", "comment", "content", "message", "remark", "note", "bio", "text"}
+)
+
+// 获取生成 p 的值时采用的 faker 名称
+//
+// 优先采用 faker 属性指定的值，否则在 guess 为 true 时根据参数名称推断，无法推断则返回空值。
+// 仅对 string 类型的参数进行推断，string.email 等子类型已经有明确的生成方式。
+func fakerName(p *ast.Param, guess bool) string {
+	if p.Faker != nil {
+		return p.Faker.V()
+	}
+	if !guess || p.Type.V() != ast.TypeString {
+		return ""
+	}
+
+	words := splitName(p.Name.V())
+	if len(words) == 0 {
+		return ""
+	}
+	last := words[len(words)-1]
+
+	switch {
+	case containsAny(words, uuidWords...):
+		return ast.FakerUUID
+	case containsAny(words, "ip"):
+		return ast.FakerIP
+	case containsAny(words, phoneWords...):
+		return ast.FakerPhone
+	case containsAny(words, "country"):
+		return ast.FakerCountry
+	case containsAny(words, "currency"):
+		return ast.FakerCurrency
+	case containsAny([]string{last}, addressWords...):
+		return ast.FakerAddress
+	case containsAny([]string{last}, nameWords...),
+		last == "name" && (len(words) == 1 || containsAny([]string{words[len(words)-2]}, namePrefixes...)):
+		return ast.FakerName
+	case containsAny([]string{last}, wordWords...):
+		return ast.FakerWord
+	case containsAny([]string{last}, sentenceWords...):
+		return ast.FakerSentence
+	default:
+		return ""
+	}
+}
+
+// 将参数名称拆分成小写的单词
+//
+// 支持 user_name、user-name 和 userName 等形式。
+func splitName(name string) []string {
+	words := make([]string, 0, 3)
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	prevUpper := false
+	for _, r := range name {
+		switch {
+		case r == '_' || r == '-' || r == '.' || unicode.IsSpace(r):
+			flush()
+			prevUpper = false
+		case unicode.IsUpper(r):
+			if !prevUpper {
+				flush()
+			}
+			word.WriteRune(unicode.ToLower(r))
+			prevUpper = true
+		default:
+			word.WriteRune(r)
+			prevUpper = false
+		}
+	}
+	flush()
+
+	return words
+}
+
+func containsAny(words []string, v ...string) bool {
+	for _, w := range words {
+		for _, vv := range v {
+			if w == vv {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// 采用名称为 name 的 faker 生成 p 的值
+//
+// 优先采用 GenOptions.Fakers 中的实现，其次为默认实现，都不存在则由 String 生成。
+func (g *GenOptions) fake(name string, p *ast.Param) string {
+	if f, found := g.Fakers[name]; found {
+		return f(p)
+	}
+	if f, found := defaultFakers[name]; found {
+		return f(g)
+	}
+	return g.String(p)
+}
+
+func pick(g *GenOptions, items []string) string {
+	return items[g.index(len(items))]
+}
+
+func fakeName(g *GenOptions) string {
+	return pick(g, firstNames) + " " + pick(g, lastNames)
+}
+
+func fakePhone(g *GenOptions) string {
+	return fmt.Sprintf("+1 555-%03d-%04d", g.index(1000), g.index(10000))
+}
+
+func fakeAddress(g *GenOptions) string {
+	return fmt.Sprintf("%d %s %s, %s", g.index(9999)+1, pick(g, streets), pick(g, streetKind), pick(g, cities))
+}
+
+func fakeUUID(g *GenOptions) string {
+	var b [16]byte
+	for i := range b {
+		b[i] = byte(g.index(256))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func fakeIP(g *GenOptions) string {
+	parts := make([]string, 4)
+	for i := range parts {
+		parts[i] = strconv.Itoa(g.index(254) + 1)
+	}
+	return strings.Join(parts, ".")
+}
+
+// 生成 4 到 12 个单词组成的句子
+func fakeSentence(g *GenOptions) string {
+	words := make([]string, 4+g.index(9))
+	for i := range words {
+		words[i] = pick(g, loremWords)
+	}
+	words[0] = strings.ToUpper(words[0][:1]) + words[0][1:]
+	return strings.Join(words, " ") + "."
+}
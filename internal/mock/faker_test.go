@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"math/rand"
+	"net"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert/v3"
+
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+func newFakerParam(name, typ, faker string) *ast.Param {
+	p := &ast.Param{
+		Name: &ast.Attribute{Value: xmlenc.String{Value: name}},
+		Type: &ast.TypeAttribute{Value: xmlenc.String{Value: typ}},
+	}
+	if faker != "" {
+		p.Faker = &ast.Attribute{Value: xmlenc.String{Value: faker}}
+	}
+	return p
+}
+
+func TestSplitName(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Empty(splitName(""))
+	a.Equal(splitName("name"), []string{"name"}).
+		Equal(splitName("user_name"), []string{"user", "name"}).
+		Equal(splitName("user-name"), []string{"user", "name"}).
+		Equal(splitName("userName"), []string{"user", "name"}).
+		Equal(splitName("clientIP"), []string{"client", "ip"}).
+		Equal(splitName("UUID"), []string{"uuid"}).
+		Equal(splitName("Country.Code"), []string{"country", "code"})
+}
+
+func TestFakerName(t *testing.T) {
+	a := assert.New(t, false)
+
+	data := map[string]string{
+		"name":        ast.FakerName,
+		"userName":    ast.FakerName,
+		"nickname":    ast.FakerName,
+		"author":      ast.FakerName,
+		"fileName":    "",
+		"mobilePhone": ast.FakerPhone,
+		"tel":         ast.FakerPhone,
+		"address":     ast.FakerAddress,
+		"home_street": ast.FakerAddress,
+		"emailAddr":   ast.FakerAddress,
+		"uuid":        ast.FakerUUID,
+		"orderGUID":   ast.FakerUUID,
+		"client_ip":   ast.FakerIP,
+		"zip":         "",
+		"countryCode": ast.FakerCountry,
+		"currency":    ast.FakerCurrency,
+		"tag":         ast.FakerWord,
+		"description": ast.FakerSentence,
+		"id":          "",
+	}
+	for name, faker := range data {
+		a.Equal(fakerName(newFakerParam(name, ast.TypeString, ""), true), faker, "%s 的 faker 不正确", name)
+	}
+
+	// 非 string 类型不推断
+	a.Empty(fakerName(newFakerParam("name", ast.TypeEmail, ""), true))
+	a.Empty(fakerName(newFakerParam("name", ast.TypeNumber, ""), true))
+
+	// 未启用推断
+	a.Empty(fakerName(newFakerParam("name", ast.TypeString, ""), false))
+
+	// 指定了 faker 属性
+	a.Equal(fakerName(newFakerParam("id", ast.TypeString, ast.FakerUUID), false), ast.FakerUUID).
+		Equal(fakerName(newFakerParam("name", ast.TypeString, ast.FakerNone), true), ast.FakerNone)
+}
+
+func TestGenOptions_fake(t *testing.T) {
+	a := assert.New(t, false)
+
+	p := newFakerParam("name", ast.TypeString, "")
+	a.Equal(testOptions.fake(ast.FakerName, p), "James Smith").
+		Equal(testOptions.fake("not-exists", p), "1024")
+
+	g := &GenOptions{
+		Index:  testOptions.Index,
+		String: testOptions.String,
+		Fakers: map[string]func(*ast.Param) string{
+			ast.FakerName: func(p *ast.Param) string { return "custom " + p.Name.V() },
+		},
+	}
+	a.Equal(g.fake(ast.FakerName, p), "custom name").
+		Equal(g.fake(ast.FakerCountry, p), "CN")
+
+	g = &GenOptions{Index: func(max int) int { return rand.Intn(max) }}
+	for i := 0; i < 100; i++ {
+		a.Equal(2, len(strings.Split(fakeName(g), " ")))
+		a.True(regexp.MustCompile(`^\+1 555-\d{3}-\d{4}$`).MatchString(fakePhone(g)))
+		a.True(regexp.MustCompile(`^\d+ \w+ \w+, \w+$`).MatchString(fakeAddress(g)))
+		a.True(regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(fakeUUID(g)))
+		a.NotNil(net.ParseIP(fakeIP(g)).To4())
+
+		s := fakeSentence(g)
+		words := strings.Split(strings.TrimSuffix(s, "."), " ")
+		a.True(strings.HasSuffix(s, ".")).
+			True(len(words) >= 4 && len(words) <= 12).
+			True(s[0] >= 'A' && s[0] <= 'Z')
+	}
+}
+
+func TestGenOptions_generateString_faker(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 未启用推断时，由 String 生成，但依然采用 faker 属性。
+	a.Equal(testOptions.generateString(newFakerParam("name", ast.TypeString, "")), "1024").
+		Equal(testOptions.generateString(newFakerParam("id", ast.TypeString, ast.FakerCountry)), "CN")
+
+	g := *testOptions
+	g.GuessFaker = true
+	a.Equal(g.generateString(newFakerParam("name", ast.TypeString, "")), "James Smith").
+		Equal(g.generateString(newFakerParam("name", ast.TypeString, ast.FakerNone)), "1024").
+		Equal(g.generateString(newFakerParam("id", ast.TypeString, ast.FakerCountry)), "CN")
+
+	// 依然受 max-length 的约束
+	p := newFakerParam("name", ast.TypeString, "")
+	p.MaxLength = &ast.NumberAttribute{Value: ast.Number{Int: 5}}
+	a.Equal(g.generateString(p), "James")
+
+	// enum 的优先级高于 faker
+	p = newFakerParam("name", ast.TypeString, "")
+	p.Enums = []*ast.Enum{{Value: &ast.Attribute{Value: xmlenc.String{Value: "e1"}}}}
+	a.Equal(g.generateString(p), "e1")
+}
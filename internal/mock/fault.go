@@ -0,0 +1,311 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/locale"
+)
+
+// FaultHeader 用于在单个请求中覆盖延迟与故障注入的设置
+//
+// 格式为 X-Apidoc-Fault: latency=100ms-500ms, error=0.5, server-error=0.1, reset=0.1, bandwidth=1024，
+// 未指定的项采用 Options.Fault 中的值。
+const FaultHeader = "X-Apidoc-Fault"
+
+// 概率的精度，即概率值会被转换成 [0, rateScale] 之间的整数进行比较。
+const rateScale = 1000
+
+// 带宽限制时，每秒分多少次输出内容。
+const throttleTimes = 10
+
+// 故障注入的结果
+const (
+	faultNone        = iota
+	faultError       // 返回文档中定义的错误内容
+	faultServerError // 返回 500
+	faultReset       // 断开连接
+)
+
+// Latency 表示延迟的时间范围
+//
+// Max 小于等于 Min 时，表示固定延迟 Min。
+type Latency struct {
+	Min, Max time.Duration
+}
+
+// Fault 延迟与故障注入的设置项
+//
+// 所有的概率值都在 [0, 1] 之间，0 表示不启用。
+type Fault struct {
+	Latency     Latency            // 所有 API 的延迟
+	PathLatency map[string]Latency // 指定 API 的延迟，键名为文档中 API 的路径，比如 /users/{id}。
+
+	ErrorRate       float64 // 返回文档中定义的错误内容（状态码不小于 400）的概率
+	ServerErrorRate float64 // 返回 500 的概率
+	ResetRate       float64 // 直接断开连接的概率
+
+	Bandwidth int // 每秒输出的最大字节数，0 表示不限制。
+}
+
+type throttleWriter struct {
+	http.ResponseWriter
+	bandwidth int
+}
+
+// 根据 r 中的 FaultHeader 报头生成当前请求的设置项
+//
+// 报头中未指定的项采用 f 中的值，f 可以为空。
+func (f *Fault) merge(r *http.Request) (*Fault, error) {
+	ret := &Fault{}
+	if f != nil {
+		*ret = *f
+	}
+
+	for _, header := range r.Header.Values(FaultHeader) {
+		for _, item := range strings.FieldsFunc(header, func(r rune) bool { return r == ',' || r == ';' }) {
+			index := strings.IndexByte(item, '=')
+			if index < 0 {
+				return nil, core.NewError(locale.ErrInvalidFormat)
+			}
+
+			key := strings.ToLower(strings.TrimSpace(item[:index]))
+			val := strings.Trim(strings.TrimSpace(item[index+1:]), `"`)
+
+			var err error
+			switch key {
+			case "latency":
+				ret.PathLatency = nil // 报头中指定的延迟作用于当前请求，优先于指定路径的延迟。
+				ret.Latency, err = ParseLatency(val)
+			case "error":
+				ret.ErrorRate, err = parseRate(val)
+			case "server-error":
+				ret.ServerErrorRate, err = parseRate(val)
+			case "reset":
+				ret.ResetRate, err = parseRate(val)
+			case "bandwidth":
+				ret.Bandwidth, err = strconv.Atoi(val)
+			default:
+				continue
+			}
+			if err != nil || ret.Bandwidth < 0 {
+				return nil, core.NewError(locale.ErrInvalidValue).WithField(key)
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+// ParseLatency 将 100ms 或是 100ms-500ms 格式的字符串转换成 Latency
+func ParseLatency(v string) (Latency, error) {
+	l := Latency{}
+
+	min, max, found := strings.Cut(v, "-")
+	d, err := time.ParseDuration(strings.TrimSpace(min))
+	if err != nil {
+		return l, err
+	}
+	l.Min = d
+
+	if found {
+		if d, err = time.ParseDuration(strings.TrimSpace(max)); err != nil {
+			return l, err
+		}
+		l.Max = d
+	}
+
+	if l.Min < 0 || (found && l.Max < l.Min) {
+		return l, locale.NewError(locale.ErrInvalidValue)
+	}
+	return l, nil
+}
+
+func parseRate(v string) (float64, error) {
+	rate, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, err
+	}
+	if rate < 0 || rate > 1 {
+		return 0, locale.NewError(locale.ErrInvalidValue)
+	}
+	return rate, nil
+}
+
+// 获取 api 的延迟时间
+func (f *Fault) latency(g *GenOptions, api *ast.API) time.Duration {
+	l := f.Latency
+	if pl, found := f.PathLatency[api.Path.Path.V()]; found {
+		l = pl
+	}
+	return l.duration(g)
+}
+
+// 从 l 指定的范围中取一个延迟时间
+func (l Latency) duration(g *GenOptions) time.Duration {
+	if l.Max <= l.Min {
+		return l.Min
+	}
+	ms := int((l.Max - l.Min) / time.Millisecond)
+	return l.Min + time.Duration(g.index(ms+1))*time.Millisecond
+}
+
+// 延迟 d 之后返回 true，如果在此期间客户端断开了连接，则返回 false。
+func sleep(r *http.Request, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+
+	t := time.NewTimer(d)
+	select {
+	case <-r.Context().Done():
+		t.Stop()
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// 根据设置的概率决定需要注入的故障
+//
+// 断开连接的优先级最高，其次为 500，最后才是文档中定义的错误内容。
+func (f *Fault) inject(g *GenOptions) int {
+	switch {
+	case hit(g, f.ResetRate):
+		return faultReset
+	case hit(g, f.ServerErrorRate):
+		return faultServerError
+	case hit(g, f.ErrorRate):
+		return faultError
+	default:
+		return faultNone
+	}
+}
+
+func hit(g *GenOptions, rate float64) bool {
+	return rate > 0 && g.index(rateScale) < int(rate*rateScale)
+}
+
+// 延迟并注入故障
+//
+// 返回值 ok 为 false 表示已经处理了该请求，调用方无须再输出任何内容；
+// status 为需要返回的状态码，0 表示由调用方自行决定；
+// 返回的 http.ResponseWriter 会根据带宽的设置限制输出速度。
+func (m *mock) injectFault(api *ast.API, w http.ResponseWriter, r *http.Request) (ww http.ResponseWriter, status int, ok bool) {
+	f, err := m.fault.merge(r)
+	if err != nil {
+		m.handleError(w, r, "headers["+FaultHeader+"].", err)
+		return nil, 0, false
+	}
+
+	if !sleep(r, f.latency(m.gen, api)) { // 客户端已经断开
+		return nil, 0, false
+	}
+
+	switch f.inject(m.gen) {
+	case faultReset:
+		m.msgHandler.Locale(core.Warn, locale.MockFaultInjected, r.Method, r.URL.Path, "reset")
+		resetConn(w)
+		return nil, 0, false
+	case faultError:
+		if status = m.errorStatus(api); status > 0 {
+			m.msgHandler.Locale(core.Warn, locale.MockFaultInjected, r.Method, r.URL.Path, strconv.Itoa(status))
+			return newThrottleWriter(w, f.Bandwidth), status, true
+		}
+		fallthrough // 文档中未定义错误内容，直接返回 500。
+	case faultServerError:
+		m.msgHandler.Locale(core.Warn, locale.MockFaultInjected, r.Method, r.URL.Path, strconv.Itoa(http.StatusInternalServerError))
+		w.WriteHeader(http.StatusInternalServerError)
+		return nil, 0, false
+	}
+
+	return newThrottleWriter(w, f.Bandwidth), 0, true
+}
+
+// 从文档定义的错误内容中随机选择一个状态码，不存在则返回 0。
+func (m *mock) errorStatus(api *ast.API) int {
+	codes := make([]int, 0, len(api.Responses)+len(m.doc.Responses))
+	for _, responses := range [][]*ast.Request{api.Responses, m.doc.Responses} {
+		for _, resp := range responses {
+			if code := resp.Status.V(); code >= 400 && !containsStatus(codes, code) {
+				codes = append(codes, code)
+			}
+		}
+	}
+
+	if len(codes) == 0 {
+		return 0
+	}
+	return codes[m.gen.index(len(codes))]
+}
+
+func containsStatus(codes []int, code int) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// 断开与客户端的连接
+//
+// 如果可以接管连接，会在关闭时发送 RST，否则中断当前的处理，由 http.Server 关闭连接。
+func resetConn(w http.ResponseWriter) {
+	if hj, ok := w.(http.Hijacker); ok {
+		if conn, _, err := hj.Hijack(); err == nil {
+			if tcp, ok := conn.(*net.TCPConn); ok {
+				_ = tcp.SetLinger(0) // 关闭时直接发送 RST
+			}
+			_ = conn.Close()
+			return
+		}
+	}
+	panic(http.ErrAbortHandler)
+}
+
+func newThrottleWriter(w http.ResponseWriter, bandwidth int) http.ResponseWriter {
+	if bandwidth <= 0 {
+		return w
+	}
+	return &throttleWriter{ResponseWriter: w, bandwidth: bandwidth}
+}
+
+// 按照带宽的限制分批输出内容
+func (w *throttleWriter) Write(data []byte) (int, error) {
+	size := w.bandwidth / throttleTimes
+	if size <= 0 {
+		size = 1
+	}
+	interval := time.Second * time.Duration(size) / time.Duration(w.bandwidth)
+
+	var written int
+	for len(data) > 0 {
+		chunk := data
+		if len(chunk) > size {
+			chunk = chunk[:size]
+		}
+
+		n, err := w.ResponseWriter.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		if f, ok := w.ResponseWriter.(http.Flusher); ok {
+			f.Flush()
+		}
+
+		data = data[len(chunk):]
+		if len(data) > 0 {
+			time.Sleep(interval)
+		}
+	}
+	return written, nil
+}
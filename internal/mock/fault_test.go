@@ -0,0 +1,253 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert/v3"
+	"github.com/issue9/assert/v3/rest"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/core/messagetest"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+const faultDoc = `<apidoc version="1.1.1">
+	<title>test</title>
+	<mimetype>application/json</mimetype>
+	<response status="500" type="object" mimetype="application/json">
+		<param name="msg" type="string" summary="msg" />
+	</response>
+	<api method="GET" summary="get">
+		<path path="/users/{id}">
+			<param name="id" type="number" summary="id" />
+		</path>
+		<response status="200" type="object" mimetype="application/json">
+			<param name="name" type="string" summary="name" />
+		</response>
+		<response status="404" type="object" mimetype="application/json">
+			<param name="code" type="number" summary="code" />
+		</response>
+	</api>
+	<api method="GET" summary="list">
+		<path path="/users" />
+		<response status="200" type="string" mimetype="application/json" />
+	</api>
+</apidoc>`
+
+func newFaultMock(a *assert.Assertion, f *Fault) (http.Handler, *messagetest.Result) {
+	rslt := messagetest.NewMessageHandler()
+	d := &ast.APIDoc{APIDoc: &ast.APIDocVersionAttribute{Value: xmlenc.String{Value: ast.Version}}}
+	d.Parse(rslt.Handler, core.Block{Data: []byte(faultDoc)})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	rslt = messagetest.NewMessageHandler()
+	m, err := New(rslt.Handler, d, &Options{Indent: indent, Gen: testOptions, Fault: f})
+	a.NotError(err).NotNil(m)
+	return m, rslt
+}
+
+func TestFault_merge(t *testing.T) {
+	a := assert.New(t, false)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	f, err := (*Fault)(nil).merge(r)
+	a.NotError(err).Equal(f, &Fault{})
+
+	base := &Fault{
+		Latency:     Latency{Min: time.Second},
+		PathLatency: map[string]Latency{"/users": {Min: time.Second}},
+		ErrorRate:   0.5,
+	}
+	f, err = base.merge(r)
+	a.NotError(err).Equal(f, base).False(f == base)
+
+	r.Header.Set(FaultHeader, "latency=10ms-20ms, server-error=0.1; reset=0.2,bandwidth=100,unknown=1")
+	f, err = base.merge(r)
+	a.NotError(err).
+		Equal(f.Latency, Latency{Min: 10 * time.Millisecond, Max: 20 * time.Millisecond}).
+		Nil(f.PathLatency).
+		Equal(f.ErrorRate, 0.5).
+		Equal(f.ServerErrorRate, 0.1).
+		Equal(f.ResetRate, 0.2).
+		Equal(f.Bandwidth, 100)
+	a.Equal(base.ServerErrorRate, 0) // 不会修改原对象
+
+	r.Header.Set(FaultHeader, "error")
+	_, err = base.merge(r)
+	a.Error(err)
+
+	r.Header.Set(FaultHeader, "error=2")
+	_, err = base.merge(r)
+	a.Error(err)
+
+	r.Header.Set(FaultHeader, "bandwidth=-1")
+	_, err = base.merge(r)
+	a.Error(err)
+}
+
+func TestParseLatency(t *testing.T) {
+	a := assert.New(t, false)
+
+	l, err := ParseLatency("100ms")
+	a.NotError(err).Equal(l, Latency{Min: 100 * time.Millisecond})
+
+	l, err = ParseLatency(" 100ms - 1s ")
+	a.NotError(err).Equal(l, Latency{Min: 100 * time.Millisecond, Max: time.Second})
+
+	_, err = ParseLatency("")
+	a.Error(err)
+
+	_, err = ParseLatency("100ms-")
+	a.Error(err)
+
+	_, err = ParseLatency("1s-100ms")
+	a.Error(err)
+}
+
+func TestParseRate(t *testing.T) {
+	a := assert.New(t, false)
+
+	r, err := parseRate("0.5")
+	a.NotError(err).Equal(r, 0.5)
+
+	_, err = parseRate("-0.5")
+	a.Error(err)
+
+	_, err = parseRate("1.5")
+	a.Error(err)
+
+	_, err = parseRate("abc")
+	a.Error(err)
+}
+
+func TestFault_latency(t *testing.T) {
+	a := assert.New(t, false)
+
+	users := &ast.API{Path: &ast.Path{Path: &ast.Attribute{Value: xmlenc.String{Value: "/users"}}}}
+	user := &ast.API{Path: &ast.Path{Path: &ast.Attribute{Value: xmlenc.String{Value: "/users/{id}"}}}}
+	g := &GenOptions{Index: func(max int) int { return max - 1 }}
+
+	f := &Fault{}
+	a.Equal(f.latency(g, users), 0)
+
+	f.Latency = Latency{Min: time.Second}
+	a.Equal(f.latency(g, users), time.Second)
+
+	f.Latency = Latency{Min: time.Second, Max: 2 * time.Second}
+	a.Equal(f.latency(g, users), 2*time.Second)
+	a.Equal(f.latency(testOptions, users), time.Second)
+
+	f.PathLatency = map[string]Latency{"/users/{id}": {Min: 10 * time.Millisecond}}
+	a.Equal(f.latency(g, user), 10*time.Millisecond).
+		Equal(f.latency(g, users), 2*time.Second)
+}
+
+func TestFault_inject(t *testing.T) {
+	a := assert.New(t, false)
+
+	f := &Fault{}
+	a.Equal(f.inject(testOptions), faultNone)
+
+	f.ErrorRate = 0.1
+	a.Equal(f.inject(testOptions), faultError)
+
+	f.ServerErrorRate = 0.1
+	a.Equal(f.inject(testOptions), faultServerError)
+
+	f.ResetRate = 0.1
+	a.Equal(f.inject(testOptions), faultReset)
+
+	// Index 返回最大值，所有概率都不命中
+	g := &GenOptions{Index: func(max int) int { return max - 1 }}
+	a.Equal(f.inject(g), faultNone)
+
+	f.ErrorRate = 1
+	a.Equal(f.inject(g), faultError)
+}
+
+func TestThrottleWriter(t *testing.T) {
+	a := assert.New(t, false)
+
+	w := httptest.NewRecorder()
+	a.Equal(newThrottleWriter(w, 0), w)
+
+	tw := newThrottleWriter(w, 100)
+	start := time.Now()
+	n, err := tw.Write([]byte("123456789012345678901234567890"))
+	a.NotError(err).Equal(n, 30)
+	a.Equal(w.Body.String(), "123456789012345678901234567890").
+		True(time.Since(start) >= 200*time.Millisecond)
+}
+
+func TestMock_fault(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 未设置
+	h, rslt := newFaultMock(a, nil)
+	srv := rest.NewServer(a, h, nil)
+	srv.Get("/users/1").Header("accept", "application/json").Do(nil).
+		Status(http.StatusOK)
+
+	// 通过报头指定
+	srv.Get("/users/1").
+		Header("accept", "application/json").
+		Header(FaultHeader, "error=1").
+		Do(nil).
+		Status(http.StatusNotFound)
+
+	srv.Get("/users/1").
+		Header("accept", "application/json").
+		Header(FaultHeader, "server-error=1").
+		Do(nil).
+		Status(http.StatusInternalServerError)
+
+	srv.Get("/users/1").
+		Header("accept", "application/json").
+		Header(FaultHeader, "error=abc").
+		Do(nil).
+		Status(http.StatusBadRequest)
+
+	// Prefer 的优先级高于故障注入
+	srv.Get("/users/1").
+		Header("accept", "application/json").
+		Header(FaultHeader, "error=1").
+		Header(preferHeader, "code=200").
+		Do(nil).
+		Status(http.StatusOK)
+	rslt.Handler.Stop()
+	a.NotEmpty(rslt.Warns)
+
+	// 通过 Options 指定
+	h, rslt = newFaultMock(a, &Fault{Latency: Latency{Min: 10 * time.Millisecond}, ErrorRate: 1})
+	srv = rest.NewServer(a, h, nil)
+	start := time.Now()
+	srv.Get("/users/1").Header("accept", "application/json").Do(nil).
+		Status(http.StatusNotFound)
+	a.True(time.Since(start) >= 10*time.Millisecond)
+
+	srv.Get("/users/1").
+		Header("accept", "application/json").
+		Header(FaultHeader, "error=0").
+		Do(nil).
+		Status(http.StatusOK)
+
+	// api 未定义错误内容，采用全局的定义
+	srv.Get("/users").Header("accept", "application/json").Do(nil).
+		Status(http.StatusInternalServerError)
+	rslt.Handler.Stop()
+
+	// 断开连接
+	h, rslt = newFaultMock(a, &Fault{ResetRate: 1})
+	s := httptest.NewServer(h)
+	resp, err := http.Get(s.URL + "/users/1")
+	a.Error(err).Nil(resp)
+	s.Close()
+	rslt.Handler.Stop()
+}
@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// 录制的请求与返回内容
+//
+// 每一条记录保存为 fixtures 目录下的一个 JSON 文件，
+// 以 method、path、query 和 body 作为匹配条件。
+type fixture struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Body   string `json:"body,omitempty"`
+
+	Status   int         `json:"status"`
+	Headers  http.Header `json:"headers,omitempty"`
+	Response []byte      `json:"response,omitempty"` // 以 base64 保存，保证二进制以及压缩后的内容不被破坏。
+}
+
+// 保存于 fixtures 目录下的所有录制内容
+type fixtures struct {
+	dir   string
+	mux   sync.RWMutex
+	items map[string]*fixture
+}
+
+type fixtureContextKey int
+
+const fixtureKey fixtureContextKey = 1
+
+// 录制的内容可能包含认证信息，所以文件仅允许当前用户读写。
+const (
+	fixtureDirMode  = 0o755
+	fixtureFileMode = 0o600
+)
+
+// 不需要录制的报头
+var ignoreFixtureHeaders = []string{
+	"Connection",
+	"Content-Length",
+	"Date",
+	"Keep-Alive",
+	"Transfer-Encoding",
+}
+
+// 加载 dir 目录下的录制内容
+//
+// dir 不存在时，返回空的对象，在首次录制时会自动创建该目录。
+func loadFixtures(dir string) (*fixtures, error) {
+	f := &fixtures{dir: dir, items: make(map[string]*fixture, 10)}
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return f, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		fx := &fixture{}
+		if err := json.Unmarshal(data, fx); err != nil {
+			return nil, err
+		}
+		f.items[fx.key()] = fx
+	}
+
+	return f, nil
+}
+
+// 根据请求生成不包含返回内容的 fixture 对象
+func newFixture(r *http.Request, body []byte) *fixture {
+	return &fixture{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query().Encode(), // Encode 会对键名进行排序
+		Body:   normalizeBody(body),
+	}
+}
+
+// 对 JSON 内容进行压缩，使格式上的差异不影响匹配结果。
+func normalizeBody(body []byte) string {
+	body = bytes.TrimSpace(body)
+	buf := &bytes.Buffer{}
+	if json.Valid(body) && json.Compact(buf, body) == nil {
+		return buf.String()
+	}
+	return string(body)
+}
+
+// 用于匹配的键名
+func (fx *fixture) key() string {
+	return fx.Method + " " + fx.Path + "?" + fx.Query + "\n" + fx.Body
+}
+
+// 保存的文件名
+func (fx *fixture) filename() string {
+	sum := sha1.Sum([]byte(fx.key()))
+	return strings.ToLower(fx.Method) + "-" + hex.EncodeToString(sum[:]) + ".json"
+}
+
+func (fx *fixture) render(w http.ResponseWriter) error {
+	for k, vals := range fx.Headers {
+		for _, v := range vals {
+			w.Header().Add(k, v)
+		}
+	}
+	w.WriteHeader(fx.Status)
+	_, err := w.Write(fx.Response)
+	return err
+}
+
+// 查找与请求相匹配的录制内容
+func (f *fixtures) find(r *http.Request, body []byte) *fixture {
+	f.mux.RLock()
+	defer f.mux.RUnlock()
+	return f.items[newFixture(r, body).key()]
+}
+
+// 保存录制内容，返回保存的文件路径。
+func (f *fixtures) save(fx *fixture) (string, error) {
+	data, err := json.MarshalIndent(fx, "", "\t")
+	if err != nil {
+		return "", err
+	}
+
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	if err := os.MkdirAll(f.dir, fixtureDirMode); err != nil {
+		return "", err
+	}
+	path := filepath.Join(f.dir, fx.filename())
+	if err := os.WriteFile(path, data, fixtureFileMode); err != nil {
+		return "", err
+	}
+
+	f.items[fx.key()] = fx
+	return path, nil
+}
+
+// 将请求的内容附加在 r 上，以便在获取到返回内容之后进行录制。
+func withFixture(r *http.Request, body []byte) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), fixtureKey, newFixture(r, body)))
+}
+
+// 录制 resp 的内容
+//
+// resp.Request 必须是经过 withFixture 处理的请求，否则不作任何处理。
+func (f *fixtures) record(resp *http.Response) (string, error) {
+	fx, ok := resp.Request.Context().Value(fixtureKey).(*fixture)
+	if !ok {
+		return "", nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if err = resp.Body.Close(); err != nil {
+		return "", err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	headers := resp.Header.Clone()
+	for _, k := range ignoreFixtureHeaders {
+		headers.Del(k)
+	}
+
+	fx.Status = resp.StatusCode
+	fx.Headers = headers
+	fx.Response = body
+	return f.save(fx)
+}
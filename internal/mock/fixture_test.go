@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert/v3"
+	"github.com/issue9/assert/v3/rest"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/core/messagetest"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+func TestNormalizeBody(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(normalizeBody(nil), "").
+		Equal(normalizeBody([]byte(" {\n\"id\": 1 }\n")), `{"id":1}`).
+		Equal(normalizeBody([]byte(" <xml>\n</xml> ")), "<xml>\n</xml>")
+}
+
+func TestFixtures(t *testing.T) {
+	a := assert.New(t, false)
+	dir := filepath.Join(t.TempDir(), "fixtures")
+
+	// 目录不存在
+	f, err := loadFixtures(dir)
+	a.NotError(err).NotNil(f).Empty(f.items)
+
+	r := httptest.NewRequest(http.MethodPost, "/users?b=2&a=1", nil)
+	fx := newFixture(r, []byte(`{ "name": "n1" }`))
+	a.Equal(fx.Query, "a=1&b=2").Equal(fx.Body, `{"name":"n1"}`)
+	fx.Status = http.StatusCreated
+	fx.Headers = http.Header{"Content-Type": []string{"application/json"}}
+	fx.Response = []byte(`{"id":1}`)
+	path, err := f.save(fx)
+	a.NotError(err).FileExists(path)
+	a.True(strings.HasPrefix(filepath.Base(path), "post-"))
+
+	// 查询参数的顺序以及 JSON 的格式不影响匹配结果
+	r = httptest.NewRequest(http.MethodPost, "/users?a=1&b=2", nil)
+	a.Equal(f.find(r, []byte(`{"name":"n1"}`)), fx).
+		Nil(f.find(r, []byte(`{"name":"n2"}`)))
+	r = httptest.NewRequest(http.MethodPut, "/users?a=1&b=2", nil)
+	a.Nil(f.find(r, []byte(`{"name":"n1"}`)))
+
+	// 重新加载
+	f, err = loadFixtures(dir)
+	a.NotError(err).Equal(1, len(f.items))
+	r = httptest.NewRequest(http.MethodPost, "/users?a=1&b=2", nil)
+	a.Equal(f.find(r, []byte(`{"name":"n1"}`)), fx)
+
+	w := httptest.NewRecorder()
+	a.NotError(fx.render(w))
+	a.Equal(w.Code, http.StatusCreated).
+		Equal(w.Header().Get("Content-Type"), "application/json").
+		Equal(w.Body.String(), `{"id":1}`)
+
+	if runtime.GOOS != "windows" {
+		stat, err := os.Stat(path)
+		a.NotError(err).Equal(stat.Mode().Perm(), os.FileMode(fixtureFileMode))
+		stat, err = os.Stat(dir)
+		a.NotError(err).Equal(stat.Mode().Perm(), os.FileMode(fixtureDirMode))
+	}
+
+	// 格式错误的文件
+	a.NotError(os.WriteFile(filepath.Join(dir, "invalid.json"), []byte("{"), os.ModePerm))
+	_, err = loadFixtures(dir)
+	a.Error(err)
+}
+
+func TestFixtures_binary(t *testing.T) {
+	a := assert.New(t, false)
+	dir := t.TempDir()
+
+	f, err := loadFixtures(dir)
+	a.NotError(err).NotNil(f)
+
+	// 非 UTF-8 的内容，比如压缩后的内容或是图片。
+	body := []byte{0x1f, 0x8b, 0x08, 0x00, 0xff, 0xfe, 0x80, 0x00}
+	r := httptest.NewRequest(http.MethodGet, "/images/1.png", nil)
+	fx := newFixture(r, nil)
+	fx.Status = http.StatusOK
+	fx.Headers = http.Header{"Content-Type": []string{"image/png"}, "Content-Encoding": []string{"gzip"}}
+	fx.Response = body
+	_, err = f.save(fx)
+	a.NotError(err)
+
+	// 重新加载之后回放
+	f, err = loadFixtures(dir)
+	a.NotError(err)
+	fx = f.find(httptest.NewRequest(http.MethodGet, "/images/1.png", nil), nil)
+	a.NotNil(fx)
+
+	w := httptest.NewRecorder()
+	a.NotError(fx.render(w))
+	a.Equal(w.Code, http.StatusOK).
+		Equal(w.Header().Get("Content-Encoding"), "gzip").
+		Equal(w.Body.Bytes(), body)
+}
+
+func TestMock_record(t *testing.T) {
+	a := assert.New(t, false)
+	dir := t.TempDir()
+
+	rslt := messagetest.NewMessageHandler()
+	d := &ast.APIDoc{APIDoc: &ast.APIDocVersionAttribute{Value: xmlenc.String{Value: ast.Version}}}
+	d.Parse(rslt.Handler, core.Block{Data: []byte(proxyDoc)})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Upstream", "1")
+		if r.URL.Query().Get("fields") == "name" { // 不符合文档定义的返回内容
+			w.Write([]byte(`{"id":"1"}`))
+			return
+		}
+		w.Write([]byte(`{"id":1,"name":"n1"}`))
+	}))
+	defer upstream.Close()
+	u, err := url.Parse(upstream.URL)
+	a.NotError(err)
+
+	// 录制
+	rslt = messagetest.NewMessageHandler()
+	h, err := New(rslt.Handler, d, &Options{Indent: indent, Gen: testOptions, Fixtures: dir, Upstream: u})
+	a.NotError(err).NotNil(h)
+	srv := rest.NewServer(a, h, nil)
+	srv.Get("/users/1?fields=id").Do(nil).Status(http.StatusOK)
+	srv.Get("/users/1?fields=name").Do(nil).Status(http.StatusOK)    // 返回内容不符合文档
+	srv.Get("/users/1?fields=invalid").Do(nil).Status(http.StatusOK) // 请求不符合文档
+	srv.Get("/not-exists").Do(nil).Status(http.StatusOK)             // 未定义的 API
+	rslt.Handler.Stop()
+	a.Equal(2, len(rslt.Errors))
+	entries, err := os.ReadDir(dir)
+	a.NotError(err).Equal(1, len(entries))
+
+	// 回放
+	rslt = messagetest.NewMessageHandler()
+	h, err = New(rslt.Handler, d, &Options{Indent: indent, Gen: testOptions, Fixtures: dir})
+	a.NotError(err).NotNil(h)
+	srv = rest.NewServer(a, h, nil)
+	srv.Get("/users/1?fields=id").Header("accept", "application/json").Do(nil).
+		Status(http.StatusOK).
+		Header("X-Upstream", "1").
+		StringBody(`{"id":1,"name":"n1"}`)
+
+	// 未录制的内容，依然返回随机生成的数据。
+	srv.Get("/users/2?fields=id").Header("accept", "application/json").Do(nil).
+		Status(http.StatusOK).
+		Header("X-Upstream", "")
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+}
@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/url"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/locale"
+)
+
+// 解析 multipart/form-data 时，保存在内存中的最大字节数，超出部分会保存在临时文件中。
+const maxMultipartMemory = 32 << 20
+
+// 验证 application/x-www-form-urlencoded 格式的内容
+func validForm(p *ast.Request, content []byte) error {
+	values, err := url.ParseQuery(string(content))
+	if err != nil {
+		return core.NewError(locale.ErrInvalidFormat)
+	}
+	return validFormValues(p, values, nil)
+}
+
+// 验证 multipart/form-data 格式的内容
+//
+// boundary 为 Content-Type 报头中的同名参数。
+func validMultipart(p *ast.Request, content []byte, boundary string) error {
+	if boundary == "" {
+		return core.NewError(locale.ErrIsEmpty, "boundary").WithField("boundary")
+	}
+
+	form, err := multipart.NewReader(bytes.NewReader(content), boundary).ReadForm(maxMultipartMemory)
+	if err != nil {
+		return core.NewError(locale.ErrInvalidFormat)
+	}
+	defer form.RemoveAll()
+
+	return validFormValues(p, form.Value, form.File)
+}
+
+// 根据 p 的子元素验证表单中的值
+//
+// 表单中的值只能是基本类型或是文件，文件只能出现在 files 中，其它类型只能出现在 values 中。
+func validFormValues(p *ast.Request, values url.Values, files map[string][]*multipart.FileHeader) error {
+	items := p.Param().Items
+
+	for _, item := range items {
+		name := item.Name.V()
+		vals, fs := values[name], files[name]
+		if len(vals) == 0 && len(fs) == 0 {
+			if !item.Optional.V() && item.Default == nil && !item.ReadOnly.V() {
+				return core.NewError(locale.ErrIsEmpty, name).WithField(name)
+			}
+			continue
+		}
+
+		if err := checkAccess(item, true, name); err != nil {
+			return err
+		}
+
+		if err := validFormItem(item, vals, fs); err != nil {
+			return err
+		}
+	}
+
+	// 不存在于文档中的字段
+	for _, name := range sortedFormKeys(values, files) {
+		if findItem(items, name) == nil {
+			return core.NewError(locale.ErrNotFound).WithField(name)
+		}
+	}
+
+	return nil
+}
+
+func validFormItem(p *ast.Param, values []string, files []*multipart.FileHeader) error {
+	name := p.Name.V()
+	primitive, _ := ast.ParseType(p.Type.V())
+
+	if primitive == ast.TypeFile {
+		if len(values) > 0 { // 文件必须以文件的形式上传
+			return core.NewError(locale.ErrInvalidFormat).WithField(name)
+		}
+		if !p.Array.V() && len(files) > 1 {
+			return core.NewError(locale.ErrInvalidValue).WithField(name)
+		}
+
+		for _, f := range files {
+			if err := p.CheckFile(int(f.Size), f.Header.Get("Content-Type")); err != nil {
+				return err.WithField(name)
+			}
+		}
+		if p.Array.V() {
+			if err := p.CheckItems(len(files), nil); err != nil {
+				return err.WithField(name)
+			}
+		}
+		return nil
+	}
+
+	if len(files) > 0 || primitive == ast.TypeObject || primitive == ast.TypeMap {
+		return core.NewError(locale.ErrInvalidFormat).WithField(name)
+	}
+	if !p.Array.V() && len(values) > 1 {
+		return core.NewError(locale.ErrInvalidValue).WithField(name)
+	}
+
+	for _, v := range values {
+		if err := validSimpleParam(p, name, v); err != nil {
+			if serr, ok := err.(*core.Error); ok {
+				return serr.WithField(name)
+			}
+			return err
+		}
+	}
+	if p.Array.V() {
+		if err := p.CheckItems(len(values), values); err != nil {
+			return err.WithField(name)
+		}
+	}
+
+	return nil
+}
+
+func findItem(items []*ast.Param, name string) *ast.Param {
+	for _, item := range items {
+		if item.Name.V() == name {
+			return item
+		}
+	}
+	return nil
+}
+
+// 返回 values 和 files 中所有的键名，按字母顺序排列。
+func sortedFormKeys(values url.Values, files map[string][]*multipart.FileHeader) []string {
+	m := make(map[string]any, len(values)+len(files))
+	for k := range values {
+		m[k] = nil
+	}
+	for k := range files {
+		m[k] = nil
+	}
+	return sortedKeys(m)
+}
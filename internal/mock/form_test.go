@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"testing"
+
+	"github.com/issue9/assert/v3"
+
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+func newFormRequest() *ast.Request {
+	return &ast.Request{
+		Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeObject}},
+		Items: []*ast.Param{
+			{
+				Name: &ast.Attribute{Value: xmlenc.String{Value: "name"}},
+				Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+			},
+			{
+				Name:     &ast.Attribute{Value: xmlenc.String{Value: "tags"}},
+				Type:     &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+				Array:    &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+				Optional: &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+				MaxItems: &ast.NumberAttribute{Value: ast.Number{Int: 2}},
+			},
+			{
+				Name:     &ast.Attribute{Value: xmlenc.String{Value: "avatar"}},
+				Type:     &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeFile}},
+				Optional: &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+				MaxSize:  &ast.NumberAttribute{Value: ast.Number{Int: 10}},
+				Accept:   &ast.Attribute{Value: xmlenc.String{Value: "image/*"}},
+			},
+		},
+	}
+}
+
+type formPart struct {
+	name, filename, ct, content string
+}
+
+func newMultipart(a *assert.Assertion, parts ...*formPart) ([]byte, string) {
+	buf := new(bytes.Buffer)
+	w := multipart.NewWriter(buf)
+	for _, part := range parts {
+		if part.filename == "" {
+			a.NotError(w.WriteField(part.name, part.content))
+			continue
+		}
+
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Disposition", `form-data; name="`+part.name+`"; filename="`+part.filename+`"`)
+		h.Set("Content-Type", part.ct)
+		pw, err := w.CreatePart(h)
+		a.NotError(err)
+		_, err = pw.Write([]byte(part.content))
+		a.NotError(err)
+	}
+	a.NotError(w.Close())
+	return buf.Bytes(), w.Boundary()
+}
+
+func TestValidForm(t *testing.T) {
+	a := assert.New(t, false)
+	p := newFormRequest()
+
+	data := map[string]bool{
+		"name=n":                      true,
+		"name=n&tags=t1&tags=t2":      true,
+		"tags=t1":                     false, // 缺少 name
+		"name=n&name=n2":              false, // name 不是数组
+		"name=n&tags=1&tags=2&tags=3": false, // max-items
+		"name=n&age=1":                false, // 不存在的字段
+		"name=n&avatar=xx":            false, // 文件不能以普通值提交
+		"name=%zz":                    false,
+	}
+	for content, ok := range data {
+		err := validForm(p, []byte(content))
+		if ok {
+			a.NotError(err, "%s 返回了错误 %s", content, err)
+		} else {
+			a.Error(err, "%s 未返回错误", content)
+		}
+	}
+}
+
+func TestValidMultipart(t *testing.T) {
+	a := assert.New(t, false)
+	p := newFormRequest()
+
+	content, boundary := newMultipart(a,
+		&formPart{name: "name", content: "n"},
+		&formPart{name: "avatar", filename: "a.png", ct: "image/png", content: "png"},
+	)
+	a.NotError(validMultipart(p, content, boundary))
+	a.Error(validMultipart(p, content, ""))
+	a.Error(validMultipart(p, content, "not-exists"))
+
+	// 文件大小超出 max-size
+	content, boundary = newMultipart(a,
+		&formPart{name: "name", content: "n"},
+		&formPart{name: "avatar", filename: "a.png", ct: "image/png", content: "01234567890"},
+	)
+	a.Error(validMultipart(p, content, boundary))
+
+	// 不符合 accept
+	content, boundary = newMultipart(a,
+		&formPart{name: "name", content: "n"},
+		&formPart{name: "avatar", filename: "a.txt", ct: "text/plain", content: "txt"},
+	)
+	a.Error(validMultipart(p, content, boundary))
+
+	// 普通的字段不能以文件上传
+	content, boundary = newMultipart(a,
+		&formPart{name: "name", filename: "name.txt", ct: "text/plain", content: "n"},
+	)
+	a.Error(validMultipart(p, content, boundary))
+
+	// 多个文件
+	content, boundary = newMultipart(a,
+		&formPart{name: "name", content: "n"},
+		&formPart{name: "avatar", filename: "a.png", ct: "image/png", content: "png"},
+		&formPart{name: "avatar", filename: "b.png", ct: "image/png", content: "png"},
+	)
+	a.Error(validMultipart(p, content, boundary))
+}
+
+func TestValidRequest_form(t *testing.T) {
+	a := assert.New(t, false)
+	p := newFormRequest()
+	p.Mimetype = &ast.Attribute{Value: xmlenc.String{Value: "multipart/form-data"}}
+
+	content, boundary := newMultipart(a,
+		&formPart{name: "name", content: "n"},
+		&formPart{name: "avatar", filename: "a.png", ct: "image/png", content: "png"},
+	)
+	r := httptest.NewRequest(http.MethodPost, "/path", bytes.NewReader(content))
+	r.Header.Set("content-type", "multipart/form-data; boundary="+boundary)
+	a.NotError(validRequest(nil, []*ast.Request{p}, r))
+
+	p.Mimetype = &ast.Attribute{Value: xmlenc.String{Value: "application/x-www-form-urlencoded"}}
+	r = httptest.NewRequest(http.MethodPost, "/path", bytes.NewBufferString("name=n&tags=t1"))
+	r.Header.Set("content-type", "application/x-www-form-urlencoded")
+	a.NotError(validRequest(nil, []*ast.Request{p}, r))
+
+	r = httptest.NewRequest(http.MethodPost, "/path", bytes.NewBufferString("tags=t1"))
+	r.Header.Set("content-type", "application/x-www-form-urlencoded")
+	a.Error(validRequest(nil, []*ast.Request{p}, r))
+}
@@ -4,10 +4,12 @@ package mock
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 
 	"github.com/issue9/errwrap"
@@ -26,9 +28,24 @@ type jsonValidator struct {
 	states []byte
 
 	names []string // 按顺序保存变量名称
+
+	arrays []*jsonArray // 按顺序保存正在验证的数组
+
+	// 是否为请求内容
+	//
+	// 请求中不能包含只读的字段，返回中不能包含只写的字段。
+	request bool
+}
+
+// 正在验证的数组，用于在数组结束时验证 min-items 等约束。
+type jsonArray struct {
+	param  *ast.Param // 为空表示无法找到对应的参数，比如嵌套的数组。
+	field  string
+	size   int
+	values []string // 基本类型元素的值
 }
 
-func validJSON(p *ast.Request, content []byte) error {
+func validJSON(p *ast.Request, content []byte, request bool) error {
 	if p == nil {
 		if bytes.Equal(content, []byte("null")) {
 			return nil
@@ -42,15 +59,16 @@ func validJSON(p *ast.Request, content []byte) error {
 		return core.NewError(locale.ErrInvalidFormat)
 	}
 
-	validator := newJSONValidator(p)
+	validator := newJSONValidator(p, request)
 	return validator.valid(json.NewDecoder(bytes.NewReader(content)))
 }
 
-func newJSONValidator(r *ast.Request) *jsonValidator {
+func newJSONValidator(r *ast.Request, request bool) *jsonValidator {
 	return &jsonValidator{
-		param:  r.Param(),
-		states: []byte{0}, // 状态有默认值
-		names:  []string{},
+		param:   r.Param(),
+		states:  []byte{0}, // 状态有默认值
+		names:   []string{},
+		request: request,
 	}
 }
 
@@ -64,7 +82,21 @@ func (validator *jsonValidator) valid(d *json.Decoder) error {
 			return err
 		}
 		if token == nil { // 对应 JSON null
-			return nil
+			switch validator.state() {
+			case ':':
+				err = validator.validNull()
+				validator.popState()
+				validator.popName()
+			case '[':
+				err = validator.validNull()
+				validator.addItem(nil)
+			}
+			// 其它状态表示整个内容为 null，不作验证。
+
+			if err != nil {
+				return err
+			}
+			continue
 		}
 
 		switch v := token.(type) {
@@ -76,12 +108,14 @@ func (validator *jsonValidator) valid(d *json.Decoder) error {
 				validator.popName()
 			case '[':
 				err = validator.validValue(ast.TypeString, v)
+				validator.addItem(v)
 			case 0: // 表示数据为单个值，比如 "str"
 				err = validator.validValue(ast.TypeString, v)
 			// case ']', '}': // 格式错误，由 json.Valid 保证
 			default: // case '{' 属性名
 				validator.pushState(':')
 				validator.pushName(v)
+				err = validator.validAccess()
 			}
 
 			if err != nil {
@@ -90,8 +124,12 @@ func (validator *jsonValidator) valid(d *json.Decoder) error {
 		case json.Delim: // [、]、{、}
 			switch v {
 			case '[':
+				validator.pushArray()
 				validator.pushState('[')
 			case ']':
+				if err := validator.popArray(); err != nil {
+					return err
+				}
 				validator.popName()
 
 				validator.popState()
@@ -99,6 +137,27 @@ func (validator *jsonValidator) valid(d *json.Decoder) error {
 					validator.popState()
 				}
 			case '{':
+				if validator.state() == '[' {
+					validator.addItem(nil)
+				}
+
+				// 复合类型需要读取完整的对象之后，才能确定其对应的变体。
+				if p := validator.find(); p != nil && len(p.Variants) > 0 {
+					obj, err := readJSONValue(d, v)
+					if err != nil {
+						return err
+					}
+					if err := validator.validTree(p, obj, strings.Join(validator.names, "."), false); err != nil {
+						return err
+					}
+
+					if validator.state() == ':' {
+						validator.popState()
+						validator.popName()
+					}
+					continue
+				}
+
 				validator.pushState('{')
 			case '}':
 				validator.popName()
@@ -113,12 +172,16 @@ func (validator *jsonValidator) valid(d *json.Decoder) error {
 			if validator.state() != '[' {
 				validator.popState()
 				validator.popName()
+			} else {
+				validator.addItem(v)
 			}
 		case float64, json.Number: // json number
 			err = validator.validValue(ast.TypeNumber, v)
 			if validator.state() != '[' { // 只有键值对结束时，才弹出键名
 				validator.popState()
 				validator.popName()
+			} else {
+				validator.addItem(v)
 			}
 		}
 
@@ -128,6 +191,42 @@ func (validator *jsonValidator) valid(d *json.Decoder) error {
 	}
 }
 
+// 验证当前位置的 null 值，仅 nullable 的参数才允许 null。
+func (validator *jsonValidator) validNull() error {
+	field := strings.Join(validator.names, ".")
+
+	p := validator.find()
+	if p == nil {
+		return core.NewError(locale.ErrNotFound).WithField(field)
+	}
+	if !p.Nullable.V() {
+		return core.NewError(locale.ErrInvalidValue).WithField(field)
+	}
+	return nil
+}
+
+// 验证当前位置的字段是否允许出现在请求或是返回中
+func (validator *jsonValidator) validAccess() error {
+	p := validator.find()
+	if p == nil { // 不存在的字段由 validValue 等处理
+		return nil
+	}
+	return checkAccess(p, validator.request, strings.Join(validator.names, "."))
+}
+
+// 检测 p 是否允许出现在请求或是返回中
+//
+// request 表示是否为请求内容，field 表示 p 在整个对象中的位置信息。
+func checkAccess(p *ast.Param, request bool, field string) error {
+	switch {
+	case request && p.ReadOnly.V():
+		return core.NewError(locale.ErrReadOnlyField).WithField(field)
+	case !request && p.WriteOnly.V():
+		return core.NewError(locale.ErrWriteOnlyField).WithField(field)
+	}
+	return nil
+}
+
 // 如果 t == "" 表示不需要验证类型，比如 null 可以赋值给任何类型
 func (validator *jsonValidator) validValue(t string, v any) error {
 	field := strings.Join(validator.names, ".")
@@ -137,9 +236,20 @@ func (validator *jsonValidator) validValue(t string, v any) error {
 		return core.NewError(locale.ErrNotFound).WithField(field)
 	}
 
+	return validJSONPrimitive(p, t, v, field)
+}
+
+// 验证基本类型的值 v 是否符合 p 的定义
+//
+// t 为 v 在 JSON 中的类型；field 表示 p 在整个对象中的位置信息。
+func validJSONPrimitive(p *ast.Param, t string, v any, field string) error {
 	pt := p.Type.V()
 
-	if primitive, _ := ast.ParseType(pt); primitive != t {
+	primitive, _ := ast.ParseType(pt)
+	if primitive == ast.TypeFile { // 文件以 base64 编码的字符串表示
+		primitive = ast.TypeString
+	}
+	if primitive != t {
 		return core.NewError(locale.ErrInvalidFormat).WithField(field)
 	}
 
@@ -176,22 +286,236 @@ func (validator *jsonValidator) validValue(t string, v any) error {
 		if !isValidRFC3339DateTime(vv) {
 			return core.NewError(locale.ErrInvalidFormat).WithField(field)
 		}
+	case ast.TypeFile:
+		data, err := base64.StdEncoding.DecodeString(fmt.Sprint(v))
+		if err != nil {
+			return core.NewError(locale.ErrInvalidFormat).WithField(field)
+		}
+		if err := p.CheckFile(len(data), ""); err != nil {
+			return err.WithField(field)
+		}
 	case ast.TypeImage: // 可能是相对站点的根路径，不作类型检测
 	case ast.TypeInt, ast.TypeFloat: // 数值类型都被 json 解释为 float64，无法判断值是浮点还是整数。
 	}
 
-	if isEnum(p) {
-		for _, enum := range p.Enums {
-			if enum.Value.V() == fmt.Sprint(v) {
-				return nil
+	val := fmt.Sprint(v)
+	if isEnum(p) && !hasEnum(p, val) {
+		return core.NewError(locale.ErrInvalidValue).WithField(field)
+	}
+
+	if err := p.CheckValue(val); err != nil {
+		return err.WithField(field)
+	}
+	return nil
+}
+
+// 从 d 中读取一个完整的值
+//
+// token 为该值的第一个标记，如果是对象或是数组，返回值分别为 map[string]any 和 []any。
+func readJSONValue(d *json.Decoder, token json.Token) (any, error) {
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	switch delim {
+	case '{':
+		obj := map[string]any{}
+		for d.More() {
+			key, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			t, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			if obj[fmt.Sprint(key)], err = readJSONValue(d, t); err != nil {
+				return nil, err
 			}
 		}
-		return core.NewError(locale.ErrInvalidValue).WithField(field)
+		_, err := d.Token() // }
+		return obj, err
+	case '[':
+		arr := []any{}
+		for d.More() {
+			t, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			item, err := readJSONValue(d, t)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, item)
+		}
+		_, err := d.Token() // ]
+		return arr, err
+	default: // ] 和 }，格式错误，由 json.Valid 保证
+		return nil, core.NewError(locale.ErrInvalidFormat)
+	}
+}
+
+// 验证由 readJSONValue 读取的值 v 是否符合 p 的定义
+//
+// 与流式的验证不同，此处会检测对象中非可选的子元素是否存在，以便于区分不同的变体。
+func (validator *jsonValidator) validTree(p *ast.Param, v any, field string, chkArray bool) error {
+	if v == nil {
+		if !p.Nullable.V() {
+			return core.NewError(locale.ErrInvalidValue).WithField(field)
+		}
+		return nil
+	}
+
+	if chkArray && p.Array.V() {
+		arr, ok := v.([]any)
+		if !ok {
+			return core.NewError(locale.ErrInvalidFormat).WithField(field)
+		}
+
+		values := make([]string, 0, len(arr))
+		for _, item := range arr {
+			if err := validator.validTree(p, item, field, false); err != nil {
+				return err
+			}
+
+			switch item.(type) {
+			case map[string]any, []any:
+			default:
+				values = append(values, fmt.Sprint(item))
+			}
+		}
+		if len(values) != len(arr) { // 包含非基本类型的元素，不检测是否重复。
+			values = nil
+		}
+
+		if err := p.CheckItems(len(arr), values); err != nil {
+			return err.WithField(field)
+		}
+		return nil
+	}
+
+	switch vv := v.(type) {
+	case map[string]any:
+		return validator.validObject(p, vv, field)
+	case string:
+		return validJSONPrimitive(p, ast.TypeString, vv, field)
+	case bool:
+		return validJSONPrimitive(p, ast.TypeBool, vv, field)
+	case float64:
+		return validJSONPrimitive(p, ast.TypeNumber, vv, field)
+	default: // []any
+		return core.NewError(locale.ErrInvalidFormat).WithField(field)
+	}
+}
+
+func (validator *jsonValidator) validObject(p *ast.Param, obj map[string]any, field string) error {
+	if value := p.MapValue(); value != nil { // 字典可以是任意的键名
+		for _, key := range sortedKeys(obj) {
+			if err := validator.validTree(value, obj[key], buildJSONField(field, key), false); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if p.Type.V() != ast.TypeObject {
+		return core.NewError(locale.ErrInvalidFormat).WithField(field)
+	}
+
+	if len(p.Variants) > 0 {
+		return validator.validVariants(p, obj, field)
+	}
+
+	for _, item := range p.Items {
+		if checkAccess(item, validator.request, "") != nil { // 不允许出现的字段，也就不存在是否必须的问题。
+			continue
+		}
+		if _, found := obj[item.Name.V()]; !found && !item.Optional.V() {
+			return core.NewError(locale.ErrIsEmpty, item.Name.V()).WithField(buildJSONField(field, item.Name.V()))
+		}
+	}
+
+	for _, key := range sortedKeys(obj) {
+		var item *ast.Param
+		for _, pp := range p.Items {
+			if pp.Name.V() == key {
+				item = pp
+				break
+			}
+		}
+
+		f := buildJSONField(field, key)
+		if item == nil {
+			return core.NewError(locale.ErrNotFound).WithField(f)
+		}
+		if err := checkAccess(item, validator.request, f); err != nil {
+			return err
+		}
+		if err := validator.validTree(item, obj[key], f, true); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// 验证 obj 是否符合复合类型 p 中的变体
+//
+// 指定了 discriminator 时，直接根据其值确定变体；
+// 否则 one-of 要求仅符合其中一个变体，any-of 至少符合其中一个变体。
+func (validator *jsonValidator) validVariants(p *ast.Param, obj map[string]any, field string) error {
+	variants := p.Expand()
+
+	if disc := p.Discriminator.V(); disc != "" {
+		val := fmt.Sprint(obj[disc])
+		for i, v := range p.Variants {
+			if v.Value.V() == val {
+				return validator.validObject(variants[i], obj, field)
+			}
+		}
+		return core.NewError(locale.ErrInvalidValue).WithField(buildJSONField(field, disc))
+	}
+
+	var matched int
+	var err error
+	for _, v := range variants {
+		if e := validator.validObject(v, obj, field); e != nil {
+			if err == nil {
+				err = e
+			}
+			continue
+		}
+		matched++
+	}
+
+	switch {
+	case matched == 0:
+		return err
+	case matched > 1 && p.ComposeType() == ast.ComposeOneOf:
+		return core.NewError(locale.ErrInvalidValue).WithField(field)
+	}
+	return nil
+}
+
+func buildJSONField(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // 返回当前的状态
 func (validator *jsonValidator) state() byte {
 	if len(validator.states) > 0 {
@@ -220,12 +544,66 @@ func (validator *jsonValidator) popName() {
 	}
 }
 
+func (validator *jsonValidator) pushArray() {
+	var p *ast.Param
+	if validator.state() == '[' { // 嵌套的数组，无法与参数对应。
+		validator.addItem(nil)
+	} else {
+		p = validator.find()
+	}
+
+	validator.arrays = append(validator.arrays, &jsonArray{
+		param: p,
+		field: strings.Join(validator.names, "."),
+	})
+}
+
+// 弹出最后一个数组，并验证其是否符合 min-items 等约束。
+func (validator *jsonValidator) popArray() error {
+	if len(validator.arrays) == 0 {
+		return nil
+	}
+	arr := validator.arrays[len(validator.arrays)-1]
+	validator.arrays = validator.arrays[:len(validator.arrays)-1]
+
+	if arr.param == nil || !arr.param.Array.V() {
+		return nil
+	}
+
+	values := arr.values
+	if len(values) != arr.size { // 包含非基本类型的元素，不检测是否重复。
+		values = nil
+	}
+	if err := arr.param.CheckItems(arr.size, values); err != nil {
+		return err.WithField(arr.field)
+	}
+	return nil
+}
+
+// 向最后一个数组添加元素，v 为 nil 表示非基本类型的元素。
+func (validator *jsonValidator) addItem(v any) {
+	if len(validator.arrays) == 0 {
+		return
+	}
+
+	arr := validator.arrays[len(validator.arrays)-1]
+	arr.size++
+	if v != nil {
+		arr.values = append(arr.values, fmt.Sprint(v))
+	}
+}
+
 // 如果 names 为空，返回 validator.param
 func (validator *jsonValidator) find() *ast.Param {
 	p := validator.param
 
 LOOP:
 	for _, name := range validator.names {
+		if value := p.MapValue(); value != nil { // 字典中的任意键名都对应相同的值
+			p = value
+			continue
+		}
+
 		for _, pp := range p.Items {
 			if pp.Name.V() == name {
 				p = pp
@@ -239,19 +617,24 @@ LOOP:
 }
 
 type jsonBuilder struct {
-	w      *errwrap.Buffer
-	deep   int
-	indent string // 单次的缩进
+	w       *errwrap.Buffer
+	deep    int
+	indent  string // 单次的缩进
+	request bool   // 是否为请求内容
 }
 
-func buildJSON(p *ast.Request, indent string, g *GenOptions) ([]byte, error) {
+// 生成 p 的 JSON 内容
+//
+// request 表示生成的是请求内容，会去掉只读的字段，否则去掉只写的字段。
+func buildJSON(p *ast.Request, request bool, indent string, g *GenOptions) ([]byte, error) {
 	if p != nil && p.Type.V() == ast.TypeNone {
 		return nil, nil
 	}
 
 	builder := &jsonBuilder{
-		w:      &errwrap.Buffer{},
-		indent: indent,
+		w:       &errwrap.Buffer{},
+		indent:  indent,
+		request: request,
 	}
 
 	if err := builder.encode(p.Param(), true, g); err != nil {
@@ -266,14 +649,27 @@ func (builder *jsonBuilder) encode(p *ast.Param, chkArray bool, g *GenOptions) e
 		return builder.writeValue(nil).w.Err
 	}
 
+	if chkArray && g.generateNull(p) {
+		return builder.writeValue(nil).w.Err
+	}
+
 	if p.Array.V() && chkArray {
 		builder.w.WString("[\n")
 		builder.deep++
 
-		size := g.generateSliceSize()
+		size := g.generateSliceSize(p)
+		var items []any // 基本类型的元素值，需要统一生成以保证 unique-items 约束。
+		if primitive, _ := ast.ParseType(p.Type.V()); primitive != ast.TypeObject && primitive != ast.TypeMap {
+			items = g.generateItems(p, size)
+			size = len(items)
+		}
+
 		last := size - 1
 		for i := 0; i < size; i++ {
-			if err := builder.writeIndent().encode(p, false, g); err != nil {
+			builder.writeIndent()
+			if items != nil {
+				builder.writeValue(items[i])
+			} else if err := builder.encode(p, false, g); err != nil {
 				return err
 			}
 
@@ -288,6 +684,11 @@ func (builder *jsonBuilder) encode(p *ast.Param, chkArray bool, g *GenOptions) e
 		return builder.writeIndent().w.WString("]").Err
 	}
 
+	if len(p.Variants) > 0 { // 复合类型随机选取其中一个变体
+		variants := p.Expand()
+		p = variants[g.index(len(variants))]
+	}
+
 	switch primitive, _ := ast.ParseType(p.Type.V()); primitive {
 	case ast.TypeNone:
 		builder.writeValue(nil)
@@ -297,12 +698,15 @@ func (builder *jsonBuilder) encode(p *ast.Param, chkArray bool, g *GenOptions) e
 		builder.writeValue(g.generateNumber(p))
 	case ast.TypeString:
 		builder.writeValue(g.generateString(p))
+	case ast.TypeFile:
+		builder.writeValue(g.generateFile(p))
 	case ast.TypeObject:
 		builder.w.WString("{\n")
 		builder.deep++
 
-		last := len(p.Items) - 1
-		for index, item := range p.Items {
+		items := filterItems(p.Items, builder.request)
+		last := len(items) - 1
+		for index, item := range items {
 			builder.writeIndent().w.WString(`"`).WString(item.Name.V()).WString(`"`).WString(": ")
 
 			if err := builder.encode(item, true, g); err != nil {
@@ -316,6 +720,29 @@ func (builder *jsonBuilder) encode(p *ast.Param, chkArray bool, g *GenOptions) e
 			}
 		}
 
+		builder.deep--
+		builder.writeIndent().w.WString("}")
+	case ast.TypeMap:
+		builder.w.WString("{\n")
+		builder.deep++
+
+		value := p.MapValue()
+		keys := g.generateMapKeys()
+		last := len(keys) - 1
+		for index, key := range keys {
+			builder.writeIndent().w.WString(`"`).WString(key).WString(`"`).WString(": ")
+
+			if err := builder.encode(value, false, g); err != nil {
+				return err
+			}
+
+			if index < last {
+				builder.w.WString(",\n")
+			} else {
+				builder.w.WString("\n")
+			}
+		}
+
 		builder.deep--
 		builder.writeIndent().w.WString("}")
 	}
@@ -17,19 +17,19 @@ func TestJSONValidator_valid(t *testing.T) {
 	a := assert.New(t, false)
 
 	r := &ast.Request{Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}}}
-	v := newJSONValidator(r)
+	v := newJSONValidator(r, false)
 	d := json.NewDecoder(strings.NewReader(`"str"`))
 	a.NotError(v.valid(d))
 	a.Empty(v.names)
 
 	r = &ast.Request{Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeNumber}}}
-	v = newJSONValidator(r)
+	v = newJSONValidator(r, false)
 	d = json.NewDecoder(strings.NewReader(`5.0`))
 	a.NotError(v.valid(d))
 	a.Empty(v.names)
 
 	r = &ast.Request{Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}}}
-	v = newJSONValidator(r)
+	v = newJSONValidator(r, false)
 	d = json.NewDecoder(strings.NewReader(`5.0`))
 	a.Error(v.valid(d))
 }
@@ -115,7 +115,7 @@ func TestValidJSON(t *testing.T) {
 	a := assert.New(t, false)
 
 	for _, item := range data {
-		err := validJSON(item.Type, []byte(item.JSON))
+		err := validJSON(item.Type, []byte(item.JSON), false)
 		a.NotError(err, "测试 %s 时返回错误值 %s", item.Title, err)
 	}
 }
@@ -124,9 +124,192 @@ func TestBuildJSON(t *testing.T) {
 	a := assert.New(t, false)
 
 	for _, item := range data {
-		data, err := buildJSON(item.Type, indent, testOptions)
+		data, err := buildJSON(item.Type, false, indent, testOptions)
 
 		a.NotError(err, "测试 %s 返回了错误值 %s", item.Title, err).
 			Equal(string(data), item.JSON, "测试 %s 失败 v1:%s,v2:%s", item.Title, string(data), item.JSON)
 	}
 }
+
+func TestJSONValidator_valid_constraints(t *testing.T) {
+	a := assert.New(t, false)
+
+	r := &ast.Request{
+		Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeObject}},
+		Items: []*ast.Param{
+			{
+				Type:        &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeNumber}},
+				Name:        &ast.Attribute{Value: xmlenc.String{Value: "ids"}},
+				Array:       &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+				MinItems:    &ast.NumberAttribute{Value: ast.Number{Int: 1}},
+				MaxItems:    &ast.NumberAttribute{Value: ast.Number{Int: 3}},
+				UniqueItems: &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+				Max:         &ast.NumberAttribute{Value: ast.Number{Int: 100}},
+			},
+			{
+				Type:      &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+				Name:      &ast.Attribute{Value: xmlenc.String{Value: "name"}},
+				MaxLength: &ast.NumberAttribute{Value: ast.Number{Int: 5}},
+			},
+		},
+	}
+
+	data := map[string]bool{
+		`{"ids":[1,2,3],"name":"abc"}`:   true,
+		`{"ids":[],"name":"abc"}`:        false, // min-items
+		`{"ids":[1,2,3,4],"name":"abc"}`: false, // max-items
+		`{"ids":[1,1],"name":"abc"}`:     false, // unique-items
+		`{"ids":[1,200],"name":"abc"}`:   false, // max
+		`{"ids":[1],"name":"abcdef"}`:    false, // max-length
+	}
+	for data, ok := range data {
+		err := newJSONValidator(r, false).valid(json.NewDecoder(strings.NewReader(data)))
+		if ok {
+			a.NotError(err, "%s 返回了错误 %s", data, err)
+		} else {
+			a.Error(err, "%s 未返回错误", data)
+		}
+	}
+}
+
+func TestValidJSON_composite(t *testing.T) {
+	a := assert.New(t, false)
+
+	newParam := func(name, typ string) *ast.Param {
+		return &ast.Param{
+			Name: &ast.Attribute{Value: xmlenc.String{Value: name}},
+			Type: &ast.TypeAttribute{Value: xmlenc.String{Value: typ}},
+		}
+	}
+
+	variants := []*ast.Variant{
+		{
+			Value: &ast.Attribute{Value: xmlenc.String{Value: "created"}},
+			Items: []*ast.Param{newParam("id", ast.TypeNumber)},
+		},
+		{
+			Value: &ast.Attribute{Value: xmlenc.String{Value: "deleted"}},
+			Items: []*ast.Param{newParam("id", ast.TypeNumber), newParam("reason", ast.TypeString)},
+		},
+	}
+	event := &ast.Param{
+		Name:  &ast.Attribute{Value: xmlenc.String{Value: "event"}},
+		Type:  &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeObject}},
+		Array: &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+		Items: []*ast.Param{newParam("kind", ast.TypeString)},
+		Composite: ast.Composite{
+			Discriminator: &ast.Attribute{Value: xmlenc.String{Value: "kind"}},
+			Variants:      variants,
+		},
+	}
+	r := &ast.Request{
+		Type:  &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeObject}},
+		Items: []*ast.Param{newParam("name", ast.TypeString), event},
+	}
+
+	data := map[string]bool{
+		`{"name":"n","event":[{"kind":"created","id":1},{"id":2,"reason":"r","kind":"deleted"}]}`: true,
+		`{"event":[{"kind":"created","id":1}],"name":"n"}`:                                        true,
+		`{"name":"n","event":[{"kind":"updated","id":1}]}`:                                        false, // 不存在的 discriminator
+		`{"name":"n","event":[{"kind":"created","id":"1"}]}`:                                      false, // 类型错误
+		`{"name":"n","event":[{"kind":"created","reason":"r","id":1}]}`:                           false, // created 中不存在 reason
+		`{"name":"n","event":[{"kind":"deleted","id":1}]}`:                                        false, // 缺少 reason
+	}
+	for data, ok := range data {
+		err := validJSON(r, []byte(data), false)
+		if ok {
+			a.NotError(err, "%s 返回了错误 %s", data, err)
+		} else {
+			a.Error(err, "%s 未返回错误", data)
+		}
+	}
+
+	// 未指定 discriminator
+	event.Discriminator = nil
+	event.Items = nil
+	a.NotError(validJSON(r, []byte(`{"name":"n","event":[{"id":1},{"id":1,"reason":"r"}]}`), false))
+	a.NotError(validJSON(r, []byte(`{"name":"n","event":[{"id":1,"reason":"r"}]}`), false))
+	a.Error(validJSON(r, []byte(`{"name":"n","event":[{"reason":"r"}]}`), false))
+
+	// one-of 要求仅符合其中一个变体
+	variants[1].Items[1].Optional = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+	a.Error(validJSON(r, []byte(`{"name":"n","event":[{"id":1}]}`), false))
+	event.Compose = &ast.Attribute{Value: xmlenc.String{Value: ast.ComposeAnyOf}}
+	a.NotError(validJSON(r, []byte(`{"name":"n","event":[{"id":1}]}`), false))
+}
+
+func TestValidJSON_map(t *testing.T) {
+	a := assert.New(t, false)
+
+	var item *tester
+	for _, i := range data {
+		if i.Title == "map" {
+			item = i
+		}
+	}
+	a.NotNil(item)
+
+	data := map[string]bool{
+		`{"scores":{"a":1,"b":2},"users":{"u1":{"name":"n1"}}}`: true,
+		`{"scores":{},"users":{}}`:                              true,
+		`{"scores":{"a":"1"},"users":{}}`:                       false, // 值的类型错误
+		`{"scores":{"a":1},"users":{"u1":{"age":1}}}`:           false, // 值中不存在 age
+		`{"scores":{"a":1},"users":{"u1":"n1"}}`:                false, // 值应该是对象
+		`{"scores":1,"users":{}}`:                               false,
+	}
+	for data, ok := range data {
+		err := validJSON(item.Type, []byte(data), false)
+		if ok {
+			a.NotError(err, "%s 返回了错误 %s", data, err)
+		} else {
+			a.Error(err, "%s 未返回错误", data)
+		}
+	}
+}
+
+func TestValidJSON_access(t *testing.T) {
+	a := assert.New(t, false)
+
+	var item *tester
+	for _, i := range data {
+		if i.Title == "access" {
+			item = i
+		}
+	}
+	a.NotNil(item)
+
+	// 返回内容
+	a.NotError(validJSON(item.Type, []byte(`{"id":1,"nickname":null}`), false))
+	a.NotError(validJSON(item.Type, []byte(`{"id":1,"nickname":"n"}`), false))
+	a.Error(validJSON(item.Type, []byte(`{"id":null,"nickname":"n"}`), false))             // id 不能为 null
+	a.Error(validJSON(item.Type, []byte(`{"id":1,"password":"p","nickname":"n"}`), false)) // 只写字段
+
+	// 请求内容
+	a.NotError(validJSON(item.Type, []byte(`{"password":"p","nickname":null}`), true))
+	a.Error(validJSON(item.Type, []byte(`{"id":1,"password":"p"}`), true)) // 只读字段
+	a.Error(validJSON(item.Type, []byte(`{"password":null}`), true))
+}
+
+func TestValidJSON_file(t *testing.T) {
+	a := assert.New(t, false)
+
+	r := &ast.Request{
+		Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeObject}},
+		Items: []*ast.Param{
+			{
+				Type:    &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeFile}},
+				Name:    &ast.Attribute{Value: xmlenc.String{Value: "avatar"}},
+				MaxSize: &ast.NumberAttribute{Value: ast.Number{Int: 4}},
+			},
+		},
+	}
+
+	data, err := buildJSON(r, false, indent, testOptions)
+	a.NotError(err)
+	a.NotError(validJSON(r, data, false))
+
+	a.NotError(validJSON(r, []byte(`{"avatar":"MTAyNA=="}`), false))
+	a.Error(validJSON(r, []byte(`{"avatar":"MTAyNDU="}`), false)) // max-size
+	a.Error(validJSON(r, []byte(`{"avatar":"not base64"}`), false))
+	a.Error(validJSON(r, []byte(`{"avatar":1}`), false))
+}
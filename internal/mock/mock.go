@@ -4,6 +4,7 @@
 package mock
 
 import (
+	"hash/fnv"
 	"image"
 	"image/gif"
 	"image/jpeg"
@@ -31,29 +32,82 @@ type mock struct {
 	servers    map[string]string
 	indent     string
 	gen        *GenOptions
+	store      *store    // 有状态模式下保存的数据，为空表示未启用有状态模式。
+	examples   bool      // 是否优先使用文档中的示例代码
+	security   bool      // 是否验证 api 要求的安全验证方案
+	callback   bool      // 是否根据 api 中的 callback 向客户端指定的地址发送回调
+	fault      *Fault    // 延迟与故障注入的设置，为空表示仅由 FaultHeader 报头指定。
+	fixtures   *fixtures // 录制的内容，为空表示未启用。
+	seedGen    func(int64) *GenOptions
+	admin      *admin // 管理接口，为空表示未启用。
 }
 
 // New 声明 Mock 对象
 //
 // msg 用于处理各类输出消息，仅在 ServeHTTP 中的消息才输出到 msg；
 // d doc.APIDoc 实例，调用方需要保证该数据类型的正确性；
-// indent 缩进字符串；
-// servers 用于指定 d.Servers 中每一个服务对应的路由前缀；
-// gen 生成随机数据的函数；
-func New(msg *core.MessageHandler, d *ast.APIDoc, indent, imageURL string, servers map[string]string, gen *GenOptions) (http.Handler, error) {
-	c, err := version.SemVerCompatible(d.APIDoc.V(), ast.Version)
+// o 为 mock 服务的设置项；
+func New(msg *core.MessageHandler, d *ast.APIDoc, o *Options) (http.Handler, error) {
+	return newHandler(msg, d, o, "")
+}
+
+// Load 从本地或是远程加载文档内容
+//
+// 启用了管理接口时，可以通过管理接口从 path 重新加载文档。
+func Load(h *core.MessageHandler, path core.URI, o *Options) (http.Handler, error) {
+	d, err := loadDoc(h, path)
 	if err != nil {
 		return nil, err
 	}
-	if !c {
-		return nil, locale.NewError(locale.VersionInCompatible)
+	return newHandler(h, d, o, path)
+}
+
+// path 为文档的路径，为空表示不支持通过管理接口重新加载文档。
+func newHandler(msg *core.MessageHandler, d *ast.APIDoc, o *Options, path core.URI) (http.Handler, error) {
+	if o.Upstream != nil { // 录制模式
+		if err := checkVersion(d); err != nil {
+			return nil, err
+		}
+
+		var fxs *fixtures
+		if o.Fixtures != "" {
+			var err error
+			if fxs, err = loadFixtures(o.Fixtures); err != nil {
+				return nil, err
+			}
+		}
+		return newProxy(msg, d, o.Upstream, o.Servers, fxs)
+	}
+
+	m, err := newMock(msg, d, o)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.Admin == "" {
+		return m, nil
+	}
+	return newAdmin(msg, m, o, path), nil
+}
+
+func newMock(msg *core.MessageHandler, d *ast.APIDoc, o *Options) (*mock, error) {
+	if err := checkVersion(d); err != nil {
+		return nil, err
+	}
+
+	var fxs *fixtures
+	if o.Fixtures != "" {
+		var err error
+		if fxs, err = loadFixtures(o.Fixtures); err != nil {
+			return nil, err
+		}
 	}
 
 	mu := std.NewRouter("apidoc mock server")
 	router := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		h := w.Header()
 		h.Set("Access-Control-Allow-Origin", "*")
-		h.Set("Access-Control-Allow-Headers", "Content-Type, Access-Control-Allow-Headers, Authorization")
+		h.Set("Access-Control-Allow-Headers", "Content-Type, Access-Control-Allow-Headers, Authorization, "+ExampleHeader+", "+CallbackHeader+", "+FaultHeader+", "+preferHeader)
 		h.Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, HEAD, OPTIONS")
 		mu.ServeHTTP(w, r)
 	})
@@ -63,14 +117,23 @@ func New(msg *core.MessageHandler, d *ast.APIDoc, indent, imageURL string, serve
 		doc:        d,
 		router:     mu,
 		h:          router,
-		indent:     indent,
-		servers:    servers,
-		gen:        gen,
+		indent:     o.Indent,
+		servers:    o.Servers,
+		gen:        o.Gen,
+		examples:   o.Examples,
+		security:   o.Security,
+		callback:   o.Callback,
+		fault:      o.Fault,
+		fixtures:   fxs,
+		seedGen:    o.SeedGen,
+	}
+	if o.Stateful {
+		m.store = newStore(d)
 	}
 
-	if imageURL != "" {
+	if imageURL := o.ImageURL; imageURL != "" {
 		if imageURL[0] != '/' || imageURL[len(imageURL)-1] == '/' {
-			panic("参数 imageURL 必须以 / 开头且不能以 / 结尾")
+			panic("参数 o.ImageURL 必须以 / 开头且不能以 / 结尾")
 		}
 		m.router.Get(imageURL+"/{path}", http.HandlerFunc(m.getImage))
 	}
@@ -80,8 +143,7 @@ func New(msg *core.MessageHandler, d *ast.APIDoc, indent, imageURL string, serve
 	return m, nil
 }
 
-// Load 从本地或是远程加载文档内容
-func Load(h *core.MessageHandler, path core.URI, indent, imageURL string, servers map[string]string, gen *GenOptions) (http.Handler, error) {
+func loadDoc(h *core.MessageHandler, path core.URI) (*ast.APIDoc, error) {
 	data, err := path.ReadAll(nil)
 	if err != nil {
 		return nil, err
@@ -97,32 +159,52 @@ func Load(h *core.MessageHandler, path core.URI, indent, imageURL string, server
 	// 加载并验证
 	d := &ast.APIDoc{}
 	d.Parse(h, b)
-	return New(h, d, indent, imageURL, servers, gen)
+	return d, nil
+}
+
+// 检测文档的版本是否与当前程序兼容
+func checkVersion(d *ast.APIDoc) error {
+	c, err := version.SemVerCompatible(d.APIDoc.V(), ast.Version)
+	if err != nil {
+		return err
+	}
+	if !c {
+		return locale.NewError(locale.VersionInCompatible)
+	}
+	return nil
 }
 
 func (m *mock) parse() {
-	for _, api := range m.doc.APIs {
-		handler := m.buildAPI(api)
+	handleAPIs(m.msgHandler, m.router, m.doc, m.servers, m.buildAPI)
+}
+
+// 将 d 中的所有 API 注册到 router 中
+//
+// servers 用于指定 d.Servers 中每一个服务对应的路由前缀；
+// build 用于生成每一个 API 对应的处理函数。
+func handleAPIs(msg *core.MessageHandler, router *std.Router, d *ast.APIDoc, servers map[string]string, build func(*ast.API) http.Handler) {
+	for _, api := range d.APIs {
+		handler := build(api)
 		method := api.Method.V()
 		path := api.Path.Path.V()
 
 		if len(api.Servers) == 0 {
-			m.router.Handle(path, handler, method)
+			router.Handle(path, handler, method)
 			continue
 		}
 
 		for _, srv := range api.Servers {
-			prefix, found := m.servers[srv.V()]
+			prefix, found := servers[srv.V()]
 			if !found {
 				prefix = "/" + srv.V()
 			}
-			m.router.Prefix(prefix).Handle(path, handler, method)
+			router.Prefix(prefix).Handle(path, handler, method)
 		}
 	}
 
-	routers := m.router.Routes()
+	routers := router.Routes()
 	for path, methods := range routers {
-		m.msgHandler.Locale(core.Info, locale.LoadAPI, "["+strings.Join(methods, ",")+"]", path)
+		msg.Locale(core.Info, locale.LoadAPI, "["+strings.Join(methods, ",")+"]", path)
 	}
 }
 
@@ -130,6 +212,19 @@ func (m *mock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	m.h.ServeHTTP(w, r)
 }
 
+// 返回用于生成 r 的返回内容的 GenOptions
+//
+// 每次调用都会返回新的对象，同一个请求应该只调用一次。
+func (m *mock) requestGen(r *http.Request) *GenOptions {
+	if m.seedGen == nil {
+		return m.gen
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(r.Method + " " + r.URL.Path + "?" + r.URL.Query().Encode())) // Encode 会对键名进行排序
+	return m.seedGen(int64(h.Sum64()))
+}
+
 func (m *mock) getImage(w http.ResponseWriter, r *http.Request) {
 	width, height := 500, 500
 	var err error
@@ -649,6 +649,160 @@ var data = []*tester{
             <name>1024</name>
         </tags>
     </group>
+</root>`,
+	},
+
+	{
+		Title: "composite",
+		Type: &ast.Request{
+			Name: &ast.Attribute{Value: xmlenc.String{Value: "event"}},
+			Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeObject}},
+			Items: []*ast.Param{
+				{
+					Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+					Name: &ast.Attribute{Value: xmlenc.String{Value: "kind"}},
+					XML:  ast.XML{XMLAttr: &ast.BoolAttribute{Value: ast.Bool{Value: true}}},
+				},
+			},
+			Composite: ast.Composite{
+				Discriminator: &ast.Attribute{Value: xmlenc.String{Value: "kind"}},
+				Variants: []*ast.Variant{
+					{
+						Value: &ast.Attribute{Value: xmlenc.String{Value: "created"}},
+						Items: []*ast.Param{
+							{
+								Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeNumber}},
+								Name: &ast.Attribute{Value: xmlenc.String{Value: "id"}},
+							},
+						},
+					},
+					{
+						Value: &ast.Attribute{Value: xmlenc.String{Value: "deleted"}},
+						Items: []*ast.Param{
+							{
+								Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+								Name: &ast.Attribute{Value: xmlenc.String{Value: "reason"}},
+							},
+						},
+					},
+				},
+			},
+		},
+		JSON: `{
+    "kind": "created",
+    "id": 1024
+}`,
+		XML: `<event kind="created">
+    <id>1024</id>
+</event>`,
+	},
+
+	{
+		Title: "access",
+		Type: &ast.Request{
+			Name: &ast.Attribute{Value: xmlenc.String{Value: "user"}},
+			Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeObject}},
+			Items: []*ast.Param{
+				{
+					Type:     &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeNumber}},
+					Name:     &ast.Attribute{Value: xmlenc.String{Value: "id"}},
+					ReadOnly: &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+				},
+				{
+					Type:      &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+					Name:      &ast.Attribute{Value: xmlenc.String{Value: "password"}},
+					WriteOnly: &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+				},
+				{
+					Type:     &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+					Name:     &ast.Attribute{Value: xmlenc.String{Value: "nickname"}},
+					Nullable: &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+				},
+			},
+		},
+		JSON: `{
+    "id": 1024,
+    "nickname": null
+}`,
+		XML: `<user>
+    <id>1024</id>
+    <nickname>1024</nickname>
+</user>`,
+	},
+
+	{
+		Title: "map",
+		Type: &ast.Request{
+			Name: &ast.Attribute{Value: xmlenc.String{Value: "root"}},
+			Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeObject}},
+			Items: []*ast.Param{
+				{
+					Type: &ast.TypeAttribute{Value: xmlenc.String{Value: "map.number"}},
+					Name: &ast.Attribute{Value: xmlenc.String{Value: "scores"}},
+				},
+				{
+					Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeMap}},
+					Name: &ast.Attribute{Value: xmlenc.String{Value: "users"}},
+					Items: []*ast.Param{
+						{
+							Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+							Name: &ast.Attribute{Value: xmlenc.String{Value: "name"}},
+						},
+					},
+				},
+			},
+		},
+		JSON: `{
+    "scores": {
+        "key1": 1024,
+        "key2": 1024,
+        "key3": 1024,
+        "key4": 1024,
+        "key5": 1024
+    },
+    "users": {
+        "key1": {
+            "name": "1024"
+        },
+        "key2": {
+            "name": "1024"
+        },
+        "key3": {
+            "name": "1024"
+        },
+        "key4": {
+            "name": "1024"
+        },
+        "key5": {
+            "name": "1024"
+        }
+    }
+}`,
+		XML: `<root>
+    <scores>
+        <key1>1024</key1>
+        <key2>1024</key2>
+        <key3>1024</key3>
+        <key4>1024</key4>
+        <key5>1024</key5>
+    </scores>
+    <users>
+        <key1>
+            <name>1024</name>
+        </key1>
+        <key2>
+            <name>1024</name>
+        </key2>
+        <key3>
+            <name>1024</name>
+        </key3>
+        <key4>
+            <name>1024</name>
+        </key4>
+        <key5>
+            <name>1024</name>
+        </key5>
+    </users>
 </root>`,
 	},
 }
@@ -662,7 +816,7 @@ func TestNew(t *testing.T) {
 	a.Empty(rslt.Errors)
 
 	rslt = messagetest.NewMessageHandler()
-	mock, err := New(rslt.Handler, d, indent, "/images", map[string]string{"client": "/test"}, testOptions)
+	mock, err := New(rslt.Handler, d, &Options{Indent: indent, ImageURL: "/images", Servers: map[string]string{"client": "/test"}, Gen: testOptions})
 	a.NotError(err).NotNil(mock)
 	srv := rest.NewServer(a, mock, nil)
 
@@ -676,7 +830,7 @@ func TestNew(t *testing.T) {
 	a.NotEmpty(rslt.Errors)
 
 	rslt = messagetest.NewMessageHandler()
-	mock, err = New(rslt.Handler, d, indent, "/images", map[string]string{"admin": "/test"}, testOptions)
+	mock, err = New(rslt.Handler, d, &Options{Indent: indent, ImageURL: "/images", Servers: map[string]string{"admin": "/test"}, Gen: testOptions})
 	a.NotError(err).NotNil(mock)
 	srv = rest.NewServer(a, mock, nil)
 
@@ -733,21 +887,83 @@ func TestNew(t *testing.T) {
 
 	// 版本号兼容性
 	rslt = messagetest.NewMessageHandler()
-	mock, err = New(rslt.Handler, &ast.APIDoc{APIDoc: &ast.APIDocVersionAttribute{Value: xmlenc.String{Value: "1.0.1"}}}, indent, "/images", nil, testOptions)
+	mock, err = New(rslt.Handler, &ast.APIDoc{APIDoc: &ast.APIDocVersionAttribute{Value: xmlenc.String{Value: "1.0.1"}}}, &Options{Indent: indent, ImageURL: "/images", Gen: testOptions})
 	a.Error(err).Nil(mock)
 	rslt.Handler.Stop()
 }
 
+func TestNew_examples(t *testing.T) {
+	a := assert.New(t, false)
+
+	const doc = `<apidoc version="1.1.1">
+	<title>test</title>
+	<mimetype>application/json</mimetype>
+	<mimetype>application/xml</mimetype>
+	<api method="GET" summary="get">
+		<path path="/users" />
+		<response status="200" type="object" name="user">
+			<param name="name" type="string" summary="name" />
+			<example mimetype="application/json" summary="default"><![CDATA[{"name":"n1"}]]></example>
+			<example mimetype="application/json" summary="other"><![CDATA[{"name":"n2"}]]></example>
+		</response>
+	</api>
+</apidoc>`
+	rslt := messagetest.NewMessageHandler()
+	d := &ast.APIDoc{APIDoc: &ast.APIDocVersionAttribute{Value: xmlenc.String{Value: ast.Version}}}
+	d.Parse(rslt.Handler, core.Block{Data: []byte(doc)})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	rslt = messagetest.NewMessageHandler()
+	mock, err := New(rslt.Handler, d, &Options{Indent: indent, ImageURL: "/images", Gen: testOptions, Examples: true})
+	a.NotError(err).NotNil(mock)
+	srv := rest.NewServer(a, mock, nil)
+
+	srv.Get("/users").Header("accept", "application/json").Do(nil).
+		Status(http.StatusOK).
+		Header("content-type", "application/json").
+		StringBody(`{"name":"n1"}`)
+
+	srv.Get("/users").
+		Header("accept", "application/json").
+		Header(ExampleHeader, "other").
+		Do(nil).
+		Status(http.StatusOK).
+		StringBody(`{"name":"n2"}`)
+
+	// 没有相匹配的示例代码，采用随机数据。
+	srv.Get("/users").Header("accept", "application/xml").Do(nil).
+		Status(http.StatusOK).
+		Header("content-type", "application/xml").
+		BodyNotEmpty()
+
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	// 不存在的示例名称
+	rslt = messagetest.NewMessageHandler()
+	mock, err = New(rslt.Handler, d, &Options{Indent: indent, ImageURL: "/images", Gen: testOptions, Examples: true})
+	a.NotError(err).NotNil(mock)
+	srv = rest.NewServer(a, mock, nil)
+	srv.Get("/users").
+		Header("accept", "application/json").
+		Header(ExampleHeader, "not-exists").
+		Do(nil).
+		Status(http.StatusBadRequest)
+	rslt.Handler.Stop()
+	a.NotEmpty(rslt.Errors)
+}
+
 func TestLoad(t *testing.T) {
 	a := assert.New(t, false)
 	rslt := messagetest.NewMessageHandler()
-	mock, err := Load(rslt.Handler, "./not-exists", indent, "/images", nil, testOptions)
+	mock, err := Load(rslt.Handler, "./not-exists", &Options{Indent: indent, ImageURL: "/images", Gen: testOptions})
 	rslt.Handler.Stop()
 	a.Error(err).Nil(mock)
 
 	// LoadFromPath
 	rslt = messagetest.NewMessageHandler()
-	mock, err = Load(rslt.Handler, asttest.URI(a), indent, "/images", map[string]string{"admin": "/admin"}, testOptions)
+	mock, err = Load(rslt.Handler, asttest.URI(a), &Options{Indent: indent, ImageURL: "/images", Servers: map[string]string{"admin": "/admin"}, Gen: testOptions})
 	rslt.Handler.Stop()
 	a.NotError(err).NotNil(mock)
 
@@ -757,11 +973,33 @@ func TestLoad(t *testing.T) {
 	defer srv.Close()
 
 	rslt = messagetest.NewMessageHandler()
-	mock, err = Load(rslt.Handler, core.URI(srv.URL+"/index.xml"), indent, "/images", map[string]string{"admin": "/admin"}, testOptions)
+	mock, err = Load(rslt.Handler, core.URI(srv.URL+"/index.xml"), &Options{Indent: indent, ImageURL: "/images", Servers: map[string]string{"admin": "/admin"}, Gen: testOptions})
 	rslt.Handler.Stop()
 	a.NotError(err).NotNil(mock)
 }
 
+func TestMock_requestGen(t *testing.T) {
+	a := assert.New(t, false)
+
+	m := &mock{gen: testOptions}
+	r := httptest.NewRequest(http.MethodGet, "/users?b=2&a=1", nil)
+	a.Equal(m.requestGen(r), testOptions)
+
+	seeds := make([]int64, 0, 4)
+	m.seedGen = func(seed int64) *GenOptions {
+		seeds = append(seeds, seed)
+		return testOptions
+	}
+	a.Equal(m.requestGen(r), testOptions)
+	m.requestGen(httptest.NewRequest(http.MethodGet, "/users?a=1&b=2", nil))
+	m.requestGen(httptest.NewRequest(http.MethodGet, "/users/1?a=1&b=2", nil))
+	m.requestGen(httptest.NewRequest(http.MethodDelete, "/users?a=1&b=2", nil))
+	a.Equal(4, len(seeds)).
+		Equal(seeds[0], seeds[1]). // 查询参数的顺序不影响结果
+		NotEqual(seeds[0], seeds[2]).
+		NotEqual(seeds[0], seeds[3])
+}
+
 func TestIsValidRFC3339Date(t *testing.T) {
 	a := assert.New(t, false)
 	a.True(isValidRFC3339Date("2010-01-02"))
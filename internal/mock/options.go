@@ -3,11 +3,70 @@
 package mock
 
 import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"net/url"
 	"strconv"
 
 	"github.com/caixw/apidoc/v7/internal/ast"
 )
 
+// Options mock 服务的设置项
+type Options struct {
+	Indent   string            // 缩进字符串
+	ImageURL string            // 图片的路由前缀，必须以 / 开头且不能以 / 结尾，为空表示不提供图片。
+	Servers  map[string]string // 指定 APIDoc.Servers 中每一个服务对应的路由前缀
+	Gen      *GenOptions       // 生成随机数据的函数
+
+	// 是否启用有状态模式
+	//
+	// 启用后 POST、PUT、PATCH 和 DELETE 会修改内存中的数据，GET 则优先返回这些数据。
+	Stateful bool
+
+	// 是否优先使用文档中与 Accept 相匹配的示例代码作为返回内容
+	Examples bool
+
+	// 是否验证 api 要求的安全验证方案
+	//
+	// 启用后，未提供相应凭证的请求会返回 401，仅验证凭证是否存在，不验证其值。
+	Security bool
+
+	// 是否发送 api 中定义的回调
+	//
+	// 启用后，包含 CallbackHeader 报头的请求在处理完成之后，
+	// 会根据 api 中 callback 的定义向该报头指定的地址发送请求。
+	Callback bool
+
+	// 延迟与故障注入的设置
+	//
+	// 可以为空，每个请求都可以通过 FaultHeader 报头覆盖该设置。
+	Fault *Fault
+
+	// 录制内容的保存目录
+	//
+	// 不为空时，如果存在与请求相匹配的录制内容，会优先返回该内容，而不是随机生成的数据。
+	Fixtures string
+
+	// 被代理的服务地址
+	//
+	// 不为空时，会以录制模式运行：所有的请求都会被转发至 Upstream，
+	// 符合文档定义的请求与返回内容会被录制到 Fixtures 指定的目录。
+	Upstream *url.URL
+
+	// 根据种子生成 GenOptions
+	//
+	// 不为空时，会根据请求的 method、path 和 query 计算出种子，
+	// 再由该函数生成返回内容所使用的 GenOptions，以保证相同的请求始终返回相同的内容。
+	// 故障注入和回调等与返回内容无关的功能依然采用 Gen。
+	SeedGen func(seed int64) *GenOptions
+
+	// 管理接口的路由前缀
+	//
+	// 必须以 / 开头且不能以 / 结尾，为空表示不启用管理接口，录制模式下该值无效。
+	Admin string
+}
+
 // GenOptions 生成随机数据的函数
 type GenOptions struct {
 	// 返回一个随机的数值
@@ -30,12 +89,36 @@ type GenOptions struct {
 	//
 	// 该数值被用于从数组中获取其中的某个元素。
 	Index func(max int) int
+
+	// 指定 faker 的实现
+	//
+	// 键名为 faker 的名称，比如 ast.FakerName，值为对应的生成函数，
+	// 可用于替换默认的实现，未指定的 faker 依然采用默认实现。
+	Fakers map[string]func(p *ast.Param) string
+
+	// 是否根据参数名称推断 faker
+	//
+	// 为 false 时，仅采用参数中 faker 属性指定的值，其它字符串依然由 String 生成。
+	GuessFaker bool
 }
 
+// nullable 的参数生成 null 的概率为 1/nullRate
+const nullRate = 5
+
 func isEnum(p *ast.Param) bool {
 	return len(p.Enums) > 0
 }
 
+// v 是否为 p 的枚举值之一
+func hasEnum(p *ast.Param, v string) bool {
+	for _, enum := range p.Enums {
+		if enum.Value.V() == v {
+			return true
+		}
+	}
+	return false
+}
+
 func (g *GenOptions) generateBool() bool {
 	return g.Bool()
 }
@@ -49,16 +132,255 @@ func (g *GenOptions) generateNumber(p *ast.Param) any {
 		}
 		return v
 	}
-	return g.Number(p)
+
+	v := g.Number(p)
+	if p.Min == nil && p.Max == nil {
+		return v
+	}
+
+	num, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+	if err != nil { // 由用户提供的 GenOptions.Number 返回了非数值
+		panic(err)
+	}
+
+	if isIntRange(p) {
+		return int(g.numberInRange(p, num, true))
+	}
+	return g.numberInRange(p, num, false)
+}
+
+// 在 p 指定的范围内是否只生成整数
+func isIntRange(p *ast.Param) bool {
+	switch p.Type.V() {
+	case ast.TypeInt:
+		return true
+	case ast.TypeFloat:
+		return false
+	default:
+		return !p.Min.IsFloat() && !p.Max.IsFloat()
+	}
+}
+
+// 将 num 调整到 p 指定的范围之内
+func (g *GenOptions) numberInRange(p *ast.Param, num float64, isInt bool) float64 {
+	var min, max float64
+	if p.Min != nil {
+		min = numberValue(p.Min)
+		if isInt {
+			c := math.Ceil(min)
+			if c == min && p.ExclusiveMin.V() {
+				c++
+			}
+			min = c
+		}
+	}
+	if p.Max != nil {
+		max = numberValue(p.Max)
+		if isInt {
+			f := math.Floor(max)
+			if f == max && p.ExclusiveMax.V() {
+				f--
+			}
+			max = f
+		}
+	}
+
+	switch {
+	case p.Min != nil && p.Max != nil:
+		if max <= min { // 加载文档时已经排除了空的范围，此处仅作保护。
+			return min
+		}
+
+		if isInt {
+			if span := max - min; span < math.MaxInt32 {
+				return min + float64(g.index(int(span)+1))
+			}
+			// 范围过大时按比例取值，防止 int 溢出。
+			return math.Min(max, min+math.Floor((max-min)*float64(g.index(1001))/1000))
+		}
+		// 取 (0, 1) 之间的比例值，保证不会等于边界值。
+		return min + (max-min)*float64(g.index(999)+1)/1000
+	case p.Min != nil:
+		if num < min || (!isInt && p.ExclusiveMin.V() && num == min) {
+			return min + math.Abs(num) + 1
+		}
+	case p.Max != nil:
+		if num > max || (!isInt && p.ExclusiveMax.V() && num == max) {
+			return max - math.Abs(num) - 1
+		}
+	}
+	return num
+}
+
+func numberValue(num *ast.NumberAttribute) float64 {
+	if num.IsFloat() {
+		return num.FloatValue()
+	}
+	return float64(num.IntValue())
 }
 
 func (g *GenOptions) generateString(p *ast.Param) string {
 	if isEnum(p) {
 		return p.Enums[g.Index(len(p.Enums))].Value.V()
 	}
-	return g.String(p)
+
+	var s string
+	if re, err := p.Regexp(); err != nil { // 加载的时候已经作语法验证，此处还出错则直接 panic
+		panic(err)
+	} else if re != nil {
+		s = g.generatePattern(re)
+	} else if f := fakerName(p, g.GuessFaker); f != "" && f != ast.FakerNone {
+		s = g.fake(f, p)
+	} else {
+		s = g.String(p)
+	}
+
+	if p.MinLength == nil && p.MaxLength == nil {
+		return s
+	}
+
+	runes := []rune(s)
+	if p.MinLength != nil {
+		for len(runes) < p.MinLength.IntValue() {
+			if more := []rune(g.String(p)); len(more) > 0 {
+				runes = append(runes, more...)
+			} else {
+				runes = append(runes, 'x')
+			}
+		}
+	}
+	if p.MaxLength != nil && len(runes) > p.MaxLength.IntValue() {
+		runes = runes[:p.MaxLength.IntValue()]
+	}
+	return string(runes)
+}
+
+// 生成数组的长度
+func (g *GenOptions) generateSliceSize(p *ast.Param) int {
+	size := g.SliceSize()
+
+	switch {
+	case p.MinItems != nil && p.MaxItems != nil:
+		min, max := p.MinItems.IntValue(), p.MaxItems.IntValue()
+		if size < min || size > max {
+			size = min + g.index(max-min+1)
+		}
+	case p.MinItems != nil:
+		if min := p.MinItems.IntValue(); size < min {
+			size = min
+		}
+	case p.MaxItems != nil:
+		if max := p.MaxItems.IntValue(); size > max {
+			size = max
+		}
+	}
+
+	return size
+}
+
+// 是否为 nullable 的参数生成 null 值
+//
+// 仅偶尔返回 true，以保证大部分时候依然能生成有效的值。
+func (g *GenOptions) generateNull(p *ast.Param) bool {
+	return p.Nullable.V() && g.index(nullRate) == 0
+}
+
+// 返回 items 中可以出现在当前方向上的元素
+//
+// request 为 true 表示请求内容，去掉只读的元素，否则去掉只写的元素。
+func filterItems(items []*ast.Param, request bool) []*ast.Param {
+	ret := make([]*ast.Param, 0, len(items))
+	for _, item := range items {
+		if (request && !item.ReadOnly.V()) || (!request && !item.WriteOnly.V()) {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
+
+// 生成文件的内容，返回 base64 编码之后的字符串
+//
+// 文件内容由 String 生成，并根据 min-size 和 max-size 调整其长度。
+func (g *GenOptions) generateFile(p *ast.Param) string {
+	data := []byte(g.String(p))
+	if p.MaxSize != nil && len(data) > p.MaxSize.IntValue() {
+		data = data[:p.MaxSize.IntValue()]
+	}
+	if p.MinSize != nil && len(data) < p.MinSize.IntValue() {
+		data = append(data, make([]byte, p.MinSize.IntValue()-len(data))...)
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// 生成字典的键名
+//
+// 键名为 key 加上从 1 开始的序号，比如 key1、key2，数量由 SliceSize 决定。
+func (g *GenOptions) generateMapKeys() []string {
+	keys := make([]string, g.SliceSize())
+	for i := range keys {
+		keys[i] = "key" + strconv.Itoa(i+1)
+	}
+	return keys
+}
+
+// 生成基本类型的数组元素
+//
+// 如果 p.UniqueItems 为 true，会去除重复的值，可选值不足时，返回的数量可能少于 size。
+func (g *GenOptions) generateItems(p *ast.Param, size int) []any {
+	items := make([]any, 0, size)
+	exists := make(map[string]struct{}, size)
+
+	for i := 0; len(items) < size && i < size*10; i++ {
+		v := g.generateValue(p)
+		if p.UniqueItems.V() {
+			key := fmt.Sprint(v)
+			if _, found := exists[key]; found {
+				continue
+			}
+			exists[key] = struct{}{}
+		}
+		items = append(items, v)
+	}
+
+	return items
+}
+
+// 生成基本类型的值
+func (g *GenOptions) generateValue(p *ast.Param) any {
+	switch primitive, _ := ast.ParseType(p.Type.V()); primitive {
+	case ast.TypeBool:
+		return g.generateBool()
+	case ast.TypeNumber:
+		return g.generateNumber(p)
+	case ast.TypeString:
+		return g.generateString(p)
+	case ast.TypeFile:
+		return g.generateFile(p)
+	default:
+		return nil
+	}
+}
+
+// 生成报头、cookie 等简单类型的值
+//
+// 如果 p 不是可以转换成字符串的简单类型，则返回 false。
+func (g *GenOptions) generateSimple(p *ast.Param) (string, bool) {
+	switch primitive, _ := ast.ParseType(p.Type.V()); primitive {
+	case ast.TypeBool:
+		return strconv.FormatBool(g.generateBool()), true
+	case ast.TypeNumber:
+		return fmt.Sprint(g.generateNumber(p)), true
+	case ast.TypeString:
+		return g.generateString(p), true
+	default:
+		return "", false
+	}
 }
 
-func (g *GenOptions) generateSliceSize() int {
-	return g.SliceSize()
+// 返回 [0, max) 之间的值，与 Index 不同，max 可以小于等于 1。
+func (g *GenOptions) index(max int) int {
+	if max <= 1 {
+		return 0
+	}
+	return g.Index(max)
 }
@@ -2,7 +2,16 @@
 
 package mock
 
-import "github.com/caixw/apidoc/v7/internal/ast"
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/issue9/assert/v3"
+
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
 
 const indent = "    "
 
@@ -27,3 +36,150 @@ var testOptions = &GenOptions{
 	SliceSize: func() int { return 5 },
 	Index:     func(max int) int { return 0 },
 }
+
+func TestGenOptions_generateNumber(t *testing.T) {
+	a := assert.New(t, false)
+
+	p := &ast.Param{Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeNumber}}}
+	a.Equal(testOptions.generateNumber(p), 1024)
+
+	p.Max = &ast.NumberAttribute{Value: ast.Number{Int: 100}}
+	v := testOptions.generateNumber(p)
+	a.Nil(p.CheckValue(fmt.Sprint(v)), "%v 不在范围内", v)
+
+	p.Min = &ast.NumberAttribute{Value: ast.Number{Int: 10}}
+	p.ExclusiveMin = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+	a.Equal(testOptions.generateNumber(p), 11)
+
+	p = &ast.Param{
+		Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeFloat}},
+		Min:  &ast.NumberAttribute{Value: ast.Number{Float: 1.5, IsFloat: true}},
+		Max:  &ast.NumberAttribute{Value: ast.Number{Float: 2.5, IsFloat: true}},
+	}
+	v = testOptions.generateNumber(p)
+	a.Nil(p.CheckValue(fmt.Sprint(v)), "%v 不在范围内", v)
+
+	// 范围超出 int 的表示范围
+	g := &GenOptions{Number: testOptions.Number, Index: func(max int) int { return max - 1 }}
+	p = &ast.Param{
+		Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeInt}},
+		Min:  &ast.NumberAttribute{Value: ast.Number{Int: math.MinInt / 2}},
+		Max:  &ast.NumberAttribute{Value: ast.Number{Int: math.MaxInt / 2}},
+	}
+	v = g.generateNumber(p)
+	a.Nil(p.CheckValue(fmt.Sprint(v)), "%v 不在范围内", v)
+
+	// 空的范围
+	p = &ast.Param{
+		Type:         &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeInt}},
+		Min:          &ast.NumberAttribute{Value: ast.Number{Int: 1}},
+		Max:          &ast.NumberAttribute{Value: ast.Number{Int: 2}},
+		ExclusiveMin: &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+		ExclusiveMax: &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+	}
+	a.NotPanic(func() { g.generateNumber(p) })
+}
+
+func TestGenOptions_generateString(t *testing.T) {
+	a := assert.New(t, false)
+
+	p := &ast.Param{
+		Type:      &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+		MinLength: &ast.NumberAttribute{Value: ast.Number{Int: 6}},
+	}
+	a.Equal(testOptions.generateString(p), "10241024")
+
+	p.MaxLength = &ast.NumberAttribute{Value: ast.Number{Int: 6}}
+	a.Equal(testOptions.generateString(p), "102410")
+
+	p = &ast.Param{
+		Type:    &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+		Pattern: &ast.Attribute{Value: xmlenc.String{Value: "^[a-c]{3}-\\d+$"}},
+	}
+	a.Equal(testOptions.generateString(p), "aaa-0")
+}
+
+func TestGenOptions_generateSliceSize(t *testing.T) {
+	a := assert.New(t, false)
+
+	p := &ast.Param{}
+	a.Equal(testOptions.generateSliceSize(p), 5)
+
+	p.MinItems = &ast.NumberAttribute{Value: ast.Number{Int: 8}}
+	a.Equal(testOptions.generateSliceSize(p), 8)
+
+	p.MaxItems = &ast.NumberAttribute{Value: ast.Number{Int: 10}}
+	a.Equal(testOptions.generateSliceSize(p), 8)
+
+	p.MinItems = nil
+	p.MaxItems.Value.Int = 2
+	a.Equal(testOptions.generateSliceSize(p), 2)
+}
+
+func TestGenOptions_generateItems(t *testing.T) {
+	a := assert.New(t, false)
+
+	p := &ast.Param{Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeNumber}}}
+	a.Equal(testOptions.generateItems(p, 3), []any{1024, 1024, 1024})
+
+	// testOptions 始终返回相同的值，去重之后仅剩一个元素
+	p.UniqueItems = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+	a.Equal(testOptions.generateItems(p, 3), []any{1024})
+}
+
+func TestGenOptions_generateNull(t *testing.T) {
+	a := assert.New(t, false)
+
+	p := &ast.Param{Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}}}
+	a.False(testOptions.generateNull(p))
+
+	p.Nullable = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+	a.True(testOptions.generateNull(p))
+
+	g := &GenOptions{Index: func(max int) int { return max - 1 }}
+	a.False(g.generateNull(p))
+}
+
+func TestFilterItems(t *testing.T) {
+	a := assert.New(t, false)
+
+	items := []*ast.Param{
+		{Name: &ast.Attribute{Value: xmlenc.String{Value: "id"}}, ReadOnly: &ast.BoolAttribute{Value: ast.Bool{Value: true}}},
+		{Name: &ast.Attribute{Value: xmlenc.String{Value: "password"}}, WriteOnly: &ast.BoolAttribute{Value: ast.Bool{Value: true}}},
+	}
+	ret := filterItems(items, false)
+	a.Equal(1, len(ret)).Equal(ret[0].Name.V(), "id")
+
+	ret = filterItems(items, true)
+	a.Equal(1, len(ret)).Equal(ret[0].Name.V(), "password")
+}
+
+func TestGenOptions_generateFile(t *testing.T) {
+	a := assert.New(t, false)
+
+	p := &ast.Param{Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeFile}}}
+	a.Equal(testOptions.generateFile(p), "MTAyNA==") // 1024
+
+	p.MaxSize = &ast.NumberAttribute{Value: ast.Number{Int: 2}}
+	a.Equal(testOptions.generateFile(p), "MTA=") // 10
+
+	p.MaxSize = nil
+	p.MinSize = &ast.NumberAttribute{Value: ast.Number{Int: 5}}
+	a.Equal(testOptions.generateFile(p), "MTAyNAA=") // 1024\0
+}
+
+func TestGenOptions_generateSimple(t *testing.T) {
+	a := assert.New(t, false)
+
+	val, ok := testOptions.generateSimple(&ast.Param{Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeBool}}})
+	a.True(ok).Equal(val, "true")
+
+	val, ok = testOptions.generateSimple(&ast.Param{Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeInt}}})
+	a.True(ok).Equal(val, "1024")
+
+	val, ok = testOptions.generateSimple(&ast.Param{Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}}})
+	a.True(ok).Equal(val, "1024")
+
+	val, ok = testOptions.generateSimple(&ast.Param{Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeObject}}})
+	a.False(ok).Empty(val)
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// 正则表达式中 *、+ 以及未指定上限的重复次数，在生成数据时的最大重复次数。
+const maxPatternRepeat = 5
+
+// 可打印的 ASCII 字符范围，在字符集允许的情况下，优先从此范围内生成字符。
+const (
+	minPrintable = ' '
+	maxPrintable = '~'
+)
+
+// 生成一个匹配正则表达式 re 的字符串
+func (g *GenOptions) generatePattern(re *regexp.Regexp) string {
+	r, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil { // re 是已经编译成功的表达式，不可能出错。
+		panic(err)
+	}
+
+	builder := &strings.Builder{}
+	g.writePattern(builder, r)
+	return builder.String()
+}
+
+func (g *GenOptions) writePattern(builder *strings.Builder, r *syntax.Regexp) {
+	switch r.Op {
+	case syntax.OpLiteral:
+		builder.WriteString(string(r.Rune))
+	case syntax.OpCharClass:
+		if len(r.Rune) > 0 {
+			builder.WriteRune(g.charClassRune(r.Rune))
+		}
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		builder.WriteRune(rune(minPrintable + g.index(maxPrintable-minPrintable+1)))
+	case syntax.OpCapture:
+		g.writePattern(builder, r.Sub[0])
+	case syntax.OpStar:
+		g.repeatPattern(builder, r.Sub[0], 0, maxPatternRepeat)
+	case syntax.OpPlus:
+		g.repeatPattern(builder, r.Sub[0], 1, maxPatternRepeat)
+	case syntax.OpQuest:
+		g.repeatPattern(builder, r.Sub[0], 0, 1)
+	case syntax.OpRepeat:
+		max := r.Max
+		if max < 0 {
+			max = r.Min + maxPatternRepeat
+		}
+		g.repeatPattern(builder, r.Sub[0], r.Min, max)
+	case syntax.OpConcat:
+		for _, sub := range r.Sub {
+			g.writePattern(builder, sub)
+		}
+	case syntax.OpAlternate:
+		g.writePattern(builder, r.Sub[g.index(len(r.Sub))])
+	default: // ^、$、\b 以及空匹配等不占用字符的内容
+	}
+}
+
+func (g *GenOptions) repeatPattern(builder *strings.Builder, r *syntax.Regexp, min, max int) {
+	cnt := min + g.index(max-min+1)
+	for i := 0; i < cnt; i++ {
+		g.writePattern(builder, r)
+	}
+}
+
+// 从字符集 ranges 中选取一个字符
+//
+// ranges 为成对出现的字符范围，如果与可打印的 ASCII 字符有交集，则仅从交集中选取。
+func (g *GenOptions) charClassRune(ranges []rune) rune {
+	printable := make([]rune, 0, len(ranges))
+	for i := 0; i < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if lo < minPrintable {
+			lo = minPrintable
+		}
+		if hi > maxPrintable {
+			hi = maxPrintable
+		}
+		if lo <= hi {
+			printable = append(printable, lo, hi)
+		}
+	}
+	if len(printable) > 0 {
+		ranges = printable
+	}
+
+	i := g.index(len(ranges)/2) * 2
+	lo, hi := ranges[i], ranges[i+1]
+	return lo + rune(g.index(int(hi-lo)+1))
+}
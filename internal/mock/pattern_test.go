@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"math/rand"
+	"regexp"
+	"testing"
+
+	"github.com/issue9/assert/v3"
+)
+
+func TestGenOptions_generatePattern(t *testing.T) {
+	a := assert.New(t, false)
+
+	g := &GenOptions{Index: func(max int) int { return rand.Intn(max) }}
+
+	patterns := []string{
+		"abc",
+		"^[a-z]+$",
+		"^\\d{3,5}-\\w*$",
+		"(abc|def)?x.y",
+		"[^a-z]{2,}",
+		"^[\\p{Han}]{2}$",
+		"^\\S+@\\S+\\.com$",
+	}
+
+	for _, pattern := range patterns {
+		re := regexp.MustCompile(pattern)
+		for i := 0; i < 20; i++ {
+			s := g.generatePattern(re)
+			a.True(re.MatchString(s), "%s 生成的 %s 不匹配", pattern, s)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/locale"
+)
+
+const (
+	preferHeader        = "Prefer"
+	preferAppliedHeader = "Preference-Applied"
+)
+
+// 表示 Prefer 报头中与 mock 相关的内容
+//
+// 格式为 Prefer: code=404, example=name，
+// 其中 code 用于指定返回的状态码，example 用于指定示例代码的 summary。
+//
+// https://tools.ietf.org/html/rfc7240
+type prefer struct {
+	code    int
+	example string
+}
+
+func parsePrefer(r *http.Request) (*prefer, error) {
+	p := &prefer{}
+
+	for _, header := range r.Header.Values(preferHeader) {
+		for _, item := range strings.FieldsFunc(header, func(r rune) bool { return r == ',' || r == ';' }) {
+			index := strings.IndexByte(item, '=')
+			if index < 0 { // 其它无值的首选项，比如 respond-async
+				continue
+			}
+
+			key := strings.ToLower(strings.TrimSpace(item[:index]))
+			val := strings.Trim(strings.TrimSpace(item[index+1:]), `"`)
+			switch key {
+			case "code":
+				code, err := strconv.Atoi(val)
+				if err != nil || code < 100 || code > 599 {
+					return nil, core.NewError(locale.ErrInvalidValue).WithField("code")
+				}
+				p.code = code
+			case "example":
+				p.example = val
+			}
+		}
+	}
+
+	return p, nil
+}
+
+// 是否通过 Prefer 指定了返回的状态码或是示例代码
+//
+// 其它的首选项，比如 return=minimal 和 respond-async 并不影响返回内容。
+// 格式错误时同样返回 true，由 renderResponse 报告错误。
+func preferred(r *http.Request) bool {
+	p, err := parsePrefer(r)
+	return err != nil || p.code > 0 || p.example != ""
+}
+
+// 获取所有状态码为 code 的返回对象
+func filterResponses(responses []*ast.Request, code int) []*ast.Request {
+	ret := make([]*ast.Request, 0, len(responses))
+	for _, resp := range responses {
+		if resp.Status.V() == code {
+			ret = append(ret, resp)
+		}
+	}
+	return ret
+}
+
+func (p *prefer) applied() string {
+	items := make([]string, 0, 2)
+	if p.code > 0 {
+		items = append(items, "code="+strconv.Itoa(p.code))
+	}
+	if p.example != "" {
+		items = append(items, "example="+p.example)
+	}
+	return strings.Join(items, ", ")
+}
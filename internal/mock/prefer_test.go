@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/issue9/assert/v3"
+	"github.com/issue9/assert/v3/rest"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/core/messagetest"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+func TestParsePrefer(t *testing.T) {
+	a := assert.New(t, false)
+
+	r := httptest.NewRequest(http.MethodGet, "/path", nil)
+	p, err := parsePrefer(r)
+	a.NotError(err).Equal(p, &prefer{}).Empty(p.applied())
+
+	r = httptest.NewRequest(http.MethodGet, "/path", nil)
+	r.Header.Set("Prefer", "code=404, example=\"not found\"")
+	p, err = parsePrefer(r)
+	a.NotError(err).
+		Equal(p, &prefer{code: 404, example: "not found"}).
+		Equal(p.applied(), "code=404, example=not found")
+
+	r = httptest.NewRequest(http.MethodGet, "/path", nil)
+	r.Header.Add("Prefer", "respond-async; wait=10")
+	r.Header.Add("Prefer", "CODE=201")
+	p, err = parsePrefer(r)
+	a.NotError(err).Equal(p, &prefer{code: 201})
+
+	r = httptest.NewRequest(http.MethodGet, "/path", nil)
+	r.Header.Set("Prefer", "code=abc")
+	p, err = parsePrefer(r)
+	a.Error(err).Nil(p)
+
+	r = httptest.NewRequest(http.MethodGet, "/path", nil)
+	r.Header.Set("Prefer", "code=600")
+	p, err = parsePrefer(r)
+	a.Error(err).Nil(p)
+}
+
+func TestPreferred(t *testing.T) {
+	a := assert.New(t, false)
+
+	data := []*struct {
+		header    string
+		preferred bool
+	}{
+		{},
+		{header: "return=minimal"},
+		{header: "respond-async, wait=10"},
+		{header: "code=404", preferred: true},
+		{header: "return=minimal, example=m1", preferred: true},
+		{header: "code=xx", preferred: true},
+	}
+
+	for _, item := range data {
+		r := httptest.NewRequest(http.MethodGet, "/users", nil)
+		if item.header != "" {
+			r.Header.Set(preferHeader, item.header)
+		}
+		a.Equal(preferred(r), item.preferred, "%s", item.header)
+	}
+}
+
+func TestMock_prefer(t *testing.T) {
+	a := assert.New(t, false)
+
+	const doc = `<apidoc version="1.1.1">
+	<title>test</title>
+	<mimetype>application/json</mimetype>
+	<response status="500" type="string" />
+	<api method="GET" summary="get">
+		<path path="/users" />
+		<response status="200" type="string" />
+		<response status="404" type="object">
+			<param name="message" type="string" summary="message" />
+			<example mimetype="application/json" summary="m1"><![CDATA[{"message":"m1"}]]></example>
+			<example mimetype="application/json" summary="m2"><![CDATA[{"message":"m2"}]]></example>
+		</response>
+	</api>
+</apidoc>`
+	rslt := messagetest.NewMessageHandler()
+	d := &ast.APIDoc{APIDoc: &ast.APIDocVersionAttribute{Value: xmlenc.String{Value: ast.Version}}}
+	d.Parse(rslt.Handler, core.Block{Data: []byte(doc)})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	rslt = messagetest.NewMessageHandler()
+	defer rslt.Handler.Stop()
+	mock, err := New(rslt.Handler, d, &Options{Indent: indent, ImageURL: "/images", Gen: testOptions})
+	a.NotError(err).NotNil(mock)
+	srv := rest.NewServer(a, mock, nil)
+
+	srv.Get("/users").Header("accept", "application/json").Do(nil).
+		Status(http.StatusOK)
+
+	srv.Get("/users").
+		Header("accept", "application/json").
+		Header("Prefer", "code=404").
+		Do(nil).
+		Status(http.StatusNotFound).
+		Header(preferAppliedHeader, "code=404")
+
+	// 未启用 examples，但是通过 Prefer 指定了示例代码
+	srv.Get("/users").
+		Header("accept", "application/json").
+		Header("Prefer", "code=404, example=m2").
+		Do(nil).
+		Status(http.StatusNotFound).
+		StringBody(`{"message":"m2"}`)
+
+	// doc.Responses 中的状态码
+	srv.Get("/users").
+		Header("accept", "application/json").
+		Header("Prefer", "code=500").
+		Do(nil).
+		Status(http.StatusInternalServerError)
+
+	// 未定义的状态码
+	srv.Get("/users").
+		Header("accept", "application/json").
+		Header("Prefer", "code=422").
+		Do(nil).
+		Status(http.StatusBadRequest)
+
+	// 无效的状态码
+	srv.Get("/users").
+		Header("accept", "application/json").
+		Header("Prefer", "code=xx").
+		Do(nil).
+		Status(http.StatusBadRequest)
+}
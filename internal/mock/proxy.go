@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+
+	"github.com/issue9/mux/v7"
+	"github.com/issue9/mux/v7/examples/std"
+	"github.com/issue9/mux/v7/types"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/locale"
+)
+
+// 契约测试的代理服务
+type proxy struct {
+	msgHandler *core.MessageHandler
+	doc        *ast.APIDoc
+	upstream   *url.URL
+	router     *std.Router
+	fixtures   *fixtures // 录制内容的保存位置，为空表示不录制。
+}
+
+// NewProxy 声明契约测试的代理服务
+//
+// 所有的请求都会被转发至 upstream，同时根据 d 中的定义验证请求以及 upstream 返回的内容，
+// 不符合文档定义的内容会以错误的形式输出至 msg，但不会影响返回给客户端的内容。
+//
+// servers 用于指定 d.Servers 中每一个服务对应的路由前缀；
+func NewProxy(msg *core.MessageHandler, d *ast.APIDoc, upstream *url.URL, servers map[string]string) (http.Handler, error) {
+	return newProxy(msg, d, upstream, servers, nil)
+}
+
+// 声明代理服务
+//
+// fixtures 不为空时，会将符合文档定义的请求与返回内容录制到 fixtures 中。
+func newProxy(msg *core.MessageHandler, d *ast.APIDoc, upstream *url.URL, servers map[string]string, fixtures *fixtures) (http.Handler, error) {
+	if err := checkVersion(d); err != nil {
+		return nil, err
+	}
+
+	p := &proxy{
+		msgHandler: msg,
+		doc:        d,
+		upstream:   upstream,
+		fixtures:   fixtures,
+	}
+
+	// 未在文档中定义的请求，同样需要转发。
+	undocumented := p.undocumented()
+	p.router = mux.NewRouterOf[http.Handler]("apidoc proxy server",
+		func(w http.ResponseWriter, r *http.Request, ps types.Route, h http.Handler) {
+			h.ServeHTTP(w, std.WithValue(r, ps))
+		},
+		undocumented,
+		func(types.Node) http.Handler { return undocumented },
+		func(types.Node) http.Handler { return undocumented },
+	)
+
+	handleAPIs(msg, p.router, d, servers, p.buildAPI)
+
+	return p, nil
+}
+
+// LoadProxy 从本地或是远程加载文档内容并声明契约测试的代理服务
+func LoadProxy(h *core.MessageHandler, path core.URI, upstream *url.URL, servers map[string]string) (http.Handler, error) {
+	d, err := loadDoc(h, path)
+	if err != nil {
+		return nil, err
+	}
+	return NewProxy(h, d, upstream, servers)
+}
+
+func (p *proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.router.ServeHTTP(w, r)
+}
+
+func (p *proxy) newReverseProxy(modify func(*http.Response) error) *httputil.ReverseProxy {
+	rp := httputil.NewSingleHostReverseProxy(p.upstream)
+
+	// 不转发客户端的 Accept-Encoding，由 http.Transport 自行协商并解压，
+	// 否则验证和录制时读取到的是压缩后的内容。
+	director := rp.Director
+	rp.Director = func(r *http.Request) {
+		director(r)
+		r.Header.Del("Accept-Encoding")
+	}
+
+	rp.ModifyResponse = modify
+	rp.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		p.msgHandler.Error(requestError(r, "", err))
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	return rp
+}
+
+func (p *proxy) undocumented() http.Handler {
+	rp := p.newReverseProxy(nil)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.msgHandler.Locale(core.Warn, locale.UndocumentedAPI, r.Method, r.URL.Path)
+		rp.ServeHTTP(w, r)
+	})
+}
+
+func (p *proxy) buildAPI(api *ast.API) http.Handler {
+	rp := p.newReverseProxy(func(resp *http.Response) error {
+		if field, err := p.validResponse(api, resp); err != nil {
+			p.msgHandler.Error(requestError(resp.Request, field, err))
+			return nil
+		}
+
+		if p.fixtures != nil {
+			path, err := p.fixtures.record(resp)
+			if err != nil {
+				p.msgHandler.Error(requestError(resp.Request, "", err))
+			} else if path != "" {
+				p.msgHandler.Locale(core.Info, locale.MockFixtureRecorded, resp.Request.Method, resp.Request.URL.Path, path)
+			}
+		}
+		return nil
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.msgHandler.Locale(core.Succ, locale.RequestAPI, r.Method, r.URL.Path)
+		if api.Deprecated != nil {
+			p.msgHandler.Locale(core.Warn, locale.DeprecatedWarn, r.Method, r.URL.Path, api.Deprecated.V())
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			p.msgHandler.Error(requestError(r, "request.body.", err))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// 验证会读取 r.Body 的内容，所以在验证之后需要重新赋值。
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if field, err := validAPIRequest(p.doc.XMLNamespaces, api, r); err != nil {
+			p.msgHandler.Error(requestError(r, field, err))
+		} else if p.fixtures != nil { // 仅录制符合文档定义的请求
+			r = withFixture(r, body)
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		rp.ServeHTTP(w, r)
+	})
+}
+
+// 验证 upstream 返回的内容是否符合 api 的定义
+//
+// 返回出错的字段前缀以及错误信息。
+func (p *proxy) validResponse(api *ast.API, resp *http.Response) (string, error) {
+	responses := filterResponses(api.Responses, resp.StatusCode)
+	if len(responses) == 0 { // 仅在 api.Responses 无法匹配任何内容的时候，才从 doc.Responses 中查找内容
+		responses = filterResponses(p.doc.Responses, resp.StatusCode)
+	}
+	return validHTTPResponse(p.doc.XMLNamespaces, responses, resp)
+}
+
+// 验证 resp 是否符合 responses 中的定义
+//
+// responses 应该是已经按状态码过滤之后的内容，
+// 返回出错的字段前缀以及错误信息。
+func validHTTPResponse(ns []*ast.XMLNamespace, responses []*ast.Request, resp *http.Response) (string, error) {
+	if len(responses) == 0 {
+		return "response.status", core.NewError(locale.ErrNotFound)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "response.body.", err
+	}
+	if err = resp.Body.Close(); err != nil {
+		return "response.body.", err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var req *ast.Request
+	header := resp.Header.Get("Content-Type")
+	if header != "" {
+		ct, _, err := mime.ParseMediaType(header)
+		if err != nil {
+			return "response.headers[content-type]", err
+		}
+		req = findRequestByContentType(responses, ct)
+	} else if len(body) == 0 { // 没有内容也没有 content-type，比如 204。
+		req = responses[0]
+	}
+	if req == nil {
+		return "response.headers[content-type]", core.NewError(locale.ErrInvalidValue)
+	}
+
+	for _, header := range req.Headers {
+		field := "response.headers[" + header.Name.V() + "]"
+		if err := validSimpleParam(header, field, resp.Header.Get(header.Name.V())); err != nil {
+			return field, err
+		}
+	}
+
+	for _, cookie := range req.Cookies {
+		field := "response.cookies[" + cookie.Name.V() + "]"
+		if err := validSimpleParam(cookie, field, findCookie(resp.Cookies(), cookie.Name.V())); err != nil {
+			return field, err
+		}
+	}
+
+	if len(body) == 0 {
+		if req.Type.V() == ast.TypeNone {
+			return "", nil
+		}
+		return "response.body.", core.NewError(locale.ErrBodyIsEmpty)
+	}
+
+	if err := validContent(ns, header, req, body, false); err != nil {
+		return "response.body.", err
+	}
+	return "", nil
+}
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert/v3"
+	"github.com/issue9/assert/v3/rest"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/core/messagetest"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+const proxyDoc = `<apidoc version="1.1.1">
+	<title>test</title>
+	<mimetype>application/json</mimetype>
+	<api method="GET" summary="get">
+		<path path="/users/{id}">
+			<param name="id" type="number" summary="id" />
+			<query name="fields" type="string" summary="fields" optional="true">
+				<enum value="id" summary="id" />
+				<enum value="name" summary="name" />
+			</query>
+		</path>
+		<response status="200" type="object">
+			<param name="id" type="number" summary="id" />
+			<param name="name" type="string" summary="name" />
+		</response>
+	</api>
+	<api method="DELETE" summary="delete">
+		<path path="/users/{id}"><param name="id" type="number" summary="id" /></path>
+		<response status="204" />
+	</api>
+</apidoc>`
+
+func newTestProxy(a *assert.Assertion, upstream http.HandlerFunc) (*rest.Server, *messagetest.Result) {
+	rslt := messagetest.NewMessageHandler()
+	d := &ast.APIDoc{APIDoc: &ast.APIDocVersionAttribute{Value: xmlenc.String{Value: ast.Version}}}
+	d.Parse(rslt.Handler, core.Block{Data: []byte(proxyDoc)})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	srv := httptest.NewServer(upstream)
+	a.TB().Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	a.NotError(err)
+
+	rslt = messagetest.NewMessageHandler()
+	p, err := NewProxy(rslt.Handler, d, u, nil)
+	a.NotError(err).NotNil(p)
+	return rest.NewServer(a, p, nil), rslt
+}
+
+func TestProxy(t *testing.T) {
+	a := assert.New(t, false)
+
+	// 符合文档定义
+	srv, rslt := newTestProxy(a, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write([]byte(`{"id":1,"name":"n1"}`))
+	})
+	srv.Get("/users/1?fields=id").Do(nil).
+		Status(http.StatusOK).
+		StringBody(`{"id":1,"name":"n1"}`)
+	srv.Delete("/users/1").Do(nil).Status(http.StatusNoContent)
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors).Empty(rslt.Warns)
+
+	// 请求和返回的内容都不符合文档定义，但依然返回 upstream 的内容。
+	srv, rslt = newTestProxy(a, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"1","name":"n1"}`))
+	})
+	srv.Get("/users/1?fields=not-exists").Do(nil).
+		Status(http.StatusOK).
+		StringBody(`{"id":"1","name":"n1"}`)
+	rslt.Handler.Stop()
+	a.Equal(2, len(rslt.Errors))
+
+	// 未定义的状态码
+	srv, rslt = newTestProxy(a, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	srv.Get("/users/1").Do(nil).Status(http.StatusNotFound)
+	rslt.Handler.Stop()
+	a.Equal(1, len(rslt.Errors))
+	err, ok := rslt.Errors[0].(*core.Error)
+	a.True(ok).Equal(err.Field, "response.status")
+
+	// 未在文档中定义的 API
+	srv, rslt = newTestProxy(a, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	srv.Get("/not-exists").Do(nil).Status(http.StatusAccepted)
+	srv.Post("/users/1", nil).Do(nil).Status(http.StatusAccepted)
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors).Equal(2, len(rslt.Warns))
+}
+
+func TestProxy_compressed(t *testing.T) {
+	a := assert.New(t, false)
+
+	srv, rslt := newTestProxy(a, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Write([]byte(`{"id":1,"name":"n1"}`))
+			return
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		gw := gzip.NewWriter(w)
+		gw.Write([]byte(`{"id":1,"name":"n1"}`))
+		gw.Close()
+	})
+
+	srv.Get("/users/1").Header("Accept-Encoding", "gzip, deflate, br").Do(nil).
+		Status(http.StatusOK).
+		StringBody(`{"id":1,"name":"n1"}`)
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+}
+
+func TestProxy_badGateway(t *testing.T) {
+	a := assert.New(t, false)
+
+	rslt := messagetest.NewMessageHandler()
+	d := &ast.APIDoc{APIDoc: &ast.APIDocVersionAttribute{Value: xmlenc.String{Value: ast.Version}}}
+	d.Parse(rslt.Handler, core.Block{Data: []byte(proxyDoc)})
+	rslt.Handler.Stop()
+
+	rslt = messagetest.NewMessageHandler()
+	p, err := NewProxy(rslt.Handler, d, &url.URL{Scheme: "http", Host: "127.0.0.1:1"}, nil)
+	a.NotError(err).NotNil(p)
+	rest.NewServer(a, p, nil).Get("/users/1").Do(nil).Status(http.StatusBadGateway)
+	rslt.Handler.Stop()
+	a.Equal(1, len(rslt.Errors))
+
+	// 版本号兼容性
+	p, err = NewProxy(rslt.Handler, &ast.APIDoc{APIDoc: &ast.APIDocVersionAttribute{Value: xmlenc.String{Value: "1.0.1"}}}, nil, nil)
+	a.Error(err).Nil(p)
+}
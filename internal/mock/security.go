@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/caixw/apidoc/v7/internal/ast"
+)
+
+// 请求是否提供了 api 要求的凭证
+//
+// api.Securities 中的方案只要满足其中之一即可，
+// 仅检测凭证是否存在，并不验证其值是否正确。
+func hasCredential(d *ast.APIDoc, api *ast.API, r *http.Request) bool {
+	var required bool
+	for _, sec := range api.Securities {
+		s := d.Security(sec.Name.V())
+		if s == nil { // 文档中未定义的方案，忽略。
+			continue
+		}
+
+		required = true
+		if matchSecurity(s, r) {
+			return true
+		}
+	}
+
+	return !required
+}
+
+func matchSecurity(s *ast.Security, r *http.Request) bool {
+	switch s.Type.V() {
+	case ast.SecurityTypeAPIKey:
+		key := s.Key.V()
+		switch s.In.V() {
+		case ast.SecurityInHeader:
+			return r.Header.Get(key) != ""
+		case ast.SecurityInQuery:
+			return r.URL.Query().Get(key) != ""
+		case ast.SecurityInCookie:
+			c, err := r.Cookie(key)
+			return err == nil && c.Value != ""
+		}
+	case ast.SecurityTypeHTTP:
+		return hasAuthorization(r, s.Scheme.V())
+	case ast.SecurityTypeOAuth2, ast.SecurityTypeOpenIDConnect:
+		return hasAuthorization(r, "bearer")
+	}
+	return false
+}
+
+// 报头 Authorization 是否包含了 scheme 指定的凭证，scheme 不区分大小写。
+func hasAuthorization(r *http.Request, scheme string) bool {
+	auth := r.Header.Get("Authorization")
+	prefix := scheme + " "
+	return len(auth) > len(prefix) && strings.EqualFold(auth[:len(prefix)], prefix)
+}
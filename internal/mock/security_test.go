@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/issue9/assert/v3"
+	"github.com/issue9/assert/v3/rest"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/core/messagetest"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+const securityDoc = `<apidoc version="1.1.1">
+	<title>test</title>
+	<mimetype>application/json</mimetype>
+	<security name="header" type="apikey" in="header" key="X-Token" />
+	<security name="query" type="apikey" in="query" key="token" />
+	<security name="cookie" type="apikey" in="cookie" key="session" />
+	<security name="basic" type="http" scheme="basic" />
+	<security name="oauth" type="oauth2">
+		<flow type="password" token-url="https://example.com/token" />
+	</security>
+	<api method="GET" summary="get">
+		<path path="/users" />
+		<security name="header" />
+		<security name="basic" />
+		<response status="200" type="string" />
+	</api>
+	<api method="GET" summary="get">
+		<path path="/public" />
+		<response status="200" type="string" />
+	</api>
+</apidoc>`
+
+func loadSecurityDoc(a *assert.Assertion) *ast.APIDoc {
+	rslt := messagetest.NewMessageHandler()
+	d := &ast.APIDoc{APIDoc: &ast.APIDocVersionAttribute{Value: xmlenc.String{Value: ast.Version}}}
+	d.Parse(rslt.Handler, core.Block{Data: []byte(securityDoc)})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+	return d
+}
+
+func TestMatchSecurity(t *testing.T) {
+	a := assert.New(t, false)
+	d := loadSecurityDoc(a)
+
+	r := httptest.NewRequest(http.MethodGet, "/users", nil)
+	a.False(matchSecurity(d.Security("header"), r))
+	r.Header.Set("X-Token", "1")
+	a.True(matchSecurity(d.Security("header"), r))
+
+	r = httptest.NewRequest(http.MethodGet, "/users", nil)
+	a.False(matchSecurity(d.Security("query"), r))
+	r = httptest.NewRequest(http.MethodGet, "/users?token=1", nil)
+	a.True(matchSecurity(d.Security("query"), r))
+
+	r = httptest.NewRequest(http.MethodGet, "/users", nil)
+	a.False(matchSecurity(d.Security("cookie"), r))
+	r.AddCookie(&http.Cookie{Name: "session", Value: "1"})
+	a.True(matchSecurity(d.Security("cookie"), r))
+
+	r = httptest.NewRequest(http.MethodGet, "/users", nil)
+	r.Header.Set("Authorization", "Bearer xx")
+	a.False(matchSecurity(d.Security("basic"), r)).
+		True(matchSecurity(d.Security("oauth"), r))
+	r.Header.Set("Authorization", "BASIC xx")
+	a.True(matchSecurity(d.Security("basic"), r)).
+		False(matchSecurity(d.Security("oauth"), r))
+
+	r.Header.Set("Authorization", "basic ")
+	a.False(matchSecurity(d.Security("basic"), r))
+}
+
+func TestMock_security(t *testing.T) {
+	a := assert.New(t, false)
+	d := loadSecurityDoc(a)
+
+	rslt := messagetest.NewMessageHandler()
+	defer rslt.Handler.Stop()
+	mock, err := New(rslt.Handler, d, &Options{Indent: indent, ImageURL: "/images", Gen: testOptions, Security: true})
+	a.NotError(err).NotNil(mock)
+	srv := rest.NewServer(a, mock, nil)
+
+	srv.Get("/users").Header("accept", "application/json").Do(nil).
+		Status(http.StatusUnauthorized)
+
+	srv.Get("/users").
+		Header("accept", "application/json").
+		Header("X-Token", "1").
+		Do(nil).
+		Status(http.StatusOK)
+
+	srv.Get("/users").
+		Header("accept", "application/json").
+		Header("Authorization", "Basic xx").
+		Do(nil).
+		Status(http.StatusOK)
+
+	// 未要求验证的接口
+	srv.Get("/public").Header("accept", "application/json").Do(nil).
+		Status(http.StatusOK)
+
+	// 未启用 security
+	mock, err = New(rslt.Handler, d, &Options{Indent: indent, ImageURL: "/images", Gen: testOptions})
+	a.NotError(err).NotNil(mock)
+	srv = rest.NewServer(a, mock, nil)
+	srv.Get("/users").Header("accept", "application/json").Do(nil).
+		Status(http.StatusOK)
+}
@@ -0,0 +1,429 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/issue9/mux/v7/examples/std"
+	"github.com/issue9/mux/v7/types"
+	"github.com/issue9/qheader"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/internal/ast"
+)
+
+// 在 API 的集合路径中未找到资源 ID 的参数名称时采用的默认值
+const defaultIDName = "id"
+
+// 有状态模式下保存的数据
+//
+// 以集合的路径为键名，比如 /users/{id} 和 /users 都属于 /users 集合。
+type store struct {
+	mux         sync.Mutex
+	params      map[string]string      // 以集合路径为键名，值为文档中表示资源 ID 的地址参数名称。
+	collections map[string]*collection // 以实际的集合路径为键名，比如 /users/1/posts。
+}
+
+// 同一集合下的资源
+type collection struct {
+	mux    sync.Mutex
+	path   string         // 文档中的集合路径，比如 /users/{id}/posts。
+	param  string         // 表示资源 ID 的地址参数名称
+	keys   []string       // 资源 ID 的列表，保证输出时的顺序。
+	items  map[string]any // 以资源 ID 为键名的资源
+	lastID int            // 最后一个自动生成的 ID
+}
+
+func newStore(d *ast.APIDoc) *store {
+	s := &store{collections: make(map[string]*collection, 10)}
+	s.load(d)
+	return s
+}
+
+// 从文档中获取各个集合表示资源 ID 的地址参数名称
+//
+// 保证在访问 /users/{userID} 之前，POST /users 添加的资源也能采用正确的 ID 字段。
+// 重新加载文档时，已有的数据会被保留。
+func (s *store) load(d *ast.APIDoc) {
+	params := make(map[string]string, len(d.APIs))
+	for _, api := range d.APIs {
+		if api.Path == nil || api.Path.Path == nil {
+			continue
+		}
+
+		path, param := splitResourcePath(api.Path.Path.V())
+		if _, found := params[path]; !found && param != "" {
+			params[path] = param
+		}
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.params = params
+	for _, c := range s.collections {
+		if param, found := params[c.path]; found {
+			c.mux.Lock()
+			c.param = param
+			c.mux.Unlock()
+		}
+	}
+}
+
+// 清空所有的数据
+func (s *store) reset() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.collections = make(map[string]*collection, 10)
+}
+
+// 将路径拆分为集合路径和表示资源 ID 的地址参数名称
+//
+// 如果最后一段路径不是地址参数，则 param 返回空值。
+func splitResourcePath(path string) (collection, param string) {
+	index := strings.LastIndexByte(path, '/')
+	if index < 0 {
+		return path, ""
+	}
+
+	last := path[index+1:]
+	if len(last) < 3 || last[0] != '{' || last[len(last)-1] != '}' {
+		return path, ""
+	}
+	last = paramName(last[1 : len(last)-1])
+
+	if index == 0 {
+		return "/", last
+	}
+	return path[:index], last
+}
+
+// 去掉地址参数中的正则部分，比如 id:\d+ 返回 id。
+func paramName(p string) string {
+	if i := strings.IndexByte(p, ':'); i >= 0 {
+		return p[:i]
+	}
+	return p
+}
+
+// 将路径中的地址参数替换为 ps 中的值
+//
+// 比如 /users/{id}/posts 会被替换为 /users/1/posts，
+// 保证不同父资源下的集合不会相互干扰。
+func fillPath(path string, ps types.Params) string {
+	if ps == nil || strings.IndexByte(path, '{') < 0 {
+		return path
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(path, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(path[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		b.WriteString(path[:start])
+		if v, found := ps.Get(paramName(path[start+1 : end])); found {
+			b.WriteString(v)
+		} else {
+			b.WriteString(path[start : end+1])
+		}
+		path = path[end+1:]
+	}
+	b.WriteString(path)
+
+	return b.String()
+}
+
+// 获取 path 所属的集合以及地址中的资源 ID，集合如果不存在则会创建。
+//
+// path 为文档中定义的路径，ps 为当前请求的地址参数，可以为空。
+func (s *store) collection(path string, ps types.Params) (c *collection, id string) {
+	path, param := splitResourcePath(path)
+	key := fillPath(path, ps)
+	if param != "" && ps != nil {
+		id, _ = ps.Get(param)
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	c, found := s.collections[key]
+	if !found {
+		c = &collection{path: path, param: s.params[path], keys: make([]string, 0, 10), items: make(map[string]any, 10)}
+		s.collections[key] = c
+	}
+	if param != "" && c.param == "" {
+		c.param = param
+	}
+
+	return c, id
+}
+
+func (c *collection) idName() string {
+	if c.param == "" {
+		return defaultIDName
+	}
+	return c.param
+}
+
+func (c *collection) list() []any {
+	items := make([]any, 0, len(c.keys))
+	for _, k := range c.keys {
+		items = append(items, c.items[k])
+	}
+	return items
+}
+
+func (c *collection) set(id string, v any) {
+	if _, found := c.items[id]; !found {
+		c.keys = append(c.keys, id)
+	}
+	c.items[id] = v
+}
+
+// 添加新的资源并返回其 ID
+//
+// 如果 v 是对象且包含了 ID 字段，则采用该值，否则自动生成 ID 并写入 v。
+func (c *collection) add(v any) string {
+	obj, isObject := v.(map[string]any)
+	if isObject {
+		if id, found := obj[c.idName()]; found && id != nil {
+			key := fmt.Sprint(id)
+			c.set(key, v)
+			return key
+		}
+	}
+
+	for {
+		c.lastID++
+		if _, found := c.items[strconv.Itoa(c.lastID)]; !found {
+			break
+		}
+	}
+
+	if isObject {
+		obj[c.idName()] = c.lastID
+	}
+	key := strconv.Itoa(c.lastID)
+	c.set(key, v)
+	return key
+}
+
+// 以指定的 ID 保存资源
+//
+// 如果 v 是对象，会将 ID 写入 v，保证其与地址中的值相同。
+func (c *collection) put(id string, v any) {
+	if obj, ok := v.(map[string]any); ok {
+		if n, err := strconv.Atoi(id); err == nil {
+			obj[c.idName()] = n
+		} else {
+			obj[c.idName()] = id
+		}
+	}
+	c.set(id, v)
+}
+
+func (c *collection) delete(id string) bool {
+	if _, found := c.items[id]; !found {
+		return false
+	}
+
+	delete(c.items, id)
+	for i, k := range c.keys {
+		if k == id {
+			c.keys = append(c.keys[:i], c.keys[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// 以有状态的方式处理请求
+//
+// 仅处理 JSON 格式的内容，无法处理时返回 false，由调用方按无状态的方式处理。
+func (m *mock) renderState(api *ast.API, w http.ResponseWriter, r *http.Request, body []byte) bool {
+	if !acceptJSON(r) {
+		return false
+	}
+
+	var ps types.Params
+	if route := std.GetParams(r); route != nil {
+		ps = route.Params()
+	}
+	c, id := m.store.collection(api.Path.Path.V(), ps)
+
+	status, data, err := c.handle(r, id, body, m.indent)
+	switch {
+	case err != nil:
+		m.handleError(w, r, "response.body.", err)
+		return true
+	case status == 0:
+		return false
+	case status == http.StatusNotFound:
+		w.WriteHeader(http.StatusNotFound)
+		return true
+	}
+
+	if data != nil {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.Header().Set("Server", core.Name)
+	w.WriteHeader(successStatus(api, status))
+	if data != nil {
+		if _, err := w.Write(data); err != nil {
+			m.msgHandler.Error(err) // 此时状态码已经输出
+		}
+	}
+	return true
+}
+
+// 在锁内完成对集合的操作
+//
+// 返回的内容已经序列化，输出时不再需要持有锁，避免慢速的客户端阻塞对该集合的其它请求。
+// 无法处理时 status 返回 0。
+func (c *collection) handle(r *http.Request, id string, body []byte, indent string) (status int, data []byte, err error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	var v any
+	switch r.Method {
+	case http.MethodGet:
+		if id == "" {
+			if len(c.keys) == 0 { // 没有数据时返回随机生成的数据
+				return 0, nil, nil
+			}
+			status, v = http.StatusOK, c.list()
+			break
+		}
+
+		item, found := c.items[id]
+		if !found {
+			return 0, nil, nil
+		}
+		status, v = http.StatusOK, item
+	case http.MethodPost:
+		item, ok := decodeState(r, body)
+		if !ok {
+			return 0, nil, nil
+		}
+
+		if id == "" {
+			id = c.add(item)
+		} else {
+			c.put(id, item)
+		}
+		status, v = http.StatusCreated, c.items[id]
+	case http.MethodPut, http.MethodPatch:
+		if id == "" {
+			return 0, nil, nil
+		}
+
+		item, ok := decodeState(r, body)
+		if !ok {
+			return 0, nil, nil
+		}
+
+		if r.Method == http.MethodPatch {
+			item = mergeState(c.items[id], item)
+		}
+		c.put(id, item)
+		status, v = http.StatusOK, item
+	case http.MethodDelete:
+		if id == "" {
+			return 0, nil, nil
+		}
+
+		if !c.delete(id) {
+			return http.StatusNotFound, nil, nil
+		}
+		return http.StatusNoContent, nil, nil
+	default:
+		return 0, nil, nil
+	}
+
+	if data, err = json.MarshalIndent(v, "", indent); err != nil {
+		return 0, nil, err
+	}
+	return status, data, nil
+}
+
+// 返回文档中定义的第一个 2XX 状态码，如果不存在，则返回 def。
+func successStatus(api *ast.API, def int) int {
+	for _, resp := range api.Responses {
+		if status := resp.Status.V(); status >= 200 && status < 300 {
+			return status
+		}
+	}
+	return def
+}
+
+// 客户端是否接受 JSON 格式的内容
+//
+// 未指定 Accept 报头时，等同于 */*。
+func acceptJSON(r *http.Request) bool {
+	headers := qheader.Accept(r)
+	if headers == nil {
+		return true
+	}
+
+	for _, h := range headers.Items {
+		switch strings.ToLower(h.Value) {
+		case "application/json", "*/*":
+			return true
+		case "application/xml", "text/xml":
+			return false
+		}
+	}
+	return false
+}
+
+func decodeState(r *http.Request, body []byte) (any, bool) {
+	if len(body) == 0 {
+		return nil, false
+	}
+	if mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mt != "application/json" {
+		return nil, false
+	}
+
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// 将 v 中的字段合并到 old 中
+//
+// 仅在两者都为对象时才会合并，否则直接返回 v。
+func mergeState(old, v any) any {
+	o, ok := old.(map[string]any)
+	if !ok {
+		return v
+	}
+
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+
+	merged := make(map[string]any, len(o)+len(obj))
+	for k, val := range o {
+		merged[k] = val
+	}
+	for k, val := range obj {
+		merged[k] = val
+	}
+	return merged
+}
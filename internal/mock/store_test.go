@@ -0,0 +1,361 @@
+// SPDX-License-Identifier: MIT
+
+package mock
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert/v3"
+	"github.com/issue9/assert/v3/rest"
+	"github.com/issue9/mux/v7/types"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/core/messagetest"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+const statefulDoc = `<apidoc version="1.1.1">
+	<title>test</title>
+	<mimetype>application/json</mimetype>
+	<mimetype>application/xml</mimetype>
+	<api method="GET" summary="list">
+		<path path="/users" />
+		<response status="200" type="object" array="true">
+			<param name="id" type="number" summary="id" />
+			<param name="name" type="string" summary="name" />
+		</response>
+	</api>
+	<api method="POST" summary="create">
+		<path path="/users" />
+		<request type="object">
+			<param name="name" type="string" summary="name" />
+		</request>
+		<response status="201" type="object">
+			<param name="id" type="number" summary="id" />
+			<param name="name" type="string" summary="name" />
+		</response>
+	</api>
+	<api method="GET" summary="get">
+		<path path="/users/{id}"><param name="id" type="number" summary="id" /></path>
+		<response status="200" type="object" name="user">
+			<param name="id" type="number" summary="id" />
+			<param name="name" type="string" summary="name" />
+		</response>
+	</api>
+	<api method="PATCH" summary="update">
+		<path path="/users/{id}"><param name="id" type="number" summary="id" /></path>
+		<request type="object">
+			<param name="age" type="number" summary="age" />
+		</request>
+		<response status="200" type="object">
+			<param name="id" type="number" summary="id" />
+			<param name="name" type="string" summary="name" />
+		</response>
+	</api>
+	<api method="DELETE" summary="delete">
+		<path path="/users/{id}"><param name="id" type="number" summary="id" /></path>
+		<response status="204" />
+	</api>
+</apidoc>`
+
+type stateUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Age  int    `json:"age,omitempty"`
+}
+
+func TestSplitResourcePath(t *testing.T) {
+	a := assert.New(t, false)
+
+	data := []*struct {
+		path, collection, param string
+	}{
+		{path: "/users", collection: "/users"},
+		{path: "/users/{id}", collection: "/users", param: "id"},
+		{path: "/users/{id:\\d+}", collection: "/users", param: "id"},
+		{path: "/users/{id}/posts", collection: "/users/{id}/posts"},
+		{path: "/{id}", collection: "/", param: "id"},
+		{path: "users", collection: "users"},
+	}
+
+	for _, item := range data {
+		c, p := splitResourcePath(item.path)
+		a.Equal(c, item.collection, "%s 的集合路径 %s", item.path, c).
+			Equal(p, item.param, "%s 的参数 %s", item.path, p)
+	}
+}
+
+func TestMock_renderState(t *testing.T) {
+	a := assert.New(t, false)
+
+	rslt := messagetest.NewMessageHandler()
+	d := &ast.APIDoc{APIDoc: &ast.APIDocVersionAttribute{Value: xmlenc.String{Value: ast.Version}}}
+	d.Parse(rslt.Handler, core.Block{Data: []byte(statefulDoc)})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	rslt = messagetest.NewMessageHandler()
+	defer rslt.Handler.Stop()
+	mock, err := New(rslt.Handler, d, &Options{ImageURL: "/images", Gen: testOptions, Stateful: true})
+	a.NotError(err).NotNil(mock)
+	srv := rest.NewServer(a, mock, nil)
+
+	// 没有数据时返回随机数据
+	srv.Get("/users/1").Header("accept", "application/json").Do(nil).
+		Status(http.StatusOK).
+		BodyNotEmpty()
+
+	srv.Post("/users", []byte(`{"name":"n1"}`)).
+		Header("accept", "application/json").
+		Header("content-type", "application/json").
+		Do(nil).
+		Status(http.StatusCreated).
+		JSONBody(&stateUser{ID: 1, Name: "n1"})
+
+	srv.Post("/users", []byte(`{"name":"n2"}`)).
+		Header("accept", "application/json").
+		Header("content-type", "application/json").
+		Do(nil).
+		Status(http.StatusCreated).
+		JSONBody(&stateUser{ID: 2, Name: "n2"})
+
+	srv.Get("/users").Header("accept", "application/json").Do(nil).
+		Status(http.StatusOK).
+		JSONBody(&[]*stateUser{{ID: 1, Name: "n1"}, {ID: 2, Name: "n2"}})
+
+	// 与返回内容无关的首选项不影响有状态模式
+	srv.Get("/users/2").
+		Header("accept", "application/json").
+		Header("Prefer", "return=minimal, respond-async").
+		Do(nil).
+		Status(http.StatusOK).
+		JSONBody(&stateUser{ID: 2, Name: "n2"})
+
+	// 通过 Prefer 指定了状态码，按无状态的方式处理。
+	srv.Get("/users/2").
+		Header("accept", "application/json").
+		Header("Prefer", "code=200").
+		Do(nil).
+		Status(http.StatusOK).
+		Header(preferAppliedHeader, "code=200").
+		BodyFunc(func(a *assert.Assertion, body []byte) {
+			a.NotContains(string(body), "n2")
+		})
+
+	srv.Patch("/users/1", []byte(`{"age":5}`)).
+		Header("accept", "application/json").
+		Header("content-type", "application/json").
+		Do(nil).
+		Status(http.StatusOK).
+		JSONBody(&stateUser{ID: 1, Name: "n1", Age: 5})
+
+	srv.Get("/users/1").Header("accept", "application/json").Do(nil).
+		Status(http.StatusOK).
+		JSONBody(&stateUser{ID: 1, Name: "n1", Age: 5})
+
+	srv.Delete("/users/1").Header("accept", "application/json").Do(nil).
+		Status(http.StatusNoContent).
+		BodyEmpty()
+
+	srv.Delete("/users/1").Header("accept", "application/json").Do(nil).
+		Status(http.StatusNotFound)
+
+	srv.Get("/users").Header("accept", "application/json").Do(nil).
+		Status(http.StatusOK).
+		JSONBody(&[]*stateUser{{ID: 2, Name: "n2"}})
+
+	// 无法以 JSON 格式输出的，按无状态的方式处理。
+	srv.Get("/users/2").Header("accept", "application/xml").Do(nil).
+		Status(http.StatusOK).
+		Header("content-type", "application/xml")
+}
+
+func TestMock_renderState_nested(t *testing.T) {
+	a := assert.New(t, false)
+
+	doc := `<apidoc version="1.1.1">
+	<title>test</title>
+	<mimetype>application/json</mimetype>
+	<api method="GET" summary="list">
+		<path path="/users/{uid}/posts"><param name="uid" type="number" summary="uid" /></path>
+		<response status="200" type="object" array="true">
+			<param name="id" type="number" summary="id" />
+			<param name="title" type="string" summary="title" />
+		</response>
+	</api>
+	<api method="POST" summary="create">
+		<path path="/users/{uid}/posts"><param name="uid" type="number" summary="uid" /></path>
+		<request type="object">
+			<param name="title" type="string" summary="title" />
+		</request>
+		<response status="201" type="object">
+			<param name="id" type="number" summary="id" />
+			<param name="title" type="string" summary="title" />
+		</response>
+	</api>
+</apidoc>`
+	rslt := messagetest.NewMessageHandler()
+	d := &ast.APIDoc{APIDoc: &ast.APIDocVersionAttribute{Value: xmlenc.String{Value: ast.Version}}}
+	d.Parse(rslt.Handler, core.Block{Data: []byte(doc)})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	rslt = messagetest.NewMessageHandler()
+	defer rslt.Handler.Stop()
+	mock, err := New(rslt.Handler, d, &Options{ImageURL: "/images", Gen: testOptions, Stateful: true})
+	a.NotError(err).NotNil(mock)
+	srv := rest.NewServer(a, mock, nil)
+
+	// 未指定 Accept 报头，等同于 */*。
+	srv.Post("/users/1/posts", []byte(`{"title":"t1"}`)).
+		Header("content-type", "application/json").
+		Do(nil).
+		Status(http.StatusCreated).
+		Header("content-type", "application/json").
+		JSONBody(&map[string]any{"id": 1.0, "title": "t1"})
+
+	srv.Get("/users/1/posts").Do(nil).
+		Status(http.StatusOK).
+		JSONBody(&[]map[string]any{{"id": 1.0, "title": "t1"}})
+
+	// 不同父资源下的集合相互独立
+	srv.Post("/users/2/posts", []byte(`{"title":"t2"}`)).
+		Header("content-type", "application/json").
+		Do(nil).
+		Status(http.StatusCreated).
+		JSONBody(&map[string]any{"id": 1.0, "title": "t2"})
+
+	srv.Get("/users/2/posts").Do(nil).
+		Status(http.StatusOK).
+		JSONBody(&[]map[string]any{{"id": 1.0, "title": "t2"}})
+}
+
+func TestMock_renderState_param(t *testing.T) {
+	a := assert.New(t, false)
+
+	doc := strings.ReplaceAll(statefulDoc, `<path path="/users/{id}"><param name="id"`, `<path path="/users/{userID}"><param name="userID"`)
+	rslt := messagetest.NewMessageHandler()
+	d := &ast.APIDoc{APIDoc: &ast.APIDocVersionAttribute{Value: xmlenc.String{Value: ast.Version}}}
+	d.Parse(rslt.Handler, core.Block{Data: []byte(doc)})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	rslt = messagetest.NewMessageHandler()
+	defer rslt.Handler.Stop()
+	mock, err := New(rslt.Handler, d, &Options{ImageURL: "/images", Gen: testOptions, Stateful: true})
+	a.NotError(err).NotNil(mock)
+	srv := rest.NewServer(a, mock, nil)
+
+	// 在访问 /users/{userID} 之前添加的资源，同样采用 userID 作为 ID 字段。
+	srv.Post("/users", []byte(`{"name":"n1"}`)).
+		Header("accept", "application/json").
+		Header("content-type", "application/json; charset=utf-8").
+		Do(nil).
+		Status(http.StatusCreated).
+		JSONBody(&map[string]any{"userID": 1.0, "name": "n1"})
+
+	srv.Get("/users/1").Header("accept", "application/json").Do(nil).
+		Status(http.StatusOK).
+		JSONBody(&map[string]any{"userID": 1.0, "name": "n1"})
+
+	srv.Delete("/users/1").Header("accept", "application/json").Do(nil).
+		Status(http.StatusNoContent)
+}
+
+func TestMock_renderState_put(t *testing.T) {
+	a := assert.New(t, false)
+
+	rslt := messagetest.NewMessageHandler()
+	d := &ast.APIDoc{APIDoc: &ast.APIDocVersionAttribute{Value: xmlenc.String{Value: ast.Version}}}
+	d.Parse(rslt.Handler, core.Block{Data: []byte(statefulDoc)})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	rslt = messagetest.NewMessageHandler()
+	defer rslt.Handler.Stop()
+	mock, err := New(rslt.Handler, d, &Options{ImageURL: "/images", Gen: testOptions, Stateful: true})
+	a.NotError(err).NotNil(mock)
+	srv := rest.NewServer(a, mock, nil)
+
+	// PATCH 不存在的资源时，同样会写入地址中的 ID。
+	srv.Patch("/users/5", []byte(`{"age":5}`)).
+		Header("accept", "application/json").
+		Header("content-type", "application/json;charset=UTF-8").
+		Do(nil).
+		Status(http.StatusOK).
+		JSONBody(&stateUser{ID: 5, Age: 5})
+
+	srv.Get("/users").Header("accept", "application/json").Do(nil).
+		Status(http.StatusOK).
+		JSONBody(&[]*stateUser{{ID: 5, Age: 5}})
+}
+
+func TestFillPath(t *testing.T) {
+	a := assert.New(t, false)
+
+	ps := types.NewContext()
+	ps.Set("uid", "1")
+	ps.Set("pid", "2")
+
+	a.Equal(fillPath("/users", ps), "/users").
+		Equal(fillPath("/users/{uid}/posts", ps), "/users/1/posts").
+		Equal(fillPath("/users/{uid:\\d+}/posts/{pid}/comments", ps), "/users/1/posts/2/comments").
+		Equal(fillPath("/users/{not-exists}/posts", ps), "/users/{not-exists}/posts").
+		Equal(fillPath("/users/{uid}/posts", nil), "/users/{uid}/posts")
+}
+
+func TestDecodeState(t *testing.T) {
+	a := assert.New(t, false)
+
+	data := []*struct {
+		ct   string
+		body string
+		ok   bool
+	}{
+		{ct: "application/json", body: `{"id":1}`, ok: true},
+		{ct: "application/json; charset=utf-8", body: `{"id":1}`, ok: true},
+		{ct: "Application/JSON;charset=UTF-8", body: `{"id":1}`, ok: true},
+		{ct: "application/json; charset", body: `{"id":1}`},
+		{ct: "application/xml", body: `{"id":1}`},
+		{ct: "", body: `{"id":1}`},
+		{ct: "application/json"},
+		{ct: "application/json", body: `{`},
+	}
+
+	for _, item := range data {
+		r := httptest.NewRequest(http.MethodPost, "/users", nil)
+		r.Header.Set("Content-Type", item.ct)
+		_, ok := decodeState(r, []byte(item.body))
+		a.Equal(ok, item.ok, "%s:%s", item.ct, item.body)
+	}
+
+	// 写入地址中的 ID
+	c := &collection{param: "uid", keys: []string{}, items: map[string]any{}}
+	c.put("5", map[string]any{"uid": 6})
+	c.put("x", map[string]any{})
+	a.Equal(c.items["5"], map[string]any{"uid": 5}).
+		Equal(c.items["x"], map[string]any{"uid": "x"})
+}
+
+func TestStore_load(t *testing.T) {
+	a := assert.New(t, false)
+
+	rslt := messagetest.NewMessageHandler()
+	d := &ast.APIDoc{APIDoc: &ast.APIDocVersionAttribute{Value: xmlenc.String{Value: ast.Version}}}
+	d.Parse(rslt.Handler, core.Block{Data: []byte(statefulDoc)})
+	rslt.Handler.Stop()
+
+	s := newStore(d)
+	a.Equal(s.params, map[string]string{"/users": "id"})
+	c, id := s.collection("/users", nil)
+	a.Empty(id).Equal(c.idName(), "id")
+
+	// 重新加载时更新已有集合的参数名称
+	d.APIs[2].Path.Path.Value.Value = "/users/{uid}"
+	s.load(d)
+	a.Equal(c.param, "uid")
+}
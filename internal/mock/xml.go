@@ -4,6 +4,7 @@ package mock
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/xml"
 	"errors"
 	"fmt"
@@ -16,14 +17,16 @@ import (
 	"github.com/caixw/apidoc/v7/core"
 	"github.com/caixw/apidoc/v7/internal/ast"
 	"github.com/caixw/apidoc/v7/internal/locale"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
 )
 
 type xmlValidator struct {
 	namespaces []*ast.XMLNamespace
 	decoder    *xml.Decoder
+	request    bool // 是否为请求内容，与 jsonValidator.request 相同。
 }
 
-func validXML(ns []*ast.XMLNamespace, p *ast.Request, content []byte) error {
+func validXML(ns []*ast.XMLNamespace, p *ast.Request, content []byte, request bool) error {
 	if len(content) == 0 {
 		if p == nil || p.Type.V() == ast.TypeNone {
 			return nil
@@ -34,6 +37,7 @@ func validXML(ns []*ast.XMLNamespace, p *ast.Request, content []byte) error {
 	validator := &xmlValidator{
 		namespaces: ns,
 		decoder:    xml.NewDecoder(bytes.NewReader(content)),
+		request:    request,
 	}
 	for {
 		token, err := validator.decoder.Token()
@@ -140,6 +144,10 @@ func parseXMLWrappedName(p *ast.Param, parent bool) (name string) {
 }
 
 func (v *xmlValidator) validXMLElement(start xml.StartElement, p *ast.Param, chkArray bool, field string) error {
+	if len(p.Variants) > 0 {
+		p = unionVariants(p)
+	}
+
 	if err := v.validStartElement(start, p, chkArray, field); err != nil {
 		return err
 	}
@@ -167,8 +175,21 @@ LOOP:
 				continue LOOP
 			}
 
+			if value := p.MapValue(); value != nil { // 字典的每个子元素都是一个键值对
+				entry := mapEntry(value, elem.Name.Local)
+				if err = v.validXMLElement(elem, entry, true, buildXMLField(field, entry)); err != nil {
+					return err
+				}
+				chardata = nil
+				started = true
+				continue LOOP
+			}
+
 			for _, pp := range p.Items {
 				if v.validXMLName(elem.Name, pp, true) {
+					if err := checkAccess(pp, v.request, buildXMLField(field, pp)); err != nil {
+						return err
+					}
 					if pp.XMLExtract.V() {
 						pp = p
 					}
@@ -198,11 +219,19 @@ LOOP:
 }
 
 func (v *xmlValidator) validStartElement(start xml.StartElement, p *ast.Param, chkArray bool, field string) error {
+	items := p.Items
+	if p.MapValue() != nil { // 字典的子元素描述的是值，而不是当前元素的属性
+		items = nil
+	}
+
 	for _, attr := range start.Attr {
-		for _, pp := range p.Items {
+		for _, pp := range items {
 			if !v.validXMLName(attr.Name, pp, false) {
 				continue
 			}
+			if err := checkAccess(pp, v.request, buildXMLField(field, pp)); err != nil {
+				return err
+			}
 			if err := validXMLValue(pp, buildXMLField(field, pp), attr.Value); err != nil {
 				return err
 			}
@@ -216,6 +245,57 @@ func (v *xmlValidator) validStartElement(start xml.StartElement, p *ast.Param, c
 	return nil
 }
 
+// 将复合类型的所有变体合并为一个对象
+//
+// XML 的验证是流式的，在读取完整的元素之前无法确定其对应的变体，
+// 所以仅验证子元素是否存在于任意一个变体之中。
+// 如果指定了 discriminator，对应子元素的枚举值为所有变体的 value。
+func unionVariants(p *ast.Param) *ast.Param {
+	variants := p.Expand()
+	union := *variants[0]
+	union.Items = make([]*ast.Param, 0, len(union.Items))
+
+	disc := p.Discriminator.V()
+	var enums []*ast.Enum
+	for _, v := range variants {
+	LOOP:
+		for _, item := range v.Items {
+			name := item.Name.V()
+			if disc != "" && name == disc {
+				enums = append(enums, item.Enums...)
+			}
+
+			for _, exists := range union.Items {
+				if exists.Name.V() == name {
+					continue LOOP
+				}
+			}
+			union.Items = append(union.Items, item)
+		}
+	}
+
+	if disc != "" {
+		for i, item := range union.Items {
+			if item.Name.V() == disc {
+				d := *item
+				d.Enums = enums
+				union.Items[i] = &d
+			}
+		}
+	}
+
+	return &union
+}
+
+// 返回字典中键名为 key 的元素
+//
+// value 为字典的值，XML 中以键名作为元素名称。
+func mapEntry(value *ast.Param, key string) *ast.Param {
+	entry := *value
+	entry.Name = &ast.Attribute{Value: xmlenc.String{Value: key}}
+	return &entry
+}
+
 func buildXMLField(field string, p *ast.Param) string {
 	if p.XMLAttr.V() {
 		return field + "@" + p.Name.V()
@@ -226,6 +306,10 @@ func buildXMLField(field string, p *ast.Param) string {
 // 验证 p 描述的类型与 v 是否匹配，如果不匹配返回错误信息。
 // field 表示 p 在整个对象中的位置信息。
 func validXMLValue(p *ast.Param, field, v string) error {
+	if primitive, _ := ast.ParseType(p.Type.V()); primitive == ast.TypeMap {
+		return nil
+	}
+
 	switch p.Type.V() {
 	case ast.TypeNone:
 		if v != "" {
@@ -267,21 +351,28 @@ func validXMLValue(p *ast.Param, field, v string) error {
 		if !isValidRFC3339DateTime(v) {
 			return core.NewError(locale.ErrInvalidFormat).WithField(field)
 		}
-	case ast.TypeString, ast.TypeObject, ast.TypeImage:
+	case ast.TypeFile:
+		data, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return core.NewError(locale.ErrInvalidFormat).WithField(field)
+		}
+		if err := p.CheckFile(len(data), ""); err != nil {
+			return err.WithField(field)
+		}
+	case ast.TypeString, ast.TypeImage:
+	case ast.TypeObject:
 		return nil
 	default:
 		panic(fmt.Sprintf("文档中类型定义错误 %s", p.Type.V()))
 	}
 
-	if isEnum(p) {
-		for _, enum := range p.Enums {
-			if enum.Value.V() == v {
-				return nil
-			}
-		}
+	if isEnum(p) && !hasEnum(p, v) {
 		return core.NewError(locale.ErrInvalidValue).WithField(field)
 	}
 
+	if err := p.CheckValue(v); err != nil {
+		return err.WithField(field)
+	}
 	return nil
 }
 
@@ -293,12 +384,15 @@ type xmlBuilder struct {
 	cdata    bool // 表示 chardata 是一个 cdata 数据
 }
 
-func buildXML(ns []*ast.XMLNamespace, p *ast.Request, indent string, g *GenOptions) ([]byte, error) {
+// 生成 p 的 XML 内容
+//
+// request 表示生成的是请求内容，会去掉只读的字段，否则去掉只写的字段。
+func buildXML(ns []*ast.XMLNamespace, p *ast.Request, request bool, indent string, g *GenOptions) ([]byte, error) {
 	if p == nil || p.Type.V() == ast.TypeNone {
 		return nil, nil
 	}
 
-	builder, err := parseXML(ns, p.Param(), true, true, g)
+	builder, err := parseXML(ns, p.Param(), true, true, request, g)
 	if err != nil {
 		return nil, err
 	}
@@ -318,7 +412,7 @@ func buildXML(ns []*ast.XMLNamespace, p *ast.Request, indent string, g *GenOptio
 	return buf.Bytes(), nil
 }
 
-func parseXML(ns []*ast.XMLNamespace, p *ast.Param, chkArray, root bool, g *GenOptions) (*xmlBuilder, error) {
+func parseXML(ns []*ast.XMLNamespace, p *ast.Param, chkArray, root, request bool, g *GenOptions) (*xmlBuilder, error) {
 	builder := &xmlBuilder{
 		start: xml.StartElement{
 			Name: buildXMLName(p, chkArray),
@@ -329,7 +423,7 @@ func parseXML(ns []*ast.XMLNamespace, p *ast.Param, chkArray, root bool, g *GenO
 	}
 
 	if p.Array.V() && chkArray {
-		if err := parseXMLArray(ns, p, builder, g); err != nil {
+		if err := parseXMLArray(ns, p, builder, request, g); err != nil {
 			return nil, err
 		}
 		if root {
@@ -338,12 +432,28 @@ func parseXML(ns []*ast.XMLNamespace, p *ast.Param, chkArray, root bool, g *GenO
 		goto RET
 	}
 
+	if value := p.MapValue(); value != nil { // 字典的每个键值对以键名作为元素名称
+		for _, key := range g.generateMapKeys() {
+			b, err := parseXML(ns, mapEntry(value, key), false, false, request, g)
+			if err != nil {
+				return nil, err
+			}
+			builder.items = append(builder.items, b)
+		}
+		goto RET
+	}
+
 	if p.Type.V() != ast.TypeObject {
 		builder.chardata = genXMLValue(g, p)
 		goto RET
 	}
 
-	for _, item := range p.Items {
+	if len(p.Variants) > 0 { // 复合类型随机选取其中一个变体
+		variants := p.Expand()
+		p = variants[g.index(len(variants))]
+	}
+
+	for _, item := range filterItems(p.Items, request) {
 		switch {
 		case item.XMLAttr.V():
 			attr := xml.Attr{
@@ -355,11 +465,11 @@ func parseXML(ns []*ast.XMLNamespace, p *ast.Param, chkArray, root bool, g *GenO
 			builder.chardata = genXMLValue(g, item)
 			builder.cdata = item.XMLCData.V()
 		case item.Array.V():
-			if err := parseXMLArray(ns, item, builder, g); err != nil {
+			if err := parseXMLArray(ns, item, builder, request, g); err != nil {
 				return nil, err
 			}
 		default:
-			b, err := parseXML(ns, item, true, false, g)
+			b, err := parseXML(ns, item, true, false, request, g)
 			if err != nil {
 				return nil, err
 			}
@@ -395,7 +505,7 @@ func buildXMLName(p *ast.Param, chkArray bool) xml.Name {
 	return xml.Name{Local: name}
 }
 
-func parseXMLArray(ns []*ast.XMLNamespace, p *ast.Param, parent *xmlBuilder, g *GenOptions) error {
+func parseXMLArray(ns []*ast.XMLNamespace, p *ast.Param, parent *xmlBuilder, request bool, g *GenOptions) error {
 	b := parent
 	if p.XMLWrapped.V() != "" && p.XMLWrapped.V()[0] != '>' {
 		b = &xmlBuilder{items: []*xmlBuilder{}}
@@ -403,11 +513,21 @@ func parseXMLArray(ns []*ast.XMLNamespace, p *ast.Param, parent *xmlBuilder, g *
 		parent.items = append(parent.items, b)
 	}
 
-	for i := 0; i < g.generateSliceSize(); i++ {
-		bb, err := parseXML(ns, p, false, false, g)
+	size := g.generateSliceSize(p)
+	var items []any // 基本类型的元素值，需要统一生成以保证 unique-items 约束。
+	if primitive, _ := ast.ParseType(p.Type.V()); primitive != ast.TypeObject && primitive != ast.TypeMap {
+		items = g.generateItems(p, size)
+		size = len(items)
+	}
+
+	for i := 0; i < size; i++ {
+		bb, err := parseXML(ns, p, false, false, request, g)
 		if err != nil {
 			return err
 		}
+		if items != nil {
+			bb.chardata = items[i]
+		}
 		b.items = append(b.items, bb)
 	}
 
@@ -448,6 +568,8 @@ func genXMLValue(g *GenOptions, p *ast.Param) any {
 		return g.generateNumber(p)
 	case ast.TypeString:
 		return g.generateString(p)
+	case ast.TypeFile:
+		return g.generateFile(p)
 	default: // ast.TypeObject:
 		panic(fmt.Sprintf("无效的类型 %s", p.Type.V())) // 加载的时候已经作语法验证，此处还出错则直接 panic
 	}
@@ -16,7 +16,7 @@ func TestValidXML(t *testing.T) {
 	a := assert.New(t, false)
 
 	for _, item := range data {
-		err := validXML(item.XMLNS, item.Type, []byte(item.XML))
+		err := validXML(item.XMLNS, item.Type, []byte(item.XML), false)
 		a.NotError(err, "测试 %s 时返回错误 %s", item.Title, err)
 	}
 
@@ -37,14 +37,14 @@ func TestValidXML(t *testing.T) {
 		},
 	}
 	content := `<root id="1024"><desc>1024</desc></root>`
-	a.Error(validXML(nil, p, []byte(content)))
+	a.Error(validXML(nil, p, []byte(content), false))
 }
 
 func TestBuildXML(t *testing.T) {
 	a := assert.New(t, false)
 
 	for _, item := range data {
-		data, err := buildXML(item.XMLNS, item.Type, indent, testOptions)
+		data, err := buildXML(item.XMLNS, item.Type, false, indent, testOptions)
 		a.NotError(err, "测试 %s 返回了错误信息 %s", item.Title, err).
 			Equal(string(data), item.XML, "测试 %s 返回的数据不相等\nv1:%s\nv2:%s\n", item.Title, string(data), item.XML)
 	}
@@ -394,3 +394,52 @@ func TestGenXMLValue(t *testing.T) {
 		v = genXMLValue(testOptions, &ast.Param{Type: &ast.TypeAttribute{Value: xmlenc.String{Value: "not-exists"}}})
 	})
 }
+
+func TestValidXML_composite(t *testing.T) {
+	a := assert.New(t, false)
+
+	var item *tester
+	for _, i := range data {
+		if i.Title == "composite" {
+			item = i
+		}
+	}
+	a.NotNil(item)
+
+	a.NotError(validXML(nil, item.Type, []byte(`<event kind="deleted"><reason>r</reason></event>`), false))
+	a.Error(validXML(nil, item.Type, []byte(`<event kind="updated"><id>1</id></event>`), false))
+	a.Error(validXML(nil, item.Type, []byte(`<event kind="created"><id>xx</id></event>`), false))
+}
+
+func TestValidXML_map(t *testing.T) {
+	a := assert.New(t, false)
+
+	var item *tester
+	for _, i := range data {
+		if i.Title == "map" {
+			item = i
+		}
+	}
+	a.NotNil(item)
+
+	a.NotError(validXML(nil, item.Type, []byte(`<root><scores><a>1</a><b>2</b></scores><users><u1><name>n1</name></u1></users></root>`), false))
+	a.NotError(validXML(nil, item.Type, []byte(`<root><scores></scores><users></users></root>`), false))
+	a.Error(validXML(nil, item.Type, []byte(`<root><scores><a>x</a></scores></root>`), false))
+}
+
+func TestValidXML_access(t *testing.T) {
+	a := assert.New(t, false)
+
+	var item *tester
+	for _, i := range data {
+		if i.Title == "access" {
+			item = i
+		}
+	}
+	a.NotNil(item)
+
+	a.NotError(validXML(nil, item.Type, []byte(`<user><id>1</id><nickname>n</nickname></user>`), false))
+	a.Error(validXML(nil, item.Type, []byte(`<user><id>1</id><password>p</password></user>`), false))
+	a.NotError(validXML(nil, item.Type, []byte(`<user><password>p</password></user>`), true))
+	a.Error(validXML(nil, item.Type, []byte(`<user><id>1</id><password>p</password></user>`), true))
+}
@@ -0,0 +1,1298 @@
+// SPDX-License-Identifier: MIT
+
+package openapi
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/issue9/version"
+	"gopkg.in/yaml.v3"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/locale"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+// 导入时，未指定 mimetype 的文档所采用的默认值
+const defaultMimetype = "application/json"
+
+// 按 http 请求方法对应 PathItem 中的各个字段，保证输出的顺序是固定的。
+var pathItemMethods = []struct {
+	method    string
+	operation func(*PathItem) *Operation
+}{
+	{method: http.MethodGet, operation: func(p *PathItem) *Operation { return p.Get }},
+	{method: http.MethodPut, operation: func(p *PathItem) *Operation { return p.Put }},
+	{method: http.MethodPost, operation: func(p *PathItem) *Operation { return p.Post }},
+	{method: http.MethodDelete, operation: func(p *PathItem) *Operation { return p.Delete }},
+	{method: http.MethodOptions, operation: func(p *PathItem) *Operation { return p.Options }},
+	{method: http.MethodHead, operation: func(p *PathItem) *Operation { return p.Head }},
+	{method: http.MethodPatch, operation: func(p *PathItem) *Operation { return p.Patch }},
+	{method: http.MethodTrace, operation: func(p *PathItem) *Operation { return p.Trace }},
+}
+
+// 将 openapi 转换成 ast.APIDoc 的相关操作
+type importer struct {
+	h         *core.MessageHandler
+	uri       core.URI
+	openapi   *OpenAPI
+	doc       *ast.APIDoc
+	mimetypes []string
+	refs      []string             // 正在展开的 $ref，用于检测循环引用。
+	security  []*ast.SecurityValue // 全局的 security，作用于未指定 security 的 API。
+}
+
+// Import 将 openapi 文档转换成 ast.APIDoc
+//
+// data 可以是 JSON 或是 YAML 格式的内容；
+// 无法转换的内容会以警告的形式输出到 h，其 Field 字段为该内容在源文档中的 JSON pointer。
+func Import(h *core.MessageHandler, uri core.URI, data []byte) (*ast.APIDoc, error) {
+	oa := &OpenAPI{}
+	if err := yaml.Unmarshal(data, oa); err != nil { // YAML 是 JSON 的超集，可以同时处理两种格式。
+		return nil, core.WithError(err).WithLocation(core.Location{URI: uri})
+	}
+
+	if !strings.HasPrefix(oa.OpenAPI, "3.") {
+		return nil, core.Location{URI: uri}.NewError(locale.ErrInvalidValue).WithField("/openapi")
+	}
+	if oa.Info == nil {
+		return nil, core.Location{URI: uri}.NewError(locale.ErrIsEmpty, "info").WithField("/info")
+	}
+
+	i := &importer{
+		h:       h,
+		uri:     uri,
+		openapi: oa,
+		doc: &ast.APIDoc{
+			APIDoc: &ast.APIDocVersionAttribute{Value: xmlenc.String{Value: ast.Version}},
+		},
+	}
+	i.parseInfo()
+	i.parseServers()
+	i.parseTags()
+	i.parseSecuritySchemes()
+	i.security = i.newSecurities("/security", oa.Security)
+
+	if oa.ExternalDocs != nil {
+		i.unsupported("/externalDocs")
+	}
+
+	i.parsePaths()
+
+	if len(i.mimetypes) == 0 {
+		i.mimetypes = append(i.mimetypes, defaultMimetype)
+	}
+	for _, mt := range i.mimetypes {
+		i.doc.Mimetypes = append(i.doc.Mimetypes, newElement(mt))
+	}
+
+	return i.doc, nil
+}
+
+// 输出无法转换的内容
+func (i *importer) unsupported(ptr string) {
+	i.h.Warning(core.Location{URI: i.uri}.NewError(locale.ErrUnsupportedOpenAPI).WithField(ptr))
+}
+
+func (i *importer) addMimetype(mimetype string) {
+	for _, mt := range i.mimetypes {
+		if mt == mimetype {
+			return
+		}
+	}
+	i.mimetypes = append(i.mimetypes, mimetype)
+}
+
+func (i *importer) parseInfo() {
+	info := i.openapi.Info
+
+	i.doc.Title = newElement(info.Title)
+	i.doc.Description = newRichtext(info.Description)
+
+	if version.SemVerValid(info.Version) {
+		i.doc.Version = &ast.VersionAttribute{Value: xmlenc.String{Value: info.Version}}
+	} else if info.Version != "" {
+		i.unsupported("/info/version")
+	}
+
+	if info.TermsOfService != "" {
+		i.unsupported("/info/termsOfService")
+	}
+
+	if c := info.Contact; c != nil {
+		name := c.Name
+		if name == "" {
+			name = c.Email
+		}
+		if name == "" {
+			name = c.URL
+		}
+		i.doc.Contact = &ast.Contact{Name: newAttribute(name)}
+		if c.URL != "" {
+			i.doc.Contact.URL = newElement(c.URL)
+		}
+		if c.Email != "" {
+			i.doc.Contact.Email = newElement(c.Email)
+		}
+	}
+
+	if l := info.License; l != nil {
+		if l.URL == "" {
+			i.unsupported("/info/license")
+		} else {
+			i.doc.License = &ast.Link{Text: newAttribute(l.Name), URL: newAttribute(l.URL)}
+		}
+	}
+}
+
+func (i *importer) parseServers() {
+	for index, srv := range i.openapi.Servers {
+		i.addServer(srv, "/servers/"+strconv.Itoa(index))
+	}
+}
+
+// 添加服务器并返回其在 ast.APIDoc 中的名称
+//
+// 如果已经存在相同地址的服务器，则直接返回其名称。
+func (i *importer) addServer(srv *Server, ptr string) string {
+	url := srv.URL
+	for name, v := range srv.Variables { // 变量以默认值代替
+		url = strings.ReplaceAll(url, "{"+name+"}", v.Default)
+	}
+
+	for _, s := range i.doc.Servers {
+		if s.URL.V() == url {
+			return s.Name.V()
+		}
+	}
+
+	if strings.ContainsAny(url, "{}") {
+		i.unsupported(ptr + "/url")
+	}
+
+	name := "server" + strconv.Itoa(len(i.doc.Servers)+1)
+	i.doc.Servers = append(i.doc.Servers, &ast.Server{
+		Name:    newAttribute(name),
+		URL:     newAttribute(url),
+		Summary: newAttribute(srv.Description),
+	})
+	return name
+}
+
+func (i *importer) parseTags() {
+	for index, tag := range i.openapi.Tags {
+		i.addTag(tag.Name, tag.Description)
+		if tag.ExternalDocs != nil {
+			i.unsupported("/tags/" + strconv.Itoa(index) + "/externalDocs")
+		}
+	}
+}
+
+func (i *importer) addTag(name, title string) {
+	for _, t := range i.doc.Tags {
+		if t.Name.V() == name {
+			return
+		}
+	}
+
+	if title == "" {
+		title = name
+	}
+	i.doc.Tags = append(i.doc.Tags, &ast.Tag{Name: newAttribute(name), Title: newAttribute(title)})
+}
+
+func (i *importer) parseSecuritySchemes() {
+	if i.openapi.Components == nil {
+		return
+	}
+
+	schemes := i.openapi.Components.SecuritySchemes
+	for _, name := range sortedKeys(schemes) {
+		ptr := "/components/securitySchemes/" + escapePointer(name)
+		ss := schemes[name]
+		if ss.Ref != "" {
+			i.unsupported(ptr + "/$ref")
+			continue
+		}
+
+		summary, desc := newDescription(ss.Description)
+		s := &ast.Security{Name: newAttribute(name), Summary: summary, Description: desc}
+		switch ss.Type {
+		case SecurityTypeAPIKey:
+			s.Type = newAttribute(ast.SecurityTypeAPIKey)
+			s.Key = newAttribute(ss.Name)
+			s.In = newAttribute(ss.IN)
+		case SecurityTypeHTTP:
+			s.Type = newAttribute(ast.SecurityTypeHTTP)
+			s.Scheme = newAttribute(ss.Scheme)
+			s.BearerFormat = newAttribute(ss.BearerFormat)
+		case SecurityTypeOAuth2:
+			s.Type = newAttribute(ast.SecurityTypeOAuth2)
+			if ss.Flows != nil {
+				s.Flows = newOAuthFlows(ss.Flows)
+			}
+		case SecurityTypeOpenIDConnect:
+			s.Type = newAttribute(ast.SecurityTypeOpenIDConnect)
+			s.OpenIDConnectURL = newAttribute(ss.OpenIDConnectURL)
+		default:
+			i.unsupported(ptr + "/type")
+			continue
+		}
+
+		i.doc.Securities = append(i.doc.Securities, s)
+	}
+}
+
+func newOAuthFlows(flows *OAuthFlows) []*ast.OAuthFlow {
+	items := []struct {
+		typ  string
+		flow *OAuthFlow
+	}{
+		{typ: ast.OAuthFlowImplicit, flow: flows.Implicit},
+		{typ: ast.OAuthFlowPassword, flow: flows.Password},
+		{typ: ast.OAuthFlowClientCredentials, flow: flows.ClientCredentials},
+		{typ: ast.OAuthFlowAuthorizationCode, flow: flows.AuthorizationCode},
+	}
+
+	ret := make([]*ast.OAuthFlow, 0, len(items))
+	for _, item := range items {
+		if item.flow == nil {
+			continue
+		}
+
+		f := &ast.OAuthFlow{
+			Type:             newAttribute(item.typ),
+			AuthorizationURL: newAttribute(item.flow.AuthorizationURL),
+			TokenURL:         newAttribute(item.flow.TokenURL),
+			RefreshURL:       newAttribute(item.flow.RefreshURL),
+		}
+		for _, name := range sortedKeys(item.flow.Scopes) {
+			f.Scopes = append(f.Scopes, &ast.Scope{
+				Name:    newAttribute(name),
+				Summary: newAttribute(item.flow.Scopes[name]),
+			})
+		}
+		ret = append(ret, f)
+	}
+	return ret
+}
+
+// 将 SecurityRequirement 转换成 ast.SecurityValue
+//
+// apidoc 中 api.security 之间是或的关系，无法表示需要同时满足多个方案的情况，
+// 所以包含多个方案的 SecurityRequirement 会被忽略。
+func (i *importer) newSecurities(ptr string, requirements []*SecurityRequirement) []*ast.SecurityValue {
+	values := make([]*ast.SecurityValue, 0, len(requirements))
+	for index, req := range requirements {
+		if req == nil || len(*req) == 0 { // 空对象表示可以不需要验证
+			continue
+		}
+		if len(*req) > 1 {
+			i.unsupported(ptr + "/" + strconv.Itoa(index))
+			continue
+		}
+
+		for name, scopes := range *req {
+			v := &ast.SecurityValue{Name: newAttribute(name)}
+			for _, scope := range scopes {
+				v.Scopes = append(v.Scopes, newElement(scope))
+			}
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func (i *importer) parsePaths() {
+	paths := make([]string, 0, len(i.openapi.Paths))
+	for path := range i.openapi.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		item := i.openapi.Paths[path]
+		ptr := "/paths/" + escapePointer(path)
+
+		if item.Ref != "" {
+			i.unsupported(ptr + "/$ref")
+			continue
+		}
+
+		for _, m := range pathItemMethods {
+			o := m.operation(item)
+			if o == nil {
+				continue
+			}
+
+			optr := ptr + "/" + strings.ToLower(m.method)
+			if m.method == http.MethodTrace { // apidoc 不支持 TRACE
+				i.unsupported(optr)
+				continue
+			}
+
+			if api := i.newAPI(optr, m.method, path, item, o); api != nil {
+				i.doc.APIs = append(i.doc.APIs, api)
+			}
+		}
+	}
+}
+
+func (i *importer) newAPI(ptr, method, path string, item *PathItem, o *Operation) *ast.API {
+	api := &ast.API{
+		Method:      &ast.MethodAttribute{Value: xmlenc.String{Value: method}},
+		ID:          newAttribute(o.OperationID),
+		Path:        &ast.Path{Path: newAttribute(path)},
+		Summary:     newAttribute(o.Summary),
+		Description: newRichtext(o.Description),
+	}
+	if api.Summary == nil {
+		api.Summary = newAttribute(item.Summary)
+	}
+	if api.Description == nil {
+		api.Description = newRichtext(item.Description)
+	}
+
+	if o.Deprecated {
+		api.Deprecated = i.deprecated(ptr + "/deprecated")
+	}
+	if o.ExternalDocs != nil {
+		i.unsupported(ptr + "/externalDocs")
+	}
+	if o.Security != nil { // 空数组表示取消全局的 security
+		api.Securities = i.newSecurities(ptr+"/security", o.Security)
+	} else {
+		api.Securities = i.security
+	}
+
+	for _, tag := range o.Tags {
+		i.addTag(tag, "")
+		api.Tags = append(api.Tags, &ast.TagValue{Content: ast.Content{Value: tag}})
+	}
+
+	servers, sptr := o.Servers, ptr+"/servers/"
+	if len(servers) == 0 {
+		servers, sptr = item.Servers, strings.TrimSuffix(ptr, "/"+strings.ToLower(method))+"/servers/"
+	}
+	for index, srv := range servers {
+		name := i.addServer(srv, sptr+strconv.Itoa(index))
+		api.Servers = append(api.Servers, &ast.ServerValue{Content: ast.Content{Value: name}})
+	}
+
+	i.parseParameters(ptr, item, o, api)
+
+	if o.RequestBody != nil {
+		api.Requests = i.newRequests(ptr+"/requestBody", o.RequestBody)
+	}
+	api.Responses = i.newResponses(ptr+"/responses", o.Responses)
+
+	if len(api.Responses) == 0 {
+		i.unsupported(ptr + "/responses")
+		return nil
+	}
+
+	api.Callback = i.newCallback(ptr+"/callbacks", o.Callbacks)
+
+	return api
+}
+
+// 合并 PathItem 和 Operation 中的参数，Operation 中的同名参数会覆盖 PathItem 中的参数。
+func (i *importer) parseParameters(ptr string, item *PathItem, o *Operation, api *ast.API) {
+	type param struct {
+		ptr string
+		p   *Parameter
+	}
+
+	params := make([]*param, 0, len(item.Parameters)+len(o.Parameters))
+	iptr := strings.TrimSuffix(ptr, "/"+strings.ToLower(api.Method.V())) + "/parameters/"
+	for index, p := range item.Parameters {
+		pp := iptr + strconv.Itoa(index)
+		if p, pp = i.resolveParameter(pp, p); p != nil {
+			params = append(params, &param{ptr: pp, p: p})
+		}
+	}
+
+LOOP:
+	for index, p := range o.Parameters {
+		pp := ptr + "/parameters/" + strconv.Itoa(index)
+		if p, pp = i.resolveParameter(pp, p); p == nil {
+			continue
+		}
+
+		for _, exists := range params {
+			if exists.p.Name == p.Name && exists.p.IN == p.IN {
+				exists.p, exists.ptr = p, pp
+				continue LOOP
+			}
+		}
+		params = append(params, &param{ptr: pp, p: p})
+	}
+
+	for _, item := range params {
+		p := item.p
+		if p.Schema == nil {
+			i.unsupported(item.ptr)
+			continue
+		}
+
+		if p.Example != "" || len(p.Examples) > 0 {
+			i.unsupported(item.ptr + "/example")
+		}
+
+		param := i.newParam(item.ptr+"/schema", p.Name, p.Description, p.Schema, !p.Required)
+		if param == nil {
+			continue
+		}
+		if p.Deprecated && param.Deprecated == nil {
+			param.Deprecated = i.deprecated(item.ptr + "/deprecated")
+		}
+
+		switch p.IN {
+		case ParameterINPath:
+			api.Path.Params = append(api.Path.Params, param)
+		case ParameterINQuery:
+			if p.Style.Style == StyleForm && !p.Explode {
+				param.ArrayStyle = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+			}
+			api.Path.Queries = append(api.Path.Queries, param)
+		case ParameterINHeader:
+			api.Headers = append(api.Headers, param)
+		case ParameterINCookie:
+			api.Cookies = append(api.Cookies, param)
+		default:
+			i.unsupported(item.ptr + "/in")
+		}
+	}
+}
+
+func (i *importer) resolveParameter(ptr string, p *Parameter) (*Parameter, string) {
+	if p.Ref == "" {
+		return p, ptr
+	}
+
+	var params map[string]*Parameter
+	if i.openapi.Components != nil {
+		params = i.openapi.Components.Parameters
+	}
+	return resolve(i, ptr, p.Ref, "parameters", params)
+}
+
+func (i *importer) newRequests(ptr string, body *RequestBody) []*ast.Request {
+	if body.Ref != "" {
+		var bodies map[string]*RequestBody
+		if i.openapi.Components != nil {
+			bodies = i.openapi.Components.RequestBodies
+		}
+		if body, ptr = resolve(i, ptr, body.Ref, "requestBodies", bodies); body == nil {
+			return nil
+		}
+	}
+
+	requests := make([]*ast.Request, 0, len(body.Content))
+	for _, mimetype := range sortedKeys(body.Content) {
+		mptr := ptr + "/content/" + escapePointer(mimetype)
+		req := i.newRequest(mptr, mimetype, body.Content[mimetype])
+		if req == nil {
+			continue
+		}
+
+		if req.Summary == nil && req.Description == nil {
+			req.Summary, req.Description = newDescription(body.Description)
+		}
+		requests = append(requests, req)
+	}
+
+	return requests
+}
+
+func (i *importer) newResponses(ptr string, responses map[string]*Response) []*ast.Request {
+	ret := make([]*ast.Request, 0, len(responses))
+
+	for _, key := range sortedKeys(responses) {
+		rptr := ptr + "/" + escapePointer(key)
+
+		status, err := strconv.Atoi(key)
+		if err != nil { // default 和 2XX 等格式无法转换
+			i.unsupported(rptr)
+			continue
+		}
+
+		resp := responses[key]
+		if resp.Ref != "" {
+			var items map[string]*Response
+			if i.openapi.Components != nil {
+				items = i.openapi.Components.Responses
+			}
+			if resp, rptr = resolve(i, rptr, resp.Ref, "responses", items); resp == nil {
+				continue
+			}
+		}
+
+		if len(resp.Links) > 0 {
+			i.unsupported(rptr + "/links")
+		}
+
+		headers := make([]*ast.Param, 0, len(resp.Headers))
+		for _, name := range sortedKeys(resp.Headers) {
+			if p := i.newHeader(rptr+"/headers/"+escapePointer(name), name, resp.Headers[name]); p != nil {
+				headers = append(headers, p)
+			}
+		}
+
+		summary, desc := newDescription(resp.Description)
+
+		if len(resp.Content) == 0 {
+			ret = append(ret, &ast.Request{
+				Status:      &ast.StatusAttribute{Value: ast.Number{Int: status}},
+				Summary:     summary,
+				Description: desc,
+				Headers:     headers,
+			})
+			continue
+		}
+
+		for _, mimetype := range sortedKeys(resp.Content) {
+			req := i.newRequest(rptr+"/content/"+escapePointer(mimetype), mimetype, resp.Content[mimetype])
+			if req == nil {
+				continue
+			}
+			req.Status = &ast.StatusAttribute{Value: ast.Number{Int: status}}
+			req.Headers = headers
+			if req.Summary == nil && req.Description == nil {
+				req.Summary, req.Description = summary, desc
+			}
+			ret = append(ret, req)
+		}
+	}
+
+	return ret
+}
+
+func (i *importer) newHeader(ptr, name string, h *Header) *ast.Param {
+	if h.Ref != "" {
+		var headers map[string]*Header
+		if i.openapi.Components != nil {
+			headers = i.openapi.Components.Headers
+		}
+		if h, ptr = resolve(i, ptr, h.Ref, "headers", headers); h == nil {
+			return nil
+		}
+	}
+
+	if h.Schema == nil {
+		i.unsupported(ptr)
+		return nil
+	}
+	return i.newParam(ptr+"/schema", name, h.Description, h.Schema, !h.Required)
+}
+
+func (i *importer) newRequest(ptr, mimetype string, mt *MediaType) *ast.Request {
+	i.addMimetype(mimetype)
+
+	req := &ast.Request{Mimetype: newAttribute(mimetype)}
+	if mt.Schema != nil {
+		p := i.newParam(ptr+"/schema", "", "", mt.Schema, false)
+		if p == nil {
+			return nil
+		}
+
+		req.XML = p.XML
+		req.Type = p.Type
+		req.Array = p.Array
+		req.Items = p.Items
+		req.Enums = p.Enums
+		req.Composite = p.Composite
+		req.Deprecated = p.Deprecated
+		req.Summary = p.Summary
+		req.Description = p.Description
+		if xml := mt.Schema.XML; xml != nil && xml.Name != "" {
+			req.Name = newAttribute(xml.Name)
+		}
+	}
+
+	if len(mt.Encoding) > 0 {
+		i.unsupported(ptr + "/encoding")
+	}
+
+	if mt.Example != "" {
+		req.Examples = append(req.Examples, &ast.Example{
+			Mimetype: newAttribute(mimetype),
+			Content:  &ast.ExampleValue{Value: xmlenc.String{Value: string(mt.Example)}},
+		})
+	}
+
+	for _, name := range sortedKeys(mt.Examples) {
+		eptr := ptr + "/examples/" + escapePointer(name)
+		exp := mt.Examples[name]
+		if exp.Ref != "" {
+			var examples map[string]*Example
+			if i.openapi.Components != nil {
+				examples = i.openapi.Components.Examples
+			}
+			if exp, eptr = resolve(i, eptr, exp.Ref, "examples", examples); exp == nil {
+				continue
+			}
+		}
+
+		if exp.ExternalValue != "" {
+			i.unsupported(eptr + "/externalValue")
+			continue
+		}
+
+		summary := exp.Summary
+		if summary == "" {
+			summary = name
+		}
+		req.Examples = append(req.Examples, &ast.Example{
+			Mimetype: newAttribute(mimetype),
+			Summary:  newAttribute(summary),
+			Content:  &ast.ExampleValue{Value: xmlenc.String{Value: string(exp.Value)}},
+		})
+	}
+
+	return req
+}
+
+// 将 callbacks 转换成 ast.Callback
+//
+// apidoc 中每个 API 仅支持一个回调，且回调中仅支持一个请求方法，
+// 多余的内容会以警告的形式输出。
+func (i *importer) newCallback(ptr string, callbacks map[string]*Callback) *ast.Callback {
+	var callback *ast.Callback
+
+	for _, name := range sortedKeys(callbacks) {
+		cptr := ptr + "/" + escapePointer(name)
+		exps := *callbacks[name]
+
+		for _, exp := range sortedKeys(exps) {
+			eptr := cptr + "/" + escapePointer(exp)
+			item := exps[exp]
+
+			for _, m := range pathItemMethods {
+				o := m.operation(item)
+				if o == nil {
+					continue
+				}
+
+				optr := eptr + "/" + strings.ToLower(m.method)
+				if callback != nil || m.method == http.MethodTrace {
+					i.unsupported(optr)
+					continue
+				}
+
+				callback = &ast.Callback{
+					Method:      &ast.MethodAttribute{Value: xmlenc.String{Value: m.method}},
+					Summary:     newAttribute(o.Summary),
+					Description: newRichtext(o.Description),
+				}
+				if o.Deprecated {
+					callback.Deprecated = i.deprecated(optr + "/deprecated")
+				}
+
+				if o.RequestBody != nil {
+					callback.Requests = i.newRequests(optr+"/requestBody", o.RequestBody)
+				}
+				if len(callback.Requests) == 0 { // 回调至少需要一个请求
+					callback.Requests = []*ast.Request{{}}
+				}
+				callback.Responses = i.newResponses(optr+"/responses", o.Responses)
+
+				if len(o.Parameters) > 0 {
+					i.unsupported(optr + "/parameters")
+				}
+			}
+		}
+	}
+
+	return callback
+}
+
+// 将 schema 转换成 ast.Param
+//
+// name 为参数名称；desc 为参数的描述，若为空，则采用 schema 中的描述内容。
+// 如果无法转换则返回 nil。
+func (i *importer) newParam(ptr, name, desc string, s *Schema, optional bool) *ast.Param {
+	if s.Ref != "" {
+		var schemas map[string]*Schema
+		if i.openapi.Components != nil {
+			schemas = i.openapi.Components.Schemas
+		}
+
+		if !i.enterRef(ptr, s.Ref) {
+			return nil
+		}
+		defer i.leaveRef()
+
+		ref := s.Ref
+		if s, ptr = resolve(i, ptr, ref, "schemas", schemas); s == nil {
+			return nil
+		}
+	}
+
+	p := &ast.Param{Name: newAttribute(name)}
+
+	if s.Type == TypeArray {
+		p.Array = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+		if desc == "" {
+			desc = s.Description
+		}
+		if s.Items == nil {
+			i.unsupported(ptr + "/items")
+			return nil
+		}
+
+		items := i.newParam(ptr+"/items", name, desc, s.Items, optional)
+		if items == nil {
+			return nil
+		}
+		if items.Array.V() { // 不支持多维数组
+			i.unsupported(ptr + "/items")
+			return nil
+		}
+
+		items.Array = p.Array
+		i.parseAccess(ptr, items, s)
+		if s.MinItems > 0 {
+			items.MinItems = newNumberAttribute(float64(s.MinItems))
+		}
+		if s.MaxItems != nil {
+			items.MaxItems = newNumberAttribute(float64(*s.MaxItems))
+		}
+		if s.UniqueItems {
+			items.UniqueItems = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+		}
+		if s.XML != nil && s.XML.Wrapped {
+			xmlName := s.XML.Name
+			if xmlName == "" {
+				xmlName = name
+			}
+			items.XMLWrapped = newAttribute(xmlName)
+		}
+		return items
+	}
+
+	if len(s.AllOf) > 0 {
+		if s = i.mergeAllOf(ptr, s); s == nil {
+			return nil
+		}
+	}
+
+	i.checkSchema(ptr, s)
+
+	p.Type = &ast.TypeAttribute{Value: xmlenc.String{Value: i.schemaType(ptr, s)}}
+	i.parseConstraints(ptr, p, s)
+
+	if desc == "" {
+		desc = s.Description
+	}
+	if desc == "" {
+		desc = s.Title
+	}
+	p.Summary, p.Description = newDescription(desc)
+	if p.Summary == nil && p.Description == nil && name != "" { // 参数的描述信息不能为空
+		p.Summary = newAttribute(name)
+	}
+
+	if optional {
+		p.Optional = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+	}
+	i.parseAccess(ptr, p, s)
+	if s.Deprecated {
+		p.Deprecated = i.deprecated(ptr + "/deprecated")
+	}
+	if s.Default != nil {
+		p.Default = newAttribute(fmt.Sprint(s.Default))
+	}
+
+	for _, e := range s.Enum {
+		v := fmt.Sprint(e)
+		p.Enums = append(p.Enums, &ast.Enum{Value: newAttribute(v), Summary: newAttribute(v)})
+	}
+
+	if s.XML != nil {
+		if s.XML.Attribute {
+			p.XMLAttr = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+		}
+		if s.XML.Namespace != "" || s.XML.Prefix != "" {
+			i.unsupported(ptr + "/xml")
+		}
+	}
+
+	if p.Type.V() != ast.TypeObject {
+		return p
+	}
+
+	if isMapSchema(s) {
+		return i.parseMap(ptr, p, s)
+	}
+
+	required := make(map[string]struct{}, len(s.Required))
+	for _, key := range s.Required {
+		required[key] = struct{}{}
+	}
+	for _, key := range sortedKeys(s.Properties) {
+		_, found := required[key]
+		item := i.newParam(ptr+"/properties/"+escapePointer(key), key, "", s.Properties[key], !found)
+		if item != nil {
+			p.Items = append(p.Items, item)
+		}
+	}
+
+	if len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+		i.parseVariants(ptr, p, s)
+	}
+
+	if len(p.Items) == 0 && len(p.Variants) == 0 { // 对象至少需要一个子元素
+		i.unsupported(ptr)
+		return nil
+	}
+
+	return p
+}
+
+// 将 allOf 中的各个对象合并成一个对象
+func (i *importer) mergeAllOf(ptr string, s *Schema) *Schema {
+	merged := *s
+	merged.AllOf = nil
+	merged.Properties = make(map[string]*Schema, len(s.Properties))
+	for k, v := range s.Properties {
+		merged.Properties[k] = v
+	}
+	merged.Required = append([]string{}, s.Required...)
+
+	for index, item := range s.AllOf {
+		iptr := ptr + "/allOf/" + strconv.Itoa(index)
+		if item = i.resolveAllOfItem(iptr, item); item == nil {
+			return nil
+		}
+
+		if item.Type != "" && item.Type != "object" {
+			i.unsupported(iptr)
+			return nil
+		}
+
+		for k, v := range item.Properties {
+			merged.Properties[k] = v
+		}
+		merged.Required = append(merged.Required, item.Required...)
+		if merged.Description == "" {
+			merged.Description = item.Description
+		}
+	}
+
+	merged.Type = "object"
+	return &merged
+}
+
+// 展开 allOf 中的某一项，包括其中的 $ref 以及嵌套的 allOf。
+func (i *importer) resolveAllOfItem(ptr string, item *Schema) *Schema {
+	if item.Ref != "" {
+		if !i.enterRef(ptr, item.Ref) {
+			return nil
+		}
+		defer i.leaveRef()
+
+		var schemas map[string]*Schema
+		if i.openapi.Components != nil {
+			schemas = i.openapi.Components.Schemas
+		}
+		if item, _ = resolve(i, ptr, item.Ref, "schemas", schemas); item == nil {
+			return nil
+		}
+	}
+
+	if len(item.AllOf) > 0 {
+		return i.mergeAllOf(ptr, item)
+	}
+	return item
+}
+
+// 将 ref 标记为正在展开
+//
+// 如果 ref 已经在展开中，表示存在循环引用，会输出警告并返回 false。
+// 返回 true 时，调用方需要在展开完成之后调用 leaveRef。
+func (i *importer) enterRef(ptr, ref string) bool {
+	for _, r := range i.refs {
+		if r == ref {
+			i.h.Warning(core.Location{URI: i.uri}.NewError(locale.ErrCircularReference).WithField(ptr + "/$ref"))
+			return false
+		}
+	}
+
+	i.refs = append(i.refs, ref)
+	return true
+}
+
+func (i *importer) leaveRef() { i.refs = i.refs[:len(i.refs)-1] }
+
+// 将 oneOf 或是 anyOf 转换为 p 的变体
+//
+// 仅支持由对象组成的变体，同时指定了 oneOf 和 anyOf 时，anyOf 会被忽略。
+// 变体对应 discriminator 的值依次从 mapping、引用的名称以及变体中该属性唯一的枚举值中获取，
+// 如果无法确定所有变体的值，则忽略 discriminator。
+func (i *importer) parseVariants(ptr string, p *ast.Param, s *Schema) {
+	compose, key, variants := ast.ComposeOneOf, "oneOf", s.OneOf
+	if len(variants) == 0 {
+		compose, key, variants = ast.ComposeAnyOf, "anyOf", s.AnyOf
+	} else if len(s.AnyOf) > 0 {
+		i.unsupported(ptr + "/anyOf")
+	}
+
+	var disc string
+	mapping := make(map[string]string) // 引用 → 值
+	if s.Discriminator != nil {
+		disc = s.Discriminator.PropertyName
+		for _, value := range sortedKeys(s.Discriminator.Mapping) {
+			mapping[s.Discriminator.Mapping[value]] = value
+		}
+	}
+
+	values := make(map[string]struct{}, len(variants))
+	validDisc := disc != ""
+	for index, item := range variants {
+		vptr := ptr + "/" + key + "/" + strconv.Itoa(index)
+
+		value := mapping[item.Ref]
+		if value == "" && strings.HasPrefix(item.Ref, schemaRefPrefix) {
+			value = strings.TrimPrefix(item.Ref, schemaRefPrefix)
+		}
+
+		param := i.newParam(vptr, "", "", item, false)
+		if param == nil {
+			continue
+		}
+		if param.Type.V() != ast.TypeObject || param.Array.V() || len(param.Variants) > 0 {
+			i.unsupported(vptr)
+			continue
+		}
+
+		v := &ast.Variant{Items: param.Items, Summary: param.Summary, Description: param.Description}
+		p.Variants = append(p.Variants, v)
+
+		if disc == "" {
+			continue
+		}
+
+		// discriminator 对应的属性需要在父元素中声明
+		for _, pp := range param.Items {
+			if pp.Name.V() != disc {
+				continue
+			}
+
+			if value == "" && len(pp.Enums) == 1 {
+				value = pp.Enums[0].Value.V()
+			}
+			if findParam(p.Items, disc) == nil {
+				p.Items = append(p.Items, &ast.Param{
+					Name:        pp.Name,
+					Type:        pp.Type,
+					Summary:     pp.Summary,
+					Description: pp.Description,
+				})
+			}
+		}
+
+		if _, found := values[value]; found || value == "" {
+			validDisc = false
+		}
+		values[value] = struct{}{}
+		v.Value = newAttribute(value)
+	}
+
+	if len(p.Variants) == 0 {
+		return
+	}
+	p.Compose = newAttribute(compose)
+
+	if disc == "" {
+		return
+	}
+	if item := findParam(p.Items, disc); validDisc && item != nil && item.Type.V() == ast.TypeString && !item.Array.V() {
+		p.Discriminator = newAttribute(disc)
+		return
+	}
+
+	i.unsupported(ptr + "/discriminator")
+	for _, v := range p.Variants {
+		v.Value = nil
+	}
+}
+
+// 是否为仅由 additionalProperties 描述的字典
+//
+// additionalProperties 为 false 时，AdditionalProperties 为空，不会被当作字典。
+func isMapSchema(s *Schema) bool {
+	return s.AdditionalProperties != nil && len(s.Properties) == 0 && len(s.OneOf) == 0 && len(s.AnyOf) == 0
+}
+
+// 将 additionalProperties 转换为 map 类型
+//
+// 值为对象时，其子元素作为 p 的子元素；值为基本类型时，p 的类型为 map.xx。
+// 值不能是数组、复合类型或是字典，其枚举值和约束条件也会被忽略。
+func (i *importer) parseMap(ptr string, p *ast.Param, s *Schema) *ast.Param {
+	ptr += "/additionalProperties"
+	v := s.AdditionalProperties
+	if v.Ref == "" && v.Type == "" && len(v.Properties) == 0 && len(v.AllOf) == 0 && len(v.OneOf) == 0 && len(v.AnyOf) == 0 {
+		i.unsupported(ptr) // 任意类型的值
+		return nil
+	}
+
+	value := i.newParam(ptr, p.Name.V(), "", v, false)
+	if value == nil {
+		return nil
+	}
+
+	primitive, _ := ast.ParseType(value.Type.V())
+	if value.Array.V() || len(value.Variants) > 0 || primitive == ast.TypeMap {
+		i.unsupported(ptr)
+		return nil
+	}
+
+	if primitive == ast.TypeObject {
+		p.Type = &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeMap}}
+		p.Items = value.Items
+		return p
+	}
+
+	if len(value.Enums) > 0 || value.Min != nil || value.Max != nil ||
+		value.MinLength != nil || value.MaxLength != nil || value.Pattern != nil {
+		i.unsupported(ptr)
+	}
+	p.Type = &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeMap + "." + value.Type.V()}}
+	return p
+}
+
+func findParam(items []*ast.Param, name string) *ast.Param {
+	for _, item := range items {
+		if item.Name.V() == name {
+			return item
+		}
+	}
+	return nil
+}
+
+// 检测 schema 中无法转换的字段
+func (i *importer) checkSchema(ptr string, s *Schema) {
+	unsupported := map[string]bool{
+		"not":                  s.Not != nil,
+		"additionalProperties": s.AdditionalProperties != nil && !isMapSchema(s),
+		"patternProperties":    len(s.PatternProperties) > 0,
+		"discriminator":        s.Discriminator != nil && len(s.OneOf) == 0 && len(s.AnyOf) == 0,
+		"multipleOf":           s.MultipleOf != 0,
+		"externalDocs":         s.ExternalDocs != nil,
+	}
+
+	for _, key := range sortedKeys(unsupported) {
+		if unsupported[key] {
+			i.unsupported(ptr + "/" + key)
+		}
+	}
+}
+
+// 将 schema 中的 nullable、readOnly 和 writeOnly 转换到 p 中
+//
+// readOnly 和 writeOnly 不能同时存在，此时 writeOnly 会被忽略。
+func (i *importer) parseAccess(ptr string, p *ast.Param, s *Schema) {
+	if s.Nullable {
+		p.Nullable = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+	}
+
+	if s.ReadOnly {
+		p.ReadOnly = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+		if s.WriteOnly {
+			i.unsupported(ptr + "/writeOnly")
+		}
+	} else if s.WriteOnly {
+		p.WriteOnly = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+	}
+}
+
+// 将 schema 中对值的约束转换到 p 中
+//
+// 与 p 的类型不相符的约束，以及无法编译的正则表达式，都会作为无法转换的内容输出。
+func (i *importer) parseConstraints(ptr string, p *ast.Param, s *Schema) {
+	primitive, _ := ast.ParseType(p.Type.V())
+
+	unsupported := map[string]bool{ // 数组的约束由 newParam 处理，出现在此处的均为非数组。
+		"minItems":         s.MinItems > 0,
+		"maxItems":         s.MaxItems != nil,
+		"uniqueItems":      s.UniqueItems,
+		"exclusiveMinimum": s.ExclusiveMinimum && s.Minimum == nil,
+		"exclusiveMaximum": s.ExclusiveMaximum && s.Maximum == nil,
+	}
+
+	if primitive == ast.TypeNumber {
+		if s.Minimum != nil {
+			p.Min = newNumberAttribute(*s.Minimum)
+			if s.ExclusiveMinimum {
+				p.ExclusiveMin = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+			}
+		}
+		if s.Maximum != nil {
+			p.Max = newNumberAttribute(*s.Maximum)
+			if s.ExclusiveMaximum {
+				p.ExclusiveMax = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+			}
+		}
+	} else {
+		unsupported["minimum"] = s.Minimum != nil
+		unsupported["maximum"] = s.Maximum != nil
+	}
+
+	if primitive == ast.TypeString {
+		if s.MinLength > 0 {
+			p.MinLength = newNumberAttribute(float64(s.MinLength))
+		}
+		if s.MaxLength != nil {
+			p.MaxLength = newNumberAttribute(float64(*s.MaxLength))
+		}
+		if s.Pattern != "" {
+			if _, err := regexp.Compile(s.Pattern); err != nil {
+				unsupported["pattern"] = true
+			} else {
+				p.Pattern = newAttribute(s.Pattern)
+			}
+		}
+	} else {
+		unsupported["minLength"] = s.MinLength > 0
+		unsupported["maxLength"] = s.MaxLength != nil
+		unsupported["pattern"] = s.Pattern != ""
+	}
+
+	for _, key := range sortedKeys(unsupported) {
+		if unsupported[key] {
+			i.unsupported(ptr + "/" + key)
+		}
+	}
+}
+
+func newNumberAttribute(v float64) *ast.NumberAttribute {
+	if v == math.Trunc(v) {
+		return &ast.NumberAttribute{Value: ast.Number{Int: int(v)}}
+	}
+	return &ast.NumberAttribute{Value: ast.Number{Float: v, IsFloat: true}}
+}
+
+// 获取 schema 对应的 ast 中的类型
+func (i *importer) schemaType(ptr string, s *Schema) string {
+	switch s.Type {
+	case TypeInt, TypeLong:
+		return ast.TypeInt
+	case "number", TypeDouble:
+		if s.Format == TypeFloat || s.Format == TypeDouble {
+			return ast.TypeFloat
+		}
+		return ast.TypeNumber
+	case TypeFloat:
+		return ast.TypeFloat
+	case "boolean", TypeBool:
+		return ast.TypeBool
+	case TypeString, TypePassword:
+		switch s.Format {
+		case "email":
+			return ast.TypeEmail
+		case "uri", "url":
+			return ast.TypeURL
+		case "date":
+			return ast.TypeDate
+		case "time":
+			return ast.TypeTime
+		case "date-time":
+			return ast.TypeDateTime
+		case "binary", "byte":
+			return ast.TypeFile
+		}
+		return ast.TypeString
+	case "object":
+		return ast.TypeObject
+	case "":
+		if len(s.Properties) > 0 || len(s.OneOf) > 0 || len(s.AnyOf) > 0 || s.AdditionalProperties != nil {
+			return ast.TypeObject
+		}
+	}
+
+	i.unsupported(ptr + "/type")
+	return ast.TypeString
+}
+
+// 弃用的版本号，openapi 中仅有是否弃用的标记，所以采用文档的版本号作为弃用的版本。
+func (i *importer) deprecated(ptr string) *ast.VersionAttribute {
+	if i.doc.Version == nil {
+		i.unsupported(ptr)
+		return nil
+	}
+
+	return &ast.VersionAttribute{Value: xmlenc.String{Value: i.doc.Version.V()}}
+}
+
+// 从 components 中查找 ref 指向的对象
+//
+// 返回找到的对象以及该对象在文档中的 JSON pointer，找不到时返回 nil。
+func resolve[T any](i *importer, ptr, ref, kind string, items map[string]*T) (*T, string) {
+	prefix := "#/components/" + kind + "/"
+	if !strings.HasPrefix(ref, prefix) {
+		i.unsupported(ptr + "/$ref")
+		return nil, ptr
+	}
+
+	name := strings.TrimPrefix(ref, prefix)
+	name = strings.NewReplacer("~1", "/", "~0", "~").Replace(name)
+	if item, found := items[name]; found && item != nil {
+		return item, strings.TrimPrefix(ref, "#")
+	}
+
+	i.h.Warning(core.Location{URI: i.uri}.NewError(locale.ErrNotFound).WithField(ptr + "/$ref"))
+	return nil, ptr
+}
+
+// 将 v 转换为 JSON pointer 中的合法字符
+func escapePointer(v string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(v)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func newAttribute(v string) *ast.Attribute {
+	if v == "" {
+		return nil
+	}
+	return &ast.Attribute{Value: xmlenc.String{Value: v}}
+}
+
+func newElement(v string) *ast.Element {
+	return &ast.Element{Content: ast.Content{Value: v}}
+}
+
+func newRichtext(v string) *ast.Richtext {
+	if v == "" {
+		return nil
+	}
+
+	return &ast.Richtext{
+		Type: &ast.Attribute{Value: xmlenc.String{Value: ast.RichtextTypeMarkdown}},
+		Text: &ast.CData{Value: xmlenc.String{Value: v}},
+	}
+}
+
+// 单行的内容作为 summary，多行的内容作为 description。
+func newDescription(v string) (*ast.Attribute, *ast.Richtext) {
+	v = strings.TrimSpace(v)
+	if strings.ContainsRune(v, '\n') {
+		return nil, newRichtext(v)
+	}
+	return newAttribute(v), nil
+}
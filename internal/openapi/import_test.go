@@ -0,0 +1,587 @@
+// SPDX-License-Identifier: MIT
+
+package openapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/issue9/assert/v3"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/core/messagetest"
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/locale"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+const importYAML = `openapi: 3.0.3
+info:
+  title: test
+  version: 1.0.0
+  description: desc
+  license:
+    name: MIT
+    url: https://opensource.org/licenses/MIT
+servers:
+  - url: https://{host}/v1
+    description: production
+    variables:
+      host:
+        default: example.com
+tags:
+  - name: user
+    description: users
+paths:
+  /users/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+    get:
+      tags: [user, admin]
+      operationId: getUser
+      summary: get user
+      parameters:
+        - name: fields
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+              enum: [id, name]
+        - $ref: '#/components/parameters/token'
+        - name: session
+          in: cookie
+          schema:
+            type: string
+      responses:
+        '200':
+          description: OK
+          headers:
+            X-Rate-Limit:
+              schema:
+                type: integer
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+              example:
+                id: 1
+                name: n1
+        '404':
+          $ref: '#/components/responses/NotFound'
+        default:
+          description: error
+    delete:
+      deprecated: true
+      responses:
+        '204':
+          description: no content
+      callbacks:
+        onDelete:
+          '{$request.query.callback}':
+            post:
+              requestBody:
+                content:
+                  application/json:
+                    schema:
+                      type: object
+                      properties:
+                        id:
+                          type: integer
+              responses:
+                '200':
+                  description: OK
+components:
+  parameters:
+    token:
+      name: token
+      in: header
+      required: true
+      description: access token
+      schema:
+        type: string
+  responses:
+    NotFound:
+      description: not found
+      content:
+        application/xml:
+          schema:
+            type: string
+  schemas:
+    User:
+      allOf:
+        - $ref: '#/components/schemas/Base'
+        - type: object
+          required: [name]
+          properties:
+            name:
+              type: string
+              maxLength: 10
+            email:
+              type: string
+              format: email
+            parent:
+              $ref: '#/components/schemas/User'
+    Base:
+      type: object
+      required: [id]
+      properties:
+        id:
+          type: integer
+          format: int64
+`
+
+func TestImport(t *testing.T) {
+	a := assert.New(t, false)
+
+	rslt := messagetest.NewMessageHandler()
+	doc, err := Import(rslt.Handler, "file:///openapi.yaml", []byte(importYAML))
+	rslt.Handler.Stop()
+	a.NotError(err).NotNil(doc)
+
+	a.Equal(doc.Title.Content.Value, "test").
+		Equal(doc.Version.V(), "1.0.0").
+		Equal(doc.License.URL.V(), "https://opensource.org/licenses/MIT").
+		Equal(doc.Description.V(), "desc")
+
+	a.Equal(1, len(doc.Servers)).
+		Equal(doc.Servers[0].URL.V(), "https://example.com/v1").
+		Equal(doc.Servers[0].Summary.V(), "production")
+
+	a.Equal(2, len(doc.Tags)).
+		Equal(doc.Tags[0].Title.V(), "users").
+		Equal(doc.Tags[1].Name.V(), "admin")
+
+	a.Equal(2, len(doc.Mimetypes)).
+		Equal(doc.Mimetypes[0].Content.Value, "application/json").
+		Equal(doc.Mimetypes[1].Content.Value, "application/xml")
+
+	a.Equal(2, len(doc.APIs))
+	get := doc.APIs[0]
+	a.Equal(get.Method.V(), http.MethodGet).
+		Equal(get.ID.V(), "getUser").
+		Equal(get.Path.Path.V(), "/users/{id}").
+		Equal(1, len(get.Path.Params)).
+		Equal(get.Path.Params[0].Type.V(), ast.TypeInt).
+		Equal(1, len(get.Path.Queries)).
+		True(get.Path.Queries[0].Array.V()).
+		Equal(2, len(get.Path.Queries[0].Enums)).
+		Equal(1, len(get.Headers)).
+		Equal(get.Headers[0].Name.V(), "token").
+		Equal(get.Headers[0].Summary.V(), "access token").
+		Equal(1, len(get.Cookies)).
+		Equal(get.Cookies[0].Name.V(), "session").
+		True(get.Cookies[0].Optional.V())
+
+	a.Equal(2, len(get.Responses))
+	resp := get.Responses[0]
+	a.Equal(resp.Status.V(), http.StatusOK).
+		Equal(resp.Type.V(), ast.TypeObject).
+		Equal(3, len(resp.Items)). // parent 为循环引用，被忽略
+		Equal(resp.Items[0].Name.V(), "email").
+		Equal(resp.Items[0].Type.V(), ast.TypeEmail).
+		True(resp.Items[0].Optional.V()).
+		Equal(resp.Items[1].Name.V(), "id").
+		False(resp.Items[1].Optional.V()).
+		Equal(resp.Items[2].Name.V(), "name").
+		Equal(resp.Items[2].MaxLength.IntValue(), 10).
+		Equal(1, len(resp.Headers)).
+		Equal(1, len(resp.Examples)).
+		Equal(resp.Examples[0].Content.Value.Value, "{\n\t\"id\": 1,\n\t\"name\": \"n1\"\n}")
+	resp = get.Responses[1]
+	a.Equal(resp.Status.V(), http.StatusNotFound).
+		Equal(resp.Mimetype.V(), "application/xml").
+		Equal(resp.Summary.V(), "not found")
+
+	del := doc.APIs[1]
+	a.Equal(del.Method.V(), http.MethodDelete).
+		Equal(del.Deprecated.V(), "1.0.0").
+		NotNil(del.Callback).
+		Equal(del.Callback.Method.V(), http.MethodPost).
+		Equal(1, len(del.Callback.Requests)).
+		Equal(1, len(del.Callback.Responses))
+
+	fields := make([]string, 0, len(rslt.Warns))
+	for _, w := range rslt.Warns {
+		err, ok := w.(*core.Error)
+		a.True(ok)
+		fields = append(fields, err.Field)
+	}
+	a.Contains(fields, "/paths/~1users~1{id}/get/responses/default").
+		NotContains(fields, "/paths/~1users~1{id}/get/parameters/2/in").
+		Contains(fields, "/components/schemas/User/properties/parent/$ref").
+		NotContains(fields, "/components/schemas/User/properties/name/maxLength")
+
+	// 转换后的内容是一个合法的文档
+	data, err := xmlenc.Encode("\t", doc, "", "")
+	a.NotError(err).NotEmpty(data)
+	rslt = messagetest.NewMessageHandler()
+	d := &ast.APIDoc{}
+	d.Parse(rslt.Handler, core.Block{Data: data, Location: core.Location{URI: "file:///apidoc.xml"}})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	// 无效的内容
+	rslt = messagetest.NewMessageHandler()
+	doc, err = Import(rslt.Handler, "file:///openapi.yaml", []byte("openapi: 2.0\n"))
+	rslt.Handler.Stop()
+	a.Error(err).Nil(doc)
+
+	rslt = messagetest.NewMessageHandler()
+	doc, err = Import(rslt.Handler, "file:///openapi.yaml", []byte("openapi: 3.0.0\n"))
+	rslt.Handler.Stop()
+	a.Error(err).Nil(doc)
+}
+
+func TestImporter_parseVariants(t *testing.T) {
+	a := assert.New(t, false)
+
+	const data = `openapi: 3.0.3
+info:
+  title: test
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                oneOf:
+                  - $ref: '#/components/schemas/cat'
+                  - $ref: '#/components/schemas/dog'
+                discriminator:
+                  propertyName: type
+                  mapping:
+                    kitty: '#/components/schemas/cat'
+        '201':
+          description: OK
+          content:
+            application/json:
+              schema:
+                anyOf:
+                  - $ref: '#/components/schemas/cat'
+                  - type: string
+                discriminator:
+                  propertyName: color
+components:
+  schemas:
+    cat:
+      type: object
+      required: [type]
+      properties:
+        type:
+          type: string
+        name:
+          type: string
+    dog:
+      type: object
+      properties:
+        type:
+          type: string
+        bark:
+          type: boolean
+`
+
+	rslt := messagetest.NewMessageHandler()
+	doc, err := Import(rslt.Handler, "file:///openapi.yaml", []byte(data))
+	rslt.Handler.Stop()
+	a.NotError(err).NotNil(doc)
+
+	a.Equal(2, len(rslt.Warns)) // 201 中的 string 变体，以及无法确定值的 discriminator
+	fields := make([]string, 0, len(rslt.Warns))
+	for _, w := range rslt.Warns {
+		err, ok := w.(*core.Error)
+		a.True(ok)
+		fields = append(fields, err.Field)
+	}
+	a.Equal(fields, []string{
+		"/paths/~1pets/get/responses/201/content/application~1json/schema/anyOf/1",
+		"/paths/~1pets/get/responses/201/content/application~1json/schema/discriminator",
+	})
+
+	responses := doc.APIs[0].Responses
+	a.Equal(2, len(responses))
+
+	pet := responses[0]
+	a.Equal(pet.Type.V(), ast.TypeObject).
+		Equal(pet.Discriminator.V(), "type").
+		Equal(pet.ComposeType(), ast.ComposeOneOf).
+		Equal(1, len(pet.Items)).
+		Equal(pet.Items[0].Name.V(), "type").
+		Equal(2, len(pet.Variants)).
+		Equal(pet.Variants[0].Value.V(), "kitty").
+		Equal(pet.Variants[1].Value.V(), "dog").
+		Equal(2, len(pet.Variants[1].Items))
+
+	any := responses[1]
+	a.Equal(any.ComposeType(), ast.ComposeAnyOf).
+		Nil(any.Discriminator).
+		Equal(1, len(any.Variants)).
+		Nil(any.Variants[0].Value)
+}
+
+func TestImporter_mergeAllOf_circular(t *testing.T) {
+	a := assert.New(t, false)
+
+	const data = `openapi: 3.0.3
+info:
+  title: test
+  version: 1.0.0
+paths:
+  /nodes:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/node'
+  /items:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/a'
+components:
+  schemas:
+    node:
+      allOf:
+        - $ref: '#/components/schemas/node'
+        - type: object
+          properties:
+            id:
+              type: integer
+    a:
+      allOf:
+        - $ref: '#/components/schemas/b'
+    b:
+      allOf:
+        - $ref: '#/components/schemas/a'
+`
+
+	rslt := messagetest.NewMessageHandler()
+	a.NotPanic(func() {
+		doc, err := Import(rslt.Handler, "file:///openapi.yaml", []byte(data))
+		a.NotError(err).NotNil(doc)
+	})
+	rslt.Handler.Stop()
+
+	circular := 0
+	for _, w := range rslt.Warns {
+		if err, ok := w.(*core.Error); ok && err.Err.Error() == locale.NewError(locale.ErrCircularReference).Error() {
+			circular++
+		}
+	}
+	a.Equal(circular, 2, "%v", rslt.Warns)
+}
+
+func TestImporter_parseMap(t *testing.T) {
+	a := assert.New(t, false)
+
+	const data = `openapi: 3.0.3
+info:
+  title: test
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  closed:
+                    type: object
+                    additionalProperties: false
+                    properties:
+                      id:
+                        type: string
+                  scores:
+                    type: object
+                    additionalProperties:
+                      type: integer
+                  pets:
+                    additionalProperties:
+                      $ref: '#/components/schemas/pet'
+                  tags:
+                    type: object
+                    additionalProperties:
+                      type: array
+                      items:
+                        type: string
+                  mixed:
+                    type: object
+                    properties:
+                      id:
+                        type: string
+                    additionalProperties:
+                      type: string
+components:
+  schemas:
+    pet:
+      type: object
+      properties:
+        name:
+          type: string
+`
+
+	rslt := messagetest.NewMessageHandler()
+	doc, err := Import(rslt.Handler, "file:///openapi.yaml", []byte(data))
+	rslt.Handler.Stop()
+	a.NotError(err).NotNil(doc)
+
+	fields := make([]string, 0, len(rslt.Warns))
+	for _, w := range rslt.Warns {
+		err, ok := w.(*core.Error)
+		a.True(ok)
+		fields = append(fields, err.Field)
+	}
+	a.Equal(fields, []string{
+		"/paths/~1pets/get/responses/200/content/application~1json/schema/properties/mixed/additionalProperties",
+		"/paths/~1pets/get/responses/200/content/application~1json/schema/properties/tags/additionalProperties",
+	})
+
+	items := doc.APIs[0].Responses[0].Items
+	a.Equal(4, len(items))
+
+	// additionalProperties 为 false 的依然是普通对象
+	a.Equal(items[0].Name.V(), "closed").
+		Equal(items[0].Type.V(), ast.TypeObject).
+		Equal(1, len(items[0].Items))
+
+	a.Equal(items[1].Name.V(), "mixed").
+		Equal(items[1].Type.V(), ast.TypeObject).
+		Equal(1, len(items[1].Items))
+
+	a.Equal(items[2].Name.V(), "pets").
+		Equal(items[2].Type.V(), ast.TypeMap).
+		Equal(1, len(items[2].Items)).
+		Equal(items[2].Items[0].Name.V(), "name")
+
+	a.Equal(items[3].Name.V(), "scores").
+		Equal(items[3].Type.V(), "map."+ast.TypeInt).
+		Empty(items[3].Items)
+}
+
+func TestImporter_parseAccess(t *testing.T) {
+	a := assert.New(t, false)
+
+	const data = `openapi: 3.0.3
+info:
+  title: test
+  version: 1.0.0
+paths:
+  /users:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                id:
+                  type: integer
+                  readOnly: true
+                password:
+                  type: string
+                  writeOnly: true
+                nickname:
+                  type: string
+                  nullable: true
+                tags:
+                  type: array
+                  nullable: true
+                  items:
+                    type: string
+                both:
+                  type: string
+                  readOnly: true
+                  writeOnly: true
+      responses:
+        '204':
+          description: OK
+`
+
+	rslt := messagetest.NewMessageHandler()
+	doc, err := Import(rslt.Handler, "file:///openapi.yaml", []byte(data))
+	rslt.Handler.Stop()
+	a.NotError(err).NotNil(doc)
+
+	a.Equal(1, len(rslt.Warns))
+	warn, ok := rslt.Warns[0].(*core.Error)
+	a.True(ok).Equal(warn.Field, "/paths/~1users/post/requestBody/content/application~1json/schema/properties/both/writeOnly")
+
+	items := doc.APIs[0].Requests[0].Items
+	a.Equal(5, len(items))
+
+	a.Equal(items[0].Name.V(), "both").
+		True(items[0].ReadOnly.V()).
+		False(items[0].WriteOnly.V())
+
+	a.Equal(items[1].Name.V(), "id").
+		True(items[1].ReadOnly.V()).
+		False(items[1].Nullable.V())
+
+	a.Equal(items[2].Name.V(), "nickname").
+		True(items[2].Nullable.V())
+
+	a.Equal(items[3].Name.V(), "password").
+		True(items[3].WriteOnly.V())
+
+	a.Equal(items[4].Name.V(), "tags").
+		True(items[4].Array.V()).
+		True(items[4].Nullable.V())
+}
+
+func TestImporter_file(t *testing.T) {
+	a := assert.New(t, false)
+
+	const data = `openapi: 3.0.3
+info:
+  title: test
+  version: 1.0.0
+paths:
+  /avatars:
+    post:
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              properties:
+                avatar:
+                  type: string
+                  format: binary
+                thumb:
+                  type: string
+                  format: byte
+      responses:
+        '204':
+          description: OK
+`
+
+	rslt := messagetest.NewMessageHandler()
+	doc, err := Import(rslt.Handler, "file:///openapi.yaml", []byte(data))
+	rslt.Handler.Stop()
+	a.NotError(err).NotNil(doc).Empty(rslt.Warns)
+
+	req := doc.APIs[0].Requests[0]
+	a.Equal(req.Mimetype.V(), "multipart/form-data").
+		Equal(2, len(req.Items)).
+		Equal(req.Items[0].Type.V(), ast.TypeFile).
+		Equal(req.Items[1].Type.V(), ast.TypeFile)
+}
@@ -6,10 +6,12 @@
 package openapi
 
 import (
+	"encoding/json"
 	"strconv"
 
 	"github.com/issue9/validation/is"
 	"github.com/issue9/version"
+	"gopkg.in/yaml.v3"
 
 	"github.com/caixw/apidoc/v7/core"
 	"github.com/caixw/apidoc/v7/internal/ast"
@@ -82,6 +84,28 @@ type Example struct {
 // ExampleValue 表示示例的内容类型。
 type ExampleValue string
 
+// UnmarshalYAML 实现 yaml.Unmarshaler 接口
+//
+// openapi 中的示例可以是任意类型，非字符串的内容会被转换成 JSON 格式的字符串。
+func (v *ExampleValue) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*v = ExampleValue(node.Value)
+		return nil
+	}
+
+	var val any
+	if err := node.Decode(&val); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(val, "", "\t")
+	if err != nil {
+		return err
+	}
+	*v = ExampleValue(data)
+	return nil
+}
+
 func newTag(tag *ast.Tag) *Tag {
 	return &Tag{
 		Name:        tag.Name.V(),
@@ -4,6 +4,7 @@ package openapi
 
 import (
 	"encoding/json"
+	"net/http"
 	"strconv"
 	"strings"
 
@@ -14,6 +15,11 @@ import (
 	"github.com/caixw/apidoc/v7/internal/locale"
 )
 
+const (
+	defaultCallbackName       = "callback"                  // apidoc 的回调没有名称，统一采用此值作为键名
+	defaultCallbackExpression = "{$request.query.callback}" // 未指定回调地址时采用的运行时表达式
+)
+
 // 将 doc.APIDoc 转换成 openapi
 func convert(doc *ast.APIDoc) (*OpenAPI, error) {
 	langID := doc.Lang.V()
@@ -47,6 +53,14 @@ func convert(doc *ast.APIDoc) (*OpenAPI, error) {
 		openapi.Tags = append(openapi.Tags, newTag(tag))
 	}
 
+	components := &Components{
+		Schemas:         newSchemas(doc),
+		SecuritySchemes: newSecuritySchemes(doc),
+	}
+	if len(components.Schemas) > 0 || len(components.SecuritySchemes) > 0 {
+		openapi.Components = components
+	}
+
 	if err := parsePaths(openapi, doc); err != nil {
 		return nil, err
 	}
@@ -88,6 +102,7 @@ func parsePaths(openapi *OpenAPI, d *ast.APIDoc) *core.Error {
 			operation.Description = api.Description.V()
 		}
 		setOperationParams(d, operation, api)
+		operation.Security = newSecurityRequirements(api)
 
 		// servers
 		// 不为 PathItem 设置 servers，直接写在 operation
@@ -113,71 +128,130 @@ func parsePaths(openapi *OpenAPI, d *ast.APIDoc) *core.Error {
 			}
 		}
 
-		// requests
 		if len(api.Requests) > 0 {
-			content := make(map[string]*MediaType, len(api.Requests))
-			for _, r := range api.Requests {
-				examples := make(map[string]*Example, len(r.Examples))
-				for _, exp := range r.Examples {
-					examples[exp.Mimetype.V()] = &Example{
-						Value: ExampleValue(exp.Content.Value.Value),
-					}
-				}
+			operation.RequestBody = newRequestBody(d, api.Requests)
+		}
+		operation.Responses = newResponses(d, api.Responses)
+		operation.Callbacks = newCallbacks(d, api.Callback)
+	} // end for doc.Apis
 
-				content[r.Mimetype.V()] = &MediaType{
-					Schema:   newSchemaFromRequest(d, r, true),
-					Examples: examples,
-				}
-			}
+	return nil
+}
 
-			operation.RequestBody = &RequestBody{
-				Content: content,
+func newRequestBody(d *ast.APIDoc, requests []*ast.Request) *RequestBody {
+	content := make(map[string]*MediaType, len(requests))
+	for _, r := range requests {
+		examples := make(map[string]*Example, len(r.Examples))
+		for _, exp := range r.Examples {
+			examples[exp.Mimetype.V()] = &Example{
+				Value: ExampleValue(exp.Content.Value.Value),
 			}
 		}
 
-		// responses
-		operation.Responses = make(map[string]*Response, len(api.Responses))
-		for _, resp := range api.Responses {
-			status := strconv.Itoa(resp.Status.V())
-			r, found := operation.Responses[status]
-			if !found {
-				r = &Response{
-					Description: getDescription(resp.Description, resp.Summary),
-					Headers:     make(map[string]*Header, 10),
-					Content:     make(map[string]*MediaType, 10),
-				}
-				operation.Responses[status] = r
-			}
+		content[r.Mimetype.V()] = &MediaType{
+			Schema:   newSchemaFromRequest(d, r, true),
+			Examples: examples,
+		}
+	}
 
-			for _, h := range resp.Headers {
-				r.Headers[h.Name.V()] = &Header{
-					Style:       Style{Style: StyleSimple},
-					Description: getDescription(h.Description, h.Summary),
-				}
+	return &RequestBody{Content: content}
+}
+
+func newResponses(d *ast.APIDoc, responses []*ast.Request) map[string]*Response {
+	ret := make(map[string]*Response, len(responses))
+	for _, resp := range responses {
+		status := strconv.Itoa(resp.Status.V())
+		r, found := ret[status]
+		if !found {
+			r = &Response{
+				Description: getDescription(resp.Description, resp.Summary),
+				Headers:     make(map[string]*Header, 10),
+				Content:     make(map[string]*MediaType, 10),
 			}
+			ret[status] = r
+		}
 
-			examples := make(map[string]*Example, len(resp.Examples))
-			for _, exp := range resp.Examples {
-				examples[exp.Mimetype.V()] = &Example{
-					Summary: exp.Summary.V(),
-					Value:   ExampleValue(exp.Content.Value.Value),
-				}
+		for _, h := range resp.Headers {
+			r.Headers[h.Name.V()] = &Header{
+				Style:       Style{Style: StyleSimple},
+				Description: getDescription(h.Description, h.Summary),
+			}
+		}
+		if len(resp.Cookies) > 0 { // openapi 无法描述返回的 cookie，统一以 Set-Cookie 报头表示。
+			r.Headers["Set-Cookie"] = &Header{
+				Style:       Style{Style: StyleSimple},
+				Description: getCookiesDescription(resp.Cookies),
 			}
-			r.Content[resp.Mimetype.V()] = &MediaType{
-				Schema:   newSchemaFromRequest(d, resp, true),
-				Examples: examples,
+		}
+
+		examples := make(map[string]*Example, len(resp.Examples))
+		for _, exp := range resp.Examples {
+			examples[exp.Mimetype.V()] = &Example{
+				Summary: exp.Summary.V(),
+				Value:   ExampleValue(exp.Content.Value.Value),
 			}
 		}
-	} // end for doc.Apis
+		r.Content[resp.Mimetype.V()] = &MediaType{
+			Schema:   newSchemaFromRequest(d, resp, true),
+			Examples: examples,
+		}
+	}
 
-	return nil
+	return ret
+}
+
+// 将 ast.Callback 转换成 Operation.Callbacks
+//
+// apidoc 的每个 API 最多只有一个回调，所以返回的对象最多只有一个元素，
+// 未指定回调的 path 时，以 defaultCallbackExpression 作为回调地址。
+func newCallbacks(d *ast.APIDoc, callback *ast.Callback) map[string]*Callback {
+	if callback == nil {
+		return nil
+	}
+
+	exp := defaultCallbackExpression
+	if callback.Path != nil && callback.Path.Path.V() != "" {
+		exp = callback.Path.Path.V()
+	}
+
+	item := &PathItem{}
+	operation, err := setOperation(item, callback.Method.V())
+	if err != nil { // 新建的 PathItem 不可能有重复的请求方法
+		panic(err)
+	}
+
+	operation.Deprecated = callback.Deprecated != nil
+	operation.Summary = callback.Summary.V()
+	operation.Description = callback.Description.V()
+	setParams(d, operation, callback.Path, callback.Headers, nil, callback.Requests)
+
+	if len(callback.Requests) > 0 {
+		operation.RequestBody = newRequestBody(d, callback.Requests)
+	}
+	operation.Responses = newResponses(d, callback.Responses)
+	if len(operation.Responses) == 0 { // openapi 要求至少有一个返回值
+		operation.Responses[strconv.Itoa(http.StatusOK)] = &Response{Description: http.StatusText(http.StatusOK)}
+	}
+
+	return map[string]*Callback{defaultCallbackName: {exp: item}}
 }
 
 func setOperationParams(doc *ast.APIDoc, operation *Operation, api *ast.API) {
-	l := len(api.Path.Params) + len(api.Path.Queries)
+	setParams(doc, operation, api.Path, api.Headers, api.Cookies, api.Requests)
+}
+
+// 将地址中的参数、报头以及 cookie 都转换成 operation.Parameters
+//
+// path 可以为空，requests 中的报头和 cookie 也会集中到 operation.Parameters。
+func setParams(doc *ast.APIDoc, operation *Operation, path *ast.Path, headers, cookies []*ast.Param, requests []*ast.Request) {
+	if path == nil {
+		path = &ast.Path{}
+	}
+
+	l := len(path.Params) + len(path.Queries) + len(headers) + len(cookies)
 	operation.Parameters = make([]*Parameter, 0, l)
 
-	for _, param := range api.Path.Params {
+	for _, param := range path.Params {
 		operation.Parameters = append(operation.Parameters, &Parameter{
 			Name:        param.Name.V(),
 			IN:          ParameterINPath,
@@ -187,7 +261,7 @@ func setOperationParams(doc *ast.APIDoc, operation *Operation, api *ast.API) {
 		})
 	}
 
-	for _, param := range api.Path.Queries {
+	for _, param := range path.Queries {
 		operation.Parameters = append(operation.Parameters, &Parameter{
 			Name:        param.Name.V(),
 			IN:          ParameterINQuery,
@@ -197,17 +271,57 @@ func setOperationParams(doc *ast.APIDoc, operation *Operation, api *ast.API) {
 		})
 	}
 
-	// 将各个类型的 Request 中的报头都集中到 operation.Parameters
-	for _, r := range api.Requests {
+	for _, param := range headers {
+		operation.Parameters = append(operation.Parameters, newHeaderParameter(param))
+	}
+
+	for _, param := range cookies {
+		operation.Parameters = append(operation.Parameters, newCookieParameter(doc, param))
+	}
+
+	// 将各个类型的 Request 中的报头和 cookie 都集中到 operation.Parameters
+	for _, r := range requests {
 		for _, param := range r.Headers {
-			operation.Parameters = append(operation.Parameters, &Parameter{
-				Style:       Style{Style: StyleSimple},
-				Name:        param.Name.V(),
-				IN:          ParameterINHeader,
-				Description: getDescription(param.Description, param.Summary),
-			})
+			operation.Parameters = append(operation.Parameters, newHeaderParameter(param))
+		}
+
+		for _, param := range r.Cookies {
+			operation.Parameters = append(operation.Parameters, newCookieParameter(doc, param))
+		}
+	}
+}
+
+func newHeaderParameter(param *ast.Param) *Parameter {
+	return &Parameter{
+		Style:       Style{Style: StyleSimple},
+		Name:        param.Name.V(),
+		IN:          ParameterINHeader,
+		Description: getDescription(param.Description, param.Summary),
+	}
+}
+
+func newCookieParameter(doc *ast.APIDoc, param *ast.Param) *Parameter {
+	return &Parameter{
+		Style:       Style{Style: StyleForm},
+		Name:        param.Name.V(),
+		IN:          ParameterINCookie,
+		Description: getDescription(param.Description, param.Summary),
+		Required:    !param.Optional.V(),
+		Schema:      newSchema(doc, param, true),
+	}
+}
+
+// 将返回的 cookie 列表合并成 Set-Cookie 报头的描述内容
+func getCookiesDescription(cookies []*ast.Param) string {
+	lines := make([]string, 0, len(cookies))
+	for _, cookie := range cookies {
+		line := cookie.Name.V()
+		if desc := getDescription(cookie.Description, cookie.Summary); desc != "" {
+			line += ": " + desc
 		}
+		lines = append(lines, line)
 	}
+	return strings.Join(lines, "\n")
 }
 
 func getDescription(desc *ast.Richtext, summary *ast.Attribute) string {
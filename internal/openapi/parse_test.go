@@ -11,7 +11,9 @@ import (
 	"github.com/issue9/assert/v3"
 
 	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/internal/ast"
 	"github.com/caixw/apidoc/v7/internal/ast/asttest"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
 )
 
 func TestJSON(t *testing.T) {
@@ -43,3 +45,126 @@ func TestYAML(t *testing.T) {
 	data, err := YAML(asttest.Get())
 	a.NotError(err).NotNil(data)
 }
+
+func TestSetOperationParams_cookies(t *testing.T) {
+	a := assert.New(t, false)
+
+	api := &ast.API{
+		Path: &ast.Path{},
+		Cookies: []*ast.Param{
+			{
+				Name: &ast.Attribute{Value: xmlenc.String{Value: "sid"}},
+				Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeNumber}},
+			},
+		},
+		Requests: []*ast.Request{
+			{
+				Cookies: []*ast.Param{
+					{
+						Name:     &ast.Attribute{Value: xmlenc.String{Value: "lang"}},
+						Type:     &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+						Optional: &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+					},
+				},
+			},
+		},
+	}
+
+	operation := &Operation{}
+	setOperationParams(&ast.APIDoc{}, operation, api)
+	a.Equal(2, len(operation.Parameters))
+
+	sid := operation.Parameters[0]
+	a.Equal(sid.Name, "sid").
+		Equal(sid.IN, ParameterINCookie).
+		True(sid.Required).
+		Equal(sid.Schema.Type, TypeDouble)
+	a.Nil(sid.sanitize())
+
+	lang := operation.Parameters[1]
+	a.Equal(lang.Name, "lang").
+		Equal(lang.IN, ParameterINCookie).
+		False(lang.Required)
+}
+
+func TestGetCookiesDescription(t *testing.T) {
+	a := assert.New(t, false)
+
+	cookies := []*ast.Param{
+		{
+			Name:    &ast.Attribute{Value: xmlenc.String{Value: "sid"}},
+			Summary: &ast.Attribute{Value: xmlenc.String{Value: "session"}},
+		},
+		{
+			Name: &ast.Attribute{Value: xmlenc.String{Value: "lang"}},
+		},
+	}
+	a.Equal(getCookiesDescription(cookies), "sid: session\nlang")
+}
+
+func TestNewCallbacks(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Nil(newCallbacks(&ast.APIDoc{}, nil))
+
+	cb := &ast.Callback{
+		Method:  &ast.MethodAttribute{Value: xmlenc.String{Value: http.MethodPost}},
+		Summary: &ast.Attribute{Value: xmlenc.String{Value: "summary"}},
+		Headers: []*ast.Param{
+			{Name: &ast.Attribute{Value: xmlenc.String{Value: "x-sign"}}},
+		},
+		Requests: []*ast.Request{
+			{
+				Mimetype: &ast.Attribute{Value: xmlenc.String{Value: "application/json"}},
+				Type:     &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeObject}},
+				Items: []*ast.Param{
+					{
+						Name: &ast.Attribute{Value: xmlenc.String{Value: "id"}},
+						Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeNumber}},
+					},
+				},
+			},
+		},
+	}
+	callbacks := newCallbacks(&ast.APIDoc{}, cb)
+	a.Equal(1, len(callbacks))
+	item := (*callbacks[defaultCallbackName])[defaultCallbackExpression]
+	a.NotNil(item).NotNil(item.Post).Nil(item.Get)
+	o := item.Post
+	a.Equal(o.Summary, "summary").
+		False(o.Deprecated).
+		Equal(1, len(o.Parameters)).
+		Equal(o.Parameters[0].IN, ParameterINHeader).
+		NotNil(o.RequestBody.Content["application/json"]).
+		Equal(1, len(o.Responses)).
+		NotNil(o.Responses["200"])
+	a.Nil(item.sanitize())
+
+	// 指定了 path 和 responses
+	cb.Path = &ast.Path{
+		Path: &ast.Attribute{Value: xmlenc.String{Value: "{$request.body#/url}"}},
+		Queries: []*ast.Param{
+			{
+				Name: &ast.Attribute{Value: xmlenc.String{Value: "q"}},
+				Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+			},
+		},
+	}
+	cb.Responses = []*ast.Request{
+		{
+			Status:   &ast.StatusAttribute{Value: ast.Number{Int: http.StatusAccepted}},
+			Summary:  &ast.Attribute{Value: xmlenc.String{Value: "accepted"}},
+			Mimetype: &ast.Attribute{Value: xmlenc.String{Value: "application/json"}},
+			Type:     &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+		},
+	}
+	callbacks = newCallbacks(&ast.APIDoc{}, cb)
+	item = (*callbacks[defaultCallbackName])["{$request.body#/url}"]
+	a.NotNil(item).NotNil(item.Post)
+	o = item.Post
+	a.Equal(2, len(o.Parameters)).
+		Equal(o.Parameters[0].IN, ParameterINQuery).
+		Equal(1, len(o.Responses)).
+		NotNil(o.Responses["202"])
+	a.Nil(item.sanitize())
+}
@@ -69,7 +69,9 @@ type Encoding struct {
 }
 
 // Callback Object
-type Callback PathItem
+//
+// 键名为运行时表达式，用于计算回调的地址。
+type Callback map[string]*PathItem
 
 // Response 每个 API 的返回信息
 type Response struct {
@@ -135,10 +137,11 @@ func (o *Operation) sanitize() *core.Error {
 	}
 
 	for name, call := range o.Callbacks {
-		p := (*PathItem)(call)
-		if err := p.sanitize(); err != nil {
-			err.Field = "callbacks[" + name + "]." + err.Field
-			return err
+		for exp, p := range *call {
+			if err := p.sanitize(); err != nil {
+				err.Field = "callbacks[" + name + "][" + exp + "]." + err.Field
+				return err
+			}
 		}
 	}
 
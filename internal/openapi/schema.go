@@ -3,6 +3,8 @@
 package openapi
 
 import (
+	"gopkg.in/yaml.v3"
+
 	"github.com/caixw/apidoc/v7/core"
 	"github.com/caixw/apidoc/v7/internal/ast"
 )
@@ -19,6 +21,9 @@ const (
 	TypeArray    = "array"
 )
 
+// 引用 components.schemas 中对象的前缀
+const schemaRefPrefix = "#/components/schemas/"
+
 var typeMaps = map[string]string{
 	ast.TypeBool:     TypeBool,
 	ast.TypeString:   TypeString,
@@ -31,6 +36,7 @@ var typeMaps = map[string]string{
 	ast.TypeDate:     TypeString,
 	ast.TypeTime:     TypeString,
 	ast.TypeDateTime: TypeString,
+	ast.TypeFile:     TypeString,
 }
 
 func fromDocType(t string) string {
@@ -39,25 +45,28 @@ func fromDocType(t string) string {
 
 // Schema 定义了输出和输出的数据类型
 type Schema struct {
-	Type string `json:"type,omitempty" yaml:"type,omitempty"`
-	Enum []any  `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Type   string `json:"type,omitempty" yaml:"type,omitempty"`
+	Format string `json:"format,omitempty" yaml:"format,omitempty"`
+	Enum   []any  `json:"enum,omitempty" yaml:"enum,omitempty"`
 
 	// 数值验证
-	MultipleOf       int  `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty"`
-	Maximum          int  `json:"maximum,omitempty" yaml:"maximum,omitempty"`
-	ExclusiveMaximum bool `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
-	Minimum          int  `json:"minimum,omitempty" yaml:"minimum,omitempty"`
-	ExclusiveMinimum bool `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
+	//
+	// Maximum 和 Minimum 为 0 也是有意义的值，所以采用指针以区分是否存在。
+	MultipleOf       float64  `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	ExclusiveMaximum bool     `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
+	Minimum          *float64 `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	ExclusiveMinimum bool     `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
 
 	// 字符串验证
-	MaxLength int    `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
 	MinLength int    `json:"minLength,omitempty" yaml:"minLength,omitempty"`
 	Pattern   string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
 
 	// 数组验证
 	Items           *Schema `json:"items,omitempty" yaml:"items,omitempty"`
 	AdditionalItems *Schema `json:"additionalItems,omitempty" yaml:"additionalItems,omitempty"`
-	MaxItems        int     `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	MaxItems        *int    `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
 	MinItems        int     `json:"minItems,omitempty" yaml:"minItems,omitempty"`
 	UniqueItems     bool    `json:"uniqueItems,omitempty" yaml:"uniqueItems,omitempty"`
 	Contains        *Schema `json:"contains,omitempty" yaml:"contains,omitempty"`
@@ -68,9 +77,14 @@ type Schema struct {
 	Required             []string           `json:"required,omitempty" yaml:"required,omitempty"`
 	Properties           map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
 	PatternProperties    map[string]*Schema `json:"patternProperties,omitempty" yaml:"patternProperties,omitempty"`
-	AdditionalProperties map[string]*Schema `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
-	Dependencies         map[string]*Schema `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
-	PropertyNames        *Schema            `json:"propertyNames,omitempty" yaml:"propertyNames,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	// additionalProperties 为布尔值时的值，为空表示未指定或是指定为 Schema。
+	//
+	// 值为 false 表示不允许额外的字段，此时 AdditionalProperties 为空；
+	// 值为 true 表示允许任意类型的额外字段，此时 AdditionalProperties 为空的 Schema。
+	AdditionalPropertiesAllowed *bool              `json:"-" yaml:"-"`
+	Dependencies                map[string]*Schema `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+	PropertyNames               *Schema            `json:"propertyNames,omitempty" yaml:"propertyNames,omitempty"`
 
 	AllOf []*Schema `json:"allOf,omitempty" yaml:"allOf,omitempty"`
 	AnyOf []*Schema `json:"anyOf,omitempty" yaml:"anyOf,omitempty"`
@@ -84,6 +98,7 @@ type Schema struct {
 	Title         string                 `json:"title,omitempty" yaml:"title,omitempty"`
 	Description   string                 `json:"description,omitempty" yaml:"description,omitempty"`
 	Default       any                    `json:"default,omitempty" yaml:"default,omitempty"`
+	Nullable      bool                   `json:"nullable,omitempty" yaml:"nullable,omitempty"`
 	ReadOnly      bool                   `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
 	WriteOnly     bool                   `json:"writeOnly,omitempty" yaml:"writeOnly,omitempty"`
 	Discriminator *Discriminator         `json:"discriminator,omitempty" yaml:"discriminator,omitempty"`
@@ -103,13 +118,40 @@ type XML struct {
 }
 
 // Discriminator Object
-//
-// NOTE: 暂时未用到。
 type Discriminator struct {
 	PropertyName string            `json:"propertyName" yaml:"propertyName"`
 	Mapping      map[string]string `json:"mapping,omitempty" yaml:"mapping,omitempty"`
 }
 
+// UnmarshalYAML 实现 yaml.Unmarshaler 接口
+//
+// additionalProperties 等字段允许使用布尔值代替 Schema，
+// 此时仅初始化一个空的 Schema 对象，additionalProperties 的布尔值另外保存在 AdditionalPropertiesAllowed 中。
+func (s *Schema) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!bool" {
+		*s = Schema{}
+		return nil
+	}
+
+	type schemaShadow Schema
+	if err := node.Decode((*schemaShadow)(s)); err != nil {
+		return err
+	}
+
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if k, v := node.Content[i], node.Content[i+1]; k.Value == "additionalProperties" && v.Kind == yaml.ScalarNode && v.Tag == "!!bool" {
+				allowed := v.Value == "true"
+				s.AdditionalPropertiesAllowed = &allowed
+				if !allowed {
+					s.AdditionalProperties = nil
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func (s *Schema) sanitize() *core.Error {
 	if s.ExternalDocs != nil {
 		if err := s.ExternalDocs.sanitize(); err != nil {
@@ -132,6 +174,13 @@ func (s *Schema) sanitize() *core.Error {
 		}
 	}
 
+	if s.AdditionalProperties != nil {
+		if err := s.AdditionalProperties.sanitize(); err != nil {
+			err.Field = "additionalProperties." + err.Field
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -153,11 +202,25 @@ func newXML(doc *ast.APIDoc, p *ast.Param) *XML {
 // chkArray 是否需要检测当前类型是否为数组
 func newSchema(doc *ast.APIDoc, p *ast.Param, chkArray bool) *Schema {
 	if chkArray && p.Array.V() {
-		return &Schema{
-			Type:  TypeArray,
-			Items: newSchema(doc, p, false),
-			XML:   newXML(doc, p),
+		s := &Schema{
+			Type:        TypeArray,
+			Items:       newSchema(doc, p, false),
+			XML:         newXML(doc, p),
+			MinItems:    p.MinItems.IntValue(),
+			UniqueItems: p.UniqueItems.V(),
+			Nullable:    p.Nullable.V(),
+			ReadOnly:    p.ReadOnly.V(),
+			WriteOnly:   p.WriteOnly.V(),
 		}
+		if p.MaxItems != nil {
+			maxItems := p.MaxItems.IntValue()
+			s.MaxItems = &maxItems
+		}
+		return s
+	}
+
+	if ref := p.Ref.V(); ref != "" && doc.TypeDef(ref) != nil {
+		return &Schema{Ref: schemaRefPrefix + ref}
 	}
 
 	s := &Schema{
@@ -168,6 +231,23 @@ func newSchema(doc *ast.APIDoc, p *ast.Param, chkArray bool) *Schema {
 		Deprecated:  p.Deprecated != nil,
 		Required:    make([]string, 0, len(p.Items)),
 		XML:         newXML(doc, p),
+		Nullable:    p.Nullable.V(),
+		ReadOnly:    p.ReadOnly.V(),
+		WriteOnly:   p.WriteOnly.V(),
+
+		Minimum:          numberValue(p.Min),
+		ExclusiveMinimum: p.ExclusiveMin.V(),
+		Maximum:          numberValue(p.Max),
+		ExclusiveMaximum: p.ExclusiveMax.V(),
+		MinLength:        p.MinLength.IntValue(),
+		Pattern:          p.Pattern.V(),
+	}
+	if p.MaxLength != nil {
+		maxLength := p.MaxLength.IntValue()
+		s.MaxLength = &maxLength
+	}
+	if p.Type.V() == ast.TypeFile {
+		s.Format = "binary"
 	}
 
 	// enum
@@ -178,6 +258,12 @@ func newSchema(doc *ast.APIDoc, p *ast.Param, chkArray bool) *Schema {
 		}
 	}
 
+	// 字典的值由 additionalProperties 描述
+	if value := p.MapValue(); value != nil {
+		s.AdditionalProperties = newSchema(doc, value, false)
+		return s
+	}
+
 	// Properties / Required
 	if len(p.Items) > 0 { // 如果是对象，类型改为空
 		s.Type = ""
@@ -196,10 +282,82 @@ func newSchema(doc *ast.APIDoc, p *ast.Param, chkArray bool) *Schema {
 		}
 	}
 
+	if len(p.Variants) > 0 {
+		s.Type = ""
+		setVariants(doc, s, &p.Composite)
+	}
+
 	return s
 }
 
+// 将复合类型的变体转换为 oneOf、anyOf 或是 allOf
+//
+// 如果指定了 discriminator，引用了 typedef 的变体会被添加到 Discriminator.Mapping 中。
+func setVariants(doc *ast.APIDoc, s *Schema, c *ast.Composite) {
+	variants := make([]*Schema, 0, len(c.Variants))
+	for _, v := range c.Variants {
+		variants = append(variants, newSchema(doc, v.Param(), false))
+	}
+
+	switch c.ComposeType() {
+	case ast.ComposeAllOf:
+		s.AllOf = variants
+	case ast.ComposeAnyOf:
+		s.AnyOf = variants
+	default:
+		s.OneOf = variants
+	}
+
+	if c.Discriminator == nil {
+		return
+	}
+
+	// 未引用 typedef 的变体，通过 discriminator 属性的枚举值声明其对应的值。
+	disc := c.Discriminator.V()
+	s.Discriminator = &Discriminator{PropertyName: disc}
+	for index, v := range c.Variants {
+		if ref := v.Ref.V(); ref != "" && doc.TypeDef(ref) != nil {
+			if s.Discriminator.Mapping == nil {
+				s.Discriminator.Mapping = make(map[string]string, len(c.Variants))
+			}
+			s.Discriminator.Mapping[v.Value.V()] = schemaRefPrefix + ref
+			continue
+		}
+
+		vs := variants[index]
+		if vs.Properties == nil {
+			vs.Properties = make(map[string]*Schema, 1)
+		}
+		vs.Properties[disc] = &Schema{Type: TypeString, Enum: []any{v.Value.V()}}
+	}
+}
+
+func numberValue(num *ast.NumberAttribute) *float64 {
+	if num == nil {
+		return nil
+	}
+
+	v := num.FloatValue()
+	if !num.IsFloat() {
+		v = float64(num.IntValue())
+	}
+	return &v
+}
+
 // chkArray 是否需要检测当前类型是否为数组
 func newSchemaFromRequest(doc *ast.APIDoc, p *ast.Request, chkArray bool) *Schema {
 	return newSchema(doc, p.Param(), chkArray)
 }
+
+// 将 doc.TypeDefs 转换成 components.schemas
+func newSchemas(doc *ast.APIDoc) map[string]*Schema {
+	if len(doc.TypeDefs) == 0 {
+		return nil
+	}
+
+	schemas := make(map[string]*Schema, len(doc.TypeDefs))
+	for _, t := range doc.TypeDefs {
+		schemas[t.Name.V()] = newSchema(doc, t.Param(), true)
+	}
+	return schemas
+}
@@ -6,7 +6,10 @@ import (
 	"testing"
 
 	"github.com/issue9/assert/v3"
+	"gopkg.in/yaml.v3"
 
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/core/messagetest"
 	"github.com/caixw/apidoc/v7/internal/ast"
 	"github.com/caixw/apidoc/v7/internal/xmlenc"
 )
@@ -74,4 +77,259 @@ func TestNewSchema(t *testing.T) {
 		Equal(output.Properties["p2"].Type, TypeDouble)
 
 	a.NotError(output.sanitize())
+
+	// 约束条件
+	input = &ast.Param{
+		Type:         &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeInt}},
+		Array:        &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+		Min:          &ast.NumberAttribute{Value: ast.Number{Int: 0}},
+		Max:          &ast.NumberAttribute{Value: ast.Number{Float: 10.5, IsFloat: true}},
+		ExclusiveMax: &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+		MinItems:     &ast.NumberAttribute{Value: ast.Number{Int: 1}},
+		MaxItems:     &ast.NumberAttribute{Value: ast.Number{Int: 0}},
+		UniqueItems:  &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+	}
+	output = newSchema(d, input, true)
+	a.Equal(output.Type, TypeArray).
+		Equal(output.MinItems, 1).
+		Equal(*output.MaxItems, 0).
+		True(output.UniqueItems).
+		Equal(*output.Items.Minimum, 0).
+		Equal(*output.Items.Maximum, 10.5).
+		False(output.Items.ExclusiveMinimum).
+		True(output.Items.ExclusiveMaximum)
+
+	input = &ast.Param{
+		Type:      &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+		MinLength: &ast.NumberAttribute{Value: ast.Number{Int: 1}},
+		MaxLength: &ast.NumberAttribute{Value: ast.Number{Int: 5}},
+		Pattern:   &ast.Attribute{Value: xmlenc.String{Value: "^[a-z]+$"}},
+	}
+	output = newSchema(d, input, true)
+	a.Equal(output.MinLength, 1).
+		Equal(*output.MaxLength, 5).
+		Equal(output.Pattern, "^[a-z]+$").
+		Nil(output.Minimum).
+		Nil(output.Maximum)
+}
+
+func TestNewSchema_map(t *testing.T) {
+	a := assert.New(t, false)
+
+	d := &ast.APIDoc{}
+	input := &ast.Param{
+		Name: &ast.Attribute{Value: xmlenc.String{Value: "users"}},
+		Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeMap}},
+		Items: []*ast.Param{
+			{
+				Name: &ast.Attribute{Value: xmlenc.String{Value: "name"}},
+				Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+			},
+		},
+	}
+	output := newSchema(d, input, true)
+	a.Equal(output.Type, "").
+		Empty(output.Properties).
+		NotNil(output.AdditionalProperties).
+		Equal(output.AdditionalProperties.Type, "").
+		Equal(output.AdditionalProperties.Properties["name"].Type, TypeString).
+		Equal(output.AdditionalProperties.Required, []string{"name"})
+	a.NotError(output.sanitize())
+
+	input = &ast.Param{
+		Name:  &ast.Attribute{Value: xmlenc.String{Value: "scores"}},
+		Type:  &ast.TypeAttribute{Value: xmlenc.String{Value: "map.number.int"}},
+		Array: &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+	}
+	output = newSchema(d, input, true)
+	a.Equal(output.Type, TypeArray).
+		NotNil(output.Items.AdditionalProperties).
+		Equal(output.Items.AdditionalProperties.Type, TypeLong)
+}
+
+func TestNewSchema_access(t *testing.T) {
+	a := assert.New(t, false)
+
+	d := &ast.APIDoc{}
+	input := &ast.Param{
+		Name:     &ast.Attribute{Value: xmlenc.String{Value: "id"}},
+		Type:     &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeInt}},
+		Nullable: &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+		ReadOnly: &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+	}
+	output := newSchema(d, input, true)
+	a.True(output.Nullable).
+		True(output.ReadOnly).
+		False(output.WriteOnly)
+
+	input = &ast.Param{
+		Name:      &ast.Attribute{Value: xmlenc.String{Value: "passwords"}},
+		Type:      &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+		Array:     &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+		WriteOnly: &ast.BoolAttribute{Value: ast.Bool{Value: true}},
+	}
+	output = newSchema(d, input, true)
+	a.Equal(output.Type, TypeArray).
+		False(output.Nullable).
+		True(output.WriteOnly)
+}
+
+func TestNewSchema_file(t *testing.T) {
+	a := assert.New(t, false)
+
+	input := &ast.Param{
+		Name: &ast.Attribute{Value: xmlenc.String{Value: "avatar"}},
+		Type: &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeFile}},
+	}
+	output := newSchema(&ast.APIDoc{}, input, true)
+	a.Equal(output.Type, TypeString).
+		Equal(output.Format, "binary")
+}
+
+func TestNewSchemas(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Nil(newSchemas(&ast.APIDoc{}))
+
+	const data = `<apidoc version="1.0.0">
+	<title>title</title>
+	<mimetype>application/json</mimetype>
+	<typedef name="user" type="object" summary="user">
+		<param name="id" type="number" summary="id" />
+		<param name="groups" ref="group" array="true" />
+	</typedef>
+	<typedef name="group" type="string" summary="group" />
+	<api method="GET">
+		<path path="/users" />
+		<response status="200" ref="user" mimetype="application/json" />
+	</api>
+	</apidoc>`
+	rslt := messagetest.NewMessageHandler()
+	d := &ast.APIDoc{}
+	d.Parse(rslt.Handler, core.Block{Data: []byte(data)})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	schemas := newSchemas(d)
+	a.Equal(2, len(schemas))
+	user := schemas["user"]
+	a.NotNil(user).
+		Equal(user.Title, "user").
+		Equal(user.Required, []string{"id", "groups"}).
+		Equal(user.Properties["id"].Type, TypeDouble).
+		Equal(user.Properties["groups"].Type, TypeArray).
+		Equal(user.Properties["groups"].Items.Ref, "#/components/schemas/group")
+	a.Equal(schemas["group"].Type, TypeString)
+
+	openapi, err := convert(d)
+	a.NotError(err).
+		NotNil(openapi.Components).
+		Equal(openapi.Components.Schemas, schemas)
+	resp := openapi.Paths["/users"].Get.Responses["200"]
+	a.Equal(resp.Content["application/json"].Schema, &Schema{Ref: "#/components/schemas/user"})
+}
+
+func TestSetVariants(t *testing.T) {
+	a := assert.New(t, false)
+
+	const data = `<apidoc version="1.0.0">
+	<title>title</title>
+	<mimetype>application/json</mimetype>
+	<typedef name="created" type="object" summary="created">
+		<param name="kind" type="string" summary="kind" />
+		<param name="id" type="number" summary="id" />
+	</typedef>
+	<api method="GET">
+		<path path="/events" />
+		<response status="200" type="object" summary="event" discriminator="kind" mimetype="application/json">
+			<param name="kind" type="string" summary="kind" />
+			<variant value="created" ref="created" />
+			<variant value="deleted" summary="deleted">
+				<param name="reason" type="string" summary="reason" />
+			</variant>
+		</response>
+		<response status="201" type="object" summary="all" compose="all-of" mimetype="application/json">
+			<variant ref="created" />
+			<variant summary="extra"><param name="extra" type="string" summary="extra" /></variant>
+		</response>
+	</api>
+	</apidoc>`
+	rslt := messagetest.NewMessageHandler()
+	d := &ast.APIDoc{}
+	d.Parse(rslt.Handler, core.Block{Data: []byte(data)})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	openapi, err := convert(d)
+	a.NotError(err)
+	responses := openapi.Paths["/events"].Get.Responses
+
+	event := responses["200"].Content["application/json"].Schema
+	a.Equal(event.Type, "").
+		Equal(event.Required, []string{"kind"}).
+		Equal(2, len(event.OneOf)).
+		Empty(event.AnyOf).
+		Equal(event.OneOf[0], &Schema{Ref: "#/components/schemas/created"}).
+		Equal(event.OneOf[1].Title, "deleted").
+		Equal(event.OneOf[1].Required, []string{"reason"}).
+		Equal(event.Discriminator, &Discriminator{
+			PropertyName: "kind",
+			Mapping:      map[string]string{"created": "#/components/schemas/created"},
+		})
+
+	all := responses["201"].Content["application/json"].Schema
+	a.Equal(2, len(all.AllOf)).
+		Empty(all.OneOf).
+		Nil(all.Discriminator)
+
+	// 导入
+	data2, err := JSON(d)
+	a.NotError(err)
+	rslt = messagetest.NewMessageHandler()
+	doc, err := Import(rslt.Handler, "file:///openapi.json", data2)
+	rslt.Handler.Stop()
+	a.NotError(err).NotNil(doc)
+
+	var resp *ast.Request
+	for _, r := range doc.APIs[0].Responses {
+		if r.Status.V() == 200 {
+			resp = r
+		}
+	}
+	a.NotNil(resp).
+		Equal(resp.Discriminator.V(), "kind").
+		Equal(resp.ComposeType(), ast.ComposeOneOf).
+		Equal(2, len(resp.Variants)).
+		Equal(resp.Variants[0].Value.V(), "created").
+		Equal(resp.Variants[1].Value.V(), "deleted").
+		Equal(resp.Items[0].Name.V(), "kind")
+}
+
+func TestSchema_UnmarshalYAML(t *testing.T) {
+	a := assert.New(t, false)
+
+	s := &Schema{}
+	a.NotError(yaml.Unmarshal([]byte(`{"type":"object","additionalProperties":false}`), s))
+	a.Nil(s.AdditionalProperties).
+		NotNil(s.AdditionalPropertiesAllowed).
+		False(*s.AdditionalPropertiesAllowed).
+		False(isMapSchema(s))
+
+	s = &Schema{}
+	a.NotError(yaml.Unmarshal([]byte("type: object\nadditionalProperties: true\n"), s))
+	a.NotNil(s.AdditionalProperties).
+		True(*s.AdditionalPropertiesAllowed).
+		True(isMapSchema(s))
+
+	s = &Schema{}
+	a.NotError(yaml.Unmarshal([]byte("type: object\nadditionalProperties:\n  type: string\n"), s))
+	a.Equal(s.AdditionalProperties.Type, TypeString).
+		Nil(s.AdditionalPropertiesAllowed).
+		True(isMapSchema(s))
+
+	// 嵌套在其它对象中
+	s = &Schema{}
+	a.NotError(yaml.Unmarshal([]byte("type: object\nproperties:\n  p:\n    type: object\n    additionalProperties: false\n"), s))
+	a.Nil(s.Properties["p"].AdditionalProperties).
+		False(isMapSchema(s.Properties["p"]))
 }
@@ -2,6 +2,8 @@
 
 package openapi
 
+import "github.com/caixw/apidoc/v7/internal/ast"
+
 // SecurityScheme.IN 的可选值
 const (
 	SecurityInQuery  = "query"
@@ -11,7 +13,7 @@ const (
 
 // Security.Type 的可选值
 const (
-	SecurityTypeAPIKey        = "apikey"
+	SecurityTypeAPIKey        = "apiKey"
 	SecurityTypeHTTP          = "http"
 	SecurityTypeOAuth2        = "oauth2"
 	SecurityTypeOpenIDConnect = "openIdConnect"
@@ -28,12 +30,12 @@ type SecurityRequirement map[string][]string
 type SecurityScheme struct {
 	Type             string      `json:"type" yaml:"type"`
 	Description      string      `json:"description,omitempty" yaml:"description,omitempty"`
-	Name             string      `json:"name" yaml:"name"` // 报头或是 cookie 的名称
-	IN               string      `json:"in" yaml:"in"`     // 位置, header, query 和 cookie
-	Scheme           string      `json:"scheme" yaml:"scheme"`
+	Name             string      `json:"name,omitempty" yaml:"name,omitempty"` // 报头或是 cookie 的名称
+	IN               string      `json:"in,omitempty" yaml:"in,omitempty"`     // 位置, header, query 和 cookie
+	Scheme           string      `json:"scheme,omitempty" yaml:"scheme,omitempty"`
 	BearerFormat     string      `json:"bearerFormat,omitempty" yaml:"bearerFormat,omitempty"`
-	Flows            *OAuthFlows `json:"flows" yaml:"flows"`
-	OpenIDConnectURL string      `json:"openIdConnectUrl" yaml:"openIdConnectUrl"`
+	Flows            *OAuthFlows `json:"flows,omitempty" yaml:"flows,omitempty"`
+	OpenIDConnectURL string      `json:"openIdConnectUrl,omitempty" yaml:"openIdConnectUrl,omitempty"`
 
 	Ref string `json:"$ref,omitempty" yaml:"$ref,omitempty"`
 }
@@ -51,5 +53,86 @@ type OAuthFlow struct {
 	AuthorizationURL string            `json:"authorizationUrl,omitempty" yaml:"authorizationUrl,omitempty"`
 	TokenURL         string            `json:"tokenUrl,omitempty" yaml:"tokenUrl,omitempty"`
 	RefreshURL       string            `json:"refreshUrl,omitempty" yaml:"refreshUrl,omitempty"`
-	Scopes           map[string]string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+	Scopes           map[string]string `json:"scopes" yaml:"scopes"`
+}
+
+func newSecurityScheme(s *ast.Security) *SecurityScheme {
+	ss := &SecurityScheme{Description: getDescription(s.Description, s.Summary)}
+
+	switch s.Type.V() {
+	case ast.SecurityTypeAPIKey:
+		ss.Type = SecurityTypeAPIKey
+		ss.Name = s.Key.V()
+		ss.IN = s.In.V()
+	case ast.SecurityTypeHTTP:
+		ss.Type = SecurityTypeHTTP
+		ss.Scheme = s.Scheme.V()
+		ss.BearerFormat = s.BearerFormat.V()
+	case ast.SecurityTypeOAuth2:
+		ss.Type = SecurityTypeOAuth2
+		ss.Flows = &OAuthFlows{}
+		for _, f := range s.Flows {
+			flow := newOAuthFlow(f)
+			switch f.Type.V() {
+			case ast.OAuthFlowImplicit:
+				ss.Flows.Implicit = flow
+			case ast.OAuthFlowPassword:
+				ss.Flows.Password = flow
+			case ast.OAuthFlowClientCredentials:
+				ss.Flows.ClientCredentials = flow
+			case ast.OAuthFlowAuthorizationCode:
+				ss.Flows.AuthorizationCode = flow
+			}
+		}
+	case ast.SecurityTypeOpenIDConnect:
+		ss.Type = SecurityTypeOpenIDConnect
+		ss.OpenIDConnectURL = s.OpenIDConnectURL.V()
+	}
+
+	return ss
+}
+
+func newOAuthFlow(f *ast.OAuthFlow) *OAuthFlow {
+	flow := &OAuthFlow{
+		AuthorizationURL: f.AuthorizationURL.V(),
+		TokenURL:         f.TokenURL.V(),
+		RefreshURL:       f.RefreshURL.V(),
+		Scopes:           make(map[string]string, len(f.Scopes)),
+	}
+	for _, scope := range f.Scopes {
+		flow.Scopes[scope.Name.V()] = scope.Summary.V()
+	}
+	return flow
+}
+
+// 将 doc.Securities 转换成 components.securitySchemes
+func newSecuritySchemes(doc *ast.APIDoc) map[string]*SecurityScheme {
+	if len(doc.Securities) == 0 {
+		return nil
+	}
+
+	schemes := make(map[string]*SecurityScheme, len(doc.Securities))
+	for _, s := range doc.Securities {
+		schemes[s.Name.V()] = newSecurityScheme(s)
+	}
+	return schemes
+}
+
+// 将 api.Securities 转换成 operation.security
+//
+// api.Securities 中的每一项都是可选的方案之一，所以各自对应一个 SecurityRequirement。
+func newSecurityRequirements(api *ast.API) []*SecurityRequirement {
+	if len(api.Securities) == 0 {
+		return nil
+	}
+
+	requirements := make([]*SecurityRequirement, 0, len(api.Securities))
+	for _, sec := range api.Securities {
+		scopes := make([]string, 0, len(sec.Scopes))
+		for _, scope := range sec.Scopes {
+			scopes = append(scopes, scope.V())
+		}
+		requirements = append(requirements, &SecurityRequirement{sec.Name.V(): scopes})
+	}
+	return requirements
 }
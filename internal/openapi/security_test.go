@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: MIT
+
+package openapi
+
+import (
+	"testing"
+
+	"github.com/issue9/assert/v3"
+
+	"github.com/caixw/apidoc/v7/core"
+	"github.com/caixw/apidoc/v7/core/messagetest"
+	"github.com/caixw/apidoc/v7/internal/ast"
+)
+
+func TestNewSecuritySchemes(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Nil(newSecuritySchemes(&ast.APIDoc{}))
+
+	const data = `<apidoc version="1.0.0">
+	<title>title</title>
+	<mimetype>application/json</mimetype>
+	<security name="token" type="apikey" in="header" key="X-Token" summary="token" />
+	<security name="jwt" type="http" scheme="bearer" bearer-format="JWT" />
+	<security name="oidc" type="openid-connect" openid-connect-url="https://example.com/.well-known" />
+	<security name="oauth" type="oauth2">
+		<flow type="client-credentials" token-url="https://example.com/token">
+			<scope name="read" summary="read" />
+		</flow>
+		<flow type="authorization-code" authorization-url="https://example.com/auth" token-url="https://example.com/token" />
+	</security>
+	<api method="GET">
+		<path path="/users" />
+		<security name="token" />
+		<security name="oauth"><scope>read</scope></security>
+		<response status="200" type="string" summary="OK" />
+	</api>
+	</apidoc>`
+	rslt := messagetest.NewMessageHandler()
+	d := &ast.APIDoc{}
+	d.Parse(rslt.Handler, core.Block{Data: []byte(data)})
+	rslt.Handler.Stop()
+	a.Empty(rslt.Errors)
+
+	schemes := newSecuritySchemes(d)
+	a.Equal(4, len(schemes))
+	a.Equal(schemes["token"], &SecurityScheme{Type: SecurityTypeAPIKey, Description: "token", Name: "X-Token", IN: SecurityInHeader})
+	a.Equal(schemes["jwt"], &SecurityScheme{Type: SecurityTypeHTTP, Scheme: "bearer", BearerFormat: "JWT"})
+	a.Equal(schemes["oidc"], &SecurityScheme{Type: SecurityTypeOpenIDConnect, OpenIDConnectURL: "https://example.com/.well-known"})
+
+	oauth := schemes["oauth"]
+	a.Equal(oauth.Type, SecurityTypeOAuth2).
+		NotNil(oauth.Flows).
+		Nil(oauth.Flows.Implicit).
+		Equal(oauth.Flows.ClientCredentials.Scopes, map[string]string{"read": "read"}).
+		Equal(oauth.Flows.AuthorizationCode.AuthorizationURL, "https://example.com/auth").
+		NotNil(oauth.Flows.AuthorizationCode.Scopes)
+
+	openapi, err := convert(d)
+	a.NotError(err).
+		NotNil(openapi.Components).
+		Equal(openapi.Components.SecuritySchemes, schemes)
+	a.Equal(openapi.Paths["/users"].Get.Security, []*SecurityRequirement{
+		{"token": []string{}},
+		{"oauth": []string{"read"}},
+	})
+
+	// 导入
+	data2, err := JSON(d)
+	a.NotError(err)
+	rslt = messagetest.NewMessageHandler()
+	doc, err := Import(rslt.Handler, "file:///openapi.json", data2)
+	rslt.Handler.Stop()
+	a.NotError(err).NotNil(doc).
+		Equal(1, len(rslt.Warns)) // 仅 externalDocs 无法转换
+
+	a.Equal(4, len(doc.Securities))
+	token := doc.Security("token")
+	a.NotNil(token).
+		Equal(token.Type.V(), ast.SecurityTypeAPIKey).
+		Equal(token.Key.V(), "X-Token").
+		Equal(token.In.V(), ast.SecurityInHeader)
+	oauth2 := doc.Security("oauth")
+	a.NotNil(oauth2).
+		Equal(2, len(oauth2.Flows)).
+		Equal(oauth2.Flows[0].Type.V(), ast.OAuthFlowClientCredentials).
+		Equal(oauth2.Flows[1].Type.V(), ast.OAuthFlowAuthorizationCode)
+
+	secs := doc.APIs[0].Securities
+	a.Equal(2, len(secs)).
+		Equal(secs[0].Name.V(), "token").
+		Equal(secs[1].Name.V(), "oauth").
+		Equal(secs[1].Scopes[0].Content.Value, "read")
+}
+
+func TestImporter_newSecurities(t *testing.T) {
+	a := assert.New(t, false)
+
+	const data = `openapi: 3.0.3
+info:
+  title: test
+  version: 1.0.0
+security:
+  - token: []
+paths:
+  /users:
+    get:
+      responses:
+        '200':
+          description: OK
+    post:
+      security:
+        - token: []
+          basic: []
+        - {}
+      responses:
+        '200':
+          description: OK
+    delete:
+      security: []
+      responses:
+        '200':
+          description: OK
+components:
+  securitySchemes:
+    token:
+      type: apiKey
+      in: header
+      name: X-Token
+    basic:
+      type: http
+      scheme: basic
+`
+
+	rslt := messagetest.NewMessageHandler()
+	doc, err := Import(rslt.Handler, "file:///openapi.yaml", []byte(data))
+	rslt.Handler.Stop()
+	a.NotError(err).NotNil(doc)
+
+	a.Equal(1, len(rslt.Warns))
+	w, ok := rslt.Warns[0].(*core.Error)
+	a.True(ok).Equal(w.Field, "/paths/~1users/post/security/0")
+
+	a.Equal(3, len(doc.APIs))
+	for _, api := range doc.APIs {
+		switch api.Method.V() {
+		case "GET": // 采用全局的 security
+			a.Equal(1, len(api.Securities)).Equal(api.Securities[0].Name.V(), "token")
+		default:
+			a.Empty(api.Securities)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: MIT
+
+package postman
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/caixw/apidoc/v7/internal/ast"
+)
+
+// 各类型在未指定默认值和枚举值时的示例值
+var placeholders = map[string]string{
+	ast.TypeBool:     "false",
+	ast.TypeNumber:   "0",
+	ast.TypeInt:      "0",
+	ast.TypeFloat:    "0",
+	ast.TypeEmail:    "user@example.com",
+	ast.TypeURL:      "https://example.com",
+	ast.TypeImage:    "https://example.com/image.png",
+	ast.TypeDate:     "2006-01-02",
+	ast.TypeTime:     "15:04:05Z",
+	ast.TypeDateTime: "2006-01-02T15:04:05Z",
+}
+
+// 保持字段顺序的 JSON 对象
+type object []*field
+
+type field struct {
+	key   string
+	value any
+}
+
+// MarshalJSON json.Marshaler
+func (o object) MarshalJSON() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(f.key)
+		if err != nil {
+			return nil, err
+		}
+		val, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// 生成 r 对应的内容
+//
+// 优先采用与 mimetype 相匹配的示例代码，没有示例代码时，仅 JSON 格式的内容会根据参数生成。
+// request 表示是否为请求的内容，请求中不包含只读字段，返回中不包含只写字段。
+func newBody(r *ast.Request, mimetype string, request bool) *Body {
+	switch mimetype {
+	case "application/x-www-form-urlencoded":
+		return &Body{Mode: "urlencoded", URLEncoded: newFormValues(r, request)}
+	case "multipart/form-data":
+		return &Body{Mode: "formdata", FormData: newFormValues(r, request)}
+	}
+
+	raw := findExample(r.Examples, mimetype)
+	if raw == "" && isJSON(mimetype) {
+		if data, err := json.MarshalIndent(jsonValue(r.Param(), request), "", "\t"); err == nil {
+			raw = string(data)
+		}
+	}
+
+	return &Body{
+		Mode:    "raw",
+		Raw:     raw,
+		Options: &Options{Raw: &RawOptions{Language: language(mimetype)}},
+	}
+}
+
+func newFormValues(r *ast.Request, request bool) []*KeyValue {
+	items := filterItems(r.Items, request)
+	values := make([]*KeyValue, 0, len(items))
+	for _, item := range items {
+		kv := newKeyValue(item)
+		kv.Type = "text"
+		if item.Type.V() == ast.TypeFile {
+			kv.Type = "file"
+		}
+		values = append(values, kv)
+	}
+	return values
+}
+
+func findExample(examples []*ast.Example, mimetype string) string {
+	for _, exp := range examples {
+		if exp.Mimetype.V() == mimetype && exp.Content != nil {
+			return strings.TrimSpace(exp.Content.Value.Value)
+		}
+	}
+	return ""
+}
+
+func jsonValue(p *ast.Param, request bool) any {
+	v := jsonItemValue(p, request)
+	if p.Array.V() {
+		return []any{v}
+	}
+	return v
+}
+
+func jsonItemValue(p *ast.Param, request bool) any {
+	primitive, _ := ast.ParseType(p.Type.V())
+	switch primitive {
+	case ast.TypeNone:
+		return nil
+	case ast.TypeObject:
+		items := p.Items
+		if len(p.Variants) > 0 { // 变体仅取第一个
+			items = append(append([]*ast.Param{}, items...), p.Variants[0].Items...)
+		}
+
+		obj := make(object, 0, len(items))
+		for _, item := range filterItems(items, request) {
+			obj = append(obj, &field{key: item.Name.V(), value: jsonValue(item, request)})
+		}
+		return obj
+	case ast.TypeMap:
+		return object{{key: "key", value: jsonValue(p.MapValue(), request)}}
+	}
+
+	v := paramValue(p)
+	switch primitive {
+	case ast.TypeBool:
+		b, _ := strconv.ParseBool(v)
+		return b
+	case ast.TypeNumber:
+		n, _ := strconv.ParseFloat(v, 64)
+		return n
+	default:
+		return v
+	}
+}
+
+// 过滤掉当前方向上不可用的字段
+func filterItems(items []*ast.Param, request bool) []*ast.Param {
+	ret := make([]*ast.Param, 0, len(items))
+	for _, item := range items {
+		if (request && item.ReadOnly.V()) || (!request && item.WriteOnly.V()) {
+			continue
+		}
+		ret = append(ret, item)
+	}
+	return ret
+}
+
+// 简单类型的示例值
+//
+// 依次采用默认值、第一个枚举值以及类型对应的占位值。
+func paramValue(p *ast.Param) string {
+	if v := p.Default.V(); v != "" {
+		return v
+	}
+	if len(p.Enums) > 0 {
+		return p.Enums[0].Value.V()
+	}
+	return placeholders[p.Type.V()]
+}
+
+func isJSON(mimetype string) bool {
+	return mimetype == "application/json" || strings.HasSuffix(mimetype, "+json")
+}
+
+// 原始内容的语言，无法识别的均作为 text。
+func language(mimetype string) string {
+	if syntax := ast.Syntax(mimetype); syntax != "" {
+		return syntax
+	}
+	return "text"
+}
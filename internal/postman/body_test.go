@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: MIT
+
+package postman
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/issue9/assert/v3"
+
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+func TestObject_MarshalJSON(t *testing.T) {
+	a := assert.New(t, false)
+
+	data, err := json.Marshal(object{{key: "z", value: 1}, {key: "a", value: "a"}})
+	a.NotError(err).Equal(string(data), `{"z":1,"a":"a"}`)
+
+	data, err = json.Marshal(object{})
+	a.NotError(err).Equal(string(data), `{}`)
+}
+
+func TestJSONValue(t *testing.T) {
+	a := assert.New(t, false)
+
+	status := newParam("status", ast.TypeString)
+	status.Enums = []*ast.Enum{{Value: &ast.Attribute{Value: xmlenc.String{Value: "active"}}}}
+	age := newParam("age", ast.TypeInt)
+	age.Default = &ast.Attribute{Value: xmlenc.String{Value: "18"}}
+	password := newParam("password", ast.TypeString)
+	password.WriteOnly = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+	id := newParam("id", ast.TypeNumber)
+	id.ReadOnly = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+	tags := newParam("tags", ast.TypeString)
+	tags.Array = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+	attrs := newParam("attrs", "map.bool")
+
+	p := newParam("", ast.TypeObject)
+	p.Items = []*ast.Param{id, status, age, password, tags, attrs, newParam("email", ast.TypeEmail)}
+
+	data, err := json.Marshal(jsonValue(p, true))
+	a.NotError(err).
+		Equal(string(data), `{"status":"active","age":18,"password":"","tags":[""],"attrs":{"key":false},"email":"user@example.com"}`)
+
+	data, err = json.Marshal(jsonValue(p, false))
+	a.NotError(err).
+		Equal(string(data), `{"id":0,"status":"active","age":18,"tags":[""],"attrs":{"key":false},"email":"user@example.com"}`)
+}
+
+func TestNewBody(t *testing.T) {
+	a := assert.New(t, false)
+
+	file := newParam("avatar", ast.TypeFile)
+	r := &ast.Request{
+		Type:  &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeObject}},
+		Items: []*ast.Param{newParam("name", ast.TypeString), file},
+		Examples: []*ast.Example{
+			{
+				Mimetype: &ast.Attribute{Value: xmlenc.String{Value: "application/xml"}},
+				Content:  &ast.ExampleValue{Value: xmlenc.String{Value: "\n<root />\n"}},
+			},
+		},
+	}
+
+	body := newBody(r, "multipart/form-data", true)
+	a.Equal(body.Mode, "formdata").
+		Equal(2, len(body.FormData)).
+		Equal(body.FormData[0].Type, "text").
+		Equal(body.FormData[1].Type, "file")
+
+	body = newBody(r, "application/x-www-form-urlencoded", true)
+	a.Equal(body.Mode, "urlencoded").
+		Equal(2, len(body.URLEncoded))
+
+	body = newBody(r, "application/xml", true)
+	a.Equal(body.Mode, "raw").
+		Equal(body.Raw, "<root />").
+		Equal(body.Options.Raw.Language, "xml")
+
+	// 非 JSON 且无示例代码
+	body = newBody(r, "text/plain", true)
+	a.Equal(body.Mode, "raw").
+		Empty(body.Raw).
+		Equal(body.Options.Raw.Language, "text")
+}
+
+func TestLanguage(t *testing.T) {
+	a := assert.New(t, false)
+
+	a.Equal(language("application/json"), "json").
+		Equal(language("application/problem+json"), "json").
+		Equal(language("text/xml"), "xml").
+		Equal(language("application/atom+xml"), "xml").
+		Equal(language("text/plain"), "text")
+}
@@ -0,0 +1,268 @@
+// SPDX-License-Identifier: MIT
+
+package postman
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/caixw/apidoc/v7/internal/ast"
+)
+
+// 文档中未定义任何服务时，请求地址所采用的变量名
+const baseURLVariable = "baseUrl"
+
+// 将 ast.APIDoc 转换成 Collection
+//
+// 每个标签对应一个目录，未指定标签的 API 直接放在根目录；
+// 每个服务对应一个变量，API 的请求地址以该变量作为前缀。
+func convert(doc *ast.APIDoc) *Collection {
+	c := &Collection{
+		Info: &Info{
+			Name:        doc.Title.V(),
+			Description: doc.Description.V(),
+			Schema:      Schema,
+		},
+		Items:     make([]*Item, 0, len(doc.Tags)+len(doc.APIs)),
+		Variables: make([]*Variable, 0, len(doc.Servers)),
+	}
+	if doc.Version != nil {
+		c.Info.Version = doc.Version.V()
+	}
+
+	for _, srv := range doc.Servers {
+		c.Variables = append(c.Variables, &Variable{
+			Key:         srv.Name.V(),
+			Value:       srv.URL.V(),
+			Type:        "string",
+			Description: ast.Description(srv.Description, srv.Summary),
+		})
+	}
+	if len(doc.Servers) == 0 {
+		c.Variables = append(c.Variables, &Variable{Key: baseURLVariable, Type: "string"})
+	}
+
+	folders := make(map[string]*Item, len(doc.Tags))
+	for _, tag := range doc.Tags {
+		name := tag.Title.V()
+		if name == "" {
+			name = tag.Name.V()
+		}
+		folders[tag.Name.V()] = &Item{Name: name}
+	}
+
+	items := make([]*Item, 0, len(doc.APIs)) // 未指定标签的 API
+	for _, api := range doc.APIs {
+		item := newItem(doc, api)
+
+		var found bool
+		for _, tag := range api.Tags {
+			if folder, exists := folders[tag.V()]; exists {
+				folder.Items = append(folder.Items, item)
+				found = true
+			}
+		}
+		if !found {
+			items = append(items, item)
+		}
+	}
+
+	for _, tag := range doc.Tags { // 保证目录的顺序与标签的定义顺序相同
+		if folder := folders[tag.Name.V()]; len(folder.Items) > 0 {
+			c.Items = append(c.Items, folder)
+		}
+	}
+	c.Items = append(c.Items, items...)
+
+	return c
+}
+
+func newItem(doc *ast.APIDoc, api *ast.API) *Item {
+	name := api.Summary.V()
+	if name == "" {
+		name = api.Method.V() + " " + api.Path.Path.V()
+	}
+
+	req := newRequest(doc, api)
+	item := &Item{
+		Name:      name,
+		Request:   req,
+		Responses: make([]*Response, 0, len(api.Responses)),
+	}
+	for _, resp := range api.Responses {
+		item.Responses = append(item.Responses, newResponse(doc, resp, req))
+	}
+
+	return item
+}
+
+func newRequest(doc *ast.APIDoc, api *ast.API) *Request {
+	req := &Request{
+		Method:      api.Method.V(),
+		Header:      make([]*KeyValue, 0, len(doc.Headers)+len(api.Headers)),
+		URL:         newURL(doc, api),
+		Description: ast.Description(api.Description, api.Summary),
+	}
+
+	body, mimetype := findRequest(doc, api.Requests)
+	headers := append(append([]*ast.Param{}, doc.Headers...), api.Headers...)
+	cookies := api.Cookies
+	if body != nil {
+		headers = append(headers, body.Headers...)
+		cookies = append(append([]*ast.Param{}, cookies...), body.Cookies...)
+	}
+
+	for _, header := range headers {
+		req.Header = append(req.Header, newKeyValue(header))
+	}
+	if cookie := newCookieHeader(cookies); cookie != nil {
+		req.Header = append(req.Header, cookie)
+	}
+
+	if body != nil && body.Type.V() != ast.TypeNone {
+		req.Header = append(req.Header, &KeyValue{Key: "Content-Type", Value: mimetype})
+		req.Body = newBody(body, mimetype, true)
+	}
+
+	return req
+}
+
+// 从 requests 中查找用于生成请求内容的对象
+//
+// 优先采用 JSON 格式的内容，找不到则返回第一个元素。
+// 返回的 mimetype 为该对象的 mimetype，若未指定，则采用文档中的第一个 mimetype。
+func findRequest(doc *ast.APIDoc, requests []*ast.Request) (*ast.Request, string) {
+	var req *ast.Request
+	var mimetype string
+	for _, r := range requests {
+		mt := getMimetype(doc, r)
+		if req == nil || (isJSON(mt) && !isJSON(mimetype)) {
+			req, mimetype = r, mt
+		}
+	}
+	return req, mimetype
+}
+
+func newResponse(doc *ast.APIDoc, resp *ast.Request, original *Request) *Response {
+	status := resp.Status.V()
+	name := resp.Summary.V()
+	if name == "" {
+		name = strconv.Itoa(status) + " " + http.StatusText(status)
+	}
+
+	r := &Response{
+		Name:            name,
+		OriginalRequest: original,
+		Status:          http.StatusText(status),
+		Code:            status,
+		Header:          make([]*KeyValue, 0, len(resp.Headers)+1),
+	}
+
+	for _, header := range resp.Headers {
+		r.Header = append(r.Header, newKeyValue(header))
+	}
+	for _, cookie := range resp.Cookies {
+		r.Header = append(r.Header, &KeyValue{Key: "Set-Cookie", Value: cookie.Name.V() + "=" + paramValue(cookie)})
+	}
+
+	if resp.Type.V() != ast.TypeNone {
+		mimetype := getMimetype(doc, resp)
+		r.Header = append(r.Header, &KeyValue{Key: "Content-Type", Value: mimetype})
+		if body := newBody(resp, mimetype, false); body != nil {
+			r.Body = body.Raw
+		}
+	}
+
+	return r
+}
+
+func newURL(doc *ast.APIDoc, api *ast.API) *URL {
+	host := "{{" + getServer(doc, api) + "}}"
+	path := api.Path.Path.V()
+
+	u := &URL{
+		Host:      []string{host},
+		Query:     make([]*KeyValue, 0, len(api.Path.Queries)),
+		Variables: make([]*Variable, 0, len(api.Path.Params)),
+	}
+
+	for _, param := range api.Path.Params {
+		path = strings.ReplaceAll(path, "{"+param.Name.V()+"}", ":"+param.Name.V())
+		u.Variables = append(u.Variables, &Variable{
+			Key:         param.Name.V(),
+			Value:       paramValue(param),
+			Description: ast.Description(param.Description, param.Summary),
+		})
+	}
+
+	path = strings.Trim(path, "/")
+	if path != "" {
+		u.Path = strings.Split(path, "/")
+	}
+
+	queries := make([]string, 0, len(api.Path.Queries))
+	for _, query := range api.Path.Queries {
+		kv := newKeyValue(query)
+		u.Query = append(u.Query, kv)
+		if !kv.Disabled {
+			queries = append(queries, kv.Key+"="+kv.Value)
+		}
+	}
+
+	u.Raw = host + "/" + path
+	if len(queries) > 0 {
+		u.Raw += "?" + strings.Join(queries, "&")
+	}
+
+	return u
+}
+
+// 获取 API 请求地址所采用的变量名
+func getServer(doc *ast.APIDoc, api *ast.API) string {
+	for _, srv := range api.Servers {
+		for _, s := range doc.Servers {
+			if s.Name.V() == srv.V() {
+				return s.Name.V()
+			}
+		}
+	}
+
+	if len(doc.Servers) > 0 {
+		return doc.Servers[0].Name.V()
+	}
+	return baseURLVariable
+}
+
+func newKeyValue(p *ast.Param) *KeyValue {
+	return &KeyValue{
+		Key:         p.Name.V(),
+		Value:       paramValue(p),
+		Description: ast.Description(p.Description, p.Summary),
+		Disabled:    p.Optional.V(),
+	}
+}
+
+// 将 cookies 合并成 Cookie 报头，cookies 为空时返回 nil。
+func newCookieHeader(cookies []*ast.Param) *KeyValue {
+	if len(cookies) == 0 {
+		return nil
+	}
+
+	pairs := make([]string, 0, len(cookies))
+	for _, cookie := range cookies {
+		pairs = append(pairs, cookie.Name.V()+"="+paramValue(cookie))
+	}
+	return &KeyValue{Key: "Cookie", Value: strings.Join(pairs, "; ")}
+}
+
+func getMimetype(doc *ast.APIDoc, r *ast.Request) string {
+	if mt := r.Mimetype.V(); mt != "" {
+		return mt
+	}
+
+	if len(doc.Mimetypes) > 0 {
+		return doc.Mimetypes[0].Content.Value
+	}
+	return ""
+}
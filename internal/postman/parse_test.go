@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: MIT
+
+package postman
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/issue9/assert/v3"
+
+	"github.com/caixw/apidoc/v7/internal/ast"
+	"github.com/caixw/apidoc/v7/internal/xmlenc"
+)
+
+func newParam(name, typ string) *ast.Param {
+	return &ast.Param{
+		Name: &ast.Attribute{Value: xmlenc.String{Value: name}},
+		Type: &ast.TypeAttribute{Value: xmlenc.String{Value: typ}},
+	}
+}
+
+func TestConvert(t *testing.T) {
+	a := assert.New(t, false)
+
+	doc := &ast.APIDoc{
+		Title: &ast.Element{Content: ast.Content{Value: "test"}},
+		Tags: []*ast.Tag{
+			{Name: &ast.Attribute{Value: xmlenc.String{Value: "t1"}}},
+			{Name: &ast.Attribute{Value: xmlenc.String{Value: "empty"}}},
+		},
+		APIs: []*ast.API{
+			{
+				Method: &ast.MethodAttribute{Value: xmlenc.String{Value: http.MethodGet}},
+				Path:   &ast.Path{Path: &ast.Attribute{Value: xmlenc.String{Value: "/users"}}},
+				Tags:   []*ast.TagValue{{Content: ast.Content{Value: "t1"}}},
+			},
+			{
+				Method: &ast.MethodAttribute{Value: xmlenc.String{Value: http.MethodDelete}},
+				Path:   &ast.Path{Path: &ast.Attribute{Value: xmlenc.String{Value: "/users"}}},
+			},
+		},
+	}
+
+	c := convert(doc)
+	a.Equal(1, len(c.Variables)).
+		Equal(c.Variables[0].Key, baseURLVariable)
+
+	// 没有 API 的标签不会生成目录，未指定标签的 API 放在根目录。
+	a.Equal(2, len(c.Items)).
+		Equal(c.Items[0].Name, "t1").
+		Equal(1, len(c.Items[0].Items)).
+		Equal(c.Items[1].Name, "DELETE /users").
+		Equal(c.Items[1].Request.URL.Raw, "{{baseUrl}}/users")
+}
+
+func TestNewURL(t *testing.T) {
+	a := assert.New(t, false)
+
+	doc := &ast.APIDoc{
+		Servers: []*ast.Server{
+			{Name: &ast.Attribute{Value: xmlenc.String{Value: "admin"}}},
+			{Name: &ast.Attribute{Value: xmlenc.String{Value: "client"}}},
+		},
+	}
+
+	id := newParam("id", ast.TypeNumber)
+	id.Default = &ast.Attribute{Value: xmlenc.String{Value: "5"}}
+	page := newParam("page", ast.TypeNumber)
+	size := newParam("size", ast.TypeNumber)
+	size.Optional = &ast.BoolAttribute{Value: ast.Bool{Value: true}}
+
+	api := &ast.API{
+		Path: &ast.Path{
+			Path:    &ast.Attribute{Value: xmlenc.String{Value: "/users/{id}/posts"}},
+			Params:  []*ast.Param{id},
+			Queries: []*ast.Param{page, size},
+		},
+		Servers: []*ast.ServerValue{{Content: ast.Content{Value: "client"}}},
+	}
+
+	u := newURL(doc, api)
+	a.Equal(u.Raw, "{{client}}/users/:id/posts?page=0").
+		Equal(u.Host, []string{"{{client}}"}).
+		Equal(u.Path, []string{"users", ":id", "posts"}).
+		Equal(1, len(u.Variables)).
+		Equal(u.Variables[0].Value, "5").
+		Equal(2, len(u.Query)).
+		True(u.Query[1].Disabled)
+
+	// 未指定 servers，采用文档中的第一个。
+	api.Servers = nil
+	u = newURL(doc, api)
+	a.Equal(u.Host, []string{"{{admin}}"})
+}
+
+func TestNewRequest(t *testing.T) {
+	a := assert.New(t, false)
+
+	doc := &ast.APIDoc{
+		Headers:   []*ast.Param{newParam("authorization", ast.TypeString)},
+		Mimetypes: []*ast.Element{{Content: ast.Content{Value: "application/xml"}}},
+	}
+	api := &ast.API{
+		Method:  &ast.MethodAttribute{Value: xmlenc.String{Value: http.MethodPost}},
+		Path:    &ast.Path{Path: &ast.Attribute{Value: xmlenc.String{Value: "/users"}}},
+		Cookies: []*ast.Param{newParam("sid", ast.TypeString)},
+		Requests: []*ast.Request{
+			{
+				Type:  &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeObject}},
+				Items: []*ast.Param{newParam("name", ast.TypeString)},
+			},
+			{
+				Type:     &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeObject}},
+				Mimetype: &ast.Attribute{Value: xmlenc.String{Value: "application/json"}},
+				Items:    []*ast.Param{newParam("name", ast.TypeString)},
+				Cookies:  []*ast.Param{newParam("lang", ast.TypeString)},
+			},
+		},
+	}
+
+	req := newRequest(doc, api)
+	a.Equal(req.Method, http.MethodPost).
+		Equal(3, len(req.Header)).
+		Equal(req.Header[0].Key, "authorization").
+		Equal(req.Header[1].Key, "Cookie").
+		Equal(req.Header[1].Value, "sid=; lang=").
+		Equal(req.Header[2].Key, "Content-Type").
+		Equal(req.Header[2].Value, "application/json"). // 优先采用 JSON
+		Equal(req.Body.Raw, "{\n\t\"name\": \"\"\n}")
+
+	// 没有请求内容
+	api.Requests = nil
+	api.Cookies = nil
+	req = newRequest(doc, api)
+	a.Equal(1, len(req.Header)).Nil(req.Body)
+}
+
+func TestNewResponse(t *testing.T) {
+	a := assert.New(t, false)
+
+	doc := &ast.APIDoc{
+		Mimetypes: []*ast.Element{{Content: ast.Content{Value: "application/json"}}},
+	}
+	resp := &ast.Request{
+		Status:  &ast.StatusAttribute{Value: ast.Number{Int: http.StatusOK}},
+		Type:    &ast.TypeAttribute{Value: xmlenc.String{Value: ast.TypeString}},
+		Headers: []*ast.Param{newParam("x-total", ast.TypeNumber)},
+		Cookies: []*ast.Param{newParam("sid", ast.TypeString)},
+	}
+
+	r := newResponse(doc, resp, nil)
+	a.Equal(r.Name, "200 OK").
+		Equal(r.Code, http.StatusOK).
+		Equal(r.Status, "OK").
+		Equal(3, len(r.Header)).
+		Equal(r.Header[1].Key, "Set-Cookie").
+		Equal(r.Header[2].Value, "application/json").
+		Equal(r.Body, `""`)
+
+	// 没有内容
+	resp.Type = nil
+	resp.Summary = &ast.Attribute{Value: xmlenc.String{Value: "summary"}}
+	r = newResponse(doc, resp, nil)
+	a.Equal(r.Name, "summary").
+		Equal(2, len(r.Header)).
+		Empty(r.Body)
+}
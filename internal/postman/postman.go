@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: MIT
+
+// Package postman 实现 postman collection 的相关数据类型
+//
+// https://schema.postman.com/collection/json/v2.1.0/draft-07/docs/index.html
+package postman
+
+import (
+	"encoding/json"
+
+	"github.com/caixw/apidoc/v7/internal/ast"
+)
+
+// Schema postman collection v2.1 的 schema 地址
+const Schema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// Collection postman collection 的根对象
+type Collection struct {
+	Info      *Info       `json:"info"`
+	Items     []*Item     `json:"item"`
+	Variables []*Variable `json:"variable,omitempty"`
+}
+
+// Info collection 的基本信息
+type Info struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+// Item 表示一个请求或是一个目录
+//
+// 目录仅包含 Items，请求则包含 Request 和 Responses。
+type Item struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Items       []*Item     `json:"item,omitempty"`
+	Request     *Request    `json:"request,omitempty"`
+	Responses   []*Response `json:"response,omitempty"`
+}
+
+// Request 请求内容
+type Request struct {
+	Method      string      `json:"method"`
+	Header      []*KeyValue `json:"header"`
+	Body        *Body       `json:"body,omitempty"`
+	URL         *URL        `json:"url"`
+	Description string      `json:"description,omitempty"`
+}
+
+// Response 作为示例的返回内容
+type Response struct {
+	Name            string      `json:"name"`
+	OriginalRequest *Request    `json:"originalRequest,omitempty"`
+	Status          string      `json:"status,omitempty"`
+	Code            int         `json:"code"`
+	Header          []*KeyValue `json:"header"`
+	Body            string      `json:"body,omitempty"`
+}
+
+// KeyValue 报头、查询参数以及表单的键值对
+type KeyValue struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Type        string `json:"type,omitempty"` // 仅用于 formdata，可以是 text 或是 file
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+// Body 请求或返回的内容
+type Body struct {
+	Mode       string      `json:"mode"` // raw、urlencoded 或是 formdata
+	Raw        string      `json:"raw,omitempty"`
+	URLEncoded []*KeyValue `json:"urlencoded,omitempty"`
+	FormData   []*KeyValue `json:"formdata,omitempty"`
+	Options    *Options    `json:"options,omitempty"`
+}
+
+// Options 内容的附加选项
+type Options struct {
+	Raw *RawOptions `json:"raw,omitempty"`
+}
+
+// RawOptions raw 模式下的附加选项
+type RawOptions struct {
+	Language string `json:"language"` // json、xml 或是 text
+}
+
+// URL 请求地址
+type URL struct {
+	Raw       string      `json:"raw"`
+	Host      []string    `json:"host,omitempty"`
+	Path      []string    `json:"path,omitempty"`
+	Query     []*KeyValue `json:"query,omitempty"`
+	Variables []*Variable `json:"variable,omitempty"`
+}
+
+// Variable 变量
+//
+// 可以作为 collection 的全局变量，也可以是地址中的路径参数。
+type Variable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// JSON 输出 JSON 格式数据
+func JSON(doc *ast.APIDoc) ([]byte, error) {
+	return json.MarshalIndent(convert(doc), "", "\t")
+}
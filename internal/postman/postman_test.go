@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: MIT
+
+package postman
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/issue9/assert/v3"
+
+	"github.com/caixw/apidoc/v7/internal/ast/asttest"
+)
+
+func TestJSON(t *testing.T) {
+	a := assert.New(t, false)
+	data, err := JSON(asttest.Get())
+	a.NotError(err).NotNil(data)
+
+	c := &Collection{}
+	a.NotError(json.Unmarshal(data, c))
+	a.Equal(c.Info.Name, "test").
+		Equal(c.Info.Version, "1.0.1").
+		Equal(c.Info.Schema, Schema)
+
+	a.Equal(2, len(c.Variables)).
+		Equal(c.Variables[0].Key, "admin").
+		Equal(c.Variables[0].Value, "https://example.com/admin")
+
+	// 三个标签对应三个目录
+	a.Equal(3, len(c.Items)).
+		Equal(c.Items[0].Name, "t1").
+		Equal(2, len(c.Items[0].Items)).
+		Equal(c.Items[1].Name, "t2").
+		Equal(1, len(c.Items[1].Items)).
+		Equal(c.Items[2].Name, "tag1").
+		Equal(1, len(c.Items[2].Items))
+
+	get := c.Items[0].Items[0]
+	a.Equal(get.Request.Method, "GET").
+		Equal(get.Request.URL.Raw, "{{admin}}/users").
+		Equal(1, len(get.Responses)).
+		Equal(get.Responses[0].Code, 200)
+
+	post := c.Items[0].Items[1]
+	a.Equal(post.Request.Method, "POST").
+		Equal(post.Name, "summary").
+		NotNil(post.Request.Body).
+		Equal(post.Request.Body.Raw, "xxx"). // 采用示例代码
+		Equal(post.Request.Body.Options.Raw.Language, "json")
+}
@@ -25,9 +25,18 @@ type AttrEncoder interface {
 	EncodeXMLAttr() (string, error)
 }
 
+// Omitter 在编码时忽略部分属性和子元素
+//
+// 适用于那些在解析之后由其它内容填充的字段，比如引用了其它类型的对象。
+type Omitter interface {
+	// OmitXML 是否忽略名称为 name 的属性或是子元素
+	OmitXML(name string) bool
+}
+
 var (
 	attrEncoderType = reflect.TypeOf((*AttrEncoder)(nil)).Elem()
 	encoderType     = reflect.TypeOf((*Encoder)(nil)).Elem()
+	omitterType     = reflect.TypeOf((*Omitter)(nil)).Elem()
 )
 
 // Encode 将 v 转换成 XML 内容
@@ -43,7 +52,7 @@ func Encode(indent string, v any, namespace, prefix string) ([]byte, error) {
 	e := xml.NewEncoder(buf)
 	e.Indent("", indent)
 
-	if err := encode(node.New("", reflect.ValueOf(v)), e, namespace, prefix, true); err != nil {
+	if err := encode(newNode("", reflect.ValueOf(v)), e, namespace, prefix, true); err != nil {
 		return nil, err
 	}
 
@@ -139,7 +148,36 @@ func encodeElement(e *xml.Encoder, v *node.Value, namespace, prefix string) (err
 		return nil
 	}
 
-	return encode(node.New(v.Name, v.Value), e, namespace, prefix, false)
+	return encode(newNode(v.Name, v.Value), e, namespace, prefix, false)
+}
+
+// 声明 node.Node 实例，并去掉 Omitter 指定忽略的内容。
+func newNode(name string, rv reflect.Value) *node.Node {
+	n := node.New(name, rv)
+
+	var o Omitter
+	if rv.CanInterface() && rv.Type().Implements(omitterType) {
+		o = rv.Interface().(Omitter)
+	} else if rv.CanAddr() && rv.Addr().CanInterface() && rv.Addr().Type().Implements(omitterType) {
+		o = rv.Addr().Interface().(Omitter)
+	}
+	if o == nil || (rv.Kind() == reflect.Pointer && rv.IsNil()) {
+		return n
+	}
+
+	n.Attributes = omitValues(o, n.Attributes)
+	n.Elements = omitValues(o, n.Elements)
+	return n
+}
+
+func omitValues(o Omitter, values []*node.Value) []*node.Value {
+	ret := make([]*node.Value, 0, len(values))
+	for _, v := range values {
+		if !o.OmitXML(v.Name) {
+			ret = append(ret, v)
+		}
+	}
+	return ret
 }
 
 func buildStartElement(n *node.Node, namespace, prefix string, root bool) (xml.StartElement, error) {
@@ -300,6 +300,39 @@ func TestEncode(t *testing.T) {
 	}
 }
 
+type omitObject struct {
+	RootName string   `apidoc:"root,meta,usage-root"`
+	ID       *intAttr `apidoc:"id,attr,usage,omitempty"`
+	Elem     *intTag  `apidoc:"elem,elem,usage,omitempty"`
+	Name     *intTag  `apidoc:"name,elem,usage,omitempty"`
+	omit     bool
+}
+
+func (o *omitObject) OmitXML(name string) bool {
+	return o.omit && (name == "id" || name == "elem")
+}
+
+func TestEncode_omitter(t *testing.T) {
+	a := assert.New(t, false)
+
+	type root struct {
+		RootName string        `apidoc:"apidoc,meta,usage-apidoc"`
+		Objects  []*omitObject `apidoc:"object,elem,usage"`
+	}
+
+	v := &root{Objects: []*omitObject{
+		{ID: &intAttr{Value: 1}, Elem: &intTag{Value: 2}, Name: &intTag{Value: 3}},
+		{ID: &intAttr{Value: 1}, Elem: &intTag{Value: 2}, Name: &intTag{Value: 3}, omit: true},
+	}}
+	data, err := Encode("", v, "", "")
+	a.NotError(err).
+		Equal(string(data), `<apidoc><object id="1"><elem>2</elem><name>3</name></object><object><name>3</name></object></apidoc>`)
+
+	// 顶层元素
+	data, err = Encode("", v.Objects[1], "", "")
+	a.NotError(err).Equal(string(data), `<root><name>3</name></root>`)
+}
+
 func TestNode_isOmitempty(t *testing.T) {
 	a := assert.New(t, false)
 
@@ -5,6 +5,7 @@ package apidoc
 import (
 	"math/rand"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/issue9/rands"
@@ -27,6 +28,20 @@ func (r *Range) sanitize() *core.Error {
 	return nil
 }
 
+// Latency 表示延迟的时间范围
+//
+// Max 小于等于 Min 时，表示固定延迟 Min，否则在 [Min, Max] 之间随机取值。
+type Latency struct {
+	Min, Max time.Duration
+}
+
+func (l Latency) sanitize() *core.Error {
+	if l.Min < 0 {
+		return core.NewError(locale.ErrInvalidValue).WithField("Min")
+	}
+	return nil
+}
+
 // MockOptions mock 的一些随机设置项
 type MockOptions struct {
 	Indent    string            // 缩进字符串
@@ -45,6 +60,68 @@ type MockOptions struct {
 
 	ImageBasePrefix string // 图片的基地址
 
+	// 是否启用有状态模式
+	//
+	// 启用后，对集合路径的 POST、PUT、PATCH 和 DELETE 请求会在内存中添加、修改和删除数据，
+	// 以地址中最后一个参数作为资源的 ID，GET 请求则优先返回这些数据。
+	// 仅对 JSON 格式的内容有效。
+	Stateful bool
+
+	// 是否优先使用文档中的示例代码作为返回内容
+	//
+	// 启用后，会查找与 Accept 报头相匹配的 example 作为返回内容，
+	// 还可以通过 X-Apidoc-Example 报头指定 summary 与之相同的示例代码。
+	Examples bool
+
+	// 是否验证 API 要求的安全验证方案
+	//
+	// 启用后，未提供文档中 security 所要求凭证的请求会返回 401，
+	// 仅检测凭证是否存在，并不验证其值是否正确。
+	Security bool
+
+	// 是否发送 API 中定义的回调
+	//
+	// 启用后，包含 X-Apidoc-Callback 报头的请求在处理完成之后，
+	// 会根据 API 中 callback 的定义向该报头指定的地址发送请求。
+	Callback bool
+
+	// 延迟与故障注入
+	//
+	// 用于模拟网络延迟以及服务端的各类故障，概率值的范围为 [0, 1]，0 表示不启用。
+	// 每个请求都可以通过 X-Apidoc-Fault 报头覆盖这些设置，比如：
+	//  X-Apidoc-Fault: latency=100ms-500ms, error=0.5, server-error=0.1, reset=0.1, bandwidth=1024
+	Latency         Latency            // 所有 API 的延迟
+	PathLatency     map[string]Latency // 指定 API 的延迟，键名为文档中 API 的路径，比如 /users/{id}。
+	ErrorRate       float64            // 返回文档中定义的错误内容（状态码不小于 400）的概率
+	ServerErrorRate float64            // 返回 500 的概率
+	ResetRate       float64            // 直接断开连接的概率
+	Bandwidth       int                // 每秒输出的最大字节数，0 表示不限制。
+
+	// 录制内容的保存目录
+	//
+	// 不为空时，如果存在与请求的 method、path、query 和 body 都相匹配的录制内容，
+	// 会优先返回该内容，而不是随机生成的数据。
+	Fixtures string
+
+	// 录制模式下被代理的服务地址
+	//
+	// 不为空时，所有请求都会被转发至该地址，
+	// 符合文档定义的请求与返回内容会被录制到 Fixtures 指定的目录。
+	Record string
+
+	// 生成随机数据的种子
+	//
+	// 不为 0 时，会根据该值以及请求的 method、path 和 query 生成返回内容，
+	// 相同的请求始终返回相同的内容，而不同的资源依然会返回不同的内容。
+	Seed int64
+
+	// 管理接口的路由前缀
+	//
+	// 不为空时，可以通过该前缀下的接口在运行时查看路由、重新加载文档、
+	// 覆盖指定 API 的返回内容以及查看最近的请求记录等。
+	// 必须以 / 开头且不能以 / 结尾，比如 /__admin__。
+	Admin string
+
 	DateStart time.Time // 指定生成与时间相关的数值时的最小值
 	DateEnd   time.Time // 指定生成与时间相关的数值时的最大值
 	dateSize  int64     // 根据 DateStart 和 DateEnd 生成
@@ -103,6 +180,47 @@ func (o *MockOptions) sanitize() *core.Error {
 		return core.NewError(locale.ErrIsEmpty, "EmailDomains").WithField("EmailDomains")
 	}
 
+	if err := o.Latency.sanitize(); err != nil {
+		err.Field = "Latency." + err.Field
+		return err
+	}
+
+	for path, l := range o.PathLatency {
+		if err := l.sanitize(); err != nil {
+			err.Field = "PathLatency[" + path + "]." + err.Field
+			return err
+		}
+	}
+
+	if o.ErrorRate < 0 || o.ErrorRate > 1 {
+		return core.NewError(locale.ErrInvalidValue).WithField("ErrorRate")
+	}
+
+	if o.ServerErrorRate < 0 || o.ServerErrorRate > 1 {
+		return core.NewError(locale.ErrInvalidValue).WithField("ServerErrorRate")
+	}
+
+	if o.ResetRate < 0 || o.ResetRate > 1 {
+		return core.NewError(locale.ErrInvalidValue).WithField("ResetRate")
+	}
+
+	if o.Bandwidth < 0 {
+		return core.NewError(locale.ErrInvalidValue).WithField("Bandwidth")
+	}
+
+	if o.Record != "" {
+		if o.Fixtures == "" {
+			return core.NewError(locale.ErrIsEmpty, "Fixtures").WithField("Fixtures")
+		}
+		if _, err := url.Parse(o.Record); err != nil {
+			return core.NewError(locale.ErrInvalidValue).WithField("Record")
+		}
+	}
+
+	if o.Admin != "" && (o.Admin[0] != '/' || o.Admin[len(o.Admin)-1] == '/') {
+		return core.NewError(locale.ErrInvalidValue).WithField("Admin")
+	}
+
 	now := time.Now()
 	if o.DateStart.IsZero() {
 		o.DateStart = now.Add(-time.Hour * 24 * 365)
@@ -118,6 +236,23 @@ func (o *MockOptions) sanitize() *core.Error {
 	return nil
 }
 
+// 随机数的来源
+//
+// 未指定种子时采用 math/rand 的全局函数，否则为根据种子生成的 *rand.Rand。
+type random interface {
+	Int() int
+	Intn(n int) int
+	Int63n(n int64) int64
+	Float32() float32
+}
+
+type globalRandom struct{}
+
+func (globalRandom) Int() int             { return rand.Int() }
+func (globalRandom) Intn(n int) int       { return rand.Intn(n) }
+func (globalRandom) Int63n(n int64) int64 { return rand.Int63n(n) }
+func (globalRandom) Float32() float32     { return rand.Float32() }
+
 func (o *MockOptions) gen() (*mock.GenOptions, error) {
 	if o == nil {
 		o = defaultMockOptions
@@ -126,104 +261,184 @@ func (o *MockOptions) gen() (*mock.GenOptions, error) {
 		return nil, err
 	}
 
+	return o.newGen(globalRandom{}), nil
+}
+
+// 根据种子生成 GenOptions
+//
+// seed 由 mock 根据请求计算得到，与 Seed 一起决定最终的随机种子。
+func (o *MockOptions) seedGen(seed int64) *mock.GenOptions {
+	return o.newGen(rand.New(rand.NewSource(o.Seed ^ seed)))
+}
+
+func (o *MockOptions) newGen(rnd random) *mock.GenOptions {
 	return &mock.GenOptions{
 		Number: func(p *ast.Param) any {
 			switch p.Type.V() {
 			case ast.TypeFloat:
-				return o.float()
+				return o.float(rnd)
 			case ast.TypeInt:
-				return o.integer()
+				return o.integer(rnd)
 			}
 
 			if !o.EnableFloat {
-				return o.integer()
+				return o.integer(rnd)
 			}
 
-			if rand.Int()%2 == 0 {
-				return o.integer()
+			if rnd.Int()%2 == 0 {
+				return o.integer(rnd)
 			}
-			return o.float()
+			return o.float(rnd)
 		},
 
 		String: func(p *ast.Param) string {
 			switch p.Type.V() {
 			case ast.TypeEmail:
-				return o.email()
+				return o.email(rnd)
 			case ast.TypeURL:
-				return o.url()
+				return o.url(rnd)
 			case ast.TypeImage:
-				return o.image()
+				return o.image(rnd)
 			case ast.TypeDate:
-				return o.date()
+				return o.date(rnd)
 			case ast.TypeTime:
-				return o.time()
+				return o.time(rnd)
 			case ast.TypeDateTime:
-				return o.dateTime()
+				return o.dateTime(rnd)
 			}
-			return rands.String(o.StringSize.Min, o.StringSize.Max, o.StringAlpha)
+			return randString(rnd, o.StringSize.Min, o.StringSize.Max, o.StringAlpha)
 		},
 
 		Bool: func() bool {
-			return rand.Int()%2 == 0
+			return rnd.Int()%2 == 0
 		},
 
 		SliceSize: func() int {
-			return rand.Intn(o.SliceSize.Max-o.SliceSize.Min) + o.SliceSize.Min
+			return rnd.Intn(o.SliceSize.Max-o.SliceSize.Min) + o.SliceSize.Min
 		},
 
 		Index: func(max int) int {
-			return rand.Intn(max)
+			return rnd.Intn(max)
 		},
+
+		GuessFaker: true,
+	}
+}
+
+func (o *MockOptions) options() (*mock.Options, error) {
+	if o == nil {
+		o = defaultMockOptions
+	}
+
+	g, err := o.gen()
+	if err != nil {
+		return nil, err
+	}
+
+	var seedGen func(int64) *mock.GenOptions
+	if o.Seed != 0 {
+		seedGen = o.seedGen
+	}
+
+	var upstream *url.URL
+	if o.Record != "" {
+		if upstream, err = url.Parse(o.Record); err != nil {
+			return nil, err
+		}
+	}
+
+	return &mock.Options{
+		Indent:   o.Indent,
+		ImageURL: o.ImageBasePrefix,
+		Servers:  o.Servers,
+		Gen:      g,
+		Stateful: o.Stateful,
+		Examples: o.Examples,
+		Security: o.Security,
+		Callback: o.Callback,
+		Fault:    o.fault(),
+		Fixtures: o.Fixtures,
+		Upstream: upstream,
+		SeedGen:  seedGen,
+		Admin:    o.Admin,
 	}, nil
 }
 
-func (o *MockOptions) integer() int {
-	return rand.Intn(o.NumberSize.Max-o.NumberSize.Min) + o.NumberSize.Min
+func (o *MockOptions) fault() *mock.Fault {
+	var paths map[string]mock.Latency
+	if len(o.PathLatency) > 0 {
+		paths = make(map[string]mock.Latency, len(o.PathLatency))
+		for path, l := range o.PathLatency {
+			paths[path] = mock.Latency(l)
+		}
+	}
+
+	return &mock.Fault{
+		Latency:         mock.Latency(o.Latency),
+		PathLatency:     paths,
+		ErrorRate:       o.ErrorRate,
+		ServerErrorRate: o.ServerErrorRate,
+		ResetRate:       o.ResetRate,
+		Bandwidth:       o.Bandwidth,
+	}
 }
 
-func (o *MockOptions) float() float32 {
-	return float32(o.NumberSize.Min) + rand.Float32()*float32(o.NumberSize.Max-o.NumberSize.Min)
+func (o *MockOptions) integer(rnd random) int {
+	return rnd.Intn(o.NumberSize.Max-o.NumberSize.Min) + o.NumberSize.Min
 }
 
-func (o *MockOptions) url() string {
-	url := o.URLDomains[rand.Intn(len(o.URLDomains))]
+func (o *MockOptions) float(rnd random) float32 {
+	return float32(o.NumberSize.Min) + rnd.Float32()*float32(o.NumberSize.Max-o.NumberSize.Min)
+}
+
+func (o *MockOptions) url(rnd random) string {
+	url := o.URLDomains[rnd.Intn(len(o.URLDomains))]
 	if url[len(url)-1] != '/' {
 		url += "/"
 	}
 
-	size := rand.Intn(4)
+	size := rnd.Intn(4)
 	for i := 0; i < size; i++ {
-		url += rands.String(1, 5, rands.AlphaNumber) + "/"
+		url += randString(rnd, 1, 5, rands.AlphaNumber) + "/"
 	}
 	return url
 }
 
-func (o *MockOptions) email() string {
-	domain := o.EmailDomains[rand.Intn(len(o.EmailDomains))]
-	username := rands.String(o.EmailUsernameSize.Min, o.EmailUsernameSize.Max, rands.AlphaNumber)
+func (o *MockOptions) email(rnd random) string {
+	domain := o.EmailDomains[rnd.Intn(len(o.EmailDomains))]
+	username := randString(rnd, o.EmailUsernameSize.Min, o.EmailUsernameSize.Max, rands.AlphaNumber)
 	return username + "@" + domain
 }
 
-func (o *MockOptions) image() string {
+func (o *MockOptions) image(rnd random) string {
 	path := o.ImageBasePrefix
 	if path[len(path)-1] != '/' {
 		path += "/"
 	}
-	return path + rands.String(1, 5, rands.AlphaNumber)
+	return path + randString(rnd, 1, 5, rands.AlphaNumber)
 }
 
-func (o *MockOptions) date() string {
-	s := rand.Int63n(o.dateSize)
+func (o *MockOptions) date(rnd random) string {
+	s := rnd.Int63n(o.dateSize)
 	return o.DateStart.Add(time.Duration(s) * time.Second).Format(ast.DateFormat)
 }
 
-func (o *MockOptions) time() string {
-	d := rand.Int63n(86400)
+func (o *MockOptions) time(rnd random) string {
+	d := rnd.Int63n(86400)
 	return o.DateStart.Add(time.Duration(d) * time.Second).Format(ast.TimeFormat)
 }
 
-func (o *MockOptions) dateTime() string {
-	return o.date() + "T" + o.time()
+func (o *MockOptions) dateTime(rnd random) string {
+	return o.date(rnd) + "T" + o.time(rnd)
+}
+
+// 生成长度为 [min, max) 的随机字符串，与 rands.String 相同，但采用 rnd 作为随机数的来源。
+func randString(rnd random, min, max int, bs []byte) string {
+	ret := make([]byte, min+rnd.Intn(max-min))
+	for i := range ret {
+		ret[i] = bs[rnd.Intn(len(bs))]
+	}
+	return string(ret)
 }
 
 // Mock 根据文档数据生成 Mock 中间件
@@ -231,14 +446,14 @@ func (o *MockOptions) dateTime() string {
 // data 为文档内容；
 // o 用于生成 Mock 数据的随机项，如果为 nil，则会采用默认配置项；
 func Mock(h *core.MessageHandler, data []byte, o *MockOptions) (http.Handler, error) {
-	g, err := o.gen()
+	mo, err := o.options()
 	if err != nil {
 		return nil, err
 	}
 
 	d := &ast.APIDoc{}
 	d.Parse(h, core.Block{Data: data})
-	return mock.New(h, d, o.Indent, o.ImageBasePrefix, o.Servers, g)
+	return mock.New(h, d, mo)
 }
 
 // MockFile 根据文档生成 Mock 中间件
@@ -246,10 +461,25 @@ func Mock(h *core.MessageHandler, data []byte, o *MockOptions) (http.Handler, er
 // path 为文档路径；
 // o 用于生成 Mock 数据的随机项，如果为 nil，则会采用默认配置项；
 func MockFile(h *core.MessageHandler, path core.URI, o *MockOptions) (http.Handler, error) {
-	g, err := o.gen()
+	mo, err := o.options()
+	if err != nil {
+		return nil, err
+	}
+
+	return mock.Load(h, path, mo)
+}
+
+// Proxy 根据文档生成契约测试的代理服务
+//
+// 所有的请求都会被转发至 upstream，不符合文档定义的请求和返回内容会以错误的形式输出至 h。
+// path 为文档路径；
+// upstream 为被代理的服务地址；
+// servers 为文档中所有 server 以及对应的路由前缀；
+func Proxy(h *core.MessageHandler, path core.URI, upstream string, servers map[string]string) (http.Handler, error) {
+	u, err := url.Parse(upstream)
 	if err != nil {
 		return nil, err
 	}
 
-	return mock.Load(h, path, o.Indent, o.ImageBasePrefix, o.Servers, g)
+	return mock.LoadProxy(h, path, u, servers)
 }
@@ -4,6 +4,7 @@ package apidoc
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -24,12 +25,12 @@ func TestMockOptions_url(t *testing.T) {
 	o := &MockOptions{
 		URLDomains: []string{"https://apidoc.tools/"},
 	}
-	url := o.url()
+	url := o.url(globalRandom{})
 	a.True(strings.HasPrefix(url, o.URLDomains[0])).
 		True(is.URL(url))
 
 	o.URLDomains[0] = "https://apidoc.tools"
-	url = o.url()
+	url = o.url(globalRandom{})
 	a.True(strings.HasPrefix(url, o.URLDomains[0])).
 		True(is.URL(url))
 }
@@ -41,7 +42,7 @@ func TestMockOptions_email(t *testing.T) {
 		EmailDomains:      []string{"apidoc.tools"},
 		EmailUsernameSize: Range{Min: 5, Max: 11},
 	}
-	email := o.email()
+	email := o.email(globalRandom{})
 	a.True(strings.HasSuffix(email, o.EmailDomains[0])).
 		True(is.Email(email))
 	index := strings.IndexByte(email, '@')
@@ -175,6 +176,126 @@ func TestMockOptions_gen(t *testing.T) {
 	}
 }
 
+func TestMockOptions_fault(t *testing.T) {
+	a := assert.New(t, false)
+
+	o := *defaultMockOptions
+	o.Latency = Latency{Min: time.Millisecond, Max: time.Second}
+	o.PathLatency = map[string]Latency{"/users/{id}": {Min: time.Second}}
+	o.ErrorRate = 0.5
+	o.Bandwidth = 1024
+	a.NotError(o.sanitize())
+	mo, err := o.options()
+	a.NotError(err).NotNil(mo.Fault)
+	a.Equal(mo.Fault.Latency.Max, time.Second).
+		Equal(mo.Fault.PathLatency["/users/{id}"].Min, time.Second).
+		Equal(mo.Fault.ErrorRate, 0.5).
+		Equal(mo.Fault.Bandwidth, 1024)
+
+	o.ErrorRate = 1.5
+	cerr := o.sanitize()
+	a.NotNil(cerr).Equal(cerr.Field, "ErrorRate")
+
+	o.ErrorRate = 0
+	o.PathLatency["/users/{id}"] = Latency{Min: -time.Second}
+	cerr = o.sanitize()
+	a.NotNil(cerr).Equal(cerr.Field, "PathLatency[/users/{id}].Min")
+
+	o.PathLatency = nil
+	o.Bandwidth = -1
+	cerr = o.sanitize()
+	a.NotNil(cerr).Equal(cerr.Field, "Bandwidth")
+}
+
+func TestMockOptions_record(t *testing.T) {
+	a := assert.New(t, false)
+
+	o := *defaultMockOptions
+	o.Record = "http://localhost:8080"
+	cerr := o.sanitize()
+	a.NotNil(cerr).Equal(cerr.Field, "Fixtures")
+
+	o.Fixtures = "./fixtures"
+	a.NotError(o.sanitize())
+	mo, err := o.options()
+	a.NotError(err).
+		Equal(mo.Fixtures, "./fixtures").
+		Equal(mo.Upstream.Host, "localhost:8080")
+
+	o.Record = "%"
+	cerr = o.sanitize()
+	a.NotNil(cerr).Equal(cerr.Field, "Record")
+}
+
+func TestMockOptions_admin(t *testing.T) {
+	a := assert.New(t, false)
+
+	o := *defaultMockOptions
+	o.Admin = "__admin__"
+	cerr := o.sanitize()
+	a.NotNil(cerr).Equal(cerr.Field, "Admin")
+
+	o.Admin = "/__admin__/"
+	cerr = o.sanitize()
+	a.NotNil(cerr).Equal(cerr.Field, "Admin")
+
+	o.Admin = "/__admin__"
+	a.NotError(o.sanitize())
+	mo, err := o.options()
+	a.NotError(err).Equal(mo.Admin, "/__admin__")
+}
+
+func TestMockOptions_seedGen(t *testing.T) {
+	a := assert.New(t, false)
+
+	o := *defaultMockOptions
+	o.Seed = 1024
+	a.NotError(o.sanitize())
+
+	p := &ast.Param{Type: &ast.TypeAttribute{Value: xmlenc.String{Value: "string.email"}}}
+	g1, g2, g3 := o.seedGen(1), o.seedGen(1), o.seedGen(2)
+	v1, v2, v3 := g1.String(p), g2.String(p), g3.String(p)
+	a.Equal(v1, v2).NotEqual(v1, v3)
+
+	o.Seed = 2048
+	a.NotEqual(o.seedGen(1).String(p), v1)
+
+	mo, err := o.options()
+	a.NotError(err).NotNil(mo.SeedGen)
+
+	o.Seed = 0
+	mo, err = o.options()
+	a.NotError(err).Nil(mo.SeedGen)
+}
+
+func TestMock_seed(t *testing.T) {
+	a := assert.New(t, false)
+
+	get := func(seed int64) string {
+		rslt := messagetest.NewMessageHandler()
+		defer rslt.Handler.Stop()
+
+		opt := &MockOptions{}
+		*opt = *defaultMockOptions
+		opt.Servers = map[string]string{"admin": "/admin"}
+		opt.Seed = seed
+		mock, err := Mock(rslt.Handler, asttest.XML(a), opt)
+		a.NotError(err).NotNil(mock)
+
+		r := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+		r.Header.Set("authorization", "xxx")
+		r.Header.Set("content-type", "application/json")
+		r.Header.Set("Accept", "application/json")
+		w := httptest.NewRecorder()
+		mock.ServeHTTP(w, r)
+		a.Equal(w.Code, http.StatusOK)
+		return w.Body.String()
+	}
+
+	// 相同的种子和请求，返回相同的内容。
+	a.Equal(get(1), get(1)).NotEqual(get(1), get(2))
+}
+
 func TestMock(t *testing.T) {
 	a := assert.New(t, false)
 